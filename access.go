@@ -0,0 +1,329 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// accessSampleSize is the number of documents AccessReport samples to find the stored fields of a collection.
+const accessSampleSize = 100
+
+// ErrAccessAnalyticsDisabled is returned by AccessReport when the Client was not created with WithAccessAnalytics.
+var ErrAccessAnalyticsDisabled = errors.New("mongo: access analytics is not enabled")
+
+// WithAccessAnalytics records the fields each command filters, sorts and projects on, see AccessReport.
+func WithAccessAnalytics() ClientOption {
+	return func(client *Client) {
+		recorder := &accessRecorder{collections: map[string]*collectionAccess{}}
+		client.access = recorder
+		client.commandMonitors = append(client.commandMonitors, CommandMonitor{Started: recorder.started})
+	}
+}
+
+// AccessReport describes how the collections of the Client database have been queried since it was created.
+type AccessReport struct {
+	Collections []CollectionAccess
+}
+
+// CollectionAccess describes how a collection has been queried.
+type CollectionAccess struct {
+	Collection string
+
+	// Queries is the number of commands that read the collection or filtered its documents
+	Queries int64
+
+	// Filtered, Sorted and Projected count the commands using each field, nested fields use dotted paths
+	Filtered  map[string]int64
+	Sorted    map[string]int64
+	Projected map[string]int64
+
+	// UnindexedFilters are filtered fields that no index starts with, candidates for a new index
+	UnindexedFilters []string
+
+	// NeverRead are fields of a sample of stored documents that were never filtered, sorted or projected on,
+	// candidates for a schema cleanup
+	NeverRead []string
+}
+
+// accessRecorder counts the fields used by the commands sent to MongoDB.
+type accessRecorder struct {
+	mu          sync.Mutex
+	collections map[string]*collectionAccess
+}
+
+// collectionAccess is the usage of a collection, keyed by database and collection name in accessRecorder.
+type collectionAccess struct {
+	database   string
+	collection string
+	queries    int64
+	filtered   map[string]int64
+	sorted     map[string]int64
+	projected  map[string]int64
+}
+
+// started records the fields used by a command.
+func (recorder *accessRecorder) started(evt CommandStartedEvent) {
+	if len(evt.Command) == 0 {
+		return
+	}
+	collection, ok := evt.Command[0].Value.(string)
+	if !ok {
+		return
+	}
+
+	var filters, sorts, projections []interface{}
+	switch evt.CommandName {
+	case "find":
+		filters = append(filters, commandField(evt.Command, "filter"))
+		sorts = append(sorts, commandField(evt.Command, "sort"))
+		projections = append(projections, commandField(evt.Command, "projection"))
+	case "count", "distinct":
+		filters = append(filters, commandField(evt.Command, "query"))
+	case "findAndModify":
+		filters = append(filters, commandField(evt.Command, "query"))
+		sorts = append(sorts, commandField(evt.Command, "sort"))
+		projections = append(projections, commandField(evt.Command, "fields"))
+	case "update", "delete":
+		statements, _ := commandField(evt.Command, evt.CommandName+"s").(bson.A)
+		for _, statement := range statements {
+			if doc, ok := statement.(bson.D); ok {
+				filters = append(filters, commandField(doc, "q"))
+			}
+		}
+	case "aggregate":
+		stages, _ := commandField(evt.Command, "pipeline").(bson.A)
+		for _, stage := range stages {
+			doc, ok := stage.(bson.D)
+			if !ok || len(doc) == 0 {
+				continue
+			}
+			switch doc[0].Key {
+			case "$match":
+				filters = append(filters, doc[0].Value)
+			case "$sort":
+				sorts = append(sorts, doc[0].Value)
+			case "$project":
+				projections = append(projections, doc[0].Value)
+			}
+		}
+	default:
+		return
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	key := evt.Database + "." + collection
+	access, ok := recorder.collections[key]
+	if !ok {
+		access = &collectionAccess{
+			database:   evt.Database,
+			collection: collection,
+			filtered:   map[string]int64{},
+			sorted:     map[string]int64{},
+			projected:  map[string]int64{},
+		}
+		recorder.collections[key] = access
+	}
+	access.queries++
+	for _, filter := range filters {
+		for _, field := range filterFields(filter) {
+			access.filtered[field]++
+		}
+	}
+	for _, keys := range sorts {
+		for _, field := range documentKeys(keys) {
+			access.sorted[field]++
+		}
+	}
+	for _, projection := range projections {
+		for _, field := range documentKeys(projection) {
+			access.projected[field]++
+		}
+	}
+}
+
+// commandField returns the value of a command field, or nil.
+func commandField(command bson.D, key string) interface{} {
+	value, _ := lookupField(command, key)
+	return value
+}
+
+// filterFields returns the fields a filter matches on, descending into $and, $or and $nor.
+func filterFields(filter interface{}) []string {
+	doc, ok := filter.(bson.D)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for _, elem := range doc {
+		switch elem.Key {
+		case "$and", "$or", "$nor":
+			clauses, _ := elem.Value.(bson.A)
+			for _, clause := range clauses {
+				fields = append(fields, filterFields(clause)...)
+			}
+		default:
+			if !strings.HasPrefix(elem.Key, "$") {
+				fields = append(fields, elem.Key)
+			}
+		}
+	}
+	return fields
+}
+
+// documentKeys returns the keys of a sort or projection document.
+func documentKeys(value interface{}) []string {
+	doc, ok := value.(bson.D)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(doc))
+	for _, elem := range doc {
+		keys = append(keys, elem.Key)
+	}
+	return keys
+}
+
+// AccessReport returns how the collections of the Client database have been queried. The indexes of each collection
+// are listed to find the unindexed filter fields, and a sample of its documents is read to find the never read
+// fields.
+func (connectionDetails *Client) AccessReport(opts ...Option) (*AccessReport, error) {
+	recorder := connectionDetails.access
+	if recorder == nil {
+		return nil, ErrAccessAnalyticsDisabled
+	}
+
+	recorder.mu.Lock()
+	var accesses []CollectionAccess
+	for _, access := range recorder.collections {
+		if access.database != connectionDetails.DatabaseName {
+			continue
+		}
+		accesses = append(accesses, CollectionAccess{
+			Collection: access.collection,
+			Queries:    access.queries,
+			Filtered:   copyCounts(access.filtered),
+			Sorted:     copyCounts(access.sorted),
+			Projected:  copyCounts(access.projected),
+		})
+	}
+	recorder.mu.Unlock()
+	sort.Slice(accesses, func(i, j int) bool { return accesses[i].Collection < accesses[j].Collection })
+
+	op := operation{name: "AccessReport", options: newOperationOptions(opts)}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		for i := range accesses {
+			access := &accesses[i]
+			collection := db.Collection(access.Collection)
+
+			leading, err := leadingIndexKeys(ctx, collection)
+			if err != nil {
+				return err
+			}
+			access.UnindexedFilters = nil
+			for field := range access.Filtered {
+				if !leading[field] {
+					access.UnindexedFilters = append(access.UnindexedFilters, field)
+				}
+			}
+			sort.Strings(access.UnindexedFilters)
+
+			stored, err := storedFields(ctx, collection)
+			if err != nil {
+				return err
+			}
+			access.NeverRead = nil
+			for _, field := range stored {
+				if !access.reads(field) {
+					access.NeverRead = append(access.NeverRead, field)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AccessReport{Collections: accesses}, nil
+}
+
+// reads reports whether field, one of its parents or one of its children was filtered, sorted or projected on.
+func (access *CollectionAccess) reads(field string) bool {
+	for _, used := range []map[string]int64{access.Filtered, access.Sorted, access.Projected} {
+		for name := range used {
+			if name == field || strings.HasPrefix(field, name+".") || strings.HasPrefix(name, field+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// leadingIndexKeys returns the first key of every index of collection.
+func leadingIndexKeys(ctx context.Context, collection *mongo.Collection) (map[string]bool, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []struct {
+		Key bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	leading := map[string]bool{}
+	for _, index := range indexes {
+		if len(index.Key) > 0 {
+			leading[index.Key[0].Key] = true
+		}
+	}
+	return leading, nil
+}
+
+// storedFields returns the sorted field paths of a sample of the documents of collection.
+func storedFields(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$sample", Value: bson.D{{Key: "size", Value: accessSampleSize}}}}})
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, doc := range docs {
+		collectPaths(doc, "", seen)
+	}
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// collectPaths adds the leaf field paths of doc to seen.
+func collectPaths(doc bson.D, prefix string, seen map[string]bool) {
+	for _, elem := range doc {
+		if nested, ok := elem.Value.(bson.D); ok && len(nested) > 0 {
+			collectPaths(nested, prefix+elem.Key+".", seen)
+			continue
+		}
+		seen[prefix+elem.Key] = true
+	}
+}
+
+// copyCounts returns a copy of counts.
+func copyCounts(counts map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAccessRecorder_started(t *testing.T) {
+	recorder := &accessRecorder{collections: map[string]*collectionAccess{}}
+	recorder.started(CommandStartedEvent{Database: "test", CommandName: "find", Command: bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{{Key: "age", Value: bson.D{{Key: "$gt", Value: 18}}}, {Key: "$or", Value: bson.A{
+			bson.D{{Key: "name", Value: "Akshay"}}, bson.D{{Key: "address.city", Value: "Auckland"}},
+		}}}},
+		{Key: "sort", Value: bson.D{{Key: "name", Value: 1}}},
+		{Key: "projection", Value: bson.D{{Key: "email", Value: 1}}},
+	}})
+	recorder.started(CommandStartedEvent{Database: "test", CommandName: "delete", Command: bson.D{
+		{Key: "delete", Value: "users"},
+		{Key: "deletes", Value: bson.A{bson.D{{Key: "q", Value: bson.D{{Key: "age", Value: 1}}}, {Key: "limit", Value: 1}}}},
+	}})
+	recorder.started(CommandStartedEvent{Database: "test", CommandName: "aggregate", Command: bson.D{
+		{Key: "aggregate", Value: "orders"},
+		{Key: "pipeline", Value: bson.A{bson.D{{Key: "$match", Value: bson.D{{Key: "status", Value: "paid"}}}}, bson.D{{Key: "$sort", Value: bson.D{{Key: "at", Value: -1}}}}}},
+	}})
+	recorder.started(CommandStartedEvent{Database: "test", CommandName: "insert", Command: bson.D{{Key: "insert", Value: "users"}}})
+
+	users := recorder.collections["test.users"]
+	if users == nil || users.queries != 2 {
+		t.Fatalf("Expected 2 queries on users, got %+v", users)
+	}
+	if want := map[string]int64{"age": 2, "name": 1, "address.city": 1}; !reflect.DeepEqual(users.filtered, want) {
+		t.Errorf("filtered = %v, want %v", users.filtered, want)
+	}
+	if users.sorted["name"] != 1 || users.projected["email"] != 1 {
+		t.Errorf("Unexpected sorted %v or projected %v", users.sorted, users.projected)
+	}
+
+	orders := recorder.collections["test.orders"]
+	if orders == nil || orders.filtered["status"] != 1 || orders.sorted["at"] != 1 {
+		t.Errorf("Unexpected orders access %+v", orders)
+	}
+}
+
+func TestCollectionAccess_reads(t *testing.T) {
+	access := CollectionAccess{
+		Filtered:  map[string]int64{"address": 1},
+		Sorted:    map[string]int64{},
+		Projected: map[string]int64{"profile.name": 1},
+	}
+	for field, want := range map[string]bool{"address.city": true, "profile": true, "profile.age": false, "email": false} {
+		if got := access.reads(field); got != want {
+			t.Errorf("reads(%q) = %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestCollectPaths(t *testing.T) {
+	seen := map[string]bool{}
+	collectPaths(bson.D{{Key: "_id", Value: 1}, {Key: "address", Value: bson.D{{Key: "city", Value: "Auckland"}}}, {Key: "tags", Value: bson.A{"a"}}}, "", seen)
+	var paths []string
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if want := []string{"_id", "address.city", "tags"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("collectPaths() = %v, want %v", paths, want)
+	}
+}
+
+func TestClient_AccessReport_disabled(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	if _, err := client.AccessReport(); !errors.Is(err, ErrAccessAnalyticsDisabled) {
+		t.Errorf("Expected ErrAccessAnalyticsDisabled, got %v", err)
+	}
+}
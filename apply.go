@@ -0,0 +1,377 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// namespaceExists is the server error code returned when creating a collection or a view that already exists.
+const namespaceExists = 48
+
+// ErrSpecConflict is returned by Apply when the deployment cannot be brought to the Spec, like a collection of the
+// Spec that exists as a view.
+var ErrSpecConflict = errors.New("mongo: spec conflicts with the deployment")
+
+// Kinds of ApplyAction.
+const (
+	ActionCreateCollection = "create collection"
+	ActionSetValidator     = "set validator"
+	ActionCreateIndex      = "create index"
+	ActionReplaceIndex     = "replace index"
+	ActionUpdateTTL        = "update ttl"
+	ActionDropIndex        = "drop index"
+	ActionCreateView       = "create view"
+	ActionUpdateView       = "update view"
+)
+
+// Spec is the desired state of the collections and views of a database, see Apply.
+type Spec struct {
+	Collections []CollectionSpec
+
+	// Views are created after the collections, they can be defined on the collections of the Spec
+	Views []ViewSpec
+}
+
+// CollectionSpec is the desired state of a collection.
+type CollectionSpec struct {
+	Name string
+
+	// Options the collection is created with, they are not changed once the collection exists. The validator is
+	// taken from Schema or Model.
+	Options CollectionOptions
+
+	// Indexes of the collection, matched with the existing indexes by their keys. Use ExpireAfter for TTL indexes.
+	// Text indexes are listed by the server with other keys, they are always reported as created, which is harmless.
+	Indexes []Index
+
+	// DropUnknownIndexes drops the indexes that are not in Indexes, except the "_id" index
+	DropUnknownIndexes bool
+
+	// Schema is the $jsonSchema validating the collection, generated from Model when it is nil. The validator is left
+	// alone when both are nil.
+	Schema bson.D
+
+	// Model is a struct to generate the validator from, see GenerateSchema
+	Model interface{}
+}
+
+// ViewSpec is the desired state of a view.
+type ViewSpec struct {
+	Name string
+
+	// Source is the collection or view the view reads from
+	Source string
+
+	// Pipeline of the view, mongo.Pipeline or bson.A
+	Pipeline interface{}
+}
+
+// ApplyAction is a change made by Apply.
+type ApplyAction struct {
+	// Kind is one of ActionCreateCollection, ActionSetValidator, ActionCreateIndex, ActionReplaceIndex,
+	// ActionUpdateTTL, ActionDropIndex, ActionCreateView or ActionUpdateView
+	Kind string
+
+	// Collection or view changed
+	Collection string
+
+	// Detail describes the change
+	Detail string
+
+	apply func(ctx context.Context, db *mongo.Database) error
+}
+
+// Apply brings the collections, indexes, validators and views of the Client database to the state declared by spec,
+// creating or changing only what differs, so applying the same Spec again does nothing. It returns the actions it
+// took, or would take with dryRun:
+//
+//	actions, err := client.Apply(mongo.Spec{
+//		Collections: []mongo.CollectionSpec{{
+//			Name:    "sessions",
+//			Model:   Session{},
+//			Indexes: []mongo.Index{{Keys: bson.D{{Key: "expiresAt", Value: 1}}, ExpireAfter: time.Hour}},
+//		}},
+//		Views: []mongo.ViewSpec{{Name: "active_sessions", Source: "sessions", Pipeline: activePipeline}},
+//	}, false)
+//
+// An index whose Unique, Sparse or PartialFilter changed is dropped and created again, one whose ExpireAfter changed
+// is modified in place. Collections and views missing from spec are left alone. ErrSpecConflict is returned when a
+// name of spec is a view instead of a collection, or the other way around, nothing is applied then.
+//
+// Apply can run concurrently from several instances of an application, a collection or a view created by another
+// instance in between is not an error. The actions before a failing one are applied, apply spec again once the
+// cause is fixed.
+func (connectionDetails *Client) Apply(spec Spec, dryRun bool, opts ...Option) ([]ApplyAction, error) {
+	op := operation{name: "Apply", options: newOperationOptions(opts)}
+	var actions []ApplyAction
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		specifications, err := db.ListCollectionSpecifications(ctx, bson.D{})
+		if err != nil {
+			return err
+		}
+		existing := map[string]*mongo.CollectionSpecification{}
+		for _, specification := range specifications {
+			existing[specification.Name] = specification
+		}
+		indexes := map[string][]bson.D{}
+		for _, collection := range spec.Collections {
+			if specification, ok := existing[collection.Name]; !ok || specification.Type != "collection" {
+				continue
+			}
+			cursor, err := db.Collection(collection.Name).Indexes().List(ctx)
+			if err != nil {
+				return err
+			}
+			var list []bson.D
+			if err := cursor.All(ctx, &list); err != nil {
+				return err
+			}
+			indexes[collection.Name] = list
+		}
+
+		actions, err = planApply(spec, existing, indexes)
+		if err != nil || dryRun {
+			return err
+		}
+		for _, action := range actions {
+			if err := action.apply(ctx, db); err != nil {
+				return fmt.Errorf("%s %s: %w", action.Kind, action.Collection, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// planApply returns the actions bringing the existing collections, with the indexes of the collections of spec, to
+// spec.
+func planApply(spec Spec, existing map[string]*mongo.CollectionSpecification, indexes map[string][]bson.D) ([]ApplyAction, error) {
+	var actions []ApplyAction
+	for _, collection := range spec.Collections {
+		collection := collection
+		schema := collection.Schema
+		if schema == nil && collection.Model != nil {
+			var err error
+			if schema, err = GenerateSchema(collection.Model); err != nil {
+				return nil, err
+			}
+		}
+
+		specification, ok := existing[collection.Name]
+		if !ok {
+			createOptions := collection.Options
+			if schema != nil {
+				createOptions.Validator = bson.D{{Key: "$jsonSchema", Value: schema}}
+			}
+			actions = append(actions, ApplyAction{Kind: ActionCreateCollection, Collection: collection.Name,
+				Detail: "the collection does not exist",
+				apply: func(ctx context.Context, db *mongo.Database) error {
+					return ignoreNamespaceExists(db.CreateCollection(ctx, collection.Name, createOptions.createOptions()))
+				}})
+			for _, index := range collection.Indexes {
+				actions = append(actions, createIndexAction(ActionCreateIndex, collection.Name, index))
+			}
+			continue
+		}
+		if specification.Type != "collection" {
+			return nil, fmt.Errorf("%w: %s is a %s, not a collection", ErrSpecConflict, collection.Name, specification.Type)
+		}
+
+		if schema != nil {
+			current, err := specificationSchema(specification)
+			if err != nil {
+				return nil, err
+			}
+			diff, err := DiffSchema(current, schema)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil || !diff.Empty() {
+				validator := bson.D{{Key: "$jsonSchema", Value: schema}}
+				actions = append(actions, ApplyAction{Kind: ActionSetValidator, Collection: collection.Name,
+					Detail: fmt.Sprintf("added %v, removed %v, changed %v", diff.Added, diff.Removed, diff.Changed),
+					apply: func(ctx context.Context, db *mongo.Database) error {
+						return db.RunCommand(ctx, bson.D{{Key: "collMod", Value: collection.Name}, {Key: "validator", Value: validator}}).Err()
+					}})
+			}
+		}
+
+		matched := map[int]bool{}
+		for _, index := range collection.Indexes {
+			desired := indexSignature(index.specification())
+			found := -1
+			for i, spec := range indexes[collection.Name] {
+				if !matched[i] && sameKeys(spec, index.Keys) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				actions = append(actions, createIndexAction(ActionCreateIndex, collection.Name, index))
+				continue
+			}
+			matched[found] = true
+			current := indexes[collection.Name][found]
+			if indexSignature(current) == desired {
+				continue
+			}
+			withoutTTL := index
+			withoutTTL.ExpireAfter = 0
+			if _, ttl := lookupField(current, "expireAfterSeconds"); ttl && index.ExpireAfter > 0 &&
+				indexSignature(withoutField(current, "expireAfterSeconds")) == indexSignature(withoutTTL.specification()) {
+				index := index
+				actions = append(actions, ApplyAction{Kind: ActionUpdateTTL, Collection: collection.Name,
+					Detail: fmt.Sprintf("%s to %s", indexSignature(current), desired),
+					apply: func(ctx context.Context, db *mongo.Database) error {
+						return db.RunCommand(ctx, bson.D{{Key: "collMod", Value: collection.Name}, {Key: "index", Value: bson.D{
+							{Key: "keyPattern", Value: index.Keys},
+							{Key: "expireAfterSeconds", Value: int64(index.ExpireAfter / time.Second)},
+						}}}).Err()
+					}})
+				continue
+			}
+			name, _ := lookupField(current, "name")
+			replace := createIndexAction(ActionReplaceIndex, collection.Name, index)
+			create := replace.apply
+			replace.Detail = fmt.Sprintf("%s to %s", indexSignature(current), desired)
+			replace.apply = func(ctx context.Context, db *mongo.Database) error {
+				if _, err := db.Collection(collection.Name).Indexes().DropOne(ctx, name.(string)); err != nil {
+					return err
+				}
+				return create(ctx, db)
+			}
+			actions = append(actions, replace)
+		}
+
+		if collection.DropUnknownIndexes {
+			for i, spec := range indexes[collection.Name] {
+				name, _ := lookupField(spec, "name")
+				if matched[i] || name == "_id_" {
+					continue
+				}
+				actions = append(actions, ApplyAction{Kind: ActionDropIndex, Collection: collection.Name,
+					Detail: indexSignature(spec),
+					apply: func(ctx context.Context, db *mongo.Database) error {
+						_, err := db.Collection(collection.Name).Indexes().DropOne(ctx, name.(string))
+						return err
+					}})
+			}
+		}
+	}
+
+	for _, view := range spec.Views {
+		view := view
+		pipeline, err := normalizePipeline(view.Pipeline)
+		if err != nil {
+			return nil, err
+		}
+		specification, ok := existing[view.Name]
+		if !ok {
+			actions = append(actions, ApplyAction{Kind: ActionCreateView, Collection: view.Name,
+				Detail: fmt.Sprintf("on %s", view.Source),
+				apply: func(ctx context.Context, db *mongo.Database) error {
+					return ignoreNamespaceExists(db.CreateView(ctx, view.Name, view.Source, pipeline))
+				}})
+			continue
+		}
+		if specification.Type != "view" {
+			return nil, fmt.Errorf("%w: %s is a %s, not a view", ErrSpecConflict, view.Name, specification.Type)
+		}
+		var current struct {
+			ViewOn   string `bson:"viewOn"`
+			Pipeline bson.A `bson:"pipeline"`
+		}
+		if err := bson.Unmarshal(specification.Options, &current); err != nil {
+			return nil, err
+		}
+		if current.ViewOn == view.Source && compareValues(current.Pipeline, pipeline) == 0 {
+			continue
+		}
+		actions = append(actions, ApplyAction{Kind: ActionUpdateView, Collection: view.Name,
+			Detail: fmt.Sprintf("on %s", view.Source),
+			apply: func(ctx context.Context, db *mongo.Database) error {
+				return db.RunCommand(ctx, bson.D{{Key: "collMod", Value: view.Name}, {Key: "viewOn", Value: view.Source},
+					{Key: "pipeline", Value: pipeline}}).Err()
+			}})
+	}
+	return actions, nil
+}
+
+// createIndexAction returns the action of the given kind creating index on collectionName.
+func createIndexAction(kind string, collectionName string, index Index) ApplyAction {
+	return ApplyAction{Kind: kind, Collection: collectionName, Detail: indexSignature(index.specification()),
+		apply: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(collectionName).Indexes().CreateOne(ctx, index.model())
+			return err
+		}}
+}
+
+// specification returns the index like the server lists it, see indexSignature.
+func (index Index) specification() bson.D {
+	spec := bson.D{{Key: "key", Value: index.Keys}}
+	if index.Unique {
+		spec = append(spec, bson.E{Key: "unique", Value: true})
+	}
+	if index.Sparse {
+		spec = append(spec, bson.E{Key: "sparse", Value: true})
+	}
+	if index.ExpireAfter > 0 {
+		spec = append(spec, bson.E{Key: "expireAfterSeconds", Value: int64(index.ExpireAfter / time.Second)})
+	}
+	if index.PartialFilter != nil {
+		if filter, err := toDocument(index.PartialFilter); err == nil {
+			spec = append(spec, bson.E{Key: "partialFilterExpression", Value: filter})
+		}
+	}
+	return spec
+}
+
+// sameKeys reports whether the index specification spec has keys.
+func sameKeys(spec bson.D, keys bson.D) bool {
+	value, _ := lookupField(spec, "key")
+	current, ok := value.(bson.D)
+	if !ok || len(current) != len(keys) {
+		return false
+	}
+	for i, key := range current {
+		if key.Key != keys[i].Key || compareValues(key.Value, keys[i].Value) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizePipeline converts a pipeline to the bson.A the server lists for views.
+func normalizePipeline(pipeline interface{}) (bson.A, error) {
+	if pipeline == nil {
+		return bson.A{}, nil
+	}
+	raw, err := bson.Marshal(bson.D{{Key: "pipeline", Value: pipeline}})
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Pipeline bson.A `bson:"pipeline"`
+	}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Pipeline, nil
+}
+
+// ignoreNamespaceExists returns nil for the error of creating a collection or a view that already exists.
+func ignoreNamespaceExists(err error) error {
+	var serverErr mongo.ServerError
+	if err != nil && errors.As(err, &serverErr) && serverErr.HasErrorCode(namespaceExists) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPlanApply(t *testing.T) {
+	schema := bson.D{{Key: "bsonType", Value: "object"}, {Key: "properties", Value: bson.D{
+		{Key: "name", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+	}}}
+	options, _ := bson.Marshal(bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: schema}}}})
+	viewOptions, _ := bson.Marshal(bson.D{{Key: "viewOn", Value: "users"}, {Key: "pipeline", Value: bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "active", Value: true}}}},
+	}}})
+	existing := map[string]*mongo.CollectionSpecification{
+		"users":        {Name: "users", Type: "collection", Options: options},
+		"active_users": {Name: "active_users", Type: "view", Options: viewOptions},
+	}
+	indexes := map[string][]bson.D{"users": {
+		{{Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}},
+		{{Key: "key", Value: bson.D{{Key: "email", Value: int32(1)}}}, {Key: "name", Value: "email_1"}},
+		{{Key: "key", Value: bson.D{{Key: "seenAt", Value: int32(1)}}}, {Key: "name", Value: "seenAt_1"}, {Key: "expireAfterSeconds", Value: int32(60)}},
+		{{Key: "key", Value: bson.D{{Key: "name", Value: int32(1)}}}, {Key: "name", Value: "name_1"}},
+		{{Key: "key", Value: bson.D{{Key: "legacy", Value: int32(1)}}}, {Key: "name", Value: "legacy_1"}},
+	}}
+
+	spec := Spec{
+		Collections: []CollectionSpec{
+			{
+				Name:   "users",
+				Schema: schema,
+				Indexes: []Index{
+					{Keys: bson.D{{Key: "email", Value: 1}}, Unique: true},
+					{Keys: bson.D{{Key: "seenAt", Value: 1}}, ExpireAfter: time.Hour},
+					{Keys: bson.D{{Key: "name", Value: 1}}},
+					{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+				},
+				DropUnknownIndexes: true,
+			},
+			{Name: "orders", Indexes: []Index{{Keys: bson.D{{Key: "userId", Value: 1}}}}},
+		},
+		Views: []ViewSpec{
+			{Name: "active_users", Source: "users", Pipeline: mongo.Pipeline{{{Key: "$match", Value: bson.D{{Key: "active", Value: true}}}}}},
+			{Name: "admins", Source: "users", Pipeline: bson.A{bson.D{{Key: "$match", Value: bson.D{{Key: "admin", Value: true}}}}}},
+		},
+	}
+	actions, err := planApply(spec, existing, indexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []ApplyAction
+	for _, action := range actions {
+		if action.apply == nil {
+			t.Errorf("Expected action %+v to be applicable", action)
+		}
+		action.apply = nil
+		got = append(got, action)
+	}
+	want := []ApplyAction{
+		{Kind: ActionReplaceIndex, Collection: "users", Detail: "{email: 1} to {email: 1} unique"},
+		{Kind: ActionUpdateTTL, Collection: "users", Detail: "{seenAt: 1} expireAfterSeconds 60 to {seenAt: 1} expireAfterSeconds 3600"},
+		{Kind: ActionCreateIndex, Collection: "users", Detail: "{createdAt: -1}"},
+		{Kind: ActionDropIndex, Collection: "users", Detail: "{legacy: 1}"},
+		{Kind: ActionCreateCollection, Collection: "orders", Detail: "the collection does not exist"},
+		{Kind: ActionCreateIndex, Collection: "orders", Detail: "{userId: 1}"},
+		{Kind: ActionCreateView, Collection: "admins", Detail: "on users"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected actions\n got %+v\nwant %+v", got, want)
+	}
+
+	spec.Collections[0].Schema = nil
+	spec.Collections[0].Model = struct {
+		Name string `bson:"name"`
+		Age  int    `bson:"age"`
+	}{}
+	actions, err = planApply(spec, existing, indexes)
+	if err != nil || actions[0].Kind != ActionSetValidator {
+		t.Errorf("Expected the changed validator to be set first, got %+v, %v", actions, err)
+	}
+
+	_, err = planApply(Spec{Collections: []CollectionSpec{{Name: "active_users"}}}, existing, indexes)
+	if !errors.Is(err, ErrSpecConflict) {
+		t.Errorf("Expected ErrSpecConflict for a view declared as a collection, got %v", err)
+	}
+}
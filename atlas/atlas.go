@@ -0,0 +1,145 @@
+// Package atlas manages the Atlas Search and Vector Search indexes of an Atlas cluster through the Atlas Admin API.
+//
+// Search indexes cannot be created with the createIndexes command, use this package in provisioning code next to
+// Client.CreateIndex:
+//
+//	admin := atlas.NewClient("5f1e...", "Cluster0", context.Background(), atlas.WithAPIKey(publicKey, privateKey))
+//	err := admin.EnsureSearchIndexes("shop",
+//		atlas.SearchIndex{Name: "default", Collection: "products", Definition: map[string]interface{}{"mappings": map[string]interface{}{"dynamic": true}}},
+//	)
+package atlas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultBaseURL is the Atlas Admin API used when WithBaseURL is not set.
+const DefaultBaseURL = "https://cloud.mongodb.com/api/atlas/v2"
+
+// apiVersion is the versioned media type of the Atlas Admin API requests.
+const apiVersion = "application/vnd.atlas.2024-05-30+json"
+
+// Client calls the Atlas Admin API for a cluster of a project.
+type Client struct {
+	// ProjectID is the ID of the Atlas project, also called group
+	ProjectID string
+
+	// ClusterName is the name of the cluster in the project
+	ClusterName string
+
+	// Highly recommend using timeout Context
+	Context context.Context
+
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures optional behaviour of a Client, see NewClient.
+type Option func(*Client)
+
+// WithAPIKey authenticates with the public and private key of an Atlas API key, using HTTP digest authentication.
+func WithAPIKey(publicKey string, privateKey string) Option {
+	return func(client *Client) {
+		base := client.httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient := *client.httpClient
+		httpClient.Transport = &digestTransport{username: publicKey, password: privateKey, base: base}
+		client.httpClient = &httpClient
+	}
+}
+
+// WithHTTPClient sends the requests with httpClient, for example to authenticate with a service account token. Apply
+// it before WithAPIKey when both are used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sends the requests to baseURL instead of DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(client *Client) {
+		client.baseURL = baseURL
+	}
+}
+
+// NewClient returns a Client for the cluster clusterName of the project projectID.
+func NewClient(projectID string, clusterName string, ctx context.Context, opts ...Option) *Client {
+	client := &Client{
+		ProjectID:   projectID,
+		ClusterName: clusterName,
+		Context:     ctx,
+		baseURL:     DefaultBaseURL,
+		httpClient:  &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// Error is returned for a failed Atlas Admin API request.
+type Error struct {
+	StatusCode int
+	ErrorCode  string `json:"errorCode"`
+	Detail     string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("atlas: request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("atlas: %s (%d): %s", e.ErrorCode, e.StatusCode, e.Detail)
+}
+
+// clusterPath returns the URL of path under the cluster.
+func (client *Client) clusterPath(path ...string) string {
+	u := client.baseURL + "/groups/" + url.PathEscape(client.ProjectID) + "/clusters/" + url.PathEscape(client.ClusterName)
+	for _, segment := range path {
+		u += "/" + url.PathEscape(segment)
+	}
+	return u
+}
+
+// do sends a request with body encoded as JSON and decodes the response into result when it is not nil.
+func (client *Client) do(method string, u string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(client.Context, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", apiVersion)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(apiErr)
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,100 @@
+package atlas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_EnsureSearchIndexes(t *testing.T) {
+	var created, updated []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Digest ") {
+			w.Header().Set("WWW-Authenticate", `Digest realm="MMS Public API", domain="", nonce="abc", algorithm=MD5, qop="auth", stale=false`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), `username="public"`) {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		prefix := "/groups/project/clusters/Cluster0/search/indexes"
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == prefix+"/shop/products":
+			_, _ = w.Write([]byte(`[
+				{"indexID": "1", "name": "default", "type": "search", "latestDefinition": {"mappings": {"dynamic": true}, "analyzer": "lucene.standard"}},
+				{"indexID": "2", "name": "vectors", "type": "vectorSearch", "latestDefinition": {"fields": [{"type": "vector", "path": "embedding", "numDimensions": 3}]}}
+			]`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == prefix:
+			created = append(created, body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPatch && r.URL.Path == prefix+"/2":
+			updated = append(updated, body)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("project", "Cluster0", context.Background(), WithBaseURL(server.URL), WithAPIKey("public", "private"))
+	vector := func(dimensions int) map[string]interface{} {
+		return map[string]interface{}{"fields": []map[string]interface{}{{"type": "vector", "path": "embedding", "numDimensions": dimensions}}}
+	}
+	err := client.EnsureSearchIndexes("shop",
+		SearchIndex{Name: "default", Collection: "products", Definition: map[string]interface{}{"mappings": map[string]interface{}{"dynamic": true}}},
+		SearchIndex{Name: "vectors", Collection: "products", Type: VectorSearch, Definition: vector(5)},
+		SearchIndex{Name: "default", Collection: "reviews", Definition: map[string]interface{}{"mappings": map[string]interface{}{"dynamic": true}}},
+	)
+	if err != nil {
+		t.Fatalf("EnsureSearchIndexes() error = %v", err)
+	}
+	if len(created) != 1 || created[0]["collectionName"] != "reviews" || created[0]["type"] != "search" {
+		t.Errorf("Expected the reviews index to be created, got %v", created)
+	}
+	if len(updated) != 1 {
+		t.Errorf("Expected the changed vector index to be updated, got %v", updated)
+	}
+}
+
+func TestClient_do_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorCode": "CLUSTER_NOT_FOUND", "detail": "No cluster named Cluster0"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("project", "Cluster0", context.Background(), WithBaseURL(server.URL))
+	_, err := client.SearchIndexes("shop", "products")
+	apiErr, ok := err.(*Error)
+	if !ok || apiErr.StatusCode != http.StatusNotFound || apiErr.ErrorCode != "CLUSTER_NOT_FOUND" {
+		t.Errorf("SearchIndexes() error = %v, want CLUSTER_NOT_FOUND", err)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	challenge := parseChallenge(`Digest realm="MMS Public API", nonce="n0nce", qop="auth", stale=false`)
+	if challenge["realm"] != "MMS Public API" || challenge["nonce"] != "n0nce" || challenge["qop"] != "auth" || challenge["stale"] != "false" {
+		t.Errorf("Unexpected challenge %v", challenge)
+	}
+	if parseChallenge(`Basic realm="x"`) != nil {
+		t.Errorf("Expected other schemes to be ignored")
+	}
+}
+
+func TestContains(t *testing.T) {
+	have := map[string]interface{}{"mappings": map[string]interface{}{"dynamic": true}, "analyzer": "lucene.standard"}
+	if !contains(have, map[string]interface{}{"mappings": map[string]interface{}{"dynamic": true}}) {
+		t.Errorf("Expected the defaults of Atlas to be ignored")
+	}
+	if contains(have, map[string]interface{}{"mappings": map[string]interface{}{"dynamic": false}}) {
+		t.Errorf("Expected a changed field to be detected")
+	}
+}
@@ -0,0 +1,144 @@
+package atlas
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// digestTransport authenticates requests with HTTP digest authentication, which the Atlas Admin API uses for API
+// keys.
+type digestTransport struct {
+	username string
+	password string
+	base     http.RoundTripper
+}
+
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	first, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(first)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if challenge == nil {
+		return nil, fmt.Errorf("atlas: unsupported authentication challenge %q", resp.Header.Get("WWW-Authenticate"))
+	}
+
+	retry, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	authorization, err := t.authorization(challenge, req.Method, req.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+	retry.Header.Set("Authorization", authorization)
+	return t.base.RoundTrip(retry)
+}
+
+// authorization answers a digest challenge with the "auth" quality of protection.
+func (t *digestTransport) authorization(challenge map[string]string, method string, uri string) (string, error) {
+	cnonceBytes := make([]byte, 8)
+	if _, err := rand.Read(cnonceBytes); err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(cnonceBytes)
+	const nc = "00000001"
+
+	ha1 := md5Hex(t.username + ":" + challenge["realm"] + ":" + t.password)
+	ha2 := md5Hex(method + ":" + uri)
+	var response string
+	qop := ""
+	if qops := challenge["qop"]; qops != "" {
+		for _, candidate := range strings.Split(qops, ",") {
+			if strings.TrimSpace(candidate) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return "", fmt.Errorf("atlas: unsupported digest qop %q", qops)
+		}
+		response = md5Hex(ha1 + ":" + challenge["nonce"] + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	} else {
+		response = md5Hex(ha1 + ":" + challenge["nonce"] + ":" + ha2)
+	}
+
+	parts := []string{
+		fmt.Sprintf(`username=%q`, t.username),
+		fmt.Sprintf(`realm=%q`, challenge["realm"]),
+		fmt.Sprintf(`nonce=%q`, challenge["nonce"]),
+		fmt.Sprintf(`uri=%q`, uri),
+		fmt.Sprintf(`response=%q`, response),
+		`algorithm=MD5`,
+	}
+	if qop != "" {
+		parts = append(parts, "qop="+qop, "nc="+nc, fmt.Sprintf(`cnonce=%q`, cnonce))
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		parts = append(parts, fmt.Sprintf(`opaque=%q`, opaque))
+	}
+	return "Digest " + strings.Join(parts, ", "), nil
+}
+
+// parseChallenge returns the parameters of a digest WWW-Authenticate header, or nil for another scheme.
+func parseChallenge(header string) map[string]string {
+	const prefix = "Digest "
+	if !strings.HasPrefix(header, prefix) {
+		return nil
+	}
+	params := map[string]string{}
+	rest := header[len(prefix):]
+	for rest != "" {
+		rest = strings.TrimLeft(rest, ", ")
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(rest[:eq])
+		rest = rest[eq+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+		params[strings.ToLower(key)] = value
+	}
+	return params
+}
+
+// cloneRequest returns a copy of req with a fresh body, so it can be sent twice.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,149 @@
+package atlas
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// SearchIndexType is the type of a search index.
+type SearchIndexType string
+
+const (
+	// Search is an Atlas Search index, queried with $search
+	Search SearchIndexType = "search"
+
+	// VectorSearch is an Atlas Vector Search index, queried with $vectorSearch
+	VectorSearch SearchIndexType = "vectorSearch"
+)
+
+// SearchIndex describes a search index of a collection.
+type SearchIndex struct {
+	Name       string
+	Collection string
+
+	// Type defaults to Search
+	Type SearchIndexType
+
+	// Definition is the index definition, like {"mappings": {"dynamic": true}} for Search or {"fields": [...]} for
+	// VectorSearch, it is encoded as JSON
+	Definition interface{}
+}
+
+// SearchIndexInfo is a search index as reported by Atlas.
+type SearchIndexInfo struct {
+	ID         string                 `json:"indexID"`
+	Name       string                 `json:"name"`
+	Database   string                 `json:"database"`
+	Collection string                 `json:"collectionName"`
+	Type       SearchIndexType        `json:"type"`
+	Status     string                 `json:"status"`
+	Queryable  bool                   `json:"queryable"`
+	Definition map[string]interface{} `json:"latestDefinition"`
+}
+
+// SearchIndexes returns the search indexes of collection in database.
+func (client *Client) SearchIndexes(database string, collection string) ([]SearchIndexInfo, error) {
+	var indexes []SearchIndexInfo
+	err := client.do(http.MethodGet, client.clusterPath("search", "indexes", database, collection), nil, &indexes)
+	return indexes, err
+}
+
+// EnsureSearchIndexes creates the indexes of database that do not exist, and updates the definition of the existing
+// ones when it changed. A definition is unchanged when every field it sets has the same value in the definition of
+// Atlas, which also holds the defaults.
+//
+// Atlas builds the indexes asynchronously, see SearchIndexes for their status.
+func (client *Client) EnsureSearchIndexes(database string, indexes ...SearchIndex) error {
+	existing := map[string][]SearchIndexInfo{}
+	for _, index := range indexes {
+		if _, ok := existing[index.Collection]; ok {
+			continue
+		}
+		infos, err := client.SearchIndexes(database, index.Collection)
+		if err != nil {
+			return err
+		}
+		existing[index.Collection] = infos
+	}
+
+	for _, index := range indexes {
+		if index.Type == "" {
+			index.Type = Search
+		}
+		definition, err := normalize(index.Definition)
+		if err != nil {
+			return err
+		}
+
+		var current *SearchIndexInfo
+		for i, info := range existing[index.Collection] {
+			if info.Name == index.Name {
+				current = &existing[index.Collection][i]
+				break
+			}
+		}
+		if current == nil {
+			body := map[string]interface{}{
+				"database":       database,
+				"collectionName": index.Collection,
+				"name":           index.Name,
+				"type":           index.Type,
+				"definition":     definition,
+			}
+			if err := client.do(http.MethodPost, client.clusterPath("search", "indexes"), body, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if contains(current.Definition, definition) {
+			continue
+		}
+		body := map[string]interface{}{"definition": definition}
+		if err := client.do(http.MethodPatch, client.clusterPath("search", "indexes", current.ID), body, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize converts a definition to the generic JSON values it is encoded as.
+func normalize(definition interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(encoded, &normalized)
+	return normalized, err
+}
+
+// contains reports whether every field set in want has the same value in have.
+func contains(have interface{}, want interface{}) bool {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		h, ok := have.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range w {
+			if !contains(h[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		h, ok := have.([]interface{})
+		if !ok || len(h) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !contains(h[i], w[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(have, want)
+	}
+}
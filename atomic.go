@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Increment atomically adds delta to a numeric field of the document with the given ID, a missing field is set to
+// delta. Use a negative delta to decrement.
+func (connectionDetails *Client) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return connectionDetails.updateOne("Increment", collectionName, bson.M{"_id": id}, bson.D{{Key: "$inc", Value: bson.D{{Key: field, Value: delta}}}}, opts)
+}
+
+// Push atomically appends value to an array field of the document with the given ID.
+func (connectionDetails *Client) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return connectionDetails.updateOne("Push", collectionName, bson.M{"_id": id}, bson.D{{Key: "$push", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+// Pull atomically removes every element equal to value from an array field of the document with the given ID. value
+// can also be a condition like bson.M{"$lt": 5}.
+func (connectionDetails *Client) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return connectionDetails.updateOne("Pull", collectionName, bson.M{"_id": id}, bson.D{{Key: "$pull", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+// AddToSet atomically appends value to an array field of the document with the given ID, unless it is already there.
+func (connectionDetails *Client) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return connectionDetails.updateOne("AddToSet", collectionName, bson.M{"_id": id}, bson.D{{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+// Unset removes fields from the document with the given ID.
+func (connectionDetails *Client) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	unset := bson.D{}
+	for _, field := range fields {
+		unset = append(unset, bson.E{Key: field, Value: ""})
+	}
+	return connectionDetails.updateOne("Unset", collectionName, bson.M{"_id": id}, bson.D{{Key: "$unset", Value: unset}}, opts)
+}
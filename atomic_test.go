@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_atomicOperators(t *testing.T) {
+	type counter struct {
+		ID    string   `bson:"_id"`
+		Name  string   `bson:"name,omitempty"`
+		Count int      `bson:"count"`
+		Tags  []string `bson:"tags"`
+	}
+
+	_, err := client.Save("atomic_collection", counter{ID: "1", Name: "Akshay", Count: 1, Tags: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Unable to save data. %s", err)
+	}
+
+	if _, err := client.Increment("atomic_collection", "1", "count", 2); err != nil {
+		t.Errorf("Unable to increment. %s", err)
+	}
+	if _, err := client.Push("atomic_collection", "1", "tags", "c"); err != nil {
+		t.Errorf("Unable to push. %s", err)
+	}
+	if _, err := client.Pull("atomic_collection", "1", "tags", "a"); err != nil {
+		t.Errorf("Unable to pull. %s", err)
+	}
+	updated, err := client.AddToSet("atomic_collection", "1", "tags", "b")
+	if err != nil {
+		t.Errorf("Unable to add to set. %s", err)
+	} else if updated.ModifiedCount != 0 {
+		t.Errorf("Expected an existing value not to be added again, got %d modified", updated.ModifiedCount)
+	}
+	if _, err := client.AddToSet("atomic_collection", "1", "tags", "d"); err != nil {
+		t.Errorf("Unable to add to set. %s", err)
+	}
+	if _, err := client.Unset("atomic_collection", "1", []string{"name"}); err != nil {
+		t.Errorf("Unable to unset. %s", err)
+	}
+
+	result, err := client.GetCustom("atomic_collection", bson.M{"_id": "1"})
+	if err != nil {
+		t.Fatalf("Unable to get data. %s", err)
+	}
+	var got counter
+	if err := result.Decode(&got); err != nil {
+		t.Fatalf("Unable to decode data. %s", err)
+	}
+	want := counter{ID: "1", Count: 3, Tags: []string{"b", "c", "d"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected document\n got %+v\nwant %+v", got, want)
+	}
+
+	// only the document with the given ID is updated
+	if updated, err := client.Increment("atomic_collection", "missing", "count", 1); err != nil || updated.MatchedCount != 0 {
+		t.Errorf("Expected no document to match, got %v, %v", updated, err)
+	}
+}
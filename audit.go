@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HistorySuffix is appended to the name of an audited collection to name its history collection.
+const HistorySuffix = "_history"
+
+// auditedWrites are the operations recorded by WithAuditTrail, by whether they can change more than one document.
+var auditedWrites = map[string]bool{
+	"Update": false, "UpdateCustom": false, "UpdateMany": true, "Replace": false, "Save": false,
+	"Increment": false, "Push": false, "Pull": false, "AddToSet": false, "Unset": false, "UpdateWithVersion": false,
+	"Delete": false, "DeleteCustom": false, "DeleteMany": true, "DeleteManyLimited": true, "RestoreVersion": false,
+}
+
+// HistoryEntry is the state of a document before an update or a delete, see History.
+type HistoryEntry struct {
+	ID primitive.ObjectID `bson:"_id"`
+
+	// DocumentID is the "_id" of the document
+	DocumentID interface{} `bson:"documentId"`
+
+	// Operation is the Client method that changed the document, for example "Update" or "DeleteMany"
+	Operation string `bson:"operation"`
+
+	// Actor is the actor of the context of the Client, see WithActor
+	Actor string `bson:"actor,omitempty"`
+
+	// At is when the document was changed
+	At time.Time `bson:"at"`
+
+	// Document is the document before it was changed, decode it with bson.Unmarshal
+	Document bson.Raw `bson:"document"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor, the user or service recorded in the HistoryEntry of the writes of a
+// Client using it:
+//
+//	audited := client.WithContext(mongo.WithActor(r.Context(), user.Email))
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFromContext returns the actor carried by ctx, or an empty string.
+func actorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithAuditTrail records the state of the documents of collections before every update and delete, with the actor
+// and the time of the change, in a history collection named after the collection with HistorySuffix. Use History to
+// read the changes of a document and RestoreVersion to bring one back.
+//
+// The documents are read before the write and recorded once it succeeded, a document changed in between by another
+// client is recorded in the state it was read. Failing to record the history does not fail the write, it is logged.
+// Index "documentId" in the history collections so History does not scan them.
+func WithAuditTrail(collections ...string) ClientOption {
+	return func(client *Client) {
+		if client.audited == nil {
+			client.audited = map[string]bool{}
+		}
+		for _, collection := range collections {
+			client.audited[collection] = true
+		}
+	}
+}
+
+// audit calls fn and records the history of the documents op changes when its collection is audited.
+func (connectionDetails *Client) audit(ctx context.Context, db *mongo.Database, op operation, fn func(ctx context.Context, db *mongo.Database) error) error {
+	many, ok := auditedWrites[op.name]
+	if !ok || !connectionDetails.audited[op.collection] {
+		return fn(ctx, db)
+	}
+	filter := op.filter
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := options.Find()
+	if !many {
+		find.SetLimit(1)
+	}
+	cursor, err := db.Collection(op.collection).Find(ctx, filter, find)
+	if err != nil {
+		return err
+	}
+	var before []bson.Raw
+	if err := cursor.All(ctx, &before); err != nil {
+		return err
+	}
+
+	if err := fn(ctx, db); err != nil || len(before) == 0 {
+		return err
+	}
+
+	now := time.Now()
+	actor := actorFromContext(ctx)
+	entries := make([]interface{}, len(before))
+	for i, doc := range before {
+		entries[i] = HistoryEntry{
+			ID:         primitive.NewObjectID(),
+			DocumentID: doc.Lookup("_id"),
+			Operation:  op.name,
+			Actor:      actor,
+			At:         now,
+			Document:   doc,
+		}
+	}
+	if _, err := db.Collection(op.collection+HistorySuffix).InsertMany(ctx, entries); err != nil {
+		connectionDetails.log().Warn("mongo: unable to record history", "operation", op.name, "collection", op.collection, "error", err)
+	}
+	return nil
+}
+
+// History returns the history of the document with the given id in collectionName, newest first.
+func (connectionDetails *Client) History(collectionName string, id interface{}, opts ...Option) ([]HistoryEntry, error) {
+	filter := bson.D{{Key: "documentId", Value: id}}
+	op := operation{name: "History", collection: collectionName + HistorySuffix, filter: filter, options: newOperationOptions(opts)}
+	var entries []HistoryEntry
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find := op.options.find().SetSort(bson.D{{Key: "at", Value: -1}, {Key: "_id", Value: -1}})
+		cursor, err := db.Collection(op.collection).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RestoreVersion replaces the document of the HistoryEntry with the given entryID in collectionName by the state it
+// recorded, recreating it if it was deleted. The restore is itself recorded in the history. mongo.ErrNoDocuments is
+// returned for an unknown entryID.
+func (connectionDetails *Client) RestoreVersion(collectionName string, entryID primitive.ObjectID, opts ...Option) error {
+	var entry HistoryEntry
+	op := operation{name: "History", collection: collectionName + HistorySuffix, filter: bson.D{{Key: "_id", Value: entryID}}}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.Collection(op.collection).FindOne(ctx, op.filter).Decode(&entry)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = connectionDetails.replaceOne("RestoreVersion", collectionName, bson.D{{Key: "_id", Value: entry.DocumentID}}, entry.Document, append(opts, Upsert()))
+	return err
+}
@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestActorFromContext(t *testing.T) {
+	if actor := actorFromContext(context.Background()); actor != "" {
+		t.Errorf("Expected no actor, got %q", actor)
+	}
+	if actor := actorFromContext(WithActor(context.Background(), "akshay")); actor != "akshay" {
+		t.Errorf("Expected akshay, got %q", actor)
+	}
+}
+
+func TestClient_History(t *testing.T) {
+	audited := NewMongoClient(client.ConnectionUrl, client.DatabaseName, WithActor(context.Background(), "tester"), WithAuditTrail("audited"))
+	_ = audited.DropCollections([]string{"audited", "audited" + HistorySuffix})
+	if _, err := audited.Add("audited", data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := audited.Update("audited", "1", bson.M{"name": "Raj"}); err != nil {
+		t.Fatalf("Unable to update data. %s", err)
+	}
+	if _, err := audited.Delete("audited", "1"); err != nil {
+		t.Fatalf("Unable to delete data. %s", err)
+	}
+
+	history, err := audited.History("audited", "1")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("History() = %v, %v", history, err)
+	}
+	if history[0].Operation != "Delete" || history[0].Actor != "tester" || history[1].Operation != "Update" {
+		t.Errorf("Unexpected history %+v", history)
+	}
+	var before data
+	if err := bson.Unmarshal(history[1].Document, &before); err != nil || before.Name != "Akshay" {
+		t.Errorf("Expected the state before the update, got %+v, %v", before, err)
+	}
+
+	if err := audited.RestoreVersion("audited", history[1].ID); err != nil {
+		t.Fatalf("RestoreVersion() error = %v", err)
+	}
+	var restored data
+	result, _ := audited.Get("audited", "1")
+	if err := result.Decode(&restored); err != nil || restored.Name != "Akshay" {
+		t.Errorf("Expected the restored document, got %+v, %v", restored, err)
+	}
+}
@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AutocompleteSuffix is appended to the name of a field of WithAutocomplete to name its shadow field.
+const AutocompleteSuffix = "_autocomplete"
+
+// maxAutocompletePrefix is the length of the longest prefix stored by WithAutocomplete, longer prefixes are matched
+// by their first maxAutocompletePrefix letters.
+const maxAutocompletePrefix = 20
+
+// WithAutocomplete stores the prefixes of the normalized words of the top level string fields of the documents of
+// collectionName, see Normalize, in shadow fields named after them with AutocompleteSuffix, for Suggest. The shadow
+// fields are written like the ones of WithNormalizedFields, call EnsureAutocomplete once to index them and fill them in
+// the existing documents.
+func WithAutocomplete(collectionName string, fields ...string) ClientOption {
+	return withShadow(collectionName, fields, shadow{suffix: AutocompleteSuffix, encode: func(value string) interface{} {
+		return edgeNGrams(value)
+	}})
+}
+
+// edgeNGrams returns the prefixes of the normalized words of s, up to maxAutocompletePrefix letters.
+func edgeNGrams(s string) bson.A {
+	grams := bson.A{}
+	seen := map[string]bool{}
+	for _, word := range strings.Fields(Normalize(s)) {
+		letters := []rune(word)
+		for i := 1; i <= len(letters) && i <= maxAutocompletePrefix; i++ {
+			if prefix := string(letters[:i]); !seen[prefix] {
+				seen[prefix] = true
+				grams = append(grams, prefix)
+			}
+		}
+	}
+	return grams
+}
+
+// EnsureAutocomplete indexes the shadow field of field, one of WithAutocomplete, and fills it in the documents of
+// collectionName written before the option was set. The documents are filled with a Backfill named after the
+// collection and the field, so an interrupted call resumes where it stopped and a completed one returns at once.
+func (connectionDetails *Client) EnsureAutocomplete(collectionName string, field string) error {
+	shadowField := field + AutocompleteSuffix
+	if !connectionDetails.shadows[collectionName].with(AutocompleteSuffix)[field] {
+		err := fmt.Errorf("mongo: %s of %s is not a field of WithAutocomplete", field, collectionName)
+		return connectionDetails.wrapError(operation{name: "EnsureAutocomplete", collection: collectionName}, err)
+	}
+	if _, err := connectionDetails.CreateIndex(collectionName, Index{Keys: bson.D{{Key: shadowField, Value: 1}}}); err != nil {
+		return err
+	}
+
+	backfill := BackfillOptions{
+		Name: "autocomplete:" + collectionName + "." + field,
+		Filter: bson.D{
+			{Key: field, Value: bson.D{{Key: "$type", Value: "string"}}},
+			{Key: shadowField, Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+	}
+	_, err := connectionDetails.Backfill(collectionName, backfill, func(batch []bson.Raw) ([]mongo.WriteModel, error) {
+		models := make([]mongo.WriteModel, 0, len(batch))
+		for _, doc := range batch {
+			value, ok := doc.Lookup(field).StringValueOK()
+			if !ok {
+				continue
+			}
+			filter := bson.D{{Key: "_id", Value: doc.Lookup("_id")}}
+			update := bson.D{{Key: "$set", Value: bson.D{{Key: shadowField, Value: edgeNGrams(value)}}}}
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+		}
+		return models, nil
+	})
+	return err
+}
+
+// Suggest returns up to limit distinct values of field in collectionName, one of WithAutocomplete, whose words start
+// with the words of prefix, in alphabetical order:
+//
+//	suggestions, err := client.Suggest("products", "name", "choc ch", 10) // "Chocolate chip cookies", ...
+//
+// Case and diacritics are ignored, the index created by EnsureAutocomplete serves the lookup.
+func (connectionDetails *Client) Suggest(collectionName string, field string, prefix string, limit int, opts ...Option) ([]string, error) {
+	pipeline := suggestPipeline(field, prefix, limit)
+	op := operation{name: "Suggest", collection: collectionName, filter: pipeline[0][0].Value, options: newOperationOptions(opts)}
+	var groups []struct {
+		Value string `bson:"_id"`
+	}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		cursor, err := db.Collection(collectionName).Aggregate(ctx, pipeline, op.options.aggregate())
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &groups)
+	})
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]string, len(groups))
+	for i, group := range groups {
+		suggestions[i] = group.Value
+	}
+	return suggestions, nil
+}
+
+// suggestPipeline returns the aggregation of Suggest.
+func suggestPipeline(field string, prefix string, limit int) mongo.Pipeline {
+	words := bson.A{}
+	for _, word := range strings.Fields(Normalize(prefix)) {
+		if letters := []rune(word); len(letters) > maxAutocompletePrefix {
+			word = string(letters[:maxAutocompletePrefix])
+		}
+		words = append(words, word)
+	}
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: field + AutocompleteSuffix, Value: bson.D{{Key: "$all", Value: words}}}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$" + field}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+}
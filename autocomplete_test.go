@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestEdgeNGrams(t *testing.T) {
+	want := bson.A{"c", "ch", "cho", "choc", "cr", "cre", "crem", "creme"}
+	if grams := edgeNGrams("Choc Crème"); !reflect.DeepEqual(grams, want) {
+		t.Errorf("Expected %v, got %v", want, grams)
+	}
+}
+
+func TestClient_Suggest(t *testing.T) {
+	_ = client.DropCollections([]string{"products"})
+	if _, err := client.AddMany("products", []interface{}{
+		data{ID: "1", Name: "Chocolate chip cookies"},
+		data{ID: "2", Name: "Chocolate cake"},
+	}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	completing := NewMongoClient(client.ConnectionUrl, client.DatabaseName, client.Context, WithAutocomplete("products", "name"))
+	if err := completing.EnsureAutocomplete("products", "name"); err != nil {
+		t.Fatalf("EnsureAutocomplete() error = %v", err)
+	}
+	if _, err := completing.Add("products", data{ID: "3", Name: "Chèvre chaud"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	suggestions, err := completing.Suggest("products", "name", "CH", 10)
+	if want := []string{"Chocolate cake", "Chocolate chip cookies", "Chèvre chaud"}; err != nil || !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("Suggest() = %v, %v, want %v", suggestions, err, want)
+	}
+	suggestions, err = completing.Suggest("products", "name", "choc ch", 10)
+	if want := []string{"Chocolate chip cookies"}; err != nil || !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("Suggest() = %v, %v, want %v", suggestions, err, want)
+	}
+	if err := completing.EnsureAutocomplete("products", "description"); err == nil {
+		t.Error("Expected an error for a field without autocomplete")
+	}
+}
@@ -0,0 +1,161 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCheckpointCollection stores the checkpoints of Backfill when BackfillOptions.CheckpointCollection is empty.
+const DefaultCheckpointCollection = "backfill_checkpoints"
+
+// BackfillOptions configures Client.Backfill.
+type BackfillOptions struct {
+	// Name identifies the backfill, its checkpoint is stored under this name
+	Name string
+
+	// Filter selects the documents to process - bson.M{}, bson.D{} or Filter, defaults to every document
+	Filter interface{}
+
+	// BatchSize is the number of documents per batch, defaults to 500
+	BatchSize int
+
+	// CheckpointCollection stores the checkpoints, defaults to DefaultCheckpointCollection
+	CheckpointCollection string
+
+	// Progress is called after every batch
+	Progress func(BackfillProgress)
+}
+
+// BackfillProgress reports how far a backfill went.
+type BackfillProgress struct {
+	// Processed is the number of documents processed, including the ones processed before a resume
+	Processed int64
+
+	// LastID is the "_id" of the last processed document
+	LastID interface{}
+
+	// Rate is the number of documents processed per second since the backfill started or resumed
+	Rate float64
+
+	// Elapsed since the backfill started or resumed
+	Elapsed time.Duration
+
+	// Completed is true once every document has been processed
+	Completed bool
+}
+
+// backfillCheckpoint is the document stored in the checkpoint collection.
+type backfillCheckpoint struct {
+	Name      string      `bson:"_id"`
+	LastID    interface{} `bson:"lastId"`
+	Processed int64       `bson:"processed"`
+	Completed bool        `bson:"completed"`
+	UpdatedAt time.Time   `bson:"updatedAt"`
+}
+
+// Backfill iterates collectionName in "_id" order, in batches, calling transform with each batch and bulk writing the
+// returned models. A checkpoint is saved after every batch, an interrupted backfill with the same name resumes after
+// the last saved batch.
+//
+// A completed backfill is not run again, drop its checkpoint to run it a second time.
+func (connectionDetails *Client) Backfill(collectionName string, opts BackfillOptions, transform func(batch []bson.Raw) ([]mongo.WriteModel, error)) (*BackfillProgress, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.CheckpointCollection == "" {
+		opts.CheckpointCollection = DefaultCheckpointCollection
+	}
+
+	progress := &BackfillProgress{}
+	op := operation{name: "Backfill", collection: collectionName, filter: opts.Filter}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		checkpoints := db.Collection(opts.CheckpointCollection)
+		checkpoint := backfillCheckpoint{Name: opts.Name}
+		err := checkpoints.FindOne(ctx, bson.M{"_id": opts.Name}).Decode(&checkpoint)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+		*progress = BackfillProgress{Processed: checkpoint.Processed, LastID: checkpoint.LastID, Completed: checkpoint.Completed}
+		if checkpoint.Completed {
+			return nil
+		}
+
+		base, err := toDocument(opts.Filter)
+		if err != nil {
+			return err
+		}
+		collection := connectionDetails.collection(db, collectionName, op.options)
+		start := time.Now()
+		var processed int64
+		for {
+			filter := Filter{doc: base}
+			if checkpoint.LastID != nil {
+				filter = filter.And(F("_id").Gt(checkpoint.LastID))
+			}
+			find := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(opts.BatchSize))
+			cursor, err := collection.Find(ctx, filter, find)
+			if err != nil {
+				return err
+			}
+			var batch []bson.Raw
+			if err := cursor.All(ctx, &batch); err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				break
+			}
+
+			models, err := transform(batch)
+			if err != nil {
+				return err
+			}
+			if len(models) > 0 {
+				if _, err := collection.BulkWrite(ctx, models); err != nil {
+					return err
+				}
+			}
+
+			if err := batch[len(batch)-1].Lookup("_id").Unmarshal(&checkpoint.LastID); err != nil {
+				return err
+			}
+			checkpoint.Processed += int64(len(batch))
+			checkpoint.UpdatedAt = time.Now()
+			processed += int64(len(batch))
+			if err := saveCheckpoint(ctx, checkpoints, checkpoint); err != nil {
+				return err
+			}
+
+			elapsed := time.Since(start)
+			*progress = BackfillProgress{
+				Processed: checkpoint.Processed,
+				LastID:    checkpoint.LastID,
+				Rate:      float64(processed) / elapsed.Seconds(),
+				Elapsed:   elapsed,
+			}
+			if opts.Progress != nil {
+				opts.Progress(*progress)
+			}
+			if len(batch) < opts.BatchSize {
+				break
+			}
+		}
+
+		checkpoint.Completed = true
+		checkpoint.UpdatedAt = time.Now()
+		progress.Completed = true
+		return saveCheckpoint(ctx, checkpoints, checkpoint)
+	})
+	if err != nil {
+		return progress, err
+	}
+	return progress, nil
+}
+
+func saveCheckpoint(ctx context.Context, checkpoints *mongo.Collection, checkpoint backfillCheckpoint) error {
+	_, err := checkpoints.ReplaceOne(ctx, bson.M{"_id": checkpoint.Name}, checkpoint, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClient_Backfill(t *testing.T) {
+	var testData = []interface{}{
+		data{ID: "b1", Name: "Akshay"},
+		data{ID: "b2", Name: "Raj"},
+		data{ID: "b3", Name: "Sam"},
+	}
+	_, err := client.AddMany("backfill_collection", testData)
+	if err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	defer client.DropCollections([]string{"backfill_collection", DefaultCheckpointCollection})
+
+	progress, err := client.Backfill("backfill_collection", BackfillOptions{Name: "uppercase", BatchSize: 2}, func(batch []bson.Raw) ([]mongo.WriteModel, error) {
+		var models []mongo.WriteModel
+		for _, doc := range batch {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": doc.Lookup("_id")}).
+				SetUpdate(bson.M{"$set": bson.M{"migrated": true}}))
+		}
+		return models, nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to backfill. %s", err)
+	}
+	if !progress.Completed || progress.Processed != 3 || progress.LastID != "b3" {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+}
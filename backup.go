@@ -0,0 +1,335 @@
+package mongo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// backupMagic starts an encrypted backup stream.
+const backupMagic = "MGOBAK\x00\x01"
+
+// backupChunkSize is the size of the plaintext chunks of an encrypted backup stream.
+const backupChunkSize = 64 << 10
+
+// ErrInvalidBackup is returned when reading an encrypted backup stream that was modified, truncated or is not one.
+var ErrInvalidBackup = errors.New("mongo: invalid or corrupted backup")
+
+// KeyProvider provides the AES keys of encrypted backups, see BackupOptions. Keys are 16, 24 or 32 bytes long for
+// AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// CurrentKey returns the key new backups are encrypted with and its ID, the ID is stored in the clear in the backup
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the ID read from a backup
+	Key(id string) ([]byte, error)
+}
+
+// BackupOptions configures the output of Client.Backup and the input of Client.Restore.
+type BackupOptions struct {
+	// Compress compresses the documents with gzip
+	Compress bool
+
+	// Keys encrypts the documents with AES-GCM, after compressing them, nil leaves them in the clear
+	Keys KeyProvider
+}
+
+// Backup writes the documents of collectionName matching filter to w as BSON, compressed and encrypted following
+// backup, and returns the number of documents written. Restore reads them back:
+//
+//	f, err := os.Create("orders.bak")
+//	...
+//	_, err = client.Backup("orders", nil, f, mongo.BackupOptions{Compress: true, Keys: kms})
+//
+// Without Keys the output is the .bson file of mongodump, gzipped with Compress, mongorestore reads it. An encrypted
+// backup is split in authenticated chunks, Restore detects a modified, reordered or truncated backup. w is not closed.
+func (connectionDetails *Client) Backup(collectionName string, filter interface{}, w io.Writer, backup BackupOptions, opts ...Option) (int64, error) {
+	op := operation{name: "Backup", collection: collectionName, filter: filter}
+	writer, err := NewBackupWriter(w, backup)
+	if err != nil {
+		return 0, connectionDetails.wrapError(op, err)
+	}
+	exported, err := connectionDetails.Export(collectionName, filter, writer, ExportBSON, nil, opts...)
+	if err != nil {
+		return exported, err
+	}
+	if err := writer.Close(); err != nil {
+		return exported, connectionDetails.wrapError(op, err)
+	}
+	return exported, nil
+}
+
+// Restore writes the documents of a Backup read from r into collectionName like Import does, backup must match the
+// options of the Backup. A backup that cannot be decrypted stops the restore with ErrInvalidBackup, the documents read
+// before are written.
+func (connectionDetails *Client) Restore(collectionName string, r io.Reader, backup BackupOptions, opts ImportOptions) (*ImportReport, error) {
+	reader, err := NewBackupReader(r, backup)
+	if err != nil {
+		return &ImportReport{}, connectionDetails.wrapError(operation{name: "Restore", collection: collectionName}, err)
+	}
+	defer reader.Close()
+	return connectionDetails.Import(collectionName, reader, ExportBSON, opts)
+}
+
+// NewBackupWriter returns a writer compressing and encrypting what is written to it into w following opts, for
+// backups written with Export or another tool. Close flushes it, w is not closed.
+func NewBackupWriter(w io.Writer, opts BackupOptions) (io.WriteCloser, error) {
+	var writer io.WriteCloser = nopWriteCloser{w}
+	if opts.Keys != nil {
+		encrypted, err := newEncryptWriter(w, opts.Keys)
+		if err != nil {
+			return nil, err
+		}
+		writer = encrypted
+	}
+	if opts.Compress {
+		writer = &gzipWriteCloser{Writer: gzip.NewWriter(writer), next: writer}
+	}
+	return writer, nil
+}
+
+// NewBackupReader returns a reader decrypting and decompressing r following opts, see NewBackupWriter. r is not
+// closed by Close.
+func NewBackupReader(r io.Reader, opts BackupOptions) (io.ReadCloser, error) {
+	var reader io.ReadCloser = io.NopCloser(r)
+	if opts.Keys != nil {
+		decrypted, err := newDecryptReader(r, opts.Keys)
+		if err != nil {
+			return nil, err
+		}
+		reader = io.NopCloser(decrypted)
+	}
+	if opts.Compress {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		reader = gz
+	}
+	return reader, nil
+}
+
+// nopWriteCloser is a writer with a Close that does nothing.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// gzipWriteCloser closes the writer after its gzip stream.
+type gzipWriteCloser struct {
+	*gzip.Writer
+	next io.WriteCloser
+}
+
+func (writer *gzipWriteCloser) Close() error {
+	if err := writer.Writer.Close(); err != nil {
+		return err
+	}
+	return writer.next.Close()
+}
+
+// encryptWriter encrypts a stream in chunks with AES-GCM. The stream is a header, the magic, the length of the key ID,
+// the key ID and the nonce prefix, followed by chunks made of a final flag byte, the big endian length of the sealed
+// chunk and the sealed chunk. The nonce of a chunk is the prefix followed by the chunk number, the header and the
+// final flag are authenticated with it so chunks cannot be reordered, dropped or moved to another stream.
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	chunk  uint32
+	buf    []byte
+	closed bool
+	err    error
+}
+
+// newEncryptWriter writes the header of an encrypted stream to w with the current key of keys.
+func newEncryptWriter(w io.Writer, keys KeyProvider) (*encryptWriter, error) {
+	id, key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > math.MaxUint8 {
+		return nil, fmt.Errorf("mongo: backup key ID is longer than %d bytes", math.MaxUint8)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, aead.NonceSize()-4)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header := append([]byte(backupMagic), byte(len(id)))
+	header = append(append(header, id...), prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, header: header, prefix: prefix}, nil
+}
+
+func (writer *encryptWriter) Write(p []byte) (int, error) {
+	if writer.closed {
+		return 0, errors.New("mongo: backup writer is closed")
+	}
+	if writer.err != nil {
+		return 0, writer.err
+	}
+	writer.buf = append(writer.buf, p...)
+	// a full chunk is only sealed once more data follows, the last chunk is sealed as final by Close
+	for len(writer.buf) > backupChunkSize {
+		if writer.err = writer.seal(writer.buf[:backupChunkSize], false); writer.err != nil {
+			return 0, writer.err
+		}
+		writer.buf = append(writer.buf[:0], writer.buf[backupChunkSize:]...)
+	}
+	return len(p), nil
+}
+
+// Close seals the final chunk.
+func (writer *encryptWriter) Close() error {
+	if writer.closed || writer.err != nil {
+		return writer.err
+	}
+	writer.closed = true
+	writer.err = writer.seal(writer.buf, true)
+	return writer.err
+}
+
+// seal writes the chunk plaintext.
+func (writer *encryptWriter) seal(plaintext []byte, final bool) error {
+	if writer.chunk == math.MaxUint32 {
+		return errors.New("mongo: backup is too large")
+	}
+	flag := chunkFlag(final)
+	sealed := writer.aead.Seal(nil, chunkNonce(writer.prefix, writer.chunk), plaintext, chunkData(writer.header, flag))
+	writer.chunk++
+	record := make([]byte, 5, 5+len(sealed))
+	record[0] = flag
+	binary.BigEndian.PutUint32(record[1:], uint32(len(sealed)))
+	_, err := writer.w.Write(append(record, sealed...))
+	return err
+}
+
+// decryptReader reads a stream written by an encryptWriter.
+type decryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	chunk  uint32
+	buf    []byte
+	final  bool
+}
+
+// newDecryptReader reads the header of the encrypted stream r and gets its key from keys.
+func newDecryptReader(r io.Reader, keys KeyProvider) (*decryptReader, error) {
+	header := make([]byte, len(backupMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(backupMagic)]) != backupMagic {
+		return nil, ErrInvalidBackup
+	}
+	id := make([]byte, header[len(backupMagic)])
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, ErrInvalidBackup
+	}
+	key, err := keys.Key(string(id))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, aead.NonceSize()-4)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, ErrInvalidBackup
+	}
+	header = append(append(header, id...), prefix...)
+	return &decryptReader{r: r, aead: aead, header: header, prefix: prefix}, nil
+}
+
+func (reader *decryptReader) Read(p []byte) (int, error) {
+	for len(reader.buf) == 0 {
+		if reader.final {
+			return 0, io.EOF
+		}
+		if err := reader.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, reader.buf)
+	reader.buf = reader.buf[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk.
+func (reader *decryptReader) open() error {
+	var record [5]byte
+	if _, err := io.ReadFull(reader.r, record[:]); err != nil {
+		// the final chunk was not read, the stream is truncated
+		return ErrInvalidBackup
+	}
+	size := binary.BigEndian.Uint32(record[1:])
+	if size > backupChunkSize+uint32(reader.aead.Overhead()) || reader.chunk == math.MaxUint32 {
+		return ErrInvalidBackup
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(reader.r, sealed); err != nil {
+		return ErrInvalidBackup
+	}
+	nonce := chunkNonce(reader.prefix, reader.chunk)
+	plaintext, err := reader.aead.Open(sealed[:0], nonce, sealed, chunkData(reader.header, record[0]))
+	if err != nil {
+		return ErrInvalidBackup
+	}
+	reader.chunk++
+	reader.buf = plaintext
+	if record[0] == chunkFlag(true) {
+		reader.final = true
+		var extra [1]byte
+		if n, _ := io.ReadFull(reader.r, extra[:]); n > 0 {
+			return ErrInvalidBackup
+		}
+	}
+	return nil
+}
+
+// newGCM returns the AES-GCM cipher of key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("mongo: backup key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkFlag returns the flag byte of a chunk.
+func chunkFlag(final bool) byte {
+	if final {
+		return 1
+	}
+	return 0
+}
+
+// chunkNonce returns the nonce of the chunk number n.
+func chunkNonce(prefix []byte, n uint32) []byte {
+	nonce := make([]byte, len(prefix)+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], n)
+	return nonce
+}
+
+// chunkData returns the authenticated data of a chunk, the header of the stream and the flag of the chunk.
+func chunkData(header []byte, flag byte) []byte {
+	return append(bytes.Clone(header), flag)
+}
@@ -0,0 +1,152 @@
+package mongo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// staticKeys is a KeyProvider of fixed keys, the first one is current.
+type staticKeys struct {
+	ids  []string
+	keys map[string][]byte
+}
+
+func newStaticKeys(ids ...string) staticKeys {
+	keys := staticKeys{ids: ids, keys: map[string][]byte{}}
+	for _, id := range ids {
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+		keys.keys[id] = key
+	}
+	return keys
+}
+
+func (keys staticKeys) CurrentKey() (string, []byte, error) {
+	return keys.ids[0], keys.keys[keys.ids[0]], nil
+}
+
+func (keys staticKeys) Key(id string) ([]byte, error) {
+	key, ok := keys.keys[id]
+	if !ok {
+		return nil, errors.New("unknown key " + id)
+	}
+	return key, nil
+}
+
+func writeBackup(t *testing.T, opts BackupOptions, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewBackupWriter(&buf, opts)
+	if err != nil {
+		t.Fatalf("NewBackupWriter() error = %v", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readBackup(backup []byte, opts BackupOptions) ([]byte, error) {
+	reader, err := NewBackupReader(bytes.NewReader(backup), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func TestBackupStream(t *testing.T) {
+	keys := newStaticKeys("2024-01")
+	data := make([]byte, 3*backupChunkSize+100)
+	_, _ = rand.Read(data[:backupChunkSize])
+
+	for _, opts := range []BackupOptions{{}, {Compress: true}, {Keys: keys}, {Compress: true, Keys: keys}} {
+		backup := writeBackup(t, opts, data)
+		if got, err := readBackup(backup, opts); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%+v: expected the data back, got %d bytes, %v", opts, len(got), err)
+		}
+		if opts.Keys != nil && bytes.Contains(backup, data[:64]) {
+			t.Errorf("%+v: expected the data to be encrypted", opts)
+		}
+	}
+	if backup := writeBackup(t, BackupOptions{Keys: keys}, nil); len(backup) == 0 {
+		t.Error("Expected an empty backup to have a final chunk")
+	}
+}
+
+func TestBackupStream_tampered(t *testing.T) {
+	keys := newStaticKeys("current")
+	opts := BackupOptions{Keys: keys}
+	backup := writeBackup(t, opts, bytes.Repeat([]byte("document"), backupChunkSize/4))
+
+	tests := []struct {
+		name   string
+		backup []byte
+	}{
+		{"modified", append(append([]byte{}, backup[:100]...), append([]byte{backup[100] ^ 1}, backup[101:]...)...)},
+		{"truncated", backup[:len(backup)-10]},
+		{"final chunk dropped", backup[:len(backup)-(5+backupChunkSize+16)]},
+		{"extra data", append(append([]byte{}, backup...), 0)},
+		{"not a backup", []byte("plain text")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readBackup(tt.backup, opts); !errors.Is(err, ErrInvalidBackup) {
+				t.Errorf("Expected ErrInvalidBackup, got %v", err)
+			}
+		})
+	}
+
+	if _, err := readBackup(backup, BackupOptions{Keys: newStaticKeys("other")}); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+}
+
+func TestReadBSON(t *testing.T) {
+	var buf bytes.Buffer
+	for _, doc := range []bson.D{{{Key: "_id", Value: 1}}, {{Key: "_id", Value: 2}, {Key: "name", Value: "Raj"}}} {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(raw)
+	}
+	var lines []int
+	add := func(line int, doc bson.D) error {
+		lines = append(lines, line)
+		return nil
+	}
+	if err := readBSON(bytes.NewReader(buf.Bytes()), &ImportReport{}, add); err != nil || len(lines) != 2 {
+		t.Errorf("readBSON() = %v, %v", lines, err)
+	}
+	if err := readBSON(bytes.NewReader(buf.Bytes()[:buf.Len()-3]), &ImportReport{}, add); err == nil {
+		t.Error("Expected an error for a truncated document")
+	}
+	if err := readBSON(bytes.NewReader([]byte{0, 0, 0, 0}), &ImportReport{}, add); err == nil {
+		t.Error("Expected an error for an invalid length")
+	}
+}
+
+func TestBackup(t *testing.T) {
+	_ = client.DropCollections([]string{"backup", "restored"})
+	if _, err := client.AddMany("backup", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}}); err != nil {
+		t.Fatalf("AddMany() error = %v", err)
+	}
+	opts := BackupOptions{Compress: true, Keys: newStaticKeys("current")}
+	var buf bytes.Buffer
+	if exported, err := client.Backup("backup", nil, &buf, opts); err != nil || exported != 2 {
+		t.Fatalf("Backup() = %d, %v", exported, err)
+	}
+	report, err := client.Restore("restored", &buf, opts, ImportOptions{})
+	if err != nil || report.Inserted != 2 {
+		t.Errorf("Restore() = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StartAfter resumes ForEachBatch after the document with the given "_id", as returned by a previous call.
+func StartAfter(id interface{}) Option {
+	return func(o *operationOptions) {
+		o.startAfter = id
+	}
+}
+
+// ForEachBatch pages through the documents of collectionName matching filter - bson.M{}, bson.D{} or Filter - in
+// "_id" order and calls fn with every batch of at most batchSize documents decoded as T. Pages are read with a range
+// on "_id" rather than a skip, so every page is as fast as the first one.
+//
+// It returns the "_id" of the last document of the last batch fn processed without error. When fn or a read fails,
+// pass it to StartAfter to resume after that batch:
+//
+//	lastID, err := mongo.ForEachBatch(client, "users", nil, 500, func(users []User) error {
+//		return notify(users)
+//	})
+//	if err != nil {
+//		lastID, err = mongo.ForEachBatch(client, "users", nil, 500, notify, mongo.StartAfter(lastID))
+//	}
+func ForEachBatch[T any](client *Client, collectionName string, filter interface{}, batchSize int, fn func(batch []T) error, opts ...Option) (interface{}, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	op := operation{name: "ForEachBatch", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	base, err := toDocument(filter)
+	if err != nil {
+		return nil, client.wrapError(op, err)
+	}
+
+	lastID := op.options.startAfter
+	for {
+		page := Filter{doc: base}
+		if lastID != nil {
+			page = page.And(F("_id").Gt(lastID))
+		}
+		var raws []bson.Raw
+		err := client.run(op, func(ctx context.Context, db *mongo.Database) error {
+			find := op.options.find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(batchSize))
+			cursor, err := client.collection(db, collectionName, op.options).Find(ctx, page, find)
+			if err != nil {
+				return err
+			}
+			return cursor.All(ctx, &raws)
+		})
+		if err != nil {
+			return lastID, err
+		}
+		if len(raws) == 0 {
+			return lastID, nil
+		}
+
+		batch := make([]T, len(raws))
+		for i, raw := range raws {
+			decoded, err := client.decodedRaw(collectionName, raw)
+			if err == nil {
+				err = bson.Unmarshal(decoded, &batch[i])
+			}
+			if err != nil {
+				return lastID, client.wrapError(op, err)
+			}
+		}
+		if err := fn(batch); err != nil {
+			return lastID, err
+		}
+		if err := raws[len(raws)-1].Lookup("_id").Unmarshal(&lastID); err != nil {
+			return lastID, client.wrapError(op, err)
+		}
+		if len(raws) < batchSize {
+			return lastID, nil
+		}
+	}
+}
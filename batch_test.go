@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForEachBatch_unreachable(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	lastID, err := ForEachBatch(client, "users", nil, 10, func(batch []data) error { return nil }, StartAfter("5"))
+	if err == nil {
+		t.Errorf("Expected an error without a server")
+	}
+	if lastID != "5" {
+		t.Errorf("Expected the resume point to be returned, got %v", lastID)
+	}
+}
+
+func TestForEachBatch(t *testing.T) {
+	_ = client.DropCollection("batch_collection")
+	docs := []interface{}{data{ID: "1"}, data{ID: "2"}, data{ID: "3"}, data{ID: "4"}, data{ID: "5"}}
+	if _, err := client.AddMany("batch_collection", docs); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	errStop := errors.New("stop")
+	var seen []string
+	lastID, err := ForEachBatch(client, "batch_collection", nil, 2, func(batch []data) error {
+		if len(seen) == 2 {
+			return errStop
+		}
+		for _, item := range batch {
+			seen = append(seen, item.ID)
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || lastID != "2" {
+		t.Fatalf("ForEachBatch() = %v, %v, want 2 and the error of fn", lastID, err)
+	}
+
+	lastID, err = ForEachBatch(client, "batch_collection", nil, 2, func(batch []data) error {
+		for _, item := range batch {
+			seen = append(seen, item.ID)
+		}
+		return nil
+	}, StartAfter(lastID))
+	if err != nil || lastID != "5" {
+		t.Errorf("ForEachBatch() = %v, %v, want 5", lastID, err)
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected every document once, got %v", seen)
+	}
+}
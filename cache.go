@@ -0,0 +1,167 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCacheMiss is returned by Cache.Get when the key is missing or expired.
+var ErrCacheMiss = errors.New("mongo: cache miss")
+
+// Cache is a key value cache with expiring entries stored in a collection, see Client.Cache.
+type Cache struct {
+	client     *Client
+	collection string
+
+	mu         sync.Mutex
+	indexed    bool
+	stats      CacheStats
+	refreshing map[string]bool
+}
+
+// cacheEntry is the document of a Cache entry.
+type cacheEntry struct {
+	Key       string        `bson:"_id"`
+	Value     bson.RawValue `bson:"value"`
+	ExpiresAt time.Time     `bson:"expiresAt,omitempty"`
+	StaleAt   time.Time     `bson:"staleAt,omitempty"`
+}
+
+// Cache returns a cache stored in collectionName, for sharing cached values or sessions between instances when
+// there is no Redis. Each entry is a document keyed by its key with an "expiresAt" date, a TTL index on it - created
+// on first use - lets MongoDB remove expired entries:
+//
+//	cache := client.Cache("cache")
+//	var profile Profile
+//	err := cache.GetOrLoad("profile:"+id, &profile, 10*time.Minute, func() (interface{}, error) {
+//		return loadProfile(id)
+//	})
+//
+// MongoDB removes expired documents in the background about every minute, Get does not return them in between.
+func (connectionDetails *Client) Cache(collectionName string) *Cache {
+	return &Cache{client: connectionDetails, collection: collectionName}
+}
+
+// Set stores value under key for ttl, replacing any previous value. A ttl of 0 or less never expires.
+func (cache *Cache) Set(key string, value interface{}, ttl time.Duration, opts ...Option) error {
+	return cache.set(key, value, 0, ttl, opts)
+}
+
+// set stores value under key for ttl, stale after fresh when it is above 0, see Set.
+func (cache *Cache) set(key string, value interface{}, fresh time.Duration, ttl time.Duration, opts []Option) error {
+	if err := cache.ensureIndex(); err != nil {
+		return err
+	}
+	now := time.Now()
+	doc := bson.D{{Key: "_id", Value: key}, {Key: "value", Value: value}}
+	if ttl > 0 {
+		doc = append(doc, bson.E{Key: "expiresAt", Value: now.Add(ttl)})
+	}
+	if fresh > 0 {
+		doc = append(doc, bson.E{Key: "staleAt", Value: now.Add(fresh)})
+	}
+	op := operation{name: "CacheSet", collection: cache.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	return cache.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := cache.client.collection(db, cache.collection, op.options).ReplaceOne(ctx, op.filter, doc, op.options.replace().SetUpsert(true))
+		return err
+	})
+}
+
+// Get decodes the value stored under key into result, a pointer. ErrCacheMiss is returned when the key is missing
+// or expired.
+func (cache *Cache) Get(key string, result interface{}, opts ...Option) error {
+	entry, err := cache.entry(key, opts)
+	if errors.Is(err, ErrCacheMiss) {
+		cache.count(&cache.stats.Misses)
+	}
+	if err != nil {
+		return err
+	}
+	cache.count(&cache.stats.Hits)
+	return entry.Value.Unmarshal(result)
+}
+
+// entry returns the entry stored under key, ErrCacheMiss when the key is missing or expired.
+func (cache *Cache) entry(key string, opts []Option) (cacheEntry, error) {
+	var entry cacheEntry
+	if err := cache.ensureIndex(); err != nil {
+		return entry, err
+	}
+	op := operation{name: "CacheGet", collection: cache.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	err := cache.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.Collection(cache.collection).FindOne(ctx, op.filter, op.options.findOne()).Decode(&entry)
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && !entry.ExpiresAt.IsZero() && !entry.ExpiresAt.After(time.Now())) {
+		return entry, ErrCacheMiss
+	}
+	return entry, err
+}
+
+// Delete removes key from the cache, a missing key is not an error.
+func (cache *Cache) Delete(key string, opts ...Option) error {
+	op := operation{name: "CacheDelete", collection: cache.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	return cache.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := cache.client.collection(db, cache.collection, op.options).DeleteOne(ctx, op.filter, op.options.delete())
+		return err
+	})
+}
+
+// GetOrLoad decodes the value stored under key into result, a pointer. On a miss it calls load and stores the value
+// it returns for ttl before decoding it into result. Errors of load are returned as is and nothing is stored. Failing
+// to store the loaded value is logged rather than returned, the caller still gets the value.
+//
+// Concurrent misses on the same key each call load, the cache does not coordinate them.
+func (cache *Cache) GetOrLoad(key string, result interface{}, ttl time.Duration, load func() (interface{}, error), opts ...Option) error {
+	err := cache.Get(key, result, opts...)
+	if !errors.Is(err, ErrCacheMiss) {
+		return err
+	}
+	value, err := load()
+	if err != nil {
+		return err
+	}
+	if err := cache.Set(key, value, ttl, opts...); err != nil {
+		cache.client.log().Warn("mongo: unable to cache loaded value", "collection", cache.collection, "key", key, "error", err)
+	}
+	return decodeValue(value, result)
+}
+
+// ensureIndex creates the TTL index of the cache on first use. A failure is returned and retried on the next call.
+// The index is created without holding mu, concurrent first calls may all create it, which MongoDB accepts.
+func (cache *Cache) ensureIndex() error {
+	cache.mu.Lock()
+	indexed := cache.indexed
+	cache.mu.Unlock()
+	if indexed {
+		return nil
+	}
+	op := operation{name: "CreateIndex", collection: cache.collection}
+	err := cache.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		// an expireAfterSeconds of 0 removes each document at its own expiresAt
+		index := mongo.IndexModel{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)}
+		_, err := db.Collection(cache.collection).Indexes().CreateOne(ctx, index)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	cache.mu.Lock()
+	cache.indexed = true
+	cache.mu.Unlock()
+	return nil
+}
+
+// decodeValue decodes value into result, a pointer, through BSON.
+func decodeValue(value interface{}, result interface{}) error {
+	raw, err := bson.Marshal(bson.D{{Key: "value", Value: value}})
+	if err != nil {
+		return err
+	}
+	return bson.Raw(raw).Lookup("value").Unmarshal(result)
+}
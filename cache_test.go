@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeValue(t *testing.T) {
+	var result data
+	if err := decodeValue(data{ID: "1", Name: "Akshay"}, &result); err != nil || result.Name != "Akshay" {
+		t.Errorf("decodeValue() = %+v, %v", result, err)
+	}
+	var count int64
+	if err := decodeValue(int32(3), &count); err != nil || count != 3 {
+		t.Errorf("decodeValue() = %d, %v", count, err)
+	}
+}
+
+func TestCache_GetOrLoad_connectionError(t *testing.T) {
+	cache := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test").Cache("cache")
+	loaded := false
+	var result data
+	err := cache.GetOrLoad("1", &result, time.Minute, func() (interface{}, error) {
+		loaded = true
+		return data{ID: "1"}, nil
+	})
+	if err == nil || errors.Is(err, ErrCacheMiss) || loaded {
+		t.Errorf("Expected the connection error without loading, got %v", err)
+	}
+	if cache.indexed {
+		t.Error("Expected the index to be retried on the next call")
+	}
+}
+
+func TestCache(t *testing.T) {
+	_ = client.DropCollections([]string{"cache"})
+	cache := client.Cache("cache")
+
+	var result data
+	if err := cache.Get("1", &result); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Expected ErrCacheMiss, got %v", err)
+	}
+	if err := cache.Set("1", data{ID: "1", Name: "Akshay"}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := cache.Get("1", &result); err != nil || result.Name != "Akshay" {
+		t.Errorf("Get() = %+v, %v", result, err)
+	}
+
+	if err := cache.Set("2", data{ID: "2"}, time.Millisecond); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return data{ID: "2", Name: "Raj"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		if err := cache.GetOrLoad("2", &result, time.Minute, load); err != nil || result.Name != "Raj" {
+			t.Errorf("GetOrLoad() = %+v, %v", result, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected the expired entry to be loaded once, got %d loads", loads)
+	}
+
+	if err := cache.Delete("1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := cache.Get("1", &result); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Expected ErrCacheMiss after Delete, got %v", err)
+	}
+}
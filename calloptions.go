@@ -0,0 +1,378 @@
+package mongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Option configures a single Client operation. Options that do not apply to an operation are ignored.
+type Option func(*operationOptions)
+
+// operationOptions collects the Options passed to an operation.
+type operationOptions struct {
+	upsert                   *bool
+	ordered                  *bool
+	bypassDocumentValidation *bool
+	arrayFilters             []interface{}
+	comment                  string
+	unsafeReason             string
+	validated                bool
+	writeConcern             *writeconcern.WriteConcern
+	batchSize                int32
+	startAfter               interface{}
+	readFromWriter           bool
+	hint                     interface{}
+	collation                *options.Collation
+	projection               bson.D
+	sort                     bson.D
+	limit                    int64
+	skip                     int64
+	normalized               bool
+	models                   []interface{}
+}
+
+// newOperationOptions applies opts.
+func newOperationOptions(opts []Option) *operationOptions {
+	o := &operationOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Upsert inserts a new document when no document matches the filter of an update.
+func Upsert() Option {
+	return func(o *operationOptions) {
+		upsert := true
+		o.upsert = &upsert
+	}
+}
+
+// Unordered lets AddMany continue inserting the remaining documents after one fails.
+func Unordered() Option {
+	return func(o *operationOptions) {
+		ordered := false
+		o.ordered = &ordered
+	}
+}
+
+// BypassDocumentValidation skips the collection validator for inserts and updates.
+func BypassDocumentValidation() Option {
+	return func(o *operationOptions) {
+		bypass := true
+		o.bypassDocumentValidation = &bypass
+	}
+}
+
+// ArrayFilters sets the filters that determine which array elements an update modifies - bson.M{}, or bson.D{}.
+func ArrayFilters(filters ...interface{}) Option {
+	return func(o *operationOptions) {
+		o.arrayFilters = append(o.arrayFilters, filters...)
+	}
+}
+
+// Comment attaches a comment to the operation, it shows up in the profiler, currentOp and the server logs.
+func Comment(comment string) Option {
+	return func(o *operationOptions) {
+		o.comment = comment
+	}
+}
+
+// Hint makes a read use an index, given by its name or its keys like bson.D{{Key: "email", Value: 1}}, instead of
+// the one the query planner picks.
+func Hint(index interface{}) Option {
+	return func(o *operationOptions) {
+		o.hint = index
+	}
+}
+
+// Collation is the language rules used to compare strings, see Collate. Only Locale is required.
+type Collation struct {
+	// Locale is an ICU locale like "en" or "fr_CA", "simple" compares the binary values of the strings
+	Locale string
+
+	// CaseLevel compares case at strength 1 or 2
+	CaseLevel bool
+
+	// CaseFirst sorts "upper" or "lower" case first, "off" by default
+	CaseFirst string
+
+	// Strength is the level of comparison from 1 to 5, 1 ignores case and diacritics, 2 ignores case, 3 by default
+	Strength int
+
+	// NumericOrdering compares numeric strings as numbers, so "10" is greater than "2"
+	NumericOrdering bool
+
+	// Alternate is "shifted" to ignore whitespace and punctuation up to MaxVariable, "non-ignorable" by default
+	Alternate string
+
+	// MaxVariable is "punct" or "space", the characters Alternate "shifted" ignores
+	MaxVariable string
+
+	// Normalization checks that text requires normalization and normalizes it
+	Normalization bool
+
+	// Backwards compares strings with diacritics from the end of the string, for French
+	Backwards bool
+}
+
+// driverCollation converts the collation to the driver collation, nil stays nil.
+func (collation *Collation) driverCollation() *options.Collation {
+	if collation == nil {
+		return nil
+	}
+	return &options.Collation{
+		Locale:          collation.Locale,
+		CaseLevel:       collation.CaseLevel,
+		CaseFirst:       collation.CaseFirst,
+		Strength:        collation.Strength,
+		NumericOrdering: collation.NumericOrdering,
+		Alternate:       collation.Alternate,
+		MaxVariable:     collation.MaxVariable,
+		Normalization:   collation.Normalization,
+		Backwards:       collation.Backwards,
+	}
+}
+
+// Collate sets the collation used by a read to compare strings, an index is only used when it has the same
+// collation. Strength 2 compares case-insensitively:
+//
+//	client.GetCustom("users", bson.M{"email": email}, mongo.Collate(mongo.Collation{Locale: "en", Strength: 2}))
+func Collate(collation Collation) Option {
+	return func(o *operationOptions) {
+		o.collation = collation.driverCollation()
+	}
+}
+
+// Select only returns fields, and "_id", from the documents of a read, dotted paths like "address.city" are allowed:
+//
+//	client.GetAllCustom("users", bson.M{"active": true}, &users, mongo.Select("name", "email"))
+//
+// Select and Exclude cannot be combined, except to exclude "_id".
+func Select(fields ...string) Option {
+	return project(fields, 1)
+}
+
+// Exclude leaves fields out of the documents of a read, see Select.
+func Exclude(fields ...string) Option {
+	return project(fields, 0)
+}
+
+// project adds fields with value to the projection of a read.
+func project(fields []string, value int) Option {
+	return func(o *operationOptions) {
+		for _, field := range fields {
+			o.projection = append(o.projection, bson.E{Key: field, Value: value})
+		}
+	}
+}
+
+// SortAsc sorts the documents of a read by fields in ascending order, the first field first. SortAsc and SortDesc
+// can be combined:
+//
+//	client.GetAllCustom("orders", bson.M{"status": "open"}, &orders,
+//		mongo.SortDesc("createdAt"), mongo.SortAsc("_id"), mongo.Limit(20))
+func SortAsc(fields ...string) Option {
+	return sortBy(fields, 1)
+}
+
+// SortDesc sorts the documents of a read by fields in descending order, see SortAsc.
+func SortDesc(fields ...string) Option {
+	return sortBy(fields, -1)
+}
+
+// sortBy adds fields with direction to the sort of a read.
+func sortBy(fields []string, direction int) Option {
+	return func(o *operationOptions) {
+		for _, field := range fields {
+			o.sort = append(o.sort, bson.E{Key: field, Value: direction})
+		}
+	}
+}
+
+// Limit returns at most n documents from a read.
+func Limit(n int64) Option {
+	return func(o *operationOptions) {
+		o.limit = n
+	}
+}
+
+// Skip leaves out the first n documents of a read, usually with SortAsc or SortDesc so the order is stable.
+func Skip(n int64) Option {
+	return func(o *operationOptions) {
+		o.skip = n
+	}
+}
+
+// BatchSize sets the number of documents returned by each batch of a cursor, see FindIter.
+func BatchSize(size int32) Option {
+	return func(o *operationOptions) {
+		o.batchSize = size
+	}
+}
+
+// WriteConcern sets the write concern of a write: w is the number of members that must acknowledge it or "majority",
+// journal waits for the write to be on disk, and timeout, when not 0, limits how long to wait for the
+// acknowledgements.
+//
+//	client.Add("payments", payment, mongo.WriteConcern("majority", true, 5*time.Second))
+func WriteConcern(w interface{}, journal bool, timeout time.Duration) Option {
+	return func(o *operationOptions) {
+		o.writeConcern = newWriteConcern(w, journal, timeout)
+	}
+}
+
+// newWriteConcern returns the driver write concern.
+func newWriteConcern(w interface{}, journal bool, timeout time.Duration) *writeconcern.WriteConcern {
+	return &writeconcern.WriteConcern{W: w, Journal: &journal, WTimeout: timeout}
+}
+
+func (o *operationOptions) insertOne() *options.InsertOneOptions {
+	opts := options.InsertOne()
+	if o.bypassDocumentValidation != nil {
+		opts.SetBypassDocumentValidation(*o.bypassDocumentValidation)
+	}
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	return opts
+}
+
+func (o *operationOptions) insertMany() *options.InsertManyOptions {
+	opts := options.InsertMany()
+	if o.ordered != nil {
+		opts.SetOrdered(*o.ordered)
+	}
+	if o.bypassDocumentValidation != nil {
+		opts.SetBypassDocumentValidation(*o.bypassDocumentValidation)
+	}
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	return opts
+}
+
+func (o *operationOptions) update() *options.UpdateOptions {
+	opts := options.Update()
+	if o.upsert != nil {
+		opts.SetUpsert(*o.upsert)
+	}
+	if o.bypassDocumentValidation != nil {
+		opts.SetBypassDocumentValidation(*o.bypassDocumentValidation)
+	}
+	if len(o.arrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: o.arrayFilters})
+	}
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	return opts
+}
+
+func (o *operationOptions) replace() *options.ReplaceOptions {
+	opts := options.Replace()
+	if o.upsert != nil {
+		opts.SetUpsert(*o.upsert)
+	}
+	if o.bypassDocumentValidation != nil {
+		opts.SetBypassDocumentValidation(*o.bypassDocumentValidation)
+	}
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	return opts
+}
+
+func (o *operationOptions) delete() *options.DeleteOptions {
+	opts := options.Delete()
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	return opts
+}
+
+func (o *operationOptions) findOne() *options.FindOneOptions {
+	opts := options.FindOne()
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	if o.hint != nil {
+		opts.SetHint(o.hint)
+	}
+	if o.collation != nil {
+		opts.SetCollation(o.collation)
+	}
+	if o.projection != nil {
+		opts.SetProjection(o.projection)
+	}
+	if o.sort != nil {
+		opts.SetSort(o.sort)
+	}
+	if o.skip > 0 {
+		opts.SetSkip(o.skip)
+	}
+	return opts
+}
+
+func (o *operationOptions) find() *options.FindOptions {
+	opts := options.Find()
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	if o.batchSize > 0 {
+		opts.SetBatchSize(o.batchSize)
+	}
+	if o.hint != nil {
+		opts.SetHint(o.hint)
+	}
+	if o.collation != nil {
+		opts.SetCollation(o.collation)
+	}
+	if o.projection != nil {
+		opts.SetProjection(o.projection)
+	}
+	if o.sort != nil {
+		opts.SetSort(o.sort)
+	}
+	if o.skip > 0 {
+		opts.SetSkip(o.skip)
+	}
+	if o.limit > 0 {
+		opts.SetLimit(o.limit)
+	}
+	return opts
+}
+
+func (o *operationOptions) aggregate() *options.AggregateOptions {
+	opts := options.Aggregate()
+	if o.comment != "" {
+		opts.SetComment(o.comment)
+	}
+	if o.batchSize > 0 {
+		opts.SetBatchSize(o.batchSize)
+	}
+	if o.hint != nil {
+		opts.SetHint(o.hint)
+	}
+	if o.collation != nil {
+		opts.SetCollation(o.collation)
+	}
+	return opts
+}
+
+// commandOptions adds the Comment, Hint and Collation options to a find or aggregate command.
+func (o *operationOptions) commandOptions(command bson.D) bson.D {
+	if o.comment != "" {
+		command = append(command, bson.E{Key: "comment", Value: o.comment})
+	}
+	if o.hint != nil {
+		command = append(command, bson.E{Key: "hint", Value: o.hint})
+	}
+	if o.collation != nil {
+		command = append(command, bson.E{Key: "collation", Value: o.collation.ToDocument()})
+	}
+	return command
+}
@@ -0,0 +1,126 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestOperationOptions_update(t *testing.T) {
+	opts := newOperationOptions([]Option{Upsert(), ArrayFilters(bson.M{"x.status": "active"}), Comment("backfill")}).update()
+
+	if opts.Upsert == nil || !*opts.Upsert {
+		t.Errorf("Expected upsert to be set")
+	}
+	if opts.ArrayFilters == nil || len(opts.ArrayFilters.Filters) != 1 {
+		t.Errorf("Expected one array filter, got %v", opts.ArrayFilters)
+	}
+	if opts.Comment != "backfill" {
+		t.Errorf("Expected comment to be set, got %v", opts.Comment)
+	}
+}
+
+func TestOperationOptions_insertMany(t *testing.T) {
+	opts := newOperationOptions([]Option{Unordered(), BypassDocumentValidation()}).insertMany()
+
+	if opts.Ordered == nil || *opts.Ordered {
+		t.Errorf("Expected ordered to be false")
+	}
+	if opts.BypassDocumentValidation == nil || !*opts.BypassDocumentValidation {
+		t.Errorf("Expected document validation to be bypassed")
+	}
+	if ordered := newOperationOptions(nil).insertMany().Ordered; ordered == nil || !*ordered {
+		t.Errorf("Expected ordered inserts without options")
+	}
+}
+
+func TestOperationOptions_find(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	o := newOperationOptions([]Option{Hint("email_1"), Collate(Collation{Locale: "en", Strength: 2})})
+
+	if find := o.find(); find.Hint != "email_1" || !reflect.DeepEqual(find.Collation, collation) {
+		t.Errorf("Expected hint and collation to be set on find, got %v, %v", find.Hint, find.Collation)
+	}
+	if findOne := o.findOne(); findOne.Hint != "email_1" || !reflect.DeepEqual(findOne.Collation, collation) {
+		t.Errorf("Expected hint and collation to be set on findOne, got %v, %v", findOne.Hint, findOne.Collation)
+	}
+	command := o.commandOptions(bson.D{{Key: "find", Value: "users"}})
+	if len(command) != 3 || command[1].Key != "hint" || command[2].Key != "collation" {
+		t.Errorf("Expected hint and collation in the command, got %v", command)
+	}
+}
+
+func TestClient_writeConcern(t *testing.T) {
+	client := &Client{
+		safety:        &SafetyProfile{MajorityCollections: []string{"payments"}},
+		writeConcerns: map[string]*writeconcern.WriteConcern{"logs": newWriteConcern(0, false, 0)},
+	}
+	tests := []struct {
+		name       string
+		collection string
+		opts       []Option
+		want       interface{}
+	}{
+		{"default", "users", nil, nil},
+		{"collection default", "logs", nil, 0},
+		{"operation overrides collection", "logs", []Option{WriteConcern(2, true, time.Second)}, 2},
+		{"safety profile wins", "payments", []Option{WriteConcern(1, false, 0)}, "majority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := client.writeConcern(tt.collection, newOperationOptions(tt.opts))
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("writeConcern() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.W != tt.want {
+				t.Errorf("writeConcern() = %v, want w %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConcern(t *testing.T) {
+	concern := newOperationOptions([]Option{WriteConcern("majority", true, 5*time.Second)}).writeConcern
+	if concern.W != "majority" || concern.Journal == nil || !*concern.Journal || concern.WTimeout != 5*time.Second {
+		t.Errorf("Expected majority journaled write concern with a timeout, got %+v", concern)
+	}
+}
+
+func TestOperationOptions_projection(t *testing.T) {
+	o := newOperationOptions([]Option{Select("name", "address.city"), Exclude("_id")})
+	want := bson.D{{Key: "name", Value: 1}, {Key: "address.city", Value: 1}, {Key: "_id", Value: 0}}
+
+	if projection := o.find().Projection; !reflect.DeepEqual(projection, want) {
+		t.Errorf("Expected projection %v on find, got %v", want, projection)
+	}
+	if projection := o.findOne().Projection; !reflect.DeepEqual(projection, want) {
+		t.Errorf("Expected projection %v on findOne, got %v", want, projection)
+	}
+	if projection := newOperationOptions(nil).find().Projection; projection != nil {
+		t.Errorf("Expected no projection by default, got %v", projection)
+	}
+}
+
+func TestOperationOptions_sort(t *testing.T) {
+	o := newOperationOptions([]Option{SortDesc("createdAt"), SortAsc("_id"), Limit(20), Skip(40)})
+	want := bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: 1}}
+
+	find := o.find()
+	if !reflect.DeepEqual(find.Sort, want) || *find.Limit != 20 || *find.Skip != 40 {
+		t.Errorf("Expected sort %v, limit 20 and skip 40, got %v, %d and %d", want, find.Sort, *find.Limit, *find.Skip)
+	}
+	findOne := o.findOne()
+	if !reflect.DeepEqual(findOne.Sort, want) || *findOne.Skip != 40 {
+		t.Errorf("Expected sort %v and skip 40 on findOne, got %v and %d", want, findOne.Sort, *findOne.Skip)
+	}
+	if find := newOperationOptions(nil).find(); find.Sort != nil || find.Limit != nil || find.Skip != nil {
+		t.Errorf("Expected no sort, limit or skip by default, got %v, %v and %v", find.Sort, find.Limit, find.Skip)
+	}
+}
@@ -0,0 +1,171 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCatalogCollection holds the catalog documents of WithCatalog.
+const DefaultCatalogCollection = "catalogs"
+
+// ErrUnknownCatalog is returned by CatalogValues for a field that was not configured with WithCatalog.
+var ErrUnknownCatalog = errors.New("mongo: unknown catalog")
+
+// WithCatalog keeps the distinct values of fields of collectionName in a catalog document of DefaultCatalogCollection,
+// for CatalogValues. Use it for low cardinality fields like a status, a category or tags, the values of a field must
+// fit in a document.
+//
+// The values of the documents added with Add and AddMany are added to the catalogs directly, any other write to the
+// collection marks its catalogs as stale and the next CatalogValues reads the distinct values again. Writes that are
+// not made through the Client are not seen, use RefreshCatalog after them.
+func WithCatalog(collectionName string, fields ...string) ClientOption {
+	return func(client *Client) {
+		if client.catalogs == nil {
+			client.catalogs = map[string][]string{}
+		}
+		client.catalogs[collectionName] = append(client.catalogs[collectionName], fields...)
+	}
+}
+
+// CatalogValues returns the distinct values of field in collectionName, one of WithCatalog, for example the options of
+// a filter drop-down. The values are only read from the collection when the catalog is missing or stale, an array
+// field contributes each of its elements.
+func (connectionDetails *Client) CatalogValues(collectionName string, field string, opts ...Option) ([]interface{}, error) {
+	return connectionDetails.catalogValues("CatalogValues", collectionName, field, false, opts)
+}
+
+// RefreshCatalog reads the distinct values of field in collectionName again and returns them, see CatalogValues.
+func (connectionDetails *Client) RefreshCatalog(collectionName string, field string, opts ...Option) ([]interface{}, error) {
+	return connectionDetails.catalogValues("RefreshCatalog", collectionName, field, true, opts)
+}
+
+func (connectionDetails *Client) catalogValues(name string, collectionName string, field string, refresh bool,
+	opts []Option) ([]interface{}, error) {
+	if !connectionDetails.hasCatalog(collectionName, field) {
+		return nil, fmt.Errorf("%w: %q of %q", ErrUnknownCatalog, field, collectionName)
+	}
+	op := operation{name: name, collection: collectionName, options: newOperationOptions(opts)}
+	id := catalogID(collectionName, field)
+	var values []interface{}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		catalogs := db.Collection(DefaultCatalogCollection)
+		if !refresh {
+			var catalog struct {
+				Values []interface{} `bson:"values"`
+				Stale  bool          `bson:"stale"`
+			}
+			err := catalogs.FindOne(ctx, bson.M{"_id": id}).Decode(&catalog)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+			if err == nil && !catalog.Stale {
+				values = catalog.Values
+				return nil
+			}
+		}
+
+		distinct, err := db.Collection(collectionName).Distinct(ctx, field, bson.D{})
+		if err != nil {
+			return err
+		}
+		values = distinct
+		_, err = catalogs.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+			"values":    distinct,
+			"stale":     false,
+			"updatedAt": time.Now(),
+		}}, options.Update().SetUpsert(true))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []interface{}{}
+	}
+	return values, nil
+}
+
+// hasCatalog reports whether field of collectionName is one of WithCatalog.
+func (connectionDetails *Client) hasCatalog(collectionName string, field string) bool {
+	for _, catalogField := range connectionDetails.catalogs[collectionName] {
+		if catalogField == field {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogID returns the ID of the catalog document of field of collectionName.
+func catalogID(collectionName string, field string) string {
+	return collectionName + "." + field
+}
+
+// maintainCatalogs adds the values of the documents added by op to the catalogs of its collection, or marks them as
+// stale, opErr is the error op returned.
+func (connectionDetails *Client) maintainCatalogs(ctx context.Context, op operation, opErr error) {
+	for _, field := range connectionDetails.catalogs[op.collection] {
+		var update bson.M
+		switch {
+		case op.name == "Add" || op.name == "AddMany":
+			if opErr != nil {
+				if op.name == "AddMany" {
+					update = staleUpdate()
+				}
+				break
+			}
+			values, ok := fieldValues(op.documents, field)
+			if !ok {
+				update = staleUpdate()
+			} else if len(values) > 0 {
+				update = bson.M{
+					"$addToSet": bson.M{"values": bson.M{"$each": values}},
+					"$set":      bson.M{"updatedAt": time.Now()},
+				}
+			}
+		case staleWrite(op.name):
+			update = staleUpdate()
+		}
+		if update == nil {
+			continue
+		}
+
+		err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+			// $addToSet only applies to an existing catalog, a missing catalog is read by the next CatalogValues
+			filter := bson.M{"_id": catalogID(op.collection, field)}
+			upsert := options.Update().SetUpsert(update["$addToSet"] == nil)
+			_, err := db.Collection(DefaultCatalogCollection).UpdateOne(ctx, filter, update, upsert)
+			return err
+		})
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to maintain catalog", "collection", op.collection, "field", field,
+				"operation", op.name, "error", err)
+		}
+	}
+}
+
+// fieldValues returns the values of field in documents, the elements of an array value one by one. It is false when a
+// document cannot be converted.
+func fieldValues(documents []interface{}, field string) (bson.A, bool) {
+	values := bson.A{}
+	for _, document := range documents {
+		doc, err := toDocument(document)
+		if err != nil {
+			return nil, false
+		}
+		switch value := pathValue(doc, strings.Split(field, ".")).(type) {
+		case nil:
+		case bson.A:
+			values = append(values, value...)
+		default:
+			values = append(values, value)
+		}
+	}
+	return values, true
+}
@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFieldValues(t *testing.T) {
+	documents := []interface{}{
+		bson.M{"_id": "1", "status": "open", "tags": bson.A{"go", "db"}},
+		bson.M{"_id": "2", "status": "closed", "meta": bson.M{"kind": "bug"}},
+		data{ID: "3", Name: "Akshay"},
+	}
+
+	tests := []struct {
+		field string
+		want  bson.A
+	}{
+		{"status", bson.A{"open", "closed"}},
+		{"tags", bson.A{"go", "db"}},
+		{"meta.kind", bson.A{"bug"}},
+		{"name", bson.A{"Akshay"}},
+		{"missing", bson.A{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, ok := fieldValues(documents, tt.field)
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldValues() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogValues_unknown(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test", WithCatalog("tickets", "status", "tags"))
+
+	if !client.hasCatalog("tickets", "tags") {
+		t.Error("Expected the tags catalog to be configured")
+	}
+	if _, err := client.CatalogValues("tickets", "priority"); !errors.Is(err, ErrUnknownCatalog) {
+		t.Errorf("Expected ErrUnknownCatalog, got %v", err)
+	}
+}
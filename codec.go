@@ -0,0 +1,123 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DocumentCodec transforms the documents of a collection between their model form and the form they are stored in,
+// see WithCodec.
+type DocumentCodec interface {
+	// Encode returns the stored form of doc, a model converted to BSON
+	Encode(doc bson.D) (bson.D, error)
+
+	// Decode returns the model form of doc, a stored document, before it is decoded into the result
+	Decode(doc bson.D) (bson.D, error)
+}
+
+// WithCodec stores the documents of collectionName in the form returned by codec, for alternate formats like a
+// protobuf payload in a BSON envelope or a payload tagged with its schema registry ID:
+//
+//	client := mongo.NewMongoClientDefault(uri, "events", mongo.WithCodec("events", envelopeCodec{registry}))
+//
+// Add, AddMany, Replace, Save and GetOrCreate encode the documents they write, Get, GetCustom, GetAll, GetAllCustom,
+// GetCached, FindIter and ForEachBatch decode the documents they read. Filters, and the updates of Update and the
+// other partial writes, are sent as is, they address the stored form. Shadow fields like the ones of
+// WithNormalizedFields are derived from the stored form.
+func WithCodec(collectionName string, codec DocumentCodec) ClientOption {
+	return func(client *Client) {
+		if client.codecs == nil {
+			client.codecs = map[string]DocumentCodec{}
+		}
+		client.codecs[collectionName] = codec
+	}
+}
+
+// encodedDocument returns data in the stored form of collectionName, data is returned as is when the collection has
+// no codec.
+func (connectionDetails *Client) encodedDocument(collectionName string, data interface{}) (interface{}, error) {
+	codec := connectionDetails.codecs[collectionName]
+	if codec == nil {
+		return data, nil
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(doc)
+}
+
+// encodedDocuments is encodedDocument for the documents of AddMany.
+func (connectionDetails *Client) encodedDocuments(collectionName string, data []interface{}) ([]interface{}, error) {
+	if connectionDetails.codecs[collectionName] == nil {
+		return data, nil
+	}
+	docs := make([]interface{}, len(data))
+	for i, item := range data {
+		doc, err := connectionDetails.encodedDocument(collectionName, item)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+	return docs, nil
+}
+
+// decodedRaw returns raw, a document of collectionName, in its model form. raw is returned as is when the collection
+// has no codec.
+func (connectionDetails *Client) decodedRaw(collectionName string, raw bson.Raw) (bson.Raw, error) {
+	codec := connectionDetails.codecs[collectionName]
+	if codec == nil {
+		return raw, nil
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	decoded, err := codec.Decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return bson.Marshal(decoded)
+}
+
+// decodedResult returns result, read from collectionName, holding the model form of its document. Its error is
+// returned by the Err and Decode of the returned result.
+func (connectionDetails *Client) decodedResult(collectionName string, result *mongo.SingleResult) *mongo.SingleResult {
+	if connectionDetails.codecs[collectionName] == nil {
+		return result
+	}
+	raw, err := result.Raw()
+	if err == nil {
+		raw, err = connectionDetails.decodedRaw(collectionName, raw)
+	}
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return mongo.NewSingleResultFromDocument(raw, nil, nil)
+}
+
+// decodeAll decodes the documents of cursor, read from collectionName, into result, a pointer to a slice, from their
+// model form.
+func (connectionDetails *Client) decodeAll(ctx context.Context, collectionName string, cursor *mongo.Cursor, result interface{}) error {
+	if connectionDetails.codecs[collectionName] == nil {
+		return cursor.All(ctx, result)
+	}
+	var raws []bson.Raw
+	if err := cursor.All(ctx, &raws); err != nil {
+		return err
+	}
+	docs := make([]bson.D, len(raws))
+	for i, raw := range raws {
+		decoded, err := connectionDetails.decodedRaw(collectionName, raw)
+		if err != nil {
+			return err
+		}
+		if err := bson.Unmarshal(decoded, &docs[i]); err != nil {
+			return err
+		}
+	}
+	return decodeDocuments(docs, result)
+}
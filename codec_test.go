@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// envelopeCodec stores the fields of a document, but its "_id", in a "payload" field.
+type envelopeCodec struct{}
+
+func (envelopeCodec) Encode(doc bson.D) (bson.D, error) {
+	id, _ := lookupField(doc, "_id")
+	payload, err := bson.Marshal(withoutField(doc, "_id"))
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{Key: "_id", Value: id}, {Key: "schema", Value: 1}, {Key: "payload", Value: payload}}, nil
+}
+
+func (envelopeCodec) Decode(doc bson.D) (bson.D, error) {
+	payload, ok := lookupField(doc, "payload")
+	binary, isBinary := payload.(primitive.Binary)
+	if !ok || !isBinary {
+		return nil, errors.New("missing payload")
+	}
+	var decoded bson.D
+	if err := bson.Unmarshal(binary.Data, &decoded); err != nil {
+		return nil, err
+	}
+	id, _ := lookupField(doc, "_id")
+	return append(bson.D{{Key: "_id", Value: id}}, decoded...), nil
+}
+
+func TestCodec(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test", WithCodec("events", envelopeCodec{}))
+
+	encoded, err := client.encodedDocument("events", data{ID: "1", Name: "Akshay"})
+	if err != nil {
+		t.Fatalf("encodedDocument() error = %v", err)
+	}
+	if doc := encoded.(bson.D); len(doc) != 3 || doc[1].Key != "schema" {
+		t.Errorf("Expected an envelope, got %v", doc)
+	}
+	if doc, _ := client.encodedDocument("users", data{ID: "1"}); doc != (data{ID: "1"}) {
+		t.Errorf("Expected a collection without codec to be left as is, got %v", doc)
+	}
+
+	raw, err := bson.Marshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result data
+	if err := client.decodedResult("events", mongo.NewSingleResultFromDocument(raw, nil, nil)).Decode(&result); err != nil ||
+		result != (data{ID: "1", Name: "Akshay"}) {
+		t.Errorf("decodedResult() = %+v, %v", result, err)
+	}
+	missing := client.decodedResult("events", mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil))
+	if !errors.Is(missing.Err(), mongo.ErrNoDocuments) {
+		t.Errorf("Expected mongo.ErrNoDocuments, got %v", missing.Err())
+	}
+
+	cursor, err := mongo.NewCursorFromDocuments([]interface{}{raw, raw}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []data
+	if err := client.decodeAll(context.Background(), "events", cursor, &results); err != nil || len(results) != 2 ||
+		results[1].Name != "Akshay" {
+		t.Errorf("decodeAll() = %+v, %v", results, err)
+	}
+}
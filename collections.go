@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionOptions configures CreateCollection.
+type CollectionOptions struct {
+	// Capped creates a fixed size collection, MaxSize is required with it
+	Capped bool
+
+	// MaxSize is the maximum size of a capped collection in bytes
+	MaxSize int64
+
+	// MaxDocuments is the maximum number of documents of a capped collection, 0 means no limit
+	MaxDocuments int64
+
+	// Collation is the default collation of the collection
+	Collation *Collation
+
+	// Validator is a query or $jsonSchema documents must match, see GenerateSchema
+	Validator interface{}
+
+	// TimeSeries creates a time series collection
+	TimeSeries *TimeSeriesOptions
+
+	// ExpireAfter removes the documents of a time series collection once their time is older
+	ExpireAfter time.Duration
+}
+
+// TimeSeriesOptions configures a time series collection.
+type TimeSeriesOptions struct {
+	// TimeField holds the date of each measurement
+	TimeField string
+
+	// MetaField holds the metadata identifying the series, it is optional
+	MetaField string
+
+	// Granularity is "seconds", "minutes" or "hours", it defaults to "seconds"
+	Granularity string
+}
+
+// createOptions converts the options to the driver options.
+func (collectionOptions CollectionOptions) createOptions() *options.CreateCollectionOptions {
+	opts := options.CreateCollection()
+	if collectionOptions.Capped {
+		opts.SetCapped(true).SetSizeInBytes(collectionOptions.MaxSize)
+		if collectionOptions.MaxDocuments > 0 {
+			opts.SetMaxDocuments(collectionOptions.MaxDocuments)
+		}
+	}
+	if collectionOptions.Collation != nil {
+		opts.SetCollation(collectionOptions.Collation.driverCollation())
+	}
+	if collectionOptions.Validator != nil {
+		opts.SetValidator(collectionOptions.Validator)
+	}
+	if timeSeries := collectionOptions.TimeSeries; timeSeries != nil {
+		timeSeriesOpts := options.TimeSeries().SetTimeField(timeSeries.TimeField)
+		if timeSeries.MetaField != "" {
+			timeSeriesOpts.SetMetaField(timeSeries.MetaField)
+		}
+		if timeSeries.Granularity != "" {
+			timeSeriesOpts.SetGranularity(timeSeries.Granularity)
+		}
+		opts.SetTimeSeriesOptions(timeSeriesOpts)
+	}
+	if collectionOptions.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int64(collectionOptions.ExpireAfter / time.Second))
+	}
+	return opts
+}
+
+// CreateCollection creates collectionName, it fails when the collection already exists.
+func (connectionDetails *Client) CreateCollection(collectionName string, collectionOptions CollectionOptions, opts ...Option) error {
+	op := operation{name: "CreateCollection", collection: collectionName, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.CreateCollection(ctx, collectionName, collectionOptions.createOptions())
+	})
+}
+
+// DropCollection drops collectionName, a collection that does not exist is ignored.
+func (connectionDetails *Client) DropCollection(collectionName string, opts ...Option) error {
+	op := operation{name: "DropCollection", collection: collectionName, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.Collection(collectionName).Drop(ctx)
+	})
+}
+
+// RenameCollection renames collectionName to newName within the Client database, it fails when newName already
+// exists.
+func (connectionDetails *Client) RenameCollection(collectionName string, newName string, opts ...Option) error {
+	op := operation{name: "RenameCollection", collection: collectionName, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		command := bson.D{
+			{Key: "renameCollection", Value: db.Name() + "." + collectionName},
+			{Key: "to", Value: db.Name() + "." + newName},
+		}
+		return db.Client().Database("admin").RunCommand(ctx, command).Err()
+	})
+}
+
+// ListCollections returns the collections and views of the Client database.
+func (connectionDetails *Client) ListCollections(opts ...Option) ([]*mongo.CollectionSpecification, error) {
+	op := operation{name: "ListCollections", options: newOperationOptions(opts)}
+	var specifications []*mongo.CollectionSpecification
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		specifications, err = db.ListCollectionSpecifications(ctx, bson.D{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return specifications, nil
+}
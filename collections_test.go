@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCollectionOptions_createOptions(t *testing.T) {
+	capped := CollectionOptions{Capped: true, MaxSize: 1 << 20, MaxDocuments: 100}.createOptions()
+	if !*capped.Capped || *capped.SizeInBytes != 1<<20 || *capped.MaxDocuments != 100 || capped.TimeSeriesOptions != nil {
+		t.Errorf("Unexpected capped options %+v", capped)
+	}
+
+	timeSeries := CollectionOptions{
+		TimeSeries:  &TimeSeriesOptions{TimeField: "at", MetaField: "sensor", Granularity: "minutes"},
+		ExpireAfter: 24 * time.Hour,
+	}.createOptions()
+	series := timeSeries.TimeSeriesOptions
+	if series.TimeField != "at" || *series.MetaField != "sensor" || *series.Granularity != "minutes" || *timeSeries.ExpireAfterSeconds != 86400 {
+		t.Errorf("Unexpected time series options %+v", series)
+	}
+	if timeSeries.Capped != nil {
+		t.Errorf("Expected capped to be unset")
+	}
+
+	collated := CollectionOptions{Collation: &Collation{Locale: "fr", Backwards: true}}.createOptions()
+	if collated.Collation == nil || collated.Collation.Locale != "fr" || !collated.Collation.Backwards {
+		t.Errorf("Unexpected collation %+v", collated.Collation)
+	}
+}
+
+func TestClient_CollectionLifecycle(t *testing.T) {
+	_ = client.DropCollection("lifecycle_collection")
+	_ = client.DropCollection("lifecycle_renamed")
+
+	if err := client.CreateCollection("lifecycle_collection", CollectionOptions{Validator: bson.M{"name": bson.M{"$type": "string"}}}); err != nil {
+		t.Fatalf("Unable to create collection. %s", err)
+	}
+	if err := client.RenameCollection("lifecycle_collection", "lifecycle_renamed"); err != nil {
+		t.Fatalf("Unable to rename collection. %s", err)
+	}
+
+	specifications, err := client.ListCollections()
+	if err != nil {
+		t.Fatalf("Unable to list collections. %s", err)
+	}
+	found := false
+	for _, specification := range specifications {
+		if specification.Name == "lifecycle_collection" {
+			t.Errorf("Expected lifecycle_collection to be renamed")
+		}
+		found = found || specification.Name == "lifecycle_renamed"
+	}
+	if !found {
+		t.Errorf("Expected lifecycle_renamed to be listed")
+	}
+
+	if err := client.DropCollection("lifecycle_renamed"); err != nil {
+		t.Errorf("Unable to drop collection. %s", err)
+	}
+}
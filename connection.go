@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// connection holds the connection pool opened by Client.Connect, it is shared by the Client and the views returned by
+// WithDatabase and WithContext.
+type connection struct {
+	mu     sync.RWMutex
+	client *mongo.Client
+}
+
+// Connect opens a connection pool that is kept for every following operation until Disconnect is called. Without
+// Connect every operation connects and disconnects on its own.
+//
+// Calling Connect on a connected Client does nothing.
+func (connectionDetails *Client) Connect() error {
+	if connectionDetails.conn == nil {
+		connectionDetails.conn = &connection{}
+	}
+	conn := connectionDetails.conn
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.client != nil {
+		return nil
+	}
+	client, err := connectionDetails.client()
+	if err != nil {
+		return connectionDetails.wrapError(operation{name: "Connect"}, err)
+	}
+	conn.client = client
+	return nil
+}
+
+// Disconnect closes the connection pool opened by Connect.
+func (connectionDetails *Client) Disconnect() error {
+	conn := connectionDetails.conn
+	if conn == nil {
+		return nil
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.client == nil {
+		return nil
+	}
+	err := conn.client.Disconnect(connectionDetails.Context)
+	conn.client = nil
+	if err != nil {
+		return connectionDetails.wrapError(operation{name: "Disconnect"}, err)
+	}
+	return nil
+}
+
+// WithDatabase returns a view of the Client bound to databaseName. The view shares the connection pool opened by
+// Connect, as well as every option of the Client.
+func (connectionDetails *Client) WithDatabase(databaseName string) *Client {
+	client := *connectionDetails
+	client.DatabaseName = databaseName
+	return &client
+}
+
+// acquire returns the connection pool opened by Connect, or a new connection. release must be called once the
+// operation is done, it disconnects new connections.
+func (connectionDetails *Client) acquire() (client *mongo.Client, release func(), err error) {
+	if conn := connectionDetails.conn; conn != nil {
+		conn.mu.RLock()
+		client = conn.client
+		conn.mu.RUnlock()
+		if client != nil {
+			return client, func() {}, nil
+		}
+	}
+
+	client, err = connectionDetails.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, func() {
+		err := client.Disconnect(context.Background())
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to disconnect", "database", connectionDetails.DatabaseName, "error", err)
+		}
+	}, nil
+}
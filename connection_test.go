@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"testing"
+)
+
+func TestClient_WithDatabase(t *testing.T) {
+	pooled := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	other := pooled.WithDatabase("other")
+	if other.DatabaseName != "other" || pooled.DatabaseName != "test" {
+		t.Errorf("Unexpected database names %s and %s", pooled.DatabaseName, other.DatabaseName)
+	}
+
+	if err := pooled.Connect(); err != nil {
+		t.Fatalf("Unable to connect. %s", err)
+	}
+	defer pooled.Disconnect()
+
+	parentClient, release, err := pooled.acquire()
+	if err != nil {
+		t.Fatalf("Unable to acquire a connection. %s", err)
+	}
+	release()
+	viewClient, release, err := other.acquire()
+	if err != nil {
+		t.Fatalf("Unable to acquire a connection. %s", err)
+	}
+	release()
+	if parentClient != viewClient {
+		t.Errorf("Expected the view to share the connection pool")
+	}
+}
+
+func TestClient_Disconnect(t *testing.T) {
+	pooled := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	if err := pooled.Disconnect(); err != nil {
+		t.Errorf("Expected Disconnect without Connect to do nothing, got %s", err)
+	}
+	if err := pooled.Connect(); err != nil {
+		t.Fatalf("Unable to connect. %s", err)
+	}
+	if err := pooled.Disconnect(); err != nil {
+		t.Errorf("Unable to disconnect. %s", err)
+	}
+	if pooled.conn.client != nil {
+		t.Errorf("Expected the connection pool to be released")
+	}
+}
@@ -0,0 +1,178 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CopyOptions configures Client.CopyCollection.
+type CopyOptions struct {
+	// Filter selects the documents to copy - bson.M{}, bson.D{} or Filter, defaults to every document
+	Filter interface{}
+
+	// Transform changes a document before it is written, a nil document is skipped
+	Transform func(doc bson.D) (bson.D, error)
+
+	// BatchSize is the number of documents written per batch, defaults to 1000
+	BatchSize int
+
+	// SkipIndexes does not recreate the indexes of the source collection on the destination
+	SkipIndexes bool
+}
+
+// CopyReport describes the outcome of Client.CopyCollection.
+type CopyReport struct {
+	// Copied is the number of documents written to the destination
+	Copied int64
+
+	// Skipped is the number of documents Transform returned nil for
+	Skipped int64
+
+	// Errors are the documents that could not be written
+	Errors []CopyError
+
+	// Indexes are the names of the indexes created on the destination
+	Indexes []string
+}
+
+// CopyError is a document that could not be copied.
+type CopyError struct {
+	ID  interface{}
+	Err error
+}
+
+func (e CopyError) Error() string {
+	return fmt.Sprintf("document %v: %s", e.ID, e.Err)
+}
+
+func (e CopyError) Unwrap() error {
+	return e.Err
+}
+
+// CopyCollection streams the documents of collectionName matching the filter of opts into the collection dstCollection
+// of dst, which can be the Client itself, a view of another database or a Client of another cluster. The indexes of
+// the source collection are then created on the destination, with all their options.
+//
+// Documents are written by "_id" with upserts, so running the copy again refreshes the destination instead of failing
+// on duplicate keys. A document that cannot be written is listed in the report and does not stop the copy.
+func (connectionDetails *Client) CopyCollection(collectionName string, dst *Client, dstCollection string, opts CopyOptions) (*CopyReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	filter := opts.Filter
+	if filter == nil {
+		filter = bson.D{}
+	}
+	op := operation{name: "CopyCollection", collection: collectionName, filter: filter}
+	dstOp := operation{name: "CopyCollection", collection: dstCollection}
+	report := &CopyReport{}
+
+	var indexes []bson.D
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		*report = CopyReport{}
+		source := db.Collection(collectionName)
+		cursor, err := source.Find(ctx, filter, options.Find().SetBatchSize(int32(opts.BatchSize)))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		var batch []bson.D
+		for cursor.Next(ctx) {
+			var doc bson.D
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			if opts.Transform != nil {
+				if doc, err = opts.Transform(doc); err != nil {
+					return err
+				}
+				if doc == nil {
+					report.Skipped++
+					continue
+				}
+			}
+			batch = append(batch, doc)
+			if len(batch) == opts.BatchSize {
+				if err := dst.copyBatch(dstOp, batch, report); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			if err := dst.copyBatch(dstOp, batch, report); err != nil {
+				return err
+			}
+		}
+
+		if opts.SkipIndexes {
+			return nil
+		}
+		specs, err := source.Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		return specs.All(ctx, &indexes)
+	})
+	if err != nil {
+		return report, err
+	}
+
+	specs, names := indexSpecs(indexes)
+	if len(specs) == 0 {
+		return report, nil
+	}
+	err = dst.run(dstOp, func(ctx context.Context, db *mongo.Database) error {
+		command := bson.D{{Key: "createIndexes", Value: dstCollection}, {Key: "indexes", Value: specs}}
+		return db.RunCommand(ctx, command).Err()
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Indexes = names
+	return report, nil
+}
+
+// indexSpecs returns the specifications of indexes that can be sent to createIndexes, and their names. The "_id"
+// index is left out.
+func indexSpecs(indexes []bson.D) (bson.A, []string) {
+	var specs bson.A
+	var names []string
+	for _, index := range indexes {
+		name, _ := lookupField(index, "name")
+		if name == "_id_" {
+			continue
+		}
+		spec := bson.D{}
+		for _, elem := range index {
+			if elem.Key != "v" && elem.Key != "ns" {
+				spec = append(spec, elem)
+			}
+		}
+		specs = append(specs, spec)
+		names = append(names, fmt.Sprint(name))
+	}
+	return specs, names
+}
+
+// copyBatch upserts batch by "_id", counting the written documents and the failed ones in report.
+func (connectionDetails *Client) copyBatch(op operation, batch []bson.D, report *CopyReport) error {
+	result, writeErrs, err := connectionDetails.writeBatch(op, batch, true)
+	if result != nil {
+		report.Copied += result.InsertedCount + result.UpsertedCount + result.MatchedCount
+	}
+	for _, writeErr := range writeErrs {
+		id, _ := lookupField(batch[writeErr.Index], "_id")
+		report.Errors = append(report.Errors, CopyError{ID: id, Err: errors.New(writeErr.Message)})
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndexSpecs(t *testing.T) {
+	indexes := []bson.D{
+		{{Key: "v", Value: 2}, {Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "name", Value: "_id_"}},
+		{{Key: "v", Value: 2}, {Key: "key", Value: bson.D{{Key: "email", Value: 1}}}, {Key: "name", Value: "email_1"}, {Key: "unique", Value: true}},
+	}
+	specs, names := indexSpecs(indexes)
+	if len(specs) != 1 || len(names) != 1 || names[0] != "email_1" {
+		t.Fatalf("indexSpecs() = %v, %v", specs, names)
+	}
+	spec := specs[0].(bson.D)
+	if _, ok := lookupField(spec, "v"); ok {
+		t.Errorf("Expected the index version to be left out, got %v", spec)
+	}
+	if unique, _ := lookupField(spec, "unique"); unique != true {
+		t.Errorf("Expected the index options to be kept, got %v", spec)
+	}
+}
+
+func TestClient_CopyCollection(t *testing.T) {
+	_ = client.DropCollection("copy_source")
+	target := client.WithDatabase(client.DatabaseName + "_copy")
+	_ = target.DropCollection("copy_target")
+	if _, err := client.AddMany("copy_source", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := client.CreateIndex("copy_source", Index{Keys: bson.D{{Key: "name", Value: 1}}}); err != nil {
+		t.Fatalf("Unable to create index. %s", err)
+	}
+
+	report, err := client.CopyCollection("copy_source", target, "copy_target", CopyOptions{
+		Transform: func(doc bson.D) (bson.D, error) {
+			if id, _ := lookupField(doc, "_id"); id == "2" {
+				return nil, nil
+			}
+			return doc, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unable to copy. %s", err)
+	}
+	if report.Copied != 1 || report.Skipped != 1 || len(report.Indexes) != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,234 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCountCollection holds the counter documents of maintained counts.
+const DefaultCountCollection = "maintained_counts"
+
+// ErrUnknownCount is returned by FastCount and RefreshCount for a count that was not configured with
+// WithMaintainedCounts.
+var ErrUnknownCount = errors.New("mongo: unknown maintained count")
+
+// readOperations are the operations that do not change the documents of their collection, any other operation can
+// change the documents matching a maintained count in ways the Client cannot follow and marks the counts of its
+// collection as stale, see staleWrite.
+var readOperations = map[string]bool{
+	"Get": true, "GetCustom": true, "GetAll": true, "GetAllCustom": true, "Aggregate": true, "FindIter": true,
+	"ForEachBatch": true, "ScanParallel": true, "TailCollection": true, "Prime": true, "RunNamed": true,
+	"FindFuzzy": true, "SearchText": true, "Suggest": true, "VectorSearch": true, "FindNear": true,
+	"FindWithinPolygon": true, "Export": true, "Backup": true, "DiffCollections": true, "CompareSchema": true,
+	"Histogram": true, "Percentiles": true, "RollupByTime": true, "Measurements": true, "Downsample": true,
+	"TopK": true, "Leaderboard": true, "FastCount": true, "RefreshCount": true, "CatalogValues": true,
+	"RefreshCatalog": true, "History": true, "AccessReport": true, "IncompleteIntents": true, "CacheGet": true,
+	"KVGet": true, "KVKeys": true, "ExplainFind": true, "ExplainAggregate": true, "PlanCache": true,
+	"ClearPlanCache": true, "ListCollections": true, "CreateCollection": true, "CollectionValidator": true,
+	"SetCollectionValidator": true, "ApplyCollectionSchema": true, "CreateIndex": true,
+	"CreateIndexWithProgress": true, "CreateTextIndex": true, "CreateGeoIndex": true, "CreateVectorIndex": true,
+	"EnsureAutocomplete": true, "Prepare": true, "HealthCheck": true, "SelfCheck": true, "Connect": true,
+	"Disconnect": true,
+}
+
+// staleWrite reports whether the operation name marks the maintained counts of its collection as stale. Add and
+// AddMany are followed document by document instead.
+func staleWrite(name string) bool {
+	return name != "Add" && name != "AddMany" && !readOperations[name]
+}
+
+// MaintainedCount is the number of documents of Collection matching Filter, kept in a counter document so FastCount
+// does not have to count the documents on every call.
+type MaintainedCount struct {
+	// Name of the count, it is the ID of its counter document
+	Name string
+
+	// Collection whose documents are counted
+	Collection string
+
+	// Filter the counted documents match - bson.M{}, bson.D{} or Filter, nil counts every document
+	Filter interface{}
+}
+
+// WithMaintainedCounts keeps a counter document for each of counts in DefaultCountCollection.
+//
+// Documents added with Add and AddMany increment the counters directly, any other write to the collection marks its
+// counters as stale and the next FastCount counts the documents again. Writes that are not made through the Client
+// are not seen, use RefreshCount after them.
+func WithMaintainedCounts(counts ...MaintainedCount) ClientOption {
+	return func(client *Client) {
+		client.maintainedCounts = append(client.maintainedCounts, counts...)
+	}
+}
+
+// FastCount returns the maintained count called name, the documents are only counted when the counter is missing or
+// stale.
+func (connectionDetails *Client) FastCount(name string, opts ...Option) (int64, error) {
+	return connectionDetails.fastCount("FastCount", name, false, opts)
+}
+
+// RefreshCount counts the documents of the maintained count called name again and returns it.
+func (connectionDetails *Client) RefreshCount(name string, opts ...Option) (int64, error) {
+	return connectionDetails.fastCount("RefreshCount", name, true, opts)
+}
+
+func (connectionDetails *Client) fastCount(name string, countName string, refresh bool, opts []Option) (int64, error) {
+	count, ok := connectionDetails.maintainedCount(countName)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCount, countName)
+	}
+	op := operation{name: name, collection: count.Collection, filter: count.Filter, options: newOperationOptions(opts)}
+	var total int64
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		counters := db.Collection(DefaultCountCollection)
+		counter, err := readCounter(ctx, counters, count.Name)
+		if err != nil {
+			return err
+		}
+		if !refresh && counter.Count != nil && !counter.Stale {
+			total = *counter.Count
+			return nil
+		}
+
+		filter := count.Filter
+		if filter == nil {
+			filter = bson.D{}
+		}
+		counted, err := db.Collection(count.Collection).CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+		total = counted
+		return storeCount(ctx, counters, count, counted, counter.Generation)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// counter is the counter document of a maintained count. Generation is incremented by every update of
+// maintainCounts, it is missing until the first one.
+type counter struct {
+	Count      *int64 `bson:"count"`
+	Stale      bool   `bson:"stale"`
+	Generation int64  `bson:"generation"`
+}
+
+// readCounter reads the counter called name, a missing counter is returned empty.
+func readCounter(ctx context.Context, counters *mongo.Collection, name string) (counter, error) {
+	var c counter
+	err := counters.FindOne(ctx, bson.M{"_id": name}).Decode(&c)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return counter{}, err
+	}
+	return c, nil
+}
+
+// storeCount writes counted to the counter of count when it is still at generation, the generation read before
+// counting. A counter updated by maintainCounts while counting is marked as stale instead, so an increment is not
+// overwritten by a count that may not include it and the next FastCount counts the documents again.
+func storeCount(ctx context.Context, counters *mongo.Collection, count MaintainedCount, counted int64, generation int64) error {
+	filter := bson.M{"_id": count.Name, "generation": generation}
+	if generation == 0 {
+		filter["generation"] = bson.M{"$exists": false}
+	}
+	_, err := counters.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"collection": count.Collection,
+		"count":      counted,
+		"stale":      false,
+		"updatedAt":  time.Now(),
+	}}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// the counter moved to another generation while counting, the upsert tried to insert it again
+		update := staleUpdate()
+		update["$inc"] = bson.M{"generation": 1}
+		_, err = counters.UpdateOne(ctx, bson.M{"_id": count.Name}, update)
+	}
+	return err
+}
+
+// maintainedCount returns the maintained count called name.
+func (connectionDetails *Client) maintainedCount(name string) (MaintainedCount, bool) {
+	for _, count := range connectionDetails.maintainedCounts {
+		if count.Name == name {
+			return count, true
+		}
+	}
+	return MaintainedCount{}, false
+}
+
+// maintainCounts adjusts the counters of the collection written by op, opErr is the error op returned.
+func (connectionDetails *Client) maintainCounts(ctx context.Context, op operation, opErr error) {
+	for _, count := range connectionDetails.maintainedCounts {
+		if count.Collection != op.collection {
+			continue
+		}
+		var update bson.M
+		switch {
+		case op.name == "Add" || op.name == "AddMany":
+			if opErr != nil {
+				if op.name == "AddMany" {
+					update = staleUpdate()
+				}
+				break
+			}
+			added, ok := countMatching(op.documents, count.Filter)
+			if !ok {
+				update = staleUpdate()
+			} else if added > 0 {
+				update = bson.M{"$inc": bson.M{"count": added, "generation": 1}, "$set": bson.M{"updatedAt": time.Now()}}
+			}
+		case staleWrite(op.name):
+			update = staleUpdate()
+		}
+		if update == nil {
+			continue
+		}
+		upsert := update["$inc"] == nil
+		if upsert {
+			// marking the counter as stale moves it to a new generation too, see storeCount
+			update["$inc"] = bson.M{"generation": 1}
+		}
+
+		err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+			// $inc only applies to an existing counter, a missing counter is computed by the next FastCount
+			_, err := db.Collection(DefaultCountCollection).UpdateOne(ctx, bson.M{"_id": count.Name}, update,
+				options.Update().SetUpsert(upsert))
+			return err
+		})
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to maintain count", "count", count.Name, "operation", op.name, "error", err)
+		}
+	}
+}
+
+// staleUpdate is the update marking a counter as stale.
+func staleUpdate() bson.M {
+	return bson.M{"$set": bson.M{"stale": true, "updatedAt": time.Now()}}
+}
+
+// countMatching returns how many of documents match filter, it is false when the filter cannot be evaluated locally.
+func countMatching(documents []interface{}, filter interface{}) (int64, bool) {
+	query, err := toDocument(filter)
+	if err != nil || !supportsQuery(query) {
+		return 0, false
+	}
+	var count int64
+	for _, document := range documents {
+		doc, err := toDocument(document)
+		if err != nil {
+			return 0, false
+		}
+		if matches(doc, query) {
+			count++
+		}
+	}
+	return count, true
+}
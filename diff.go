@@ -0,0 +1,168 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DiffKind tells how a document differs between two collections, see DiffCollections.
+type DiffKind int
+
+const (
+	// DiffMissing is a document of the first collection without a match in the second one
+	DiffMissing DiffKind = iota + 1
+
+	// DiffExtra is a document of the second collection without a match in the first one
+	DiffExtra
+
+	// DiffChanged is a document of both collections with different fields
+	DiffChanged
+)
+
+// DocumentDiff is a document that differs between two collections.
+type DocumentDiff struct {
+	Kind DiffKind
+
+	// Key is the value of the key field of the document
+	Key interface{}
+
+	// A is the document of the first collection, nil for DiffExtra
+	A bson.D
+
+	// B is the document of the second collection, nil for DiffMissing
+	B bson.D
+}
+
+// DiffSummary counts the documents compared by DiffCollections.
+type DiffSummary struct {
+	// Matched is the number of keys found in both collections, changed or not
+	Matched int64
+	Missing int64
+	Extra   int64
+	Changed int64
+}
+
+// Equal reports whether the collections hold the same documents.
+func (summary *DiffSummary) Equal() bool {
+	return summary.Missing == 0 && summary.Extra == 0 && summary.Changed == 0
+}
+
+// DiffCollections compares the documents of collectionA and collectionB matched by keyField, usually "_id", and calls
+// fn with every document that is missing from collectionB, extra in collectionB, or changed. fn can be nil to only
+// count them, an error returned by fn stops the comparison.
+//
+// Both collections are streamed sorted by keyField, only one document of each is held in memory at a time, so
+// keyField should be indexed on both sides. Documents are equal when they have the same fields in the same order, with
+// numbers of different types being equal when their values are.
+func (connectionDetails *Client) DiffCollections(collectionA string, collectionB string, keyField string, fn func(DocumentDiff) error, opts ...Option) (*DiffSummary, error) {
+	return connectionDetails.DiffCollectionsWith(connectionDetails, collectionA, collectionB, keyField, fn, opts...)
+}
+
+// DiffCollectionsWith is DiffCollections with collectionB read through other, which can be connected to another
+// deployment or database, like the target of a migration.
+func (connectionDetails *Client) DiffCollectionsWith(other *Client, collectionA string, collectionB string, keyField string, fn func(DocumentDiff) error, opts ...Option) (*DiffSummary, error) {
+	op := operation{name: "DiffCollections", collection: collectionA, options: newOperationOptions(opts)}
+	summary := &DiffSummary{}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		*summary = DiffSummary{}
+		find := op.options.find().SetSort(bson.D{{Key: keyField, Value: 1}}).SetAllowDiskUse(true)
+		a, err := db.Collection(collectionA).Find(ctx, bson.D{}, find)
+		if err != nil {
+			return err
+		}
+		defer a.Close(ctx)
+		return other.exec(ctx, func(ctx context.Context, otherDB *mongo.Database) error {
+			b, err := otherDB.Collection(collectionB).Find(ctx, bson.D{}, find)
+			if err != nil {
+				return err
+			}
+			defer b.Close(ctx)
+			return diffCursors(ctx, a, b, keyField, summary, fn)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// diffSide is the current document of a cursor sorted by the key field.
+type diffSide struct {
+	cursor *mongo.Cursor
+	path   []string
+	doc    bson.D
+	key    interface{}
+	done   bool
+}
+
+// next reads the following document.
+func (side *diffSide) next(ctx context.Context) error {
+	if !side.cursor.Next(ctx) {
+		side.done, side.doc, side.key = true, nil, nil
+		return side.cursor.Err()
+	}
+	side.doc = nil
+	if err := side.cursor.Decode(&side.doc); err != nil {
+		return err
+	}
+	side.key = firstValue(lookupPath(side.doc, side.path))
+	return nil
+}
+
+// diffCursors merges two cursors sorted by keyField, counting the differences in summary and reporting them to fn.
+func diffCursors(ctx context.Context, a *mongo.Cursor, b *mongo.Cursor, keyField string, summary *DiffSummary, fn func(DocumentDiff) error) error {
+	path := strings.Split(keyField, ".")
+	sideA, sideB := &diffSide{cursor: a, path: path}, &diffSide{cursor: b, path: path}
+	if err := sideA.next(ctx); err != nil {
+		return err
+	}
+	if err := sideB.next(ctx); err != nil {
+		return err
+	}
+
+	for !sideA.done || !sideB.done {
+		var order int
+		switch {
+		case sideA.done:
+			order = 1
+		case sideB.done:
+			order = -1
+		default:
+			order = compareValues(sideA.key, sideB.key)
+		}
+
+		var diff *DocumentDiff
+		var err error
+		switch {
+		case order < 0:
+			summary.Missing++
+			diff = &DocumentDiff{Kind: DiffMissing, Key: sideA.key, A: sideA.doc}
+			err = sideA.next(ctx)
+		case order > 0:
+			summary.Extra++
+			diff = &DocumentDiff{Kind: DiffExtra, Key: sideB.key, B: sideB.doc}
+			err = sideB.next(ctx)
+		default:
+			summary.Matched++
+			if !equalValues(sideA.doc, sideB.doc) {
+				summary.Changed++
+				diff = &DocumentDiff{Kind: DiffChanged, Key: sideA.key, A: sideA.doc, B: sideB.doc}
+			}
+			if err = sideA.next(ctx); err == nil {
+				err = sideB.next(ctx)
+			}
+		}
+		if diff != nil && fn != nil {
+			if fnErr := fn(*diff); fnErr != nil {
+				return fnErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
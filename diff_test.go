@@ -0,0 +1,77 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDiffCursors(t *testing.T) {
+	cursor := func(docs ...bson.D) *mongo.Cursor {
+		documents := make([]interface{}, len(docs))
+		for i, doc := range docs {
+			documents[i] = doc
+		}
+		c, err := mongo.NewCursorFromDocuments(documents, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	a := cursor(
+		bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: "a"}},
+		bson.D{{Key: "_id", Value: 2}, {Key: "name", Value: "b"}},
+		bson.D{{Key: "_id", Value: 4}, {Key: "name", Value: "d"}},
+	)
+	b := cursor(
+		bson.D{{Key: "_id", Value: int64(1)}, {Key: "name", Value: "a"}},
+		bson.D{{Key: "_id", Value: 3}, {Key: "name", Value: "c"}},
+		bson.D{{Key: "_id", Value: 4}, {Key: "name", Value: "D"}},
+		bson.D{{Key: "_id", Value: 5}, {Key: "name", Value: "e"}},
+	)
+
+	summary := &DiffSummary{}
+	var kinds []DiffKind
+	var keys []interface{}
+	err := diffCursors(context.Background(), a, b, "_id", summary, func(diff DocumentDiff) error {
+		kinds = append(kinds, diff.Kind)
+		keys = append(keys, diff.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("diffCursors() error = %v", err)
+	}
+
+	want := DiffSummary{Matched: 2, Missing: 1, Extra: 2, Changed: 1}
+	if *summary != want {
+		t.Errorf("summary = %+v, want %+v", *summary, want)
+	}
+	wantKinds := []DiffKind{DiffMissing, DiffExtra, DiffChanged, DiffExtra}
+	wantKeys := []interface{}{int32(2), int32(3), int32(4), int32(5)}
+	for i := range wantKinds {
+		if i >= len(kinds) || kinds[i] != wantKinds[i] || !equalValues(keys[i], wantKeys[i]) {
+			t.Fatalf("diffs = %v %v, want %v %v", kinds, keys, wantKinds, wantKeys)
+		}
+	}
+	if summary.Equal() {
+		t.Errorf("Expected the collections to differ")
+	}
+}
+
+func TestClient_DiffCollections(t *testing.T) {
+	for _, coll := range []string{"diff_a", "diff_b"} {
+		_ = client.DropCollection(coll)
+	}
+	_, _ = client.AddMany("diff_a", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}})
+	_, _ = client.AddMany("diff_b", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "3", Name: "Gollahalli"}})
+
+	summary, err := client.DiffCollections("diff_a", "diff_b", "_id", nil)
+	if err != nil {
+		t.Fatalf("Unable to diff collections. %s", err)
+	}
+	if summary.Matched != 1 || summary.Missing != 1 || summary.Extra != 1 || summary.Changed != 0 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+}
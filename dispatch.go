@@ -0,0 +1,97 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+)
+
+// dispatcher limits the number of concurrent operations, waiting operations are admitted by priority then in order
+// of arrival.
+type dispatcher struct {
+	mu     sync.Mutex
+	limit  int
+	active int
+
+	// waiting are the operations waiting for a slot by priority, from PriorityLow to PriorityHigh
+	waiting [3][]chan struct{}
+}
+
+// WithConcurrencyLimit limits the operations of the Client running at the same time to limit. Operations over the
+// limit wait for a running one to finish, the highest priority first, so background work does not starve
+// interactive traffic:
+//
+//	client := mongo.NewMongoClient(url, "shop", ctx, mongo.WithConcurrencyLimit(50))
+//	backfill := client.WithContext(mongo.WithPriority(ctx, mongo.PriorityLow))
+//
+// Operations of the same priority are admitted in order of arrival. A waiting operation fails when the context of
+// the Client is done. An operation keeps its slot while it is retried.
+func WithConcurrencyLimit(limit int) ClientOption {
+	return func(client *Client) {
+		if limit > 0 {
+			client.dispatcher = &dispatcher{limit: limit}
+		}
+	}
+}
+
+// acquire waits for a slot for an operation with priority, or until ctx is done. release must be called once the
+// operation is done when it returns no error.
+func (d *dispatcher) acquire(ctx context.Context, priority Priority) error {
+	d.mu.Lock()
+	if d.active < d.limit {
+		d.active++
+		d.mu.Unlock()
+		return nil
+	}
+	index := priorityIndex(priority)
+	ready := make(chan struct{})
+	d.waiting[index] = append(d.waiting[index], ready)
+	d.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for i, waiter := range d.waiting[index] {
+			if waiter == ready {
+				d.waiting[index] = append(d.waiting[index][:i], d.waiting[index][i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// the slot was handed over while ctx was done, give it to the next operation
+		d.releaseLocked()
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a finished operation, handing it over to the highest priority waiting operation.
+func (d *dispatcher) release() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.releaseLocked()
+}
+
+// releaseLocked is release with d.mu held.
+func (d *dispatcher) releaseLocked() {
+	for index := len(d.waiting) - 1; index >= 0; index-- {
+		if len(d.waiting[index]) > 0 {
+			ready := d.waiting[index][0]
+			d.waiting[index] = d.waiting[index][1:]
+			close(ready)
+			return
+		}
+	}
+	d.active--
+}
+
+// priorityIndex returns the index of priority in dispatcher.waiting, priorities out of range are clamped.
+func priorityIndex(priority Priority) int {
+	switch {
+	case priority < PriorityLow:
+		priority = PriorityLow
+	case priority > PriorityHigh:
+		priority = PriorityHigh
+	}
+	return int(priority - PriorityLow)
+}
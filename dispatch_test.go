@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDispatcher_priority(t *testing.T) {
+	d := &dispatcher{limit: 1}
+	if err := d.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	order := make(chan Priority, 3)
+	var wg sync.WaitGroup
+	for _, priority := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		wg.Add(1)
+		go func(priority Priority) {
+			defer wg.Done()
+			if err := d.acquire(context.Background(), priority); err == nil {
+				order <- priority
+				d.release()
+			}
+		}(priority)
+	}
+	for {
+		d.mu.Lock()
+		waiting := len(d.waiting[0]) + len(d.waiting[1]) + len(d.waiting[2])
+		d.mu.Unlock()
+		if waiting == 3 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	d.release()
+	got := []Priority{<-order, <-order, <-order}
+	if want := []Priority{PriorityHigh, PriorityNormal, PriorityLow}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected operations admitted by priority %v, got %v", want, got)
+	}
+	wg.Wait()
+	if d.active != 0 {
+		t.Errorf("Expected every slot to be released, got %d active", d.active)
+	}
+}
+
+func TestDispatcher_contextDone(t *testing.T) {
+	d := &dispatcher{limit: 1}
+	_ = d.acquire(context.Background(), PriorityNormal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.acquire(ctx, PriorityHigh); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+	if len(d.waiting[priorityIndex(PriorityHigh)]) != 0 {
+		t.Error("Expected the waiter to be removed")
+	}
+	d.release()
+	if d.active != 0 {
+		t.Errorf("Expected no active operation, got %d", d.active)
+	}
+}
+
+func TestWithConcurrencyLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	limited := NewMongoClient("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", ctx, WithConcurrencyLimit(1))
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		_ = limited.run(operation{name: "Get"}, func(ctx context.Context, db *mongo.Database) error {
+			close(started)
+			<-done
+			return nil
+		})
+	}()
+	<-started
+	err := limited.run(operation{name: "Get"}, func(ctx context.Context, db *mongo.Database) error {
+		return nil
+	})
+	close(done)
+	if !errors.Is(err, ErrClientTimeout) {
+		t.Errorf("Expected the waiting operation to time out, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toDocument converts a bson.M, bson.D or struct to a bson.D, nil converts to an empty document.
+func toDocument(v interface{}) (bson.D, error) {
+	if v == nil {
+		return bson.D{}, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// lookupField returns the value of a top level field.
+func lookupField(doc bson.D, key string) (interface{}, bool) {
+	for _, elem := range doc {
+		if elem.Key == key {
+			return elem.Value, true
+		}
+	}
+	return nil, false
+}
+
+// withID converts data to a bson.D and returns it with its "_id", a new ObjectID is added when data has none.
+func withID(data interface{}) (bson.D, interface{}, error) {
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if id, ok := lookupField(doc, "_id"); ok {
+		return doc, id, nil
+	}
+	id := primitive.NewObjectID()
+	return append(bson.D{{Key: "_id", Value: id}}, doc...), id, nil
+}
+
+// documentWithID returns data as a document, with the "_id" id when it has none.
+func documentWithID(data interface{}, id interface{}) (bson.D, error) {
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := lookupField(doc, "_id"); ok {
+		return doc, nil
+	}
+	return append(bson.D{{Key: "_id", Value: id}}, doc...), nil
+}
+
+// withoutField returns a copy of doc without the top level field key.
+func withoutField(doc bson.D, key string) bson.D {
+	kept := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if elem.Key != key {
+			kept = append(kept, elem)
+		}
+	}
+	return kept
+}
+
+// idKey returns the key of a document "_id", its canonical extended JSON so ids of different types do not
+// collide.
+func idKey(id interface{}) (string, error) {
+	value, err := bson.MarshalExtJSON(bson.D{{Key: "_id", Value: id}}, true, false)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// idEquality returns the value of the "_id" equality condition of query.
+func idEquality(query bson.D) (interface{}, bool) {
+	id, ok := lookupField(query, "_id")
+	if !ok {
+		return nil, false
+	}
+	if operators, isDoc := id.(bson.D); isDoc && len(operators) > 0 && strings.HasPrefix(operators[0].Key, "$") {
+		if len(operators) == 1 && operators[0].Key == "$eq" {
+			return operators[0].Value, true
+		}
+		return nil, false
+	}
+	return id, true
+}
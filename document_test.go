@@ -0,0 +1,49 @@
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWithID(t *testing.T) {
+	doc, id, err := withID(data{ID: "1", Name: "Akshay"})
+	if err != nil || id != "1" || len(doc) != 2 {
+		t.Errorf("Expected existing _id to be kept, got %v, %v", doc, err)
+	}
+
+	doc, id, err = withID(bson.M{"name": "Akshay"})
+	if _, ok := id.(primitive.ObjectID); err != nil || !ok || doc[0].Key != "_id" {
+		t.Errorf("Expected an ObjectID to be generated, got %v, %v", doc, err)
+	}
+}
+
+func TestIdKey(t *testing.T) {
+	id := primitive.NewObjectID()
+	objectIDKey, _ := idKey(id)
+	stringKey, _ := idKey(id.Hex())
+	if objectIDKey == stringKey {
+		t.Errorf("Expected an ObjectID and its hex string to have different keys, got %s", objectIDKey)
+	}
+}
+
+func TestIdEquality(t *testing.T) {
+	tests := []struct {
+		query bson.D
+		want  interface{}
+		ok    bool
+	}{
+		{bson.D{{Key: "_id", Value: "1"}}, "1", true},
+		{bson.D{{Key: "name", Value: "a"}, {Key: "_id", Value: "1"}}, "1", true},
+		{bson.D{{Key: "_id", Value: bson.D{{Key: "$eq", Value: "1"}}}}, "1", true},
+		{bson.D{{Key: "_id", Value: bson.D{{Key: "$ne", Value: "1"}}}}, nil, false},
+		{bson.D{{Key: "name", Value: "a"}}, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := idEquality(tt.query)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("idEquality(%v) = %v, %v, want %v, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
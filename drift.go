@@ -0,0 +1,216 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SchemaDiscrepancy is a difference between the collections of two deployments, see CompareSchema.
+type SchemaDiscrepancy struct {
+	// Kind is one of ProblemMissingCollection, ProblemMissingIndex or ProblemSchemaDrift
+	Kind string
+
+	// Collection is the collection that differs
+	Collection string
+
+	// Detail describes the difference
+	Detail string
+}
+
+// SchemaComparison is the result of CompareSchema.
+type SchemaComparison struct {
+	// Equal is true when no discrepancy was found
+	Equal bool
+
+	// Discrepancies found, ordered by collection
+	Discrepancies []SchemaDiscrepancy
+}
+
+// collectionSnapshot is what CompareSchema compares of a collection.
+type collectionSnapshot struct {
+	// indexes are the signatures of the indexes, see indexSignature
+	indexes []string
+	schema  bson.D
+}
+
+// CompareSchema compares the collections, indexes and validators of the Client database with the database of other,
+// which is usually connected to another deployment, like staging with production:
+//
+//	comparison, err := staging.CompareSchema(production)
+//	if err == nil && !comparison.Equal {
+//		for _, d := range comparison.Discrepancies {
+//			fmt.Printf("%s %s: %s\n", d.Collection, d.Kind, d.Detail)
+//		}
+//	}
+//
+// Indexes are matched by their keys, Unique, Sparse, ExpireAfter and partial filter, not by their name. Views and
+// system collections are not compared. The context of the Client is used for both deployments.
+func (connectionDetails *Client) CompareSchema(other *Client, opts ...Option) (*SchemaComparison, error) {
+	op := operation{name: "CompareSchema", options: newOperationOptions(opts)}
+	comparison := &SchemaComparison{}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		mine, err := snapshotSchema(ctx, db)
+		if err != nil {
+			return err
+		}
+		return other.exec(ctx, func(ctx context.Context, otherDB *mongo.Database) error {
+			theirs, err := snapshotSchema(ctx, otherDB)
+			if err != nil {
+				return err
+			}
+			comparison.Discrepancies, err = compareSnapshots(mine, theirs)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	comparison.Equal = len(comparison.Discrepancies) == 0
+	return comparison, nil
+}
+
+// snapshotSchema reads the indexes and validators of the collections of db.
+func snapshotSchema(ctx context.Context, db *mongo.Database) (map[string]collectionSnapshot, error) {
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{Key: "type", Value: "collection"}})
+	if err != nil {
+		return nil, err
+	}
+	snapshots := map[string]collectionSnapshot{}
+	for _, spec := range specs {
+		if strings.HasPrefix(spec.Name, "system.") {
+			continue
+		}
+		schema, err := specificationSchema(spec)
+		if err != nil {
+			return nil, err
+		}
+		cursor, err := db.Collection(spec.Name).Indexes().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var indexes []bson.D
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, err
+		}
+		snapshot := collectionSnapshot{schema: schema}
+		for _, index := range indexes {
+			snapshot.indexes = append(snapshot.indexes, indexSignature(index))
+		}
+		snapshots[spec.Name] = snapshot
+	}
+	return snapshots, nil
+}
+
+// compareSnapshots lists the differences between the collections of this deployment, mine, and of the other, theirs.
+func compareSnapshots(mine, theirs map[string]collectionSnapshot) ([]SchemaDiscrepancy, error) {
+	var names []string
+	for name := range mine {
+		names = append(names, name)
+	}
+	for name := range theirs {
+		if _, ok := mine[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var discrepancies []SchemaDiscrepancy
+	for _, name := range names {
+		a, inMine := mine[name]
+		b, inTheirs := theirs[name]
+		if !inMine || !inTheirs {
+			where := "this"
+			if !inMine {
+				where = "the other"
+			}
+			discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemMissingCollection, Collection: name,
+				Detail: fmt.Sprintf("the collection only exists in %s deployment", where)})
+			continue
+		}
+
+		for _, index := range missingStrings(a.indexes, b.indexes) {
+			discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemMissingIndex, Collection: name,
+				Detail: fmt.Sprintf("index on %s only exists in this deployment", index)})
+		}
+		for _, index := range missingStrings(b.indexes, a.indexes) {
+			discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemMissingIndex, Collection: name,
+				Detail: fmt.Sprintf("index on %s only exists in the other deployment", index)})
+		}
+
+		switch {
+		case a.schema == nil && b.schema == nil:
+		case b.schema == nil:
+			discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemSchemaDrift, Collection: name,
+				Detail: "only this deployment has a validator"})
+		case a.schema == nil:
+			discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemSchemaDrift, Collection: name,
+				Detail: "only the other deployment has a validator"})
+		default:
+			diff, err := DiffSchema(b.schema, a.schema)
+			if err != nil {
+				return nil, err
+			}
+			if !diff.Empty() {
+				discrepancies = append(discrepancies, SchemaDiscrepancy{Kind: ProblemSchemaDrift, Collection: name,
+					Detail: fmt.Sprintf("validator differs, fields only in this deployment %v, only in the other %v, changed %v",
+						diff.Added, diff.Removed, diff.Changed)})
+			}
+		}
+	}
+	return discrepancies, nil
+}
+
+// indexSignature describes the keys and the options of an index specification that change its behaviour, like
+// {email: 1} unique. Numeric key directions are normalized so 1 and 1.0 are the same.
+func indexSignature(spec bson.D) string {
+	var signature strings.Builder
+	signature.WriteString("{")
+	keys, _ := lookupField(spec, "key")
+	keyDoc, _ := keys.(bson.D)
+	for i, key := range keyDoc {
+		if i > 0 {
+			signature.WriteString(", ")
+		}
+		if n, ok := toFloat(key.Value); ok {
+			fmt.Fprintf(&signature, "%s: %g", key.Key, n)
+		} else {
+			fmt.Fprintf(&signature, "%s: %v", key.Key, key.Value)
+		}
+	}
+	signature.WriteString("}")
+	if unique, _ := lookupField(spec, "unique"); unique == true {
+		signature.WriteString(" unique")
+	}
+	if sparse, _ := lookupField(spec, "sparse"); sparse == true {
+		signature.WriteString(" sparse")
+	}
+	if expire, ok := toFloat(pathValue(spec, []string{"expireAfterSeconds"})); ok {
+		fmt.Fprintf(&signature, " expireAfterSeconds %g", expire)
+	}
+	if partial, ok := lookupField(spec, "partialFilterExpression"); ok {
+		filter, _ := bson.MarshalExtJSON(partial, false, false)
+		fmt.Fprintf(&signature, " partial %s", filter)
+	}
+	return signature.String()
+}
+
+// missingStrings returns the values of a that are not in b, sorted.
+func missingStrings(a, b []string) []string {
+	in := map[string]bool{}
+	for _, s := range b {
+		in[s] = true
+	}
+	var missing []string
+	for _, s := range a {
+		if !in[s] {
+			missing = append(missing, s)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndexSignature(t *testing.T) {
+	tests := []struct {
+		spec bson.D
+		want string
+	}{
+		{bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}}, "{_id: 1}"},
+		{bson.D{{Key: "key", Value: bson.D{{Key: "a", Value: 1.0}, {Key: "b", Value: int64(-1)}}}, {Key: "unique", Value: true}}, "{a: 1, b: -1} unique"},
+		{bson.D{{Key: "key", Value: bson.D{{Key: "body", Value: "text"}}}, {Key: "sparse", Value: true}}, "{body: text} sparse"},
+		{bson.D{{Key: "key", Value: bson.D{{Key: "at", Value: int32(1)}}}, {Key: "expireAfterSeconds", Value: int32(60)}}, "{at: 1} expireAfterSeconds 60"},
+	}
+	for _, tt := range tests {
+		if got := indexSignature(tt.spec); got != tt.want {
+			t.Errorf("indexSignature(%v) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	schema := func(fields ...string) bson.D {
+		properties := bson.D{}
+		for _, field := range fields {
+			properties = append(properties, bson.E{Key: field, Value: bson.D{{Key: "bsonType", Value: "string"}}})
+		}
+		return bson.D{{Key: "bsonType", Value: "object"}, {Key: "properties", Value: properties}}
+	}
+	mine := map[string]collectionSnapshot{
+		"users":  {indexes: []string{"{_id: 1}", "{email: 1} unique"}, schema: schema("name", "email")},
+		"orders": {indexes: []string{"{_id: 1}"}},
+		"audit":  {indexes: []string{"{_id: 1}"}},
+	}
+	theirs := map[string]collectionSnapshot{
+		"users":  {indexes: []string{"{_id: 1}", "{email: 1}"}, schema: schema("name")},
+		"orders": {indexes: []string{"{_id: 1}"}},
+		"events": {indexes: []string{"{_id: 1}"}},
+	}
+	discrepancies, err := compareSnapshots(mine, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SchemaDiscrepancy{
+		{Kind: ProblemMissingCollection, Collection: "audit", Detail: "the collection only exists in this deployment"},
+		{Kind: ProblemMissingCollection, Collection: "events", Detail: "the collection only exists in the other deployment"},
+		{Kind: ProblemMissingIndex, Collection: "users", Detail: "index on {email: 1} unique only exists in this deployment"},
+		{Kind: ProblemMissingIndex, Collection: "users", Detail: "index on {email: 1} only exists in the other deployment"},
+		{Kind: ProblemSchemaDrift, Collection: "users", Detail: "validator differs, fields only in this deployment [email], only in the other [], changed []"},
+	}
+	if !reflect.DeepEqual(discrepancies, want) {
+		t.Errorf("Unexpected discrepancies\n got %+v\nwant %+v", discrepancies, want)
+	}
+
+	if discrepancies, _ := compareSnapshots(theirs, theirs); len(discrepancies) != 0 {
+		t.Errorf("Expected no discrepancy between the same snapshots, got %+v", discrepancies)
+	}
+}
@@ -0,0 +1,51 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrDropNotConfirmed is returned by DropDatabase when the confirmation does not match the database name.
+var ErrDropNotConfirmed = errors.New("mongo: drop not confirmed")
+
+// DropConfirmation confirms a call to DropDatabase, see ConfirmDrop.
+type DropConfirmation struct {
+	databaseName string
+}
+
+// ConfirmDrop confirms that databaseName is the database to be dropped.
+func ConfirmDrop(databaseName string) DropConfirmation {
+	return DropConfirmation{databaseName: databaseName}
+}
+
+// DropDatabase drops the Client database, confirmation must name it.
+//
+//	client.DropDatabase(mongo.ConfirmDrop("test"))
+func (connectionDetails *Client) DropDatabase(confirmation DropConfirmation, opts ...Option) error {
+	op := operation{name: "DropDatabase", options: newOperationOptions(opts)}
+	if confirmation.databaseName == "" || confirmation.databaseName != connectionDetails.DatabaseName {
+		return connectionDetails.wrapError(op, fmt.Errorf("%w: expected confirmation for %q, got %q", ErrDropNotConfirmed,
+			connectionDetails.DatabaseName, confirmation.databaseName))
+	}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.Drop(ctx)
+	})
+}
+
+// DropCollections drops the given collections, collections that do not exist are ignored. The collections are
+// dropped one after the other, the first error stops the drop.
+func (connectionDetails *Client) DropCollections(collectionNames []string, opts ...Option) error {
+	for _, collectionName := range collectionNames {
+		op := operation{name: "DropCollections", collection: collectionName, options: newOperationOptions(opts)}
+		err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+			return db.Collection(collectionName).Drop(ctx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClient_DropDatabase_notConfirmed(t *testing.T) {
+	dropClient := NewMongoClientDefault("mongodb://localhost:27017", "test")
+
+	for _, confirmation := range []DropConfirmation{{}, ConfirmDrop("staging")} {
+		err := dropClient.DropDatabase(confirmation)
+		if !errors.Is(err, ErrDropNotConfirmed) {
+			t.Errorf("Expected ErrDropNotConfirmed, got %v", err)
+		}
+	}
+}
+
+func TestClient_DropDatabase_safetyProfile(t *testing.T) {
+	dropClient := NewMongoClientDefault("mongodb://localhost:27017", "test", WithSafetyProfile(Production))
+
+	err := dropClient.DropDatabase(ConfirmDrop("test"))
+	if !errors.Is(err, ErrUnsafeOperation) {
+		t.Errorf("Expected ErrUnsafeOperation, got %v", err)
+	}
+}
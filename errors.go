@@ -0,0 +1,126 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// Timeout errors, an OperationError matches one of them with errors.Is when the operation timed out.
+var (
+	// ErrClientTimeout is matched when the Client context deadline expired
+	ErrClientTimeout = errors.New("mongo: client timeout")
+
+	// ErrServerTimeout is matched when the server aborted the operation because it exceeded its time limit (MaxTimeMSExpired)
+	ErrServerTimeout = errors.New("mongo: server timeout")
+
+	// ErrNetworkTimeout is matched when the server could not be reached or did not answer in time
+	ErrNetworkTimeout = errors.New("mongo: network timeout")
+)
+
+// maxFilterSummary is the maximum length of OperationError.Filter.
+const maxFilterSummary = 256
+
+// OperationError is returned by Client operations, it wraps the underlying error with the details of the operation
+// that failed.
+//
+// Use errors.As to get the details, errors.Is and errors.As continue to work with the wrapped driver errors.
+type OperationError struct {
+	// Operation is the name of the Client method, for example "GetAllCustom"
+	Operation string
+
+	// Database the operation ran against
+	Database string
+
+	// Collection the operation ran against, empty for database level operations
+	Collection string
+
+	// Filter is a summary of the filter used, the values of sensitive fields are redacted
+	Filter string
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *OperationError) Error() string {
+	target := e.Database
+	if e.Collection != "" {
+		target += "." + e.Collection
+	}
+	if e.Filter != "" {
+		return fmt.Sprintf("mongo: %s on %s with filter %s: %s", e.Operation, target, e.Filter, e.Err)
+	}
+	return fmt.Sprintf("mongo: %s on %s: %s", e.Operation, target, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the operation timed out in the way described by target - ErrClientTimeout, ErrServerTimeout or
+// ErrNetworkTimeout.
+func (e *OperationError) Is(target error) bool {
+	kind := timeoutKind(e.Err)
+	return kind != nil && kind == target
+}
+
+// timeoutKind classifies err as one of the timeout errors, or returns nil if err is not a timeout.
+func timeoutKind(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && (serverErr.HasErrorCode(50) || serverErr.HasErrorCode(262)) {
+		return ErrServerTimeout
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrClientTimeout
+	}
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		return ErrNetworkTimeout
+	}
+	var netErr net.Error
+	if (mongo.IsNetworkError(err) && mongo.IsTimeout(err)) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrNetworkTimeout
+	}
+	return nil
+}
+
+// wrapError wraps err in an OperationError for op, errors that already are an OperationError are returned as is.
+func (connectionDetails *Client) wrapError(op operation, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*OperationError); ok {
+		return err
+	}
+	return &OperationError{
+		Operation:  op.name,
+		Database:   connectionDetails.DatabaseName,
+		Collection: op.collection,
+		Filter:     summarizeFilter(newRedactor(connectionDetails.redactedFields), op.filter),
+		Err:        err,
+	}
+}
+
+// summarizeFilter renders filter as extended JSON with sensitive values redacted, truncated to maxFilterSummary.
+func summarizeFilter(r redactor, filter interface{}) string {
+	if filter == nil {
+		return ""
+	}
+	doc, err := toDocument(filter)
+	if err != nil {
+		return fmt.Sprintf("%T", filter)
+	}
+	summary, err := bson.MarshalExtJSON(r.value(doc), false, false)
+	if err != nil {
+		return fmt.Sprintf("%T", filter)
+	}
+	if len(summary) > maxFilterSummary {
+		return string(summary[:maxFilterSummary]) + "..."
+	}
+	return string(summary)
+}
@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestOperationError(t *testing.T) {
+	errorClient := NewMongoClientDefault("mongodb://localhost:27017", "test")
+
+	err := errorClient.run(operation{name: "GetCustom", collection: "users", filter: bson.M{"password": "hunter2"}}, func(ctx context.Context, db *mongo.Database) error {
+		return context.DeadlineExceeded
+	})
+
+	var opErr *OperationError
+	if !errors.As(err, &opErr) {
+		t.Fatalf("Expected an OperationError, got %T", err)
+	}
+	if opErr.Operation != "GetCustom" || opErr.Database != "test" || opErr.Collection != "users" {
+		t.Errorf("Unexpected error details %+v", opErr)
+	}
+	if strings.Contains(opErr.Filter, "hunter2") || !strings.Contains(opErr.Filter, Redacted) {
+		t.Errorf("Expected filter to be redacted, got %s", opErr.Filter)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap %s", context.DeadlineExceeded)
+	}
+	if !strings.Contains(err.Error(), "GetCustom on test.users") {
+		t.Errorf("Unexpected error message %s", err)
+	}
+}
+
+func TestSummarizeFilter(t *testing.T) {
+	r := newRedactor(nil)
+	if summary := summarizeFilter(r, nil); summary != "" {
+		t.Errorf("Expected empty summary, got %s", summary)
+	}
+	if summary := summarizeFilter(r, F("token").Eq("abc")); summary != `{"token":"REDACTED"}` {
+		t.Errorf("Expected Filter to be redacted, got %s", summary)
+	}
+	summary := summarizeFilter(r, bson.M{"name": strings.Repeat("a", 1000)})
+	if len(summary) != maxFilterSummary+3 {
+		t.Errorf("Expected summary to be truncated, got %d characters", len(summary))
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestOperationError_Is(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"client", context.DeadlineExceeded, ErrClientTimeout},
+		{"server", mongo.CommandError{Code: 50, Name: "MaxTimeMSExpired"}, ErrServerTimeout},
+		{"network", timeoutError{}, ErrNetworkTimeout},
+		{"none", errors.New("failed"), nil},
+	}
+	kinds := []error{ErrClientTimeout, ErrServerTimeout, ErrNetworkTimeout}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(&OperationError{Operation: "Get", Err: tt.err})
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%s) = %v", kind, got)
+				}
+			}
+		})
+	}
+}
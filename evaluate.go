@@ -0,0 +1,409 @@
+package mongo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// EvaluatePipeline runs pipeline - mongo.Pipeline{}, bson.A{} or []bson.D{} - on documents in process, so code
+// building pipelines can be unit tested against fixtures without a running MongoDB:
+//
+//	results, err := mongo.EvaluatePipeline(fixtures, salesByStore(day))
+//
+// A documented subset of the stages is supported, anything else returns an error rather than a wrong result:
+//
+//   - $match with the filters of FakeClient
+//   - $project with inclusions, exclusions and "$field" paths
+//   - $group with an "_id" of a constant, a "$field" path or a document of them, and the $sum, $avg, $min, $max,
+//     $first, $last, $push, $addToSet and $count accumulators of constants and "$field" paths
+//   - $sort, $skip and $limit
+//
+// Expression operators like $add or $cond are not supported. Paths going through arrays of documents evaluate to the
+// array of the values found.
+func EvaluatePipeline(documents []interface{}, pipeline interface{}) ([]bson.D, error) {
+	stages, err := toStages(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]bson.D, len(documents))
+	for i, document := range documents {
+		if docs[i], err = toDocument(document); err != nil {
+			return nil, err
+		}
+	}
+	for _, stage := range stages {
+		if len(stage) != 1 {
+			return nil, fmt.Errorf("mongo: a pipeline stage needs exactly one field, got %v", stage)
+		}
+		if docs, err = evaluateStage(docs, stage[0].Key, stage[0].Value); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// Aggregate runs pipeline on the documents of collectionName with EvaluatePipeline.
+//
+// The 'result' parameter needs to be a pointer.
+func (fake *FakeClient) Aggregate(collectionName string, pipeline interface{}, result interface{}, opts ...Option) error {
+	docs, err := fake.find(collectionName, bson.D{})
+	if err != nil {
+		return err
+	}
+	documents := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		documents[i] = doc
+	}
+	results, err := EvaluatePipeline(documents, pipeline)
+	if err != nil {
+		return err
+	}
+	return decodeDocuments(results, result)
+}
+
+// toStages normalizes pipeline to its stages.
+func toStages(pipeline interface{}) ([]bson.D, error) {
+	raw, err := bson.Marshal(bson.D{{Key: "pipeline", Value: pipeline}})
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Pipeline []bson.D `bson:"pipeline"`
+	}
+	if err := bson.Unmarshal(raw, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Pipeline, nil
+}
+
+// evaluateStage applies a single stage to docs.
+func evaluateStage(docs []bson.D, stage string, spec interface{}) ([]bson.D, error) {
+	switch stage {
+	case "$match":
+		query, ok := spec.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("mongo: $match needs a document")
+		}
+		if operator := unsupportedQueryOperator(query); operator != "" {
+			return nil, fmt.Errorf("mongo: evaluator does not support the query operator %s", operator)
+		}
+		var matched []bson.D
+		for _, doc := range docs {
+			if matches(doc, query) {
+				matched = append(matched, doc)
+			}
+		}
+		return matched, nil
+	case "$project":
+		projection, ok := spec.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("mongo: $project needs a document")
+		}
+		return evaluateProject(docs, projection)
+	case "$group":
+		group, ok := spec.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("mongo: $group needs a document")
+		}
+		return evaluateGroup(docs, group)
+	case "$sort":
+		keys, ok := spec.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("mongo: $sort needs a document")
+		}
+		sorted := append([]bson.D(nil), docs...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			for _, key := range keys {
+				path := strings.Split(key.Key, ".")
+				order := compareValues(pathValue(sorted[i], path), pathValue(sorted[j], path))
+				if direction, _ := toFloat(key.Value); direction < 0 {
+					order = -order
+				}
+				if order != 0 {
+					return order < 0
+				}
+			}
+			return false
+		})
+		return sorted, nil
+	case "$skip", "$limit":
+		n, ok := toFloat(spec)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("mongo: %s needs a positive number", stage)
+		}
+		count := int(n)
+		if count > len(docs) {
+			count = len(docs)
+		}
+		if stage == "$skip" {
+			return docs[count:], nil
+		}
+		return docs[:count], nil
+	default:
+		return nil, fmt.Errorf("mongo: evaluator does not support the %s stage", stage)
+	}
+}
+
+// evaluateProject applies a $project stage. "_id" is kept unless it is excluded, inclusions and exclusions cannot be
+// mixed other than for "_id".
+func evaluateProject(docs []bson.D, projection bson.D) ([]bson.D, error) {
+	includeID := true
+	inclusion, exclusion := false, false
+	for _, field := range projection {
+		if flag, ok := projectionFlag(field.Value); ok && !flag {
+			if field.Key == "_id" {
+				includeID = false
+			} else {
+				exclusion = true
+			}
+		} else if field.Key != "_id" {
+			inclusion = true
+		}
+	}
+	if inclusion && exclusion {
+		return nil, fmt.Errorf("mongo: $project cannot mix inclusions and exclusions")
+	}
+
+	projected := make([]bson.D, len(docs))
+	for i, doc := range docs {
+		if !inclusion {
+			result := doc
+			for _, field := range projection {
+				if flag, ok := projectionFlag(field.Value); ok && !flag {
+					result = unsetPath(result, strings.Split(field.Key, "."))
+				}
+			}
+			projected[i] = result
+			continue
+		}
+		result := bson.D{}
+		if id, ok := lookupField(doc, "_id"); ok && includeID {
+			result = append(result, bson.E{Key: "_id", Value: id})
+		}
+		for _, field := range projection {
+			if field.Key == "_id" {
+				if _, ok := projectionFlag(field.Value); ok {
+					continue
+				}
+			}
+			path := strings.Split(field.Key, ".")
+			if _, ok := projectionFlag(field.Value); ok {
+				if values := lookupPath(doc, path); len(values) > 0 {
+					result = setPath(withoutField(result, field.Key), path, pathValue(doc, path))
+				}
+				continue
+			}
+			value, ok, err := evaluateExpression(doc, field.Value)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result = setPath(withoutField(result, field.Key), path, value)
+			}
+		}
+		projected[i] = result
+	}
+	return projected, nil
+}
+
+// projectionFlag returns whether a $project value is an inclusion, it is false when value is an expression.
+func projectionFlag(value interface{}) (include bool, ok bool) {
+	if flag, isBool := value.(bool); isBool {
+		return flag, true
+	}
+	if n, isNumber := toFloat(value); isNumber {
+		return n != 0, true
+	}
+	return false, false
+}
+
+// evaluateGroup applies a $group stage, the groups are returned in the order of their first document.
+func evaluateGroup(docs []bson.D, group bson.D) ([]bson.D, error) {
+	idExpression, ok := lookupField(group, "_id")
+	if !ok {
+		return nil, fmt.Errorf("mongo: $group needs an _id")
+	}
+	type groupState struct {
+		id     interface{}
+		values map[string][]interface{}
+	}
+	var groups []*groupState
+	for _, doc := range docs {
+		id, _, err := evaluateExpression(doc, idExpression)
+		if err != nil {
+			return nil, err
+		}
+		var state *groupState
+		for _, existing := range groups {
+			if compareValues(existing.id, id) == 0 {
+				state = existing
+				break
+			}
+		}
+		if state == nil {
+			state = &groupState{id: id, values: map[string][]interface{}{}}
+			groups = append(groups, state)
+		}
+		for _, field := range group {
+			if field.Key == "_id" {
+				continue
+			}
+			accumulator, ok := field.Value.(bson.D)
+			if !ok || len(accumulator) != 1 {
+				return nil, fmt.Errorf("mongo: $group field %s needs a single accumulator", field.Key)
+			}
+			value, found, err := evaluateExpression(doc, accumulator[0].Value)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				state.values[field.Key] = append(state.values[field.Key], value)
+			}
+		}
+	}
+
+	results := make([]bson.D, len(groups))
+	for i, state := range groups {
+		result := bson.D{{Key: "_id", Value: state.id}}
+		for _, field := range group {
+			if field.Key == "_id" {
+				continue
+			}
+			operator := field.Value.(bson.D)[0].Key
+			value, err := accumulate(operator, state.values[field.Key])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, bson.E{Key: field.Key, Value: value})
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// accumulate computes a $group accumulator over the values of a group, missing values are left out.
+func accumulate(operator string, values []interface{}) (interface{}, error) {
+	switch operator {
+	case "$sum", "$count":
+		var sum interface{} = int32(0)
+		for _, value := range values {
+			if _, ok := toFloat(value); ok {
+				sum = addNumbers(sum, value)
+			}
+		}
+		return sum, nil
+	case "$avg":
+		var sum float64
+		count := 0
+		for _, value := range values {
+			if n, ok := toFloat(value); ok {
+				sum += n
+				count++
+			}
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		return sum / float64(count), nil
+	case "$min", "$max":
+		var result interface{}
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			order := compareValues(value, result)
+			if result == nil || (operator == "$min" && order < 0) || (operator == "$max" && order > 0) {
+				result = value
+			}
+		}
+		return result, nil
+	case "$first", "$last":
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if operator == "$first" {
+			return values[0], nil
+		}
+		return values[len(values)-1], nil
+	case "$push", "$addToSet":
+		array := bson.A{}
+		for _, value := range values {
+			if operator == "$addToSet" && matchEqual([]interface{}{array}, value) {
+				continue
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	default:
+		return nil, fmt.Errorf("mongo: evaluator does not support the accumulator %s", operator)
+	}
+}
+
+// evaluateExpression evaluates a constant, a "$field" path or a document of them against doc. It is false when a
+// path is missing. $count evaluates its empty document to 1.
+func evaluateExpression(doc bson.D, expression interface{}) (interface{}, bool, error) {
+	switch v := expression.(type) {
+	case string:
+		if strings.HasPrefix(v, "$") {
+			path := strings.Split(v[1:], ".")
+			if len(lookupPath(doc, path)) == 0 {
+				return nil, false, nil
+			}
+			return pathValue(doc, path), true, nil
+		}
+		return v, true, nil
+	case bson.D:
+		if len(v) == 0 {
+			return int32(1), true, nil
+		}
+		if strings.HasPrefix(v[0].Key, "$") {
+			return nil, false, fmt.Errorf("mongo: evaluator does not support the expression operator %s", v[0].Key)
+		}
+		result := bson.D{}
+		for _, field := range v {
+			value, ok, err := evaluateExpression(doc, field.Value)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				result = append(result, bson.E{Key: field.Key, Value: value})
+			}
+		}
+		return result, true, nil
+	default:
+		return expression, true, nil
+	}
+}
+
+// pathValue returns the value at path in doc, the array of the values found when the path goes through an array of
+// documents, or nil when it is missing.
+func pathValue(doc bson.D, path []string) interface{} {
+	values := lookupPath(doc, path)
+	if len(values) == 1 && !throughArray(doc, path) {
+		return values[0]
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return bson.A(values)
+}
+
+// throughArray reports whether path goes through an array of doc before its last field.
+func throughArray(doc bson.D, path []string) bool {
+	var current interface{} = doc
+	for _, key := range path[:len(path)-1] {
+		switch v := current.(type) {
+		case bson.D:
+			current, _ = lookupField(v, key)
+		case bson.A:
+			return true
+		default:
+			return false
+		}
+	}
+	_, isArray := current.(bson.A)
+	return isArray
+}
@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var evaluateFixtures = []interface{}{
+	bson.D{{Key: "_id", Value: 1}, {Key: "store", Value: "auckland"}, {Key: "amount", Value: 10}, {Key: "status", Value: "paid"},
+		{Key: "items", Value: bson.A{bson.M{"sku": "a"}, bson.M{"sku": "b"}}}},
+	bson.D{{Key: "_id", Value: 2}, {Key: "store", Value: "wellington"}, {Key: "amount", Value: 5}, {Key: "status", Value: "paid"}},
+	bson.D{{Key: "_id", Value: 3}, {Key: "store", Value: "auckland"}, {Key: "amount", Value: 20}, {Key: "status", Value: "paid"}},
+	bson.D{{Key: "_id", Value: 4}, {Key: "store", Value: "auckland"}, {Key: "amount", Value: 100}, {Key: "status", Value: "refunded"}},
+}
+
+func TestEvaluatePipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline mongo.Pipeline
+		want     []bson.D
+	}{
+		{
+			"match group sort",
+			mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"status": "paid"}}},
+				{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: "$store"},
+					{Key: "total", Value: bson.M{"$sum": "$amount"}},
+					{Key: "orders", Value: bson.M{"$count": bson.M{}}},
+					{Key: "largest", Value: bson.M{"$max": "$amount"}},
+				}}},
+				{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}}}},
+			},
+			[]bson.D{
+				{{Key: "_id", Value: "auckland"}, {Key: "total", Value: int32(30)}, {Key: "orders", Value: int32(2)}, {Key: "largest", Value: int32(20)}},
+				{{Key: "_id", Value: "wellington"}, {Key: "total", Value: int32(5)}, {Key: "orders", Value: int32(1)}, {Key: "largest", Value: int32(5)}},
+			},
+		},
+		{
+			"project",
+			mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"_id": 1}}},
+				{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}, {Key: "store", Value: 1}, {Key: "skus", Value: "$items.sku"}}}},
+			},
+			[]bson.D{{{Key: "store", Value: "auckland"}, {Key: "skus", Value: bson.A{"a", "b"}}}},
+		},
+		{
+			"exclusion skip limit",
+			mongo.Pipeline{
+				{{Key: "$sort", Value: bson.D{{Key: "amount", Value: 1}}}},
+				{{Key: "$skip", Value: 1}},
+				{{Key: "$limit", Value: 1}},
+				{{Key: "$project", Value: bson.D{{Key: "items", Value: 0}, {Key: "status", Value: 0}, {Key: "store", Value: 0}}}},
+			},
+			[]bson.D{{{Key: "_id", Value: int32(1)}, {Key: "amount", Value: int32(10)}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluatePipeline(evaluateFixtures, tt.pipeline)
+			if err != nil {
+				t.Fatalf("EvaluatePipeline() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EvaluatePipeline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePipeline_unsupported(t *testing.T) {
+	pipelines := []mongo.Pipeline{
+		{{{Key: "$lookup", Value: bson.M{}}}},
+		{{{Key: "$match", Value: bson.M{"$expr": true}}}},
+		{{{Key: "$group", Value: bson.M{"_id": nil, "n": bson.M{"$stdDevPop": "$amount"}}}}},
+		{{{Key: "$project", Value: bson.M{"total": bson.M{"$add": bson.A{"$amount", 1}}}}}},
+		{{{Key: "$project", Value: bson.D{{Key: "store", Value: 1}, {Key: "amount", Value: 0}}}}},
+	}
+	for _, pipeline := range pipelines {
+		if _, err := EvaluatePipeline(evaluateFixtures, pipeline); err == nil {
+			t.Errorf("Expected an error for %v", pipeline)
+		}
+	}
+}
+
+func TestFakeClient_Aggregate(t *testing.T) {
+	fake := NewFakeClient()
+	if _, err := fake.AddMany("orders", evaluateFixtures); err != nil {
+		t.Fatalf("AddMany() error = %v", err)
+	}
+	var result []struct {
+		Store string `bson:"_id"`
+		Total int    `bson:"total"`
+	}
+	pipeline := mongo.Pipeline{{{Key: "$group", Value: bson.M{"_id": "$store", "total": bson.M{"$sum": "$amount"}}}}}
+	if err := fake.Aggregate("orders", pipeline, &result); err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(result) != 2 || result[0].Store != "auckland" || result[0].Total != 130 {
+		t.Errorf("Aggregate() = %+v", result)
+	}
+}
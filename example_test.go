@@ -3,6 +3,8 @@ package mongo_test
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 
@@ -118,6 +120,24 @@ func ExampleClient_UpdateCustom() {
 	fmt.Println("Modified items:", updated.ModifiedCount)
 }
 
+func ExampleClient_UpdateCustom_upsert() {
+	type data struct {
+		Name string `bson:"name"`
+	}
+
+	client := mongo.NewMongoClient("mongodb://localhost:27017/?retryWrites=true&w=majority", "test", context.Background())
+
+	testData := data{
+		Name: "Akshay",
+	}
+
+	updated, err := client.UpdateCustom("test_collection", bson.M{"_id": "1"}, testData, mongo.Upsert())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Upserted ID:", updated.UpsertedID)
+}
+
 func ExampleClient_Get() {
 
 	type data struct {
@@ -193,3 +213,76 @@ func ExampleClient_GetAllCustom() {
 	}
 	fmt.Println("The ID is:", testData)
 }
+
+func ExampleWithMetricsHook() {
+	client := mongo.NewMongoClient("mongodb://localhost:27017/?retryWrites=true&w=majority", "test", context.Background(),
+		mongo.WithMetricsHook(func(metrics mongo.OperationMetrics) {
+			fmt.Println(metrics.Operation, metrics.Collection, metrics.RequestBytes, metrics.ResponseBytes)
+		}))
+
+	var testData []interface{}
+	err := client.GetAllCustom("test_collection", bson.M{}, &testData)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ExampleClient_StartHealthMonitor() {
+	client := mongo.NewMongoClient("mongodb://localhost:27017/?retryWrites=true&w=majority", "test", context.Background())
+
+	monitor := client.StartHealthMonitor(10*time.Second, func(report mongo.HealthReport) {
+		fmt.Println("Healthy:", report.Healthy, report.Err)
+	})
+	defer monitor.Stop()
+
+	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !monitor.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+}
+
+func ExampleNewFakeClient() {
+	type data struct {
+		ID   string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+
+	// Code depending on mongo.Store can use the fake in unit tests
+	var store mongo.Store = mongo.NewFakeClient()
+
+	_, err := store.Add("test_collection", data{ID: "1", Name: "Akshay"})
+	if err != nil {
+		panic(err)
+	}
+
+	var decodeData data
+	get, err := store.Get("test_collection", "1")
+	if err != nil {
+		panic(err)
+	}
+	err = get.Decode(&decodeData)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(decodeData.Name)
+	// Output: Akshay
+}
+
+func ExampleClient_WithDatabase() {
+	client := mongo.NewMongoClient("mongodb://localhost:27017/?retryWrites=true&w=majority", "test", context.Background())
+
+	// Keep a single connection pool for the client and its views
+	err := client.Connect()
+	if err != nil {
+		panic(err)
+	}
+	defer client.Disconnect()
+
+	tenant := client.WithDatabase("tenant_1")
+	deleted, err := tenant.Delete("test_collection", "1")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Deleted items:", deleted.DeletedCount)
+}
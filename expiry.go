@@ -0,0 +1,201 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExpiryNotifiedField is the field an ExpiryWatcher records the notified expiry date of a document in.
+const ExpiryNotifiedField = "expiryNotifiedAt"
+
+// ExpiryOptions configures an ExpiryWatcher.
+type ExpiryOptions struct {
+	// Field holding the expiry date of the documents, defaults to "expiresAt"
+	Field string
+
+	// Before is how long before its expiry date a document is notified, 0 notifies it once expired. With a TTL index
+	// on Field, keep it above PollInterval and the minute the server takes to remove expired documents so they are
+	// notified before they are removed.
+	Before time.Duration
+
+	// PollInterval is how often a started ExpiryWatcher looks for expiring documents, defaults to 1 minute
+	PollInterval time.Duration
+}
+
+// ExpiryWatcher notifies handlers of the documents of a collection reaching their expiry date, see
+// Client.WatchExpiry.
+type ExpiryWatcher struct {
+	client     *Client
+	collection string
+	options    ExpiryOptions
+
+	mu       sync.RWMutex
+	handlers []func(document bson.Raw) error
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// WatchExpiry returns an ExpiryWatcher of the documents of collectionName, for side effects like revoking the
+// sessions of an expiring subscription or sending a reminder:
+//
+//	watcher := client.WatchExpiry("subscriptions", mongo.ExpiryOptions{Before: 72 * time.Hour})
+//	watcher.Handle(sendRenewalReminder)
+//	watcher.Start()
+//	defer watcher.Stop()
+//
+// The watcher polls for the documents whose Field is due and claims each with a findOneAndUpdate recording the
+// notified date in ExpiryNotifiedField, so a document is notified by a single instance of the application. Changing
+// Field, like renewing a subscription, notifies the document again at its new date. A document whose handler failed
+// is notified again on the next poll, one whose instance crashed while notifying it is not. Index Field so polling
+// does not scan the collection, a TTL index on it does.
+func (connectionDetails *Client) WatchExpiry(collectionName string, opts ExpiryOptions) *ExpiryWatcher {
+	if opts.Field == "" {
+		opts.Field = "expiresAt"
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Minute
+	}
+	return &ExpiryWatcher{client: connectionDetails, collection: collectionName, options: opts}
+}
+
+// Handle registers fn to be called with every expiring document, the handlers are called in the order they were
+// registered. The document is notified again when fn returns an error.
+func (watcher *ExpiryWatcher) Handle(fn func(document bson.Raw) error) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.handlers = append(watcher.handlers, fn)
+}
+
+// Start polls for expiring documents every PollInterval until Stop is called or the Client context is done. To
+// manage the polling with the other background work of the Client, register Worker instead.
+func (watcher *ExpiryWatcher) Start() {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(watcher.client.Context)
+	done := make(chan struct{})
+	watcher.cancel, watcher.done = cancel, done
+	go func() {
+		defer close(done)
+		_ = watcher.poll(ctx)
+	}()
+}
+
+// Worker returns a Worker polling for expiring documents every PollInterval, for Client.RegisterWorker. Use it
+// instead of Start and Stop.
+func (watcher *ExpiryWatcher) Worker() Worker {
+	return Worker{Name: "expiry:" + watcher.collection, Run: watcher.poll}
+}
+
+// poll notifies the expiring documents every PollInterval until ctx is done.
+func (watcher *ExpiryWatcher) poll(ctx context.Context) error {
+	ticker := time.NewTicker(watcher.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := watcher.NotifyDue(); err != nil {
+			watcher.client.log().Warn("mongo: unable to notify expiring documents", "collection", watcher.collection,
+				"error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops polling and waits for the running handlers to return.
+func (watcher *ExpiryWatcher) Stop() {
+	watcher.mu.Lock()
+	cancel, done := watcher.cancel, watcher.done
+	watcher.cancel = nil
+	watcher.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// NotifyDue calls the handlers with the documents due to be notified, the earliest to expire first, and returns how
+// many were notified. Start calls it on every poll, it can also be called directly, for example from a test or an
+// external timer.
+func (watcher *ExpiryWatcher) NotifyDue() (int, error) {
+	watcher.mu.RLock()
+	handlers := watcher.handlers
+	watcher.mu.RUnlock()
+	if len(handlers) == 0 {
+		return 0, nil
+	}
+
+	notified := 0
+	failed := bson.A{}
+	for {
+		document, err := watcher.claim(failed)
+		if err != nil || document == nil {
+			return notified, err
+		}
+		for _, handler := range handlers {
+			if err = handler(document); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			watcher.client.log().Warn("mongo: expiry handler failed", "collection", watcher.collection,
+				"id", document.Lookup("_id"), "error", err)
+			if err := watcher.release(document); err != nil {
+				return notified, err
+			}
+			failed = append(failed, document.Lookup("_id"))
+			continue
+		}
+		notified++
+	}
+}
+
+// claim records the notification of a due document that was not notified for its current expiry date, leaving out
+// the "_id"s of skip. It returns nil when there is none.
+func (watcher *ExpiryWatcher) claim(skip bson.A) (bson.Raw, error) {
+	field := watcher.options.Field
+	op := operation{name: "NotifyExpiry", collection: watcher.collection}
+	var document bson.Raw
+	err := watcher.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		document = nil
+		filter := bson.D{
+			{Key: field, Value: bson.D{{Key: "$lte", Value: time.Now().Add(watcher.options.Before)}}},
+			{Key: "$expr", Value: bson.D{{Key: "$ne", Value: bson.A{"$" + ExpiryNotifiedField, "$" + field}}}},
+		}
+		if len(skip) > 0 {
+			filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$nin", Value: skip}}})
+		}
+		update := mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: ExpiryNotifiedField, Value: "$" + field}}}}}
+		found := options.FindOneAndUpdate().SetSort(bson.D{{Key: field, Value: 1}}).SetReturnDocument(options.After)
+		err := db.Collection(watcher.collection).FindOneAndUpdate(ctx, filter, update, found).Decode(&document)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	})
+	return document, err
+}
+
+// release removes the notification of document so it is notified again, unless its expiry date changed since.
+func (watcher *ExpiryWatcher) release(document bson.Raw) error {
+	filter := bson.D{
+		{Key: "_id", Value: document.Lookup("_id")},
+		{Key: ExpiryNotifiedField, Value: document.Lookup(ExpiryNotifiedField)},
+	}
+	op := operation{name: "NotifyExpiry", collection: watcher.collection, filter: filter}
+	return watcher.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		update := bson.D{{Key: "$unset", Value: bson.D{{Key: ExpiryNotifiedField, Value: ""}}}}
+		_, err := db.Collection(watcher.collection).UpdateOne(ctx, filter, update)
+		return err
+	})
+}
@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestExpiryWatcher_NotifyDue(t *testing.T) {
+	_ = client.DropCollections([]string{"subscriptions"})
+	now := time.Now()
+	subscriptions := []interface{}{
+		bson.M{"_id": "expired", "expiresAt": now.Add(-time.Hour)},
+		bson.M{"_id": "expiring", "expiresAt": now.Add(time.Hour)},
+		bson.M{"_id": "renewed", "expiresAt": now.Add(30 * 24 * time.Hour)},
+	}
+	if _, err := client.AddMany("subscriptions", subscriptions); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	watcher := client.WatchExpiry("subscriptions", ExpiryOptions{Before: 24 * time.Hour})
+	var notified []string
+	failing := true
+	watcher.Handle(func(document bson.Raw) error {
+		id := document.Lookup("_id").StringValue()
+		if id == "expiring" && failing {
+			return errors.New("unavailable")
+		}
+		notified = append(notified, id)
+		return nil
+	})
+
+	if n, err := watcher.NotifyDue(); err != nil || n != 1 || len(notified) != 1 || notified[0] != "expired" {
+		t.Fatalf("NotifyDue() = %d, %v, notified %v", n, err, notified)
+	}
+	failing = false
+	if n, err := watcher.NotifyDue(); err != nil || n != 1 || notified[1] != "expiring" {
+		t.Errorf("Expected the failed document to be notified again, got %d, %v, notified %v", n, err, notified)
+	}
+	if n, err := watcher.NotifyDue(); err != nil || n != 0 {
+		t.Errorf("Expected every document to be notified once, got %d, %v", n, err)
+	}
+
+	if _, err := client.Update("subscriptions", "expired", bson.M{"expiresAt": now.Add(time.Minute)}); err != nil {
+		t.Fatalf("Unable to update data. %s", err)
+	}
+	if n, err := watcher.NotifyDue(); err != nil || n != 1 || notified[2] != "expired" {
+		t.Errorf("Expected a renewed document to be notified at its new date, got %d, %v, notified %v", n, err, notified)
+	}
+}
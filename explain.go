@@ -0,0 +1,149 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ExplainResult is the winning plan and the execution statistics of a query, see ExplainFind.
+type ExplainResult struct {
+	// Stages of the winning plan from the first to run to the last, for example ["IXSCAN", "FETCH"]
+	Stages []string
+
+	// Indexes used by the winning plan, empty for a collection scan
+	Indexes []string
+
+	// CollectionScan is true when the winning plan reads the whole collection, usually a missing index
+	CollectionScan bool
+
+	// Returned is the number of documents returned, KeysExamined and DocsExamined the index keys and documents read to
+	// find them
+	Returned     int64
+	KeysExamined int64
+	DocsExamined int64
+
+	// ExecutionTime is the time the server spent executing the query
+	ExecutionTime time.Duration
+
+	// Raw is the whole explain output
+	Raw bson.Raw
+}
+
+// ExplainFind runs the query of GetAllCustom with filter on collectionName and returns how the server executed it:
+//
+//	explain, err := client.ExplainFind("orders", bson.M{"customerId": id})
+//	if err == nil && explain.CollectionScan {
+//		log.Printf("orders by customer scans %d documents, add an index", explain.DocsExamined)
+//	}
+//
+// The query runs to completion to collect the statistics.
+func (connectionDetails *Client) ExplainFind(collectionName string, filter interface{}, opts ...Option) (*ExplainResult, error) {
+	op := operation{name: "ExplainFind", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := bson.D{{Key: "find", Value: collectionName}, {Key: "filter", Value: filter}}
+	return connectionDetails.explain(op, op.options.commandOptions(find))
+}
+
+// ExplainAggregate runs pipeline on collectionName like Aggregate and returns how the server executed it. The plan
+// is the one of the query reading the collection at the start of the pipeline.
+func (connectionDetails *Client) ExplainAggregate(collectionName string, pipeline interface{}, opts ...Option) (*ExplainResult, error) {
+	op := operation{name: "ExplainAggregate", collection: collectionName, options: newOperationOptions(opts)}
+	aggregate := bson.D{{Key: "aggregate", Value: collectionName}, {Key: "pipeline", Value: pipeline}, {Key: "cursor", Value: bson.D{}}}
+	return connectionDetails.explain(op, op.options.commandOptions(aggregate))
+}
+
+// explain runs the explain command of command with the "executionStats" verbosity.
+func (connectionDetails *Client) explain(op operation, command bson.D) (*ExplainResult, error) {
+	var raw bson.Raw
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		explain := bson.D{{Key: "explain", Value: command}, {Key: "verbosity", Value: "executionStats"}}
+		return db.RunCommand(ctx, explain).Decode(&raw)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseExplain(raw)
+	if err != nil {
+		return nil, connectionDetails.wrapError(op, err)
+	}
+	return result, nil
+}
+
+// parseExplain reads the winning plan and the execution statistics of an explain output. The plan of an aggregation
+// is in its first stage when the server did not push the whole pipeline down to the query.
+func parseExplain(raw bson.Raw) (*ExplainResult, error) {
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	result := &ExplainResult{Raw: raw}
+	if _, ok := lookupField(doc, "queryPlanner"); !ok {
+		if stages, ok := pathValue(doc, []string{"stages"}).(bson.A); ok && len(stages) > 0 {
+			if cursor, ok := pathValue(toD(stages[0]), []string{"$cursor"}).(bson.D); ok {
+				doc = cursor
+			}
+		}
+	}
+
+	if plan, ok := pathValue(doc, []string{"queryPlanner", "winningPlan"}).(bson.D); ok {
+		walkPlan(plan, result)
+	}
+	result.Returned = explainInt(doc, "nReturned")
+	result.KeysExamined = explainInt(doc, "totalKeysExamined")
+	result.DocsExamined = explainInt(doc, "totalDocsExamined")
+	result.ExecutionTime = time.Duration(explainInt(doc, "executionTimeMillis")) * time.Millisecond
+	return result, nil
+}
+
+// walkPlan adds the stages and the indexes of plan to result, the input stages first.
+func walkPlan(plan bson.D, result *ExplainResult) {
+	if queryPlan, ok := pathValue(plan, []string{"queryPlan"}).(bson.D); ok {
+		// the slot based engine nests the classic plan
+		walkPlan(queryPlan, result)
+		return
+	}
+	if input, ok := pathValue(plan, []string{"inputStage"}).(bson.D); ok {
+		walkPlan(input, result)
+	}
+	if inputs, ok := pathValue(plan, []string{"inputStages"}).(bson.A); ok {
+		for _, input := range inputs {
+			walkPlan(toD(input), result)
+		}
+	}
+	if shards, ok := pathValue(plan, []string{"shards"}).(bson.A); ok {
+		for _, shard := range shards {
+			if shardPlan, ok := pathValue(toD(shard), []string{"winningPlan"}).(bson.D); ok {
+				walkPlan(shardPlan, result)
+			}
+		}
+	}
+
+	stage, _ := pathValue(plan, []string{"stage"}).(string)
+	if stage == "" {
+		return
+	}
+	result.Stages = append(result.Stages, stage)
+	if stage == "COLLSCAN" {
+		result.CollectionScan = true
+	}
+	if index, ok := pathValue(plan, []string{"indexName"}).(string); ok {
+		result.Indexes = append(result.Indexes, index)
+	}
+}
+
+// explainInt returns a counter of the executionStats of an explain output.
+func explainInt(doc bson.D, field string) int64 {
+	value, _ := toFloat(pathValue(doc, []string{"executionStats", field}))
+	return int64(value)
+}
+
+// toD returns v as a bson.D, or nil.
+func toD(v interface{}) bson.D {
+	doc, _ := v.(bson.D)
+	return doc
+}
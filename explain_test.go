@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseExplain(t *testing.T) {
+	executionStats := bson.D{
+		{Key: "nReturned", Value: int32(3)},
+		{Key: "executionTimeMillis", Value: int32(12)},
+		{Key: "totalKeysExamined", Value: int32(3)},
+		{Key: "totalDocsExamined", Value: int32(3)},
+	}
+	find, _ := bson.Marshal(bson.D{
+		{Key: "queryPlanner", Value: bson.D{{Key: "winningPlan", Value: bson.D{
+			{Key: "stage", Value: "FETCH"},
+			{Key: "inputStage", Value: bson.D{{Key: "stage", Value: "IXSCAN"}, {Key: "indexName", Value: "customerId_1"}}},
+		}}}},
+		{Key: "executionStats", Value: executionStats},
+	})
+	result, err := parseExplain(find)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Stages, []string{"IXSCAN", "FETCH"}) || !reflect.DeepEqual(result.Indexes, []string{"customerId_1"}) {
+		t.Errorf("Unexpected plan %v using %v", result.Stages, result.Indexes)
+	}
+	if result.CollectionScan || result.Returned != 3 || result.DocsExamined != 3 || result.ExecutionTime != 12*time.Millisecond {
+		t.Errorf("Unexpected statistics %+v", result)
+	}
+
+	aggregate, _ := bson.Marshal(bson.D{{Key: "stages", Value: bson.A{
+		bson.D{{Key: "$cursor", Value: bson.D{
+			{Key: "queryPlanner", Value: bson.D{{Key: "winningPlan", Value: bson.D{
+				{Key: "queryPlan", Value: bson.D{{Key: "stage", Value: "COLLSCAN"}}},
+			}}}},
+			{Key: "executionStats", Value: bson.D{{Key: "nReturned", Value: int32(2)}, {Key: "totalDocsExamined", Value: int64(1000)}}},
+		}}},
+		bson.D{{Key: "$group", Value: bson.D{}}},
+	}}})
+	result, err = parseExplain(aggregate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.CollectionScan || len(result.Indexes) != 0 || result.Returned != 2 || result.DocsExamined != 1000 {
+		t.Errorf("Unexpected aggregation explain %+v", result)
+	}
+}
@@ -0,0 +1,204 @@
+package mongo
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ExportFormat is the format of Client.Export.
+type ExportFormat int
+
+const (
+	// ExportJSON writes a JSON array of relaxed extended JSON documents
+	ExportJSON ExportFormat = iota
+
+	// ExportNDJSON writes one relaxed extended JSON document per line
+	ExportNDJSON
+
+	// ExportCSV writes a header with the field paths followed by one row per document
+	ExportCSV
+
+	// ExportBSON writes the BSON documents one after the other, like the .bson files of mongodump, keeping every type
+	ExportBSON
+)
+
+// Export streams the documents of collectionName matching filter to w in format and returns the number of exported
+// documents.
+//
+// fields selects the exported fields, nested fields use dotted paths, "_id" is only exported when selected. Every
+// field is exported when fields is empty, a CSV export then uses the fields of the first document as columns. In CSV,
+// strings are written as is, dates in RFC 3339, ObjectIDs in hex and documents and arrays as extended JSON.
+func (connectionDetails *Client) Export(collectionName string, filter interface{}, w io.Writer, format ExportFormat, fields []string, opts ...Option) (int64, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	op := operation{name: "Export", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	if format != ExportJSON && format != ExportNDJSON && format != ExportCSV && format != ExportBSON {
+		return 0, connectionDetails.wrapError(op, fmt.Errorf("mongo: unknown export format %d", format))
+	}
+
+	var exported int64
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		exported = 0
+		find := op.options.find()
+		if len(fields) > 0 {
+			find.SetProjection(exportProjection(fields))
+		}
+		cursor, err := db.Collection(collectionName).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		writer := newExportWriter(w, format, fields)
+		for cursor.Next(ctx) {
+			if err := writer.write(cursor.Current); err != nil {
+				return err
+			}
+			exported++
+		}
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+		return writer.close()
+	})
+	if err != nil {
+		return exported, err
+	}
+	return exported, nil
+}
+
+// exportProjection returns the projection of fields, excluding "_id" unless it is selected.
+func exportProjection(fields []string) bson.D {
+	projection := bson.D{}
+	selectsID := false
+	for _, field := range fields {
+		projection = append(projection, bson.E{Key: field, Value: 1})
+		selectsID = selectsID || field == "_id"
+	}
+	if !selectsID {
+		projection = append(projection, bson.E{Key: "_id", Value: 0})
+	}
+	return projection
+}
+
+// exportWriter writes documents in an ExportFormat.
+type exportWriter struct {
+	w       io.Writer
+	format  ExportFormat
+	fields  []string
+	csv     *csv.Writer
+	written int
+}
+
+func newExportWriter(w io.Writer, format ExportFormat, fields []string) *exportWriter {
+	writer := &exportWriter{w: w, format: format, fields: fields}
+	if format == ExportCSV {
+		writer.csv = csv.NewWriter(w)
+	}
+	return writer
+}
+
+// write writes a document.
+func (writer *exportWriter) write(document bson.Raw) error {
+	defer func() { writer.written++ }()
+	switch writer.format {
+	case ExportCSV:
+		return writer.writeRow(document)
+	case ExportBSON:
+		_, err := writer.w.Write(document)
+		return err
+	case ExportJSON:
+		prefix := ",\n"
+		if writer.written == 0 {
+			prefix = "[\n"
+		}
+		if _, err := io.WriteString(writer.w, prefix); err != nil {
+			return err
+		}
+	}
+	line, err := bson.MarshalExtJSON(document, false, false)
+	if err != nil {
+		return err
+	}
+	if writer.format == ExportNDJSON {
+		line = append(line, '\n')
+	}
+	_, err = writer.w.Write(line)
+	return err
+}
+
+// writeRow writes a CSV row, and the header before the first one.
+func (writer *exportWriter) writeRow(document bson.Raw) error {
+	var doc bson.D
+	if err := bson.Unmarshal(document, &doc); err != nil {
+		return err
+	}
+	if writer.written == 0 {
+		if len(writer.fields) == 0 {
+			for _, elem := range doc {
+				writer.fields = append(writer.fields, elem.Key)
+			}
+		}
+		if err := writer.csv.Write(writer.fields); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(writer.fields))
+	for i, field := range writer.fields {
+		value, err := csvValue(firstValue(lookupPath(doc, strings.Split(field, "."))))
+		if err != nil {
+			return err
+		}
+		row[i] = value
+	}
+	return writer.csv.Write(row)
+}
+
+// close ends the export.
+func (writer *exportWriter) close() error {
+	switch writer.format {
+	case ExportJSON:
+		end := "\n]\n"
+		if writer.written == 0 {
+			end = "[]\n"
+		}
+		_, err := io.WriteString(writer.w, end)
+		return err
+	case ExportCSV:
+		writer.csv.Flush()
+		return writer.csv.Error()
+	}
+	return nil
+}
+
+// csvValue formats a BSON value for a CSV cell.
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case primitive.ObjectID:
+		return v.Hex(), nil
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano), nil
+	case bson.D, bson.A:
+		raw, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false, false)
+		if err != nil {
+			return "", err
+		}
+		// strip the {"v": ...} wrapper
+		return strings.TrimSuffix(strings.TrimPrefix(string(raw), `{"v":`), "}"), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExportWriter(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "_id", Value: 1}, {Key: "name", Value: "Akshay"}, {Key: "address", Value: bson.D{{Key: "city", Value: "Auckland"}}}},
+		{{Key: "_id", Value: 2}, {Key: "name", Value: "Raj, Jr"}},
+	}
+	export := func(format ExportFormat, fields []string) string {
+		var buf bytes.Buffer
+		writer := newExportWriter(&buf, format, fields)
+		for _, doc := range docs {
+			raw, err := bson.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.write(raw); err != nil {
+				t.Fatalf("write() error = %v", err)
+			}
+		}
+		if err := writer.close(); err != nil {
+			t.Fatalf("close() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	ndjson := export(ExportNDJSON, nil)
+	if lines := strings.Split(strings.TrimSpace(ndjson), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"city":"Auckland"`) {
+		t.Errorf("Unexpected NDJSON export %q", ndjson)
+	}
+
+	array := export(ExportJSON, nil)
+	var decoded []bson.M
+	if err := bson.UnmarshalExtJSON([]byte(`{"docs":`+array+`}`), false, &struct {
+		Docs *[]bson.M `bson:"docs"`
+	}{&decoded}); err != nil || len(decoded) != 2 {
+		t.Errorf("Expected a JSON array of 2 documents, got %q %v", array, err)
+	}
+
+	csv := export(ExportCSV, []string{"_id", "name", "address.city"})
+	want := "_id,name,address.city\n1,Akshay,Auckland\n2,\"Raj, Jr\",\n"
+	if csv != want {
+		t.Errorf("CSV export = %q, want %q", csv, want)
+	}
+}
+
+func TestExportProjection(t *testing.T) {
+	if got := exportProjection([]string{"name"}); len(got) != 2 || got[1].Key != "_id" || got[1].Value != 0 {
+		t.Errorf("Expected _id to be excluded, got %v", got)
+	}
+	if got := exportProjection([]string{"_id", "name"}); len(got) != 2 {
+		t.Errorf("Expected _id to be selected, got %v", got)
+	}
+}
+
+func TestCSVValue(t *testing.T) {
+	id := primitive.NewObjectID()
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, ""},
+		{"text", "text"},
+		{int32(3), "3"},
+		{id, id.Hex()},
+		{primitive.NewDateTimeFromTime(date), "2024-01-02T03:04:05Z"},
+		{bson.A{"a", "b"}, `["a","b"]`},
+	}
+	for _, tt := range tests {
+		if got, err := csvValue(tt.value); err != nil || got != tt.want {
+			t.Errorf("csvValue(%v) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestClient_Export(t *testing.T) {
+	_ = client.DropCollection("export_collection")
+	if _, err := client.AddMany("export_collection", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	var buf bytes.Buffer
+	count, err := client.Export("export_collection", nil, &buf, ExportCSV, []string{"_id", "name"})
+	if err != nil || count != 2 {
+		t.Fatalf("Export() = %d, %v", count, err)
+	}
+	if !strings.HasPrefix(buf.String(), "_id,name\n") {
+		t.Errorf("Unexpected export %q", buf.String())
+	}
+}
@@ -0,0 +1,995 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FakeClient is an in-memory Store for unit tests, it does not need a running MongoDB.
+//
+// Filters support equality on fields and dotted paths, matching array elements like MongoDB does, regular expressions
+// and the $eq, $ne, $in, $nin, $gt, $gte, $lt, $lte, $exists, $regex, $and, $or and $nor operators, other operators
+// return an error. Comparisons only match values of the same type, like MongoDB. Updates support the $set, $unset,
+// $inc, $push, $pull and $addToSet operators. The Upsert and Unordered options are supported, other options are
+// ignored. Models implementing Validator are validated before they are written. Aggregate evaluates the subset of
+// pipeline stages documented by EvaluatePipeline.
+type FakeClient struct {
+	mu          sync.RWMutex
+	collections map[string][]bson.D
+}
+
+// NewFakeClient returns an empty FakeClient.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{collections: map[string][]bson.D{}}
+}
+
+// Add can be used to add document to the fake
+func (fake *FakeClient) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	id, err := fake.insert(collectionName, doc)
+	if err != nil {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Index: 0, Code: 11000, Message: err.Error()}}}
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+// AddMany can be used to add multiple documents to the fake
+func (fake *FakeClient) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	if err := validateDocuments(collectionName, nil, data); err != nil {
+		return nil, err
+	}
+	o := newOperationOptions(opts)
+	ordered := o.ordered == nil || *o.ordered
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	result := &mongo.InsertManyResult{}
+	var writeErrors mongo.WriteErrors
+	for i, item := range data {
+		doc, err := toDocument(item)
+		if err != nil {
+			return nil, err
+		}
+		id, err := fake.insert(collectionName, doc)
+		if err != nil {
+			writeErrors = append(writeErrors, mongo.WriteError{Index: i, Code: 11000, Message: err.Error()})
+			if ordered {
+				break
+			}
+			continue
+		}
+		result.InsertedIDs = append(result.InsertedIDs, id)
+	}
+	if len(writeErrors) > 0 {
+		bulkErrors := make([]mongo.BulkWriteError, len(writeErrors))
+		for i, writeErr := range writeErrors {
+			bulkErrors[i] = mongo.BulkWriteError{WriteError: writeErr}
+		}
+		return result, mongo.BulkWriteException{WriteErrors: bulkErrors}
+	}
+	return result, nil
+}
+
+// Update can be used to update values by its ID
+func (fake *FakeClient) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return fake.UpdateCustom(collectionName, bson.M{"_id": id}, data, opts...)
+}
+
+// UpdateCustom can be used to update values by a filter - bson.M{}, or bson.D{}
+func (fake *FakeClient) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	return fake.update(collectionName, filter, updateDocument(data), newOperationOptions(opts), false)
+}
+
+// UpdateMany can be used to update values of all the documents matching a filter - bson.M{}, or bson.D{}
+func (fake *FakeClient) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	return fake.update(collectionName, filter, updateDocument(data), newOperationOptions(opts), true)
+}
+
+// Replace replaces the whole document with the given ID.
+func (fake *FakeClient) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return fake.replace(collectionName, id, doc, newOperationOptions(opts))
+}
+
+// Save inserts data, or replaces the document with the same "_id".
+func (fake *FakeClient) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, id, err := withID(data)
+	if err != nil {
+		return nil, err
+	}
+	return fake.replace(collectionName, id, doc, newOperationOptions(append(opts, Upsert())))
+}
+
+// Increment atomically adds delta to a numeric field of the document with the given ID.
+func (fake *FakeClient) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return fake.update(collectionName, bson.M{"_id": id}, bson.D{{Key: "$inc", Value: bson.D{{Key: field, Value: delta}}}}, newOperationOptions(opts), false)
+}
+
+// Push atomically appends value to an array field of the document with the given ID.
+func (fake *FakeClient) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return fake.update(collectionName, bson.M{"_id": id}, bson.D{{Key: "$push", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// Pull atomically removes every element matching value from an array field of the document with the given ID.
+func (fake *FakeClient) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return fake.update(collectionName, bson.M{"_id": id}, bson.D{{Key: "$pull", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// AddToSet atomically appends value to an array field of the document with the given ID, unless it is already there.
+func (fake *FakeClient) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return fake.update(collectionName, bson.M{"_id": id}, bson.D{{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// Unset removes fields from the document with the given ID.
+func (fake *FakeClient) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	unset := bson.D{}
+	for _, field := range fields {
+		unset = append(unset, bson.E{Key: field, Value: ""})
+	}
+	return fake.update(collectionName, bson.M{"_id": id}, bson.D{{Key: "$unset", Value: unset}}, newOperationOptions(opts), false)
+}
+
+// Delete deletes a document by ID only.
+func (fake *FakeClient) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return fake.delete(collectionName, bson.M{"_id": id}, 1)
+}
+
+// DeleteCustom deletes a document by a filter - bson.M{}, or bson.D{}
+func (fake *FakeClient) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return fake.delete(collectionName, filter, 1)
+}
+
+// DeleteMany deletes many documents - bson.M{}, or bson.D{}
+func (fake *FakeClient) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return fake.delete(collectionName, filter, -1)
+}
+
+// Get finds one document based on "_id"
+func (fake *FakeClient) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return fake.GetCustom(collectionName, bson.M{"_id": id}, opts...)
+}
+
+// GetCustom finds one document by a filter - bson.M{}, or bson.D{}
+func (fake *FakeClient) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	docs, err := fake.find(collectionName, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil), nil
+	}
+	return mongo.NewSingleResultFromDocument(docs[0], nil, nil), nil
+}
+
+// GetAll finds all documents by "_id".
+//
+// The 'result' parameter needs to be a pointer.
+func (fake *FakeClient) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return fake.GetAllCustom(collectionName, bson.M{"_id": id}, result, opts...)
+}
+
+// GetAllCustom finds all documents by filter - bson.M{}, or bson.D{}.
+//
+// The 'result' parameter needs to be a pointer.
+func (fake *FakeClient) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	docs, err := fake.find(collectionName, filter)
+	if err != nil {
+		return err
+	}
+	return decodeDocuments(docs, result)
+}
+
+// insert appends doc to the collection, adding an ObjectID "_id" when missing. It must be called with the lock held.
+func (fake *FakeClient) insert(collectionName string, doc bson.D) (interface{}, error) {
+	id, ok := lookupField(doc, "_id")
+	if !ok {
+		id = primitive.NewObjectID()
+		doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+	}
+	for _, existing := range fake.collections[collectionName] {
+		if existingID, _ := lookupField(existing, "_id"); equalValues(existingID, id) {
+			return nil, fmt.Errorf("E11000 duplicate key error collection: %s index: _id_ dup key: { _id: %v }", collectionName, id)
+		}
+	}
+	fake.collections[collectionName] = append(fake.collections[collectionName], doc)
+	return id, nil
+}
+
+// update applies the update document to the first, or every, document matching filter.
+func (fake *FakeClient) update(collectionName string, filter interface{}, update interface{}, o *operationOptions, many bool) (*mongo.UpdateResult, error) {
+	query, err := fakeQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	operators, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	result := &mongo.UpdateResult{}
+	docs := fake.collections[collectionName]
+	for i, doc := range docs {
+		if !matches(doc, query) {
+			continue
+		}
+		updated, err := applyUpdate(doc, operators)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = updated
+		result.MatchedCount++
+		if !equalValues(doc, updated) {
+			result.ModifiedCount++
+		}
+		if !many {
+			break
+		}
+	}
+
+	if result.MatchedCount > 0 || o.upsert == nil || !*o.upsert {
+		return result, nil
+	}
+	doc, err := applyUpdate(equalityFields(query), operators)
+	if err != nil {
+		return nil, err
+	}
+	id, err := fake.insert(collectionName, doc)
+	if err != nil {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Index: 0, Code: 11000, Message: err.Error()}}}
+	}
+	return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: id}, nil
+}
+
+// replace replaces the document with the given id by doc.
+func (fake *FakeClient) replace(collectionName string, id interface{}, doc bson.D, o *operationOptions) (*mongo.UpdateResult, error) {
+	doc = append(bson.D{{Key: "_id", Value: id}}, withoutField(doc, "_id")...)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	docs := fake.collections[collectionName]
+	for i, existing := range docs {
+		if existingID, _ := lookupField(existing, "_id"); !equalValues(existingID, id) {
+			continue
+		}
+		docs[i] = doc
+		modified := int64(0)
+		if !equalValues(existing, doc) {
+			modified = 1
+		}
+		return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: modified}, nil
+	}
+	if o.upsert == nil || !*o.upsert {
+		return &mongo.UpdateResult{}, nil
+	}
+	if _, err := fake.insert(collectionName, doc); err != nil {
+		return nil, err
+	}
+	return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: id}, nil
+}
+
+// fakeQuery converts filter to a query document, it fails when the query uses an operator matches does not support.
+func fakeQuery(filter interface{}) (bson.D, error) {
+	query, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+	if operator := unsupportedQueryOperator(query); operator != "" {
+		return nil, fmt.Errorf("mongo: fake does not support the query operator %s", operator)
+	}
+	return query, nil
+}
+
+// find returns copies of the documents matching filter.
+func (fake *FakeClient) find(collectionName string, filter interface{}) ([]bson.D, error) {
+	query, err := fakeQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	fake.mu.RLock()
+	defer fake.mu.RUnlock()
+	var docs []bson.D
+	for _, doc := range fake.collections[collectionName] {
+		if matches(doc, query) {
+			docs = append(docs, doc)
+		}
+	}
+	return copyDocuments(docs)
+}
+
+// delete removes up to limit documents matching filter, all of them if limit is negative.
+func (fake *FakeClient) delete(collectionName string, filter interface{}, limit int) (*mongo.DeleteResult, error) {
+	query, err := fakeQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var kept []bson.D
+	var deleted int64
+	for _, doc := range fake.collections[collectionName] {
+		if (limit < 0 || deleted < int64(limit)) && matches(doc, query) {
+			deleted++
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	fake.collections[collectionName] = kept
+	return &mongo.DeleteResult{DeletedCount: deleted}, nil
+}
+
+// copyDocuments returns deep copies of docs.
+func copyDocuments(docs []bson.D) ([]bson.D, error) {
+	copies := make([]bson.D, len(docs))
+	for i, doc := range docs {
+		docCopy, err := toDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		copies[i] = docCopy
+	}
+	return copies, nil
+}
+
+// decodeDocuments decodes docs into result, a pointer to a slice, like mongo.Cursor.All.
+func decodeDocuments(docs []bson.D, result interface{}) error {
+	items := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		items[i] = doc
+	}
+	cursor, err := mongo.NewCursorFromDocuments(items, nil, nil)
+	if err != nil {
+		return err
+	}
+	return cursor.All(context.Background(), result)
+}
+
+// matches reports whether doc matches every field of query.
+func matches(doc bson.D, query bson.D) bool {
+	for _, elem := range query {
+		switch elem.Key {
+		case "$and", "$or", "$nor":
+			if !matchLogical(doc, elem.Key, elem.Value) {
+				return false
+			}
+		default:
+			if !matchField(doc, elem.Key, elem.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchLogical evaluates the $and, $or and $nor operators, clauses is an array of queries.
+func matchLogical(doc bson.D, operator string, clauses interface{}) bool {
+	array, ok := clauses.(bson.A)
+	if !ok {
+		return false
+	}
+	for _, clause := range array {
+		query, ok := clause.(bson.D)
+		if !ok {
+			return false
+		}
+		matched := matches(doc, query)
+		switch {
+		case operator == "$and" && !matched:
+			return false
+		case operator == "$or" && matched:
+			return true
+		case operator == "$nor" && matched:
+			return false
+		}
+	}
+	return operator != "$or"
+}
+
+// matchField reports whether the value at path in doc matches condition, which is either a value, a regular
+// expression or an operator document like {"$ne": 1}.
+func matchField(doc bson.D, path string, condition interface{}) bool {
+	values := lookupPath(doc, strings.Split(path, "."))
+	if operators, ok := condition.(bson.D); ok && len(operators) > 0 && strings.HasPrefix(operators[0].Key, "$") {
+		for _, operator := range operators {
+			if operator.Key == "$options" {
+				continue
+			}
+			operand := operator.Value
+			if operator.Key == "$regex" {
+				options, _ := lookupField(operators, "$options")
+				operand = regexOperand(operand, options)
+			}
+			if !matchOperator(values, operator.Key, operand) {
+				return false
+			}
+		}
+		return true
+	}
+	if regex, ok := condition.(primitive.Regex); ok {
+		return matchRegex(values, regex)
+	}
+	return matchEqual(values, condition)
+}
+
+// queryOperators are the query operators understood by matchOperator, $and, $or and $nor are understood by matches.
+var queryOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$in": true, "$nin": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$exists": true, "$regex": true, "$options": true,
+}
+
+// supportsQuery reports whether matches can evaluate every operator of query.
+func supportsQuery(query bson.D) bool {
+	return unsupportedQueryOperator(query) == ""
+}
+
+// unsupportedQueryOperator returns the first operator of query matches cannot evaluate, or "" when there is none.
+func unsupportedQueryOperator(query bson.D) string {
+	for _, elem := range query {
+		switch elem.Key {
+		case "$and", "$or", "$nor":
+			clauses, ok := elem.Value.(bson.A)
+			if !ok {
+				return elem.Key
+			}
+			for _, clause := range clauses {
+				query, ok := clause.(bson.D)
+				if !ok {
+					return elem.Key
+				}
+				if operator := unsupportedQueryOperator(query); operator != "" {
+					return operator
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(elem.Key, "$") {
+			return elem.Key
+		}
+		if operators, ok := elem.Value.(bson.D); ok && len(operators) > 0 && strings.HasPrefix(operators[0].Key, "$") {
+			for _, operator := range operators {
+				if !queryOperators[operator.Key] {
+					return operator.Key
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// matchOperator evaluates a single query operator against the values found at a path.
+func matchOperator(values []interface{}, operator string, operand interface{}) bool {
+	switch operator {
+	case "$eq":
+		return matchEqual(values, operand)
+	case "$ne":
+		return !matchEqual(values, operand)
+	case "$in", "$nin":
+		candidates, _ := operand.(bson.A)
+		in := false
+		for _, candidate := range candidates {
+			if regex, ok := candidate.(primitive.Regex); ok {
+				in = matchRegex(values, regex)
+			} else {
+				in = matchEqual(values, candidate)
+			}
+			if in {
+				break
+			}
+		}
+		return in == (operator == "$in")
+	case "$gt", "$gte", "$lt", "$lte":
+		return matchCompare(values, operator, operand)
+	case "$exists":
+		exists := true
+		if flag, ok := operand.(bool); ok {
+			exists = flag
+		} else if n, ok := toFloat(operand); ok {
+			exists = n != 0
+		}
+		return (len(values) > 0) == exists
+	case "$regex":
+		regex, ok := operand.(primitive.Regex)
+		return ok && matchRegex(values, regex)
+	default:
+		return false
+	}
+}
+
+// matchCompare reports whether one of values, or an element of an array value, compares to operand as operator
+// requires. Like MongoDB, only values of the same type are compared.
+func matchCompare(values []interface{}, operator string, operand interface{}) bool {
+	compare := func(value interface{}) bool {
+		if typeRank(value) != typeRank(operand) {
+			return false
+		}
+		order := compareValues(value, operand)
+		switch operator {
+		case "$gt":
+			return order > 0
+		case "$gte":
+			return order >= 0
+		case "$lt":
+			return order < 0
+		default:
+			return order <= 0
+		}
+	}
+	for _, value := range values {
+		if compare(value) {
+			return true
+		}
+		if array, ok := value.(bson.A); ok {
+			for _, item := range array {
+				if compare(item) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// regexOperand returns the regular expression of a $regex operator, combining its pattern with the $options
+// operator.
+func regexOperand(pattern interface{}, options interface{}) interface{} {
+	flags, _ := options.(string)
+	switch v := pattern.(type) {
+	case string:
+		return primitive.Regex{Pattern: v, Options: flags}
+	case primitive.Regex:
+		if flags != "" {
+			v.Options = flags
+		}
+		return v
+	default:
+		return nil
+	}
+}
+
+// matchRegex reports whether one of values, or an element of an array value, is a string matching regex. The i, m
+// and s options are supported.
+func matchRegex(values []interface{}, regex primitive.Regex) bool {
+	flags := ""
+	for _, option := range regex.Options {
+		if strings.ContainsRune("ims", option) {
+			flags += string(option)
+		}
+	}
+	pattern := regex.Pattern
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	for _, value := range values {
+		if s, ok := value.(string); ok && compiled.MatchString(s) {
+			return true
+		}
+		if array, ok := value.(bson.A); ok {
+			for _, item := range array {
+				if s, ok := item.(string); ok && compiled.MatchString(s) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchEqual reports whether one of values equals want, or is an array containing want. A nil want matches a missing
+// field.
+func matchEqual(values []interface{}, want interface{}) bool {
+	if len(values) == 0 {
+		return want == nil
+	}
+	for _, value := range values {
+		if equalValues(value, want) {
+			return true
+		}
+		if array, ok := value.(bson.A); ok {
+			for _, item := range array {
+				if equalValues(item, want) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// lookupPath returns the values found at path in doc, descending into arrays of documents.
+func lookupPath(doc interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		return []interface{}{doc}
+	}
+	switch v := doc.(type) {
+	case bson.D:
+		value, ok := lookupField(v, path[0])
+		if !ok {
+			return nil
+		}
+		return lookupPath(value, path[1:])
+	case bson.A:
+		var values []interface{}
+		for _, item := range v {
+			if _, ok := item.(bson.D); ok {
+				values = append(values, lookupPath(item, path)...)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// equalValues compares two BSON values, numbers of different types are equal when their values are.
+func equalValues(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case bson.D:
+		y, ok := b.(bson.D)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i].Key != y[i].Key || !equalValues(x[i].Value, y[i].Value) {
+				return false
+			}
+		}
+		return true
+	case bson.A:
+		y, ok := b.(bson.A)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues orders two BSON values like MongoDB sorts them: values of different types by the BSON comparison
+// order, then by value. It returns -1, 0 or 1.
+func compareValues(a, b interface{}) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		return compareInts(rankA, rankB)
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case primitive.ObjectID:
+		y := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:])
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0
+		}
+		if y {
+			return -1
+		}
+		return 1
+	case time.Time, primitive.DateTime:
+		return toTime(a).Compare(toTime(b))
+	case bson.D:
+		y := b.(bson.D)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := strings.Compare(x[i].Key, y[i].Key); c != 0 {
+				return c
+			}
+			if c := compareValues(x[i].Value, y[i].Value); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(x), len(y))
+	case bson.A:
+		y := b.(bson.A)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(x), len(y))
+	}
+	if x, ok := toFloat(a); ok {
+		y, _ := toFloat(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// typeRank returns the position of the type of v in the BSON comparison order.
+func typeRank(v interface{}) int {
+	if _, ok := toFloat(v); ok {
+		return 3
+	}
+	switch v.(type) {
+	case primitive.MinKey:
+		return 1
+	case nil, primitive.Null, primitive.Undefined:
+		return 2
+	case string:
+		return 4
+	case bson.D, bson.M:
+		return 5
+	case bson.A:
+		return 6
+	case primitive.Binary:
+		return 7
+	case primitive.ObjectID:
+		return 8
+	case bool:
+		return 9
+	case time.Time, primitive.DateTime:
+		return 10
+	case primitive.Timestamp:
+		return 11
+	case primitive.Regex:
+		return 12
+	case primitive.MaxKey:
+		return 13
+	default:
+		return 14
+	}
+}
+
+// toTime converts BSON dates to time.Time.
+func toTime(v interface{}) time.Time {
+	if date, ok := v.(primitive.DateTime); ok {
+		return date.Time()
+	}
+	t, _ := v.(time.Time)
+	return t
+}
+
+// compareInts returns -1, 0 or 1.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toFloat converts BSON numbers to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// updateOperators are the update operators understood by applyUpdate.
+var updateOperators = map[string]bool{"$set": true, "$unset": true, "$inc": true, "$push": true, "$pull": true, "$addToSet": true}
+
+// applyUpdate returns a copy of doc with the update operators applied. $set, $unset, $inc, $push, $pull and $addToSet
+// are supported.
+func applyUpdate(doc bson.D, update bson.D) (bson.D, error) {
+	updated, err := copyDocuments([]bson.D{doc})
+	if err != nil {
+		return nil, err
+	}
+	result := updated[0]
+	for _, operator := range update {
+		fields, ok := operator.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("mongo: fake update operator %s needs a document", operator.Key)
+		}
+		for _, field := range fields {
+			path := strings.Split(field.Key, ".")
+			current := lookupPath(result, path)
+			switch operator.Key {
+			case "$set":
+				result = setPath(result, path, field.Value)
+			case "$unset":
+				result = unsetPath(result, path)
+			case "$inc":
+				value := field.Value
+				if len(current) > 0 {
+					value = addNumbers(current[0], field.Value)
+				}
+				result = setPath(result, path, value)
+			case "$push", "$addToSet":
+				array, _ := firstValue(current).(bson.A)
+				array = append(bson.A{}, array...)
+				for _, item := range eachValues(field.Value) {
+					if operator.Key == "$addToSet" && matchEqual([]interface{}{array}, item) {
+						continue
+					}
+					array = append(array, item)
+				}
+				result = setPath(result, path, array)
+			case "$pull":
+				array, _ := firstValue(current).(bson.A)
+				kept := bson.A{}
+				for _, item := range array {
+					if !matchPull(item, field.Value) {
+						kept = append(kept, item)
+					}
+				}
+				result = setPath(result, path, kept)
+			default:
+				return nil, fmt.Errorf("mongo: fake does not support the update operator %s", operator.Key)
+			}
+		}
+	}
+	return result, nil
+}
+
+// eachValues returns the values of a {"$each": [...]} modifier, or value itself.
+func eachValues(value interface{}) []interface{} {
+	if modifier, ok := value.(bson.D); ok && len(modifier) > 0 && modifier[0].Key == "$each" {
+		if values, ok := modifier[0].Value.(bson.A); ok {
+			return values
+		}
+	}
+	return []interface{}{value}
+}
+
+// matchPull reports whether an array element matches a $pull condition.
+func matchPull(item interface{}, condition interface{}) bool {
+	if operators, ok := condition.(bson.D); ok && len(operators) > 0 {
+		if strings.HasPrefix(operators[0].Key, "$") {
+			for _, operator := range operators {
+				if !matchOperator([]interface{}{item}, operator.Key, operator.Value) {
+					return false
+				}
+			}
+			return true
+		}
+		if doc, ok := item.(bson.D); ok {
+			return matches(doc, operators)
+		}
+	}
+	return equalValues(item, condition)
+}
+
+// addNumbers adds two BSON numbers like $inc, int32 values stay int32 unless they overflow.
+func addNumbers(a, b interface{}) interface{} {
+	if x, ok := a.(int32); ok {
+		if y, ok := b.(int32); ok {
+			if sum := int64(x) + int64(y); sum == int64(int32(sum)) {
+				return int32(sum)
+			}
+		}
+	}
+	x, xInt := a.(int64)
+	if v, ok := a.(int32); ok {
+		x, xInt = int64(v), true
+	}
+	y, yInt := b.(int64)
+	if v, ok := b.(int32); ok {
+		y, yInt = int64(v), true
+	}
+	if v, ok := b.(int); ok {
+		y, yInt = int64(v), true
+	}
+	if xInt && yInt {
+		return x + y
+	}
+	fa, _ := toFloat(a)
+	fb, _ := toFloat(b)
+	return fa + fb
+}
+
+// firstValue returns the first of values, or nil.
+func firstValue(values []interface{}) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// setPath assigns value at path in doc, creating intermediate documents.
+func setPath(doc bson.D, path []string, value interface{}) bson.D {
+	for i, elem := range doc {
+		if elem.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			doc[i].Value = value
+		} else {
+			nested, _ := elem.Value.(bson.D)
+			doc[i].Value = setPath(append(bson.D{}, nested...), path[1:], value)
+		}
+		return doc
+	}
+	if len(path) == 1 {
+		return append(doc, bson.E{Key: path[0], Value: value})
+	}
+	return append(doc, bson.E{Key: path[0], Value: setPath(bson.D{}, path[1:], value)})
+}
+
+// unsetPath removes the field at path from doc.
+func unsetPath(doc bson.D, path []string) bson.D {
+	for i, elem := range doc {
+		if elem.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return append(doc[:i:i], doc[i+1:]...)
+		}
+		if nested, ok := elem.Value.(bson.D); ok {
+			doc[i].Value = unsetPath(append(bson.D{}, nested...), path[1:])
+		}
+		return doc
+	}
+	return doc
+}
+
+// equalityFields returns the plain equality conditions of query, used as the base of an upserted document.
+func equalityFields(query bson.D) bson.D {
+	doc := bson.D{}
+	for _, elem := range query {
+		if strings.HasPrefix(elem.Key, "$") {
+			continue
+		}
+		if operators, ok := elem.Value.(bson.D); ok && len(operators) > 0 && strings.HasPrefix(operators[0].Key, "$") {
+			continue
+		}
+		doc = setPath(doc, strings.Split(elem.Key, "."), elem.Value)
+	}
+	return doc
+}
@@ -0,0 +1,261 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeUser struct {
+	ID      string   `bson:"_id"`
+	Name    string   `bson:"name"`
+	Age     int      `bson:"age"`
+	Tags    []string `bson:"tags,omitempty"`
+	Address struct {
+		City string `bson:"city"`
+	} `bson:"address"`
+}
+
+func newFakeUsers(t *testing.T) *FakeClient {
+	fake := NewFakeClient()
+	alice := fakeUser{ID: "1", Name: "Alice", Age: 30, Tags: []string{"admin", "staff"}}
+	alice.Address.City = "Auckland"
+	bob := fakeUser{ID: "2", Name: "Bob", Age: 25, Tags: []string{"staff"}}
+	bob.Address.City = "Wellington"
+	if _, err := fake.AddMany("users", []interface{}{alice, bob}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	return fake
+}
+
+func TestFakeClient_Get(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	var user fakeUser
+	result, err := fake.Get("users", "1")
+	if err != nil {
+		t.Fatalf("Unable to get data. %s", err)
+	}
+	if err := result.Decode(&user); err != nil || user.Name != "Alice" {
+		t.Errorf("Expected Alice, got %v (%v)", user, err)
+	}
+
+	result, _ = fake.Get("users", "3")
+	if err := result.Decode(&user); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("Expected ErrNoDocuments, got %v", err)
+	}
+}
+
+func TestFakeClient_GetAllCustom(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	tests := []struct {
+		name   string
+		filter interface{}
+		want   int
+	}{
+		{"all", bson.M{}, 2},
+		{"equal", bson.M{"name": "Bob"}, 1},
+		{"number types", bson.M{"age": int64(30)}, 1},
+		{"dotted path", bson.M{"address.city": "Wellington"}, 1},
+		{"array element", bson.M{"tags": "staff"}, 2},
+		{"eq", bson.M{"name": bson.M{"$eq": "Alice"}}, 1},
+		{"ne", bson.M{"tags": bson.M{"$ne": "admin"}}, 1},
+		{"missing", bson.M{"email": nil}, 2},
+		{"no match", bson.D{{Key: "name", Value: "Alice"}, {Key: "age", Value: 25}}, 0},
+		{"in", bson.M{"name": bson.M{"$in": bson.A{"Bob", "Carol"}}}, 1},
+		{"in array", bson.M{"tags": bson.M{"$in": bson.A{"admin"}}}, 1},
+		{"nin", bson.M{"tags": bson.M{"$nin": bson.A{"admin"}}}, 1},
+		{"gt", bson.M{"age": bson.M{"$gt": 25}}, 1},
+		{"range", bson.M{"age": bson.M{"$gte": 25, "$lt": 30}}, 1},
+		{"comparison type", bson.M{"age": bson.M{"$gt": "1"}}, 0},
+		{"exists", bson.M{"tags": bson.M{"$exists": true}}, 2},
+		{"not exists", bson.M{"email": bson.M{"$exists": false}}, 2},
+		{"regex", bson.M{"name": bson.M{"$regex": "^al", "$options": "i"}}, 1},
+		{"regex value", bson.M{"address.city": primitive.Regex{Pattern: "ton$"}}, 1},
+		{"regex in", bson.M{"name": bson.M{"$in": bson.A{primitive.Regex{Pattern: "^B"}}}}, 1},
+		{"and", bson.M{"$and": bson.A{bson.M{"tags": "staff"}, bson.M{"age": bson.M{"$lt": 30}}}}, 1},
+		{"or", bson.M{"$or": bson.A{bson.M{"name": "Alice"}, bson.M{"name": "Bob"}}}, 2},
+		{"nor", bson.M{"$nor": bson.A{bson.M{"name": "Alice"}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []fakeUser
+			if err := fake.GetAllCustom("users", tt.filter, &users); err != nil {
+				t.Fatalf("Unable to get data. %s", err)
+			}
+			if len(users) != tt.want {
+				t.Errorf("Expected %d users, got %d", tt.want, len(users))
+			}
+		})
+	}
+}
+
+func TestFakeClient_unsupportedOperator(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	var users []fakeUser
+	err := fake.GetAllCustom("users", bson.M{"$or": bson.A{bson.M{"tags": bson.M{"$size": 2}}}}, &users)
+	if err == nil {
+		t.Errorf("Expected an error for $size, got %v", users)
+	}
+	if _, err := fake.DeleteMany("users", bson.M{"$where": "true"}); err == nil {
+		t.Error("Expected an error for $where")
+	}
+	if count := len(fake.collections["users"]); count != 2 {
+		t.Errorf("Expected no document to be deleted, got %d left", count)
+	}
+}
+
+func TestFakeClient_Add_duplicate(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	_, err := fake.Add("users", fakeUser{ID: "1"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("Expected a duplicate key error, got %v", err)
+	}
+
+	result, err := fake.AddMany("users", []interface{}{fakeUser{ID: "1"}, fakeUser{ID: "3"}}, Unordered())
+	if !mongo.IsDuplicateKeyError(err) || len(result.InsertedIDs) != 1 {
+		t.Errorf("Expected one insert and a duplicate key error, got %v and %v", result, err)
+	}
+
+	inserted, err := fake.Add("users", bson.M{"name": "Carol"})
+	if err != nil || inserted.InsertedID == nil {
+		t.Errorf("Expected an _id to be generated, got %v", err)
+	}
+}
+
+func TestFakeClient_UpdateCustom(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	updated, err := fake.Update("users", "1", bson.M{"name": "Alicia", "address.city": "Hamilton"})
+	if err != nil || updated.MatchedCount != 1 || updated.ModifiedCount != 1 {
+		t.Fatalf("Unexpected update result %v, %v", updated, err)
+	}
+	var user fakeUser
+	result, _ := fake.Get("users", "1")
+	_ = result.Decode(&user)
+	if user.Name != "Alicia" || user.Address.City != "Hamilton" || user.Age != 30 {
+		t.Errorf("Unexpected document %v", user)
+	}
+
+	upserted, err := fake.UpdateCustom("users", bson.M{"_id": "9"}, bson.M{"name": "Dan"}, Upsert())
+	if err != nil || upserted.UpsertedID != "9" {
+		t.Errorf("Expected document to be upserted, got %v, %v", upserted, err)
+	}
+}
+
+func TestFakeClient_DeleteMany(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	deleted, err := fake.DeleteMany("users", bson.M{"tags": "staff"})
+	if err != nil || deleted.DeletedCount != 2 {
+		t.Errorf("Expected 2 deleted documents, got %v, %v", deleted, err)
+	}
+	deleted, _ = fake.Delete("users", "1")
+	if deleted.DeletedCount != 0 {
+		t.Errorf("Expected nothing to delete, got %d", deleted.DeletedCount)
+	}
+}
+
+func TestFakeClient_UpdateMany(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	updated, err := fake.UpdateMany("users", bson.M{"tags": "staff"}, bson.M{"active": true})
+	if err != nil || updated.MatchedCount != 2 || updated.ModifiedCount != 2 {
+		t.Errorf("Unexpected update result %v, %v", updated, err)
+	}
+}
+
+func TestFakeClient_Replace(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	replaced, err := fake.Replace("users", "1", bson.M{"name": "Alicia"})
+	if err != nil || replaced.MatchedCount != 1 {
+		t.Fatalf("Unexpected replace result %v, %v", replaced, err)
+	}
+	var doc bson.M
+	result, _ := fake.Get("users", "1")
+	_ = result.Decode(&doc)
+	if doc["name"] != "Alicia" || doc["age"] != nil {
+		t.Errorf("Expected the whole document to be replaced, got %v", doc)
+	}
+}
+
+func TestFakeClient_Save(t *testing.T) {
+	fake := NewFakeClient()
+
+	saved, err := fake.Save("users", bson.M{"name": "Carol"})
+	if err != nil || saved.UpsertedID == nil {
+		t.Fatalf("Expected document to be inserted, got %v, %v", saved, err)
+	}
+	saved, err = fake.Save("users", bson.M{"_id": saved.UpsertedID, "name": "Caroline"})
+	if err != nil || saved.MatchedCount != 1 || saved.ModifiedCount != 1 {
+		t.Errorf("Expected document to be replaced, got %v, %v", saved, err)
+	}
+}
+
+func TestFakeClient_atomicOperators(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	if _, err := fake.Increment("users", "1", "age", 2); err != nil {
+		t.Fatalf("Unable to increment. %s", err)
+	}
+	if _, err := fake.Increment("users", "1", "visits", 1); err != nil {
+		t.Fatalf("Unable to increment. %s", err)
+	}
+	if _, err := fake.Push("users", "1", "tags", "owner"); err != nil {
+		t.Fatalf("Unable to push. %s", err)
+	}
+	if _, err := fake.AddToSet("users", "1", "tags", "staff"); err != nil {
+		t.Fatalf("Unable to add to set. %s", err)
+	}
+	if _, err := fake.Pull("users", "1", "tags", "admin"); err != nil {
+		t.Fatalf("Unable to pull. %s", err)
+	}
+	if _, err := fake.Unset("users", "1", []string{"address.city"}); err != nil {
+		t.Fatalf("Unable to unset. %s", err)
+	}
+
+	var doc bson.M
+	result, _ := fake.Get("users", "1")
+	_ = result.Decode(&doc)
+	if doc["age"] != int32(32) || doc["visits"] != int32(1) {
+		t.Errorf("Unexpected counters %v, %v", doc["age"], doc["visits"])
+	}
+	tags := doc["tags"].(bson.A)
+	if len(tags) != 2 || tags[0] != "staff" || tags[1] != "owner" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+	if _, ok := doc["address"].(bson.M)["city"]; ok {
+		t.Errorf("Expected address.city to be removed, got %v", doc["address"])
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want int
+	}{
+		{int32(1), 2.5, -1},
+		{int64(3), int32(3), 0},
+		{"b", "a", 1},
+		{nil, 0, -1},
+		{10, "1", -1},
+		{"z", primitive.NewObjectID(), -1},
+		{false, true, -1},
+		{bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 1}}, -1},
+		{bson.A{1, 3}, bson.A{1, 2}, 1},
+		{primitive.NewDateTimeFromTime(time.Unix(10, 0)), time.Unix(20, 0), -1},
+	}
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareValues(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
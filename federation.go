@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedByFederation is returned by a Client created with WithFederation for an operation or an aggregation
+// stage Atlas Data Federation does not support.
+var ErrUnsupportedByFederation = errors.New("mongo: not supported by Atlas Data Federation")
+
+// federatedOperations are the operations a federated database instance supports, they only read.
+var federatedOperations = map[string]bool{
+	"Get":             true,
+	"GetCustom":       true,
+	"GetAll":          true,
+	"GetAllCustom":    true,
+	"Aggregate":       true,
+	"FindIter":        true,
+	"ForEachBatch":    true,
+	"ScanParallel":    true,
+	"DiffCollections": true,
+	"Export":          true,
+	"Histogram":       true,
+	"Percentiles":     true,
+	"RollupByTime":    true,
+	"Measurements":    true,
+	"Downsample":      true,
+	"TopK":            true,
+	"ListCollections": true,
+	"HealthCheck":     true,
+	"History":         true,
+}
+
+// federatedUnsupportedStages are aggregation stages a federated database instance refuses.
+var federatedUnsupportedStages = map[string]bool{
+	"$search":            true,
+	"$searchMeta":        true,
+	"$vectorSearch":      true,
+	"$changeStream":      true,
+	"$currentOp":         true,
+	"$indexStats":        true,
+	"$planCacheStats":    true,
+	"$listSessions":      true,
+	"$listLocalSessions": true,
+}
+
+// WithFederation marks the Client as connected to an Atlas Data Federation instance, which queries Online Archives,
+// cloud object storage and Atlas clusters together. It is meant for analytical reads, often as the read Client of a
+// SplitClient:
+//
+//	archive := mongo.NewMongoClientDefault(federationURL, "sales", mongo.WithFederation())
+//	store := mongo.NewSplitClient(primary, archive)
+//
+// Writes, index and collection management, sessions and Atlas Search stages are refused with
+// ErrUnsupportedByFederation before anything is sent to the server.
+//
+// Reads of a federated instance are not consistent with the cluster: documents are moved to an Online Archive
+// asynchronously, so a recently archived document may be missing or read twice when querying the cluster and the
+// archive together, and files of object storage are read as they are when queried. There is no snapshot across
+// sources, and queries scan the underlying data without indexes, prefer filters on the partition fields of the
+// archive.
+func WithFederation() ClientOption {
+	return func(client *Client) {
+		client.federated = true
+	}
+}
+
+// checkFederation returns an error wrapping ErrUnsupportedByFederation when the Client is federated and op is not a
+// read.
+func (connectionDetails *Client) checkFederation(op operation) error {
+	if !connectionDetails.federated || federatedOperations[op.name] {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedByFederation, op.name)
+}
+
+// checkFederatedPipeline returns an error wrapping ErrUnsupportedByFederation when the Client is federated and
+// pipeline has a stage it does not support.
+func (connectionDetails *Client) checkFederatedPipeline(pipeline interface{}) error {
+	if !connectionDetails.federated {
+		return nil
+	}
+	stages := reflect.ValueOf(pipeline)
+	if stages.Kind() != reflect.Slice {
+		return nil
+	}
+	for i := 0; i < stages.Len(); i++ {
+		stage, err := toDocument(stages.Index(i).Interface())
+		if err != nil || len(stage) == 0 {
+			continue
+		}
+		if federatedUnsupportedStages[stage[0].Key] {
+			return fmt.Errorf("%w: %s stage", ErrUnsupportedByFederation, stage[0].Key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClient_WithFederation(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithFederation())
+
+	if _, err := client.Add("orders", bson.M{"total": 10}); !errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("Add() = %v, want ErrUnsupportedByFederation", err)
+	}
+	if err := client.DropCollection("orders"); !errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("DropCollection() = %v, want ErrUnsupportedByFederation", err)
+	}
+	if err := client.WithSession(func(*Client) error { return nil }); !errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("WithSession() = %v, want ErrUnsupportedByFederation", err)
+	}
+
+	search := mongo.Pipeline{{{Key: "$search", Value: bson.D{{Key: "text", Value: bson.D{}}}}}}
+	var result []bson.M
+	if err := client.Aggregate("orders", search, &result); !errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("Aggregate($search) = %v, want ErrUnsupportedByFederation", err)
+	}
+
+	// reads are sent to the server, which is unreachable here
+	if err := client.GetAllCustom("orders", bson.M{}, &result); err == nil || errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("GetAllCustom() = %v, want a connection error", err)
+	}
+}
+
+func TestClient_checkFederatedPipeline(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	if err := client.checkFederatedPipeline(bson.A{bson.M{"$search": bson.M{}}}); err != nil {
+		t.Errorf("Expected pipelines to be accepted without federation, got %v", err)
+	}
+	client = NewMongoClientDefault("mongodb://localhost:27017", "test", WithFederation())
+	if err := client.checkFederatedPipeline([]bson.D{{{Key: "$match", Value: bson.D{}}}}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := client.checkFederatedPipeline(bson.A{bson.M{"$vectorSearch": bson.M{}}}); !errors.Is(err, ErrUnsupportedByFederation) {
+		t.Errorf("checkFederatedPipeline() = %v, want ErrUnsupportedByFederation", err)
+	}
+}
@@ -0,0 +1,247 @@
+package mongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FileStore is a Store persisting every collection to a JSON file of a directory, for development environments
+// without MongoDB. It is not meant for production.
+//
+// Documents are kept in memory and queried like FakeClient does, with the same filter and update support. Each write
+// rewrites the file of its collection, named after it with a ".json" extension, as a JSON array of canonical extended
+// JSON documents so types like ObjectIDs and dates are kept. The files are read once by NewFileStore, changes made to
+// them while the FileStore is open are not seen and are overwritten by the next write to the collection.
+type FileStore struct {
+	mu   sync.Mutex
+	dir  string
+	fake *FakeClient
+}
+
+// NewFileStore opens a FileStore in dir, creating the directory when it does not exist and loading the collections
+// of its ".json" files.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	store := &FileStore{dir: dir, fake: NewFakeClient()}
+	for _, file := range files {
+		docs, err := readCollectionFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("mongo: unable to read %s: %w", file, err)
+		}
+		store.fake.collections[strings.TrimSuffix(filepath.Base(file), ".json")] = docs
+	}
+	return store, nil
+}
+
+// Add can be used to add document to the store
+func (store *FileStore) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	return persisted(store, collectionName, func() (*mongo.InsertOneResult, error) {
+		return store.fake.Add(collectionName, data, opts...)
+	})
+}
+
+// AddMany can be used to add multiple documents to the store
+func (store *FileStore) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	return persisted(store, collectionName, func() (*mongo.InsertManyResult, error) {
+		return store.fake.AddMany(collectionName, data, opts...)
+	})
+}
+
+// Update a document by id.
+func (store *FileStore) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Update(collectionName, id, data, opts...)
+	})
+}
+
+// UpdateCustom updates the first document matching filter.
+func (store *FileStore) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.UpdateCustom(collectionName, filter, data, opts...)
+	})
+}
+
+// UpdateMany updates every document matching filter.
+func (store *FileStore) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.UpdateMany(collectionName, filter, data, opts...)
+	})
+}
+
+// Replace a document by id.
+func (store *FileStore) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Replace(collectionName, id, data, opts...)
+	})
+}
+
+// Save replaces the document with the same "_id", inserting it when it does not exist.
+func (store *FileStore) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Save(collectionName, data, opts...)
+	})
+}
+
+// Increment adds delta to field of the document with the given id.
+func (store *FileStore) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Increment(collectionName, id, field, delta, opts...)
+	})
+}
+
+// Push appends value to the array field of the document with the given id.
+func (store *FileStore) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Push(collectionName, id, field, value, opts...)
+	})
+}
+
+// Pull removes value from the array field of the document with the given id.
+func (store *FileStore) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Pull(collectionName, id, field, value, opts...)
+	})
+}
+
+// AddToSet appends value to the array field of the document with the given id unless it is already present.
+func (store *FileStore) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.AddToSet(collectionName, id, field, value, opts...)
+	})
+}
+
+// Unset removes fields from the document with the given id.
+func (store *FileStore) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	return persisted(store, collectionName, func() (*mongo.UpdateResult, error) {
+		return store.fake.Unset(collectionName, id, fields, opts...)
+	})
+}
+
+// Delete a document by id.
+func (store *FileStore) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return persisted(store, collectionName, func() (*mongo.DeleteResult, error) {
+		return store.fake.Delete(collectionName, id, opts...)
+	})
+}
+
+// DeleteCustom deletes the first document matching filter.
+func (store *FileStore) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return persisted(store, collectionName, func() (*mongo.DeleteResult, error) {
+		return store.fake.DeleteCustom(collectionName, filter, opts...)
+	})
+}
+
+// DeleteMany deletes every document matching filter.
+func (store *FileStore) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return persisted(store, collectionName, func() (*mongo.DeleteResult, error) {
+		return store.fake.DeleteMany(collectionName, filter, opts...)
+	})
+}
+
+// Get finds a document by id.
+func (store *FileStore) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return store.fake.Get(collectionName, id, opts...)
+}
+
+// GetCustom finds the first document matching filter.
+func (store *FileStore) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	return store.fake.GetCustom(collectionName, filter, opts...)
+}
+
+// GetAll finds all documents by id.
+//
+// The 'result' parameter needs to be a pointer.
+func (store *FileStore) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return store.fake.GetAll(collectionName, id, result, opts...)
+}
+
+// GetAllCustom finds all documents by filter - bson.M{}, or bson.D{}.
+//
+// The 'result' parameter needs to be a pointer.
+func (store *FileStore) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	return store.fake.GetAllCustom(collectionName, filter, result, opts...)
+}
+
+// persisted runs the write fn and writes the file of collectionName. The file is also written when fn fails, as an
+// unordered AddMany can fail after inserting documents.
+func persisted[T any](store *FileStore, collectionName string, fn func() (T, error)) (T, error) {
+	if strings.ContainsAny(collectionName, `/\`) || collectionName == "" || collectionName == "." || collectionName == ".." {
+		var zero T
+		return zero, fmt.Errorf("mongo: collection name %q cannot be used as a file name", collectionName)
+	}
+	result, err := fn()
+	if persistErr := store.persist(collectionName); persistErr != nil {
+		return result, persistErr
+	}
+	return result, err
+}
+
+// persist writes the documents of collectionName to its file, through a temporary file so it is never left partly
+// written.
+func (store *FileStore) persist(collectionName string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.fake.mu.RLock()
+	docs := store.fake.collections[collectionName]
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	var err error
+	for i, doc := range docs {
+		var line []byte
+		if line, err = bson.MarshalExtJSON(doc, true, false); err != nil {
+			break
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		buf.Write(line)
+	}
+	store.fake.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	buf.WriteString("\n]\n")
+
+	file := filepath.Join(store.dir, collectionName+".json")
+	if err := os.WriteFile(file+".tmp", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file+".tmp", file)
+}
+
+// readCollectionFile reads the documents of a file written by FileStore.
+func readCollectionFile(file string) ([]bson.D, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, err
+	}
+	docs := make([]bson.D, len(items))
+	for i, item := range items {
+		if err := bson.UnmarshalExtJSON(item, true, &docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
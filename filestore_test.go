@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	id := primitive.NewObjectID()
+	if _, err := store.Add("users", bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "Akshay"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := store.AddMany("users", []interface{}{data{ID: "1", Name: "Raj"}, data{ID: "2", Name: "Sam"}}); err != nil {
+		t.Fatalf("AddMany() error = %v", err)
+	}
+	if _, err := store.Update("users", "1", bson.M{"name": "Raj Kumar"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := store.Delete("users", "2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	var docs []bson.M
+	if err := reopened.GetAllCustom("users", bson.M{}, &docs); err != nil {
+		t.Fatalf("GetAllCustom() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents, got %v", docs)
+	}
+	if docs[0]["_id"] != id {
+		t.Errorf("Expected the ObjectID to be kept, got %T %v", docs[0]["_id"], docs[0]["_id"])
+	}
+	if docs[1]["name"] != "Raj Kumar" {
+		t.Errorf("Expected the update to be kept, got %v", docs[1])
+	}
+}
+
+func TestFileStore_invalidCollection(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	if _, err := store.Add("../users", data{ID: "1"}); err == nil {
+		t.Errorf("Expected an error for a collection name with a path separator")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "users.json")); err == nil {
+		t.Errorf("Expected no file to be written outside the directory")
+	}
+}
@@ -0,0 +1,161 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Filter is a query filter built with F, And and Or. It can be used anywhere a bson.M{} filter is accepted.
+//
+//	client.GetAllCustom("users", mongo.F("age").Gt(18).And(mongo.F("name").Regex("^Ak")), &users)
+//
+// The zero Filter matches every document.
+type Filter struct {
+	doc bson.D
+}
+
+// Field builds conditions on a single field, see F.
+type Field struct {
+	name string
+}
+
+// F starts a condition on the field name, dotted paths like "address.city" are allowed.
+func F(name string) Field {
+	return Field{name: name}
+}
+
+func (field Field) condition(operator string, value interface{}) Filter {
+	return Filter{doc: bson.D{{Key: field.name, Value: bson.D{{Key: operator, Value: value}}}}}
+}
+
+// Eq matches documents where the field equals value.
+func (field Field) Eq(value interface{}) Filter {
+	return Filter{doc: bson.D{{Key: field.name, Value: value}}}
+}
+
+// Ne matches documents where the field does not equal value.
+func (field Field) Ne(value interface{}) Filter {
+	return field.condition("$ne", value)
+}
+
+// Gt matches documents where the field is greater than value.
+func (field Field) Gt(value interface{}) Filter {
+	return field.condition("$gt", value)
+}
+
+// Gte matches documents where the field is greater than or equal to value.
+func (field Field) Gte(value interface{}) Filter {
+	return field.condition("$gte", value)
+}
+
+// Lt matches documents where the field is less than value.
+func (field Field) Lt(value interface{}) Filter {
+	return field.condition("$lt", value)
+}
+
+// Lte matches documents where the field is less than or equal to value.
+func (field Field) Lte(value interface{}) Filter {
+	return field.condition("$lte", value)
+}
+
+// Between matches documents where the field is greater than or equal to from and less than to.
+func (field Field) Between(from, to interface{}) Filter {
+	return Filter{doc: bson.D{{Key: field.name, Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}}}}
+}
+
+// In matches documents where the field equals one of values.
+func (field Field) In(values ...interface{}) Filter {
+	return field.condition("$in", bson.A(values))
+}
+
+// Nin matches documents where the field equals none of values.
+func (field Field) Nin(values ...interface{}) Filter {
+	return field.condition("$nin", bson.A(values))
+}
+
+// Exists matches documents that have, or do not have, the field.
+func (field Field) Exists(exists bool) Filter {
+	return field.condition("$exists", exists)
+}
+
+// Regex matches documents where the field matches the regular expression pattern, options are the MongoDB regex
+// options like "i".
+func (field Field) Regex(pattern string, options ...string) Filter {
+	regex := primitive.Regex{Pattern: pattern}
+	for _, option := range options {
+		regex.Options += option
+	}
+	return field.condition("$regex", regex)
+}
+
+// Size matches documents where the field is an array of length size.
+func (field Field) Size(size int) Filter {
+	return field.condition("$size", size)
+}
+
+// ElemMatch matches documents where the field is an array with at least one element matching filter.
+func (field Field) ElemMatch(filter Filter) Filter {
+	return field.condition("$elemMatch", filter.BSON())
+}
+
+// And returns a Filter matching documents that match f and every one of filters.
+func (f Filter) And(filters ...Filter) Filter {
+	return And(append([]Filter{f}, filters...)...)
+}
+
+// Or returns a Filter matching documents that match f or any one of filters.
+func (f Filter) Or(filters ...Filter) Filter {
+	return Or(append([]Filter{f}, filters...)...)
+}
+
+// And returns a Filter matching documents that match every one of filters.
+func And(filters ...Filter) Filter {
+	return combine("$and", filters)
+}
+
+// Or returns a Filter matching documents that match any one of filters.
+func Or(filters ...Filter) Filter {
+	return combine("$or", filters)
+}
+
+// Nor returns a Filter matching documents that match none of filters.
+func Nor(filters ...Filter) Filter {
+	return combine("$nor", filters)
+}
+
+// combine joins filters with operator, flattening filters already joined by the same operator and skipping empty
+// ones.
+func combine(operator string, filters []Filter) Filter {
+	var clauses bson.A
+	for _, filter := range filters {
+		if len(filter.doc) == 0 {
+			continue
+		}
+		if len(filter.doc) == 1 && filter.doc[0].Key == operator {
+			clauses = append(clauses, filter.doc[0].Value.(bson.A)...)
+			continue
+		}
+		clauses = append(clauses, filter.doc)
+	}
+	switch {
+	case len(clauses) == 0:
+		return Filter{}
+	case len(clauses) == 1 && operator != "$nor":
+		return Filter{doc: clauses[0].(bson.D)}
+	default:
+		return Filter{doc: bson.D{{Key: operator, Value: clauses}}}
+	}
+}
+
+// BSON returns the filter document.
+func (f Filter) BSON() bson.D {
+	if f.doc == nil {
+		return bson.D{}
+	}
+	return f.doc
+}
+
+// MarshalBSON lets a Filter be used as a filter document.
+func (f Filter) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(f.BSON())
+}
@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFilter_BSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bson.D
+	}{
+		{"zero", Filter{}, bson.D{}},
+		{"eq", F("name").Eq("Akshay"), bson.D{{Key: "name", Value: "Akshay"}}},
+		{"gt", F("age").Gt(18), bson.D{{Key: "age", Value: bson.D{{Key: "$gt", Value: 18}}}}},
+		{"between", F("age").Between(18, 65), bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: 18}, {Key: "$lt", Value: 65}}}}},
+		{"in", F("tags").In("a", "b"), bson.D{{Key: "tags", Value: bson.D{{Key: "$in", Value: bson.A{"a", "b"}}}}}},
+		{"and", F("age").Gt(18).And(F("name").Regex("^Ak", "i")), bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "age", Value: bson.D{{Key: "$gt", Value: 18}}}},
+			bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: primitive.Regex{Pattern: "^Ak", Options: "i"}}}}},
+		}}}},
+		{"flatten", F("a").Eq(1).Or(F("b").Eq(2)).Or(F("c").Eq(3)), bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "b", Value: 2}}, bson.D{{Key: "c", Value: 3}},
+		}}}},
+		{"single", And(Filter{}, F("a").Exists(true)), bson.D{{Key: "a", Value: bson.D{{Key: "$exists", Value: true}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.BSON(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_MarshalBSON(t *testing.T) {
+	fake := newFakeUsers(t)
+
+	var users []fakeUser
+	if err := fake.GetAllCustom("users", F("name").Eq("Bob"), &users); err != nil {
+		t.Fatalf("Unable to get data. %s", err)
+	}
+	if len(users) != 1 || users[0].Name != "Bob" {
+		t.Errorf("Expected Bob, got %v", users)
+	}
+}
@@ -0,0 +1,226 @@
+package mongo
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PhoneticSuffix is appended to the name of a field of WithPhoneticFields to name its shadow field.
+const PhoneticSuffix = "_phonetic"
+
+// fuzzyCandidates is the maximum number of documents FindFuzzy ranks.
+const fuzzyCandidates = 1000
+
+// fuzzyThreshold is the minimum similarity of the documents returned by FindFuzzy.
+const fuzzyThreshold = 0.3
+
+// WithPhoneticFields stores the Soundex codes of the words of the top level string fields of the documents of
+// collectionName in shadow fields named after them with PhoneticSuffix, see FindFuzzy. The shadow fields are written
+// like the ones of WithNormalizedFields, index them.
+func WithPhoneticFields(collectionName string, fields ...string) ClientOption {
+	return withShadow(collectionName, fields, shadow{suffix: PhoneticSuffix, encode: func(value string) interface{} {
+		return soundexCodes(value)
+	}})
+}
+
+// Soundex returns the American Soundex code of word, a letter and three digits shared by the words that sound alike:
+// "Robert" and "Rupert" are both "R163". Letters outside of a-z, once diacritics are removed, are ignored.
+func Soundex(word string) string {
+	// the digits of the letters a to z, 0 for the vowels, h, w and y
+	const digits = "01230120022455012623010202"
+	var letters []byte
+	for _, r := range Normalize(word) {
+		if r >= 'a' && r <= 'z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	code := []byte{letters[0] - 'a' + 'A'}
+	last := digits[letters[0]-'a']
+	for _, letter := range letters[1:] {
+		if len(code) == 4 {
+			break
+		}
+		digit := digits[letter-'a']
+		if digit != '0' && digit != last {
+			code = append(code, digit)
+		}
+		// h and w do not separate letters with the same digit, vowels do
+		if letter != 'h' && letter != 'w' {
+			last = digit
+		}
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+// soundexCodes returns the Soundex codes of the words of s.
+func soundexCodes(s string) bson.A {
+	codes := bson.A{}
+	for _, word := range strings.Fields(s) {
+		if code := Soundex(word); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// FindFuzzy finds the documents of collectionName whose field roughly matches query, like a name misspelled or
+// spelled as it sounds, the closest first, and decodes them into result, a pointer to a slice:
+//
+//	err := client.FindFuzzy("customers", "name", "jon smyth", &customers, mongo.Limit(10))
+//
+// The candidates are the documents sharing a Soundex code with query, when field is one of WithPhoneticFields, or a
+// trigram of its letters. They are ranked by the similarity of their trigrams with the ones of query, plus a bonus
+// when every word of query sounds like one of theirs, and the ones that are not similar enough are left out. The
+// trigram regular expression cannot use an index, use WithPhoneticFields on large collections and a filter with
+// Limit to keep the scan small.
+func (connectionDetails *Client) FindFuzzy(collectionName string, field string, query string, result interface{}, opts ...Option) error {
+	fields := connectionDetails.shadows[collectionName]
+	filter := fuzzyFilter(field, query, fields.with(PhoneticSuffix)[field], fields.with(NormalizedSuffix)[field])
+	op := operation{name: "FindFuzzy", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var docs []bson.D
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find := op.options.find().SetLimit(fuzzyCandidates).SetSkip(0)
+		cursor, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &docs)
+	})
+	if err != nil {
+		return err
+	}
+
+	docs = rankFuzzy(docs, field, query)
+	if skip := int(op.options.skip); skip > 0 {
+		if skip > len(docs) {
+			skip = len(docs)
+		}
+		docs = docs[skip:]
+	}
+	if limit := int(op.options.limit); limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return connectionDetails.wrapError(op, decodeDocuments(docs, result))
+}
+
+// fuzzyFilter returns the filter of the candidates of FindFuzzy.
+func fuzzyFilter(field string, query string, phonetic bool, normalized bool) bson.D {
+	// the trigrams inside the words, the padded ones of trigrams would not match the start of a value
+	seen := map[string]bool{}
+	var patterns []string
+	for _, word := range strings.Fields(Normalize(query)) {
+		letters := []rune(word)
+		grams := []string{word}
+		if len(letters) >= 3 {
+			grams = grams[:0]
+			for i := 0; i+3 <= len(letters); i++ {
+				grams = append(grams, string(letters[i:i+3]))
+			}
+		}
+		for _, gram := range grams {
+			if !seen[gram] {
+				seen[gram] = true
+				patterns = append(patterns, regexp.QuoteMeta(gram))
+			}
+		}
+	}
+	matchField, options := field, "i"
+	if normalized {
+		matchField, options = field+NormalizedSuffix, ""
+	}
+	clauses := bson.A{bson.D{{Key: matchField, Value: bson.D{
+		{Key: "$regex", Value: strings.Join(patterns, "|")},
+		{Key: "$options", Value: options},
+	}}}}
+	if phonetic {
+		codes := soundexCodes(query)
+		clauses = append(bson.A{bson.D{{Key: field + PhoneticSuffix, Value: bson.D{{Key: "$in", Value: codes}}}}}, clauses...)
+	}
+	return bson.D{{Key: "$or", Value: clauses}}
+}
+
+// rankFuzzy returns the docs similar enough to query, the most similar first.
+func rankFuzzy(docs []bson.D, field string, query string) []bson.D {
+	queryGrams := trigrams(query)
+	queryCodes := soundexCodes(query)
+	type ranked struct {
+		doc   bson.D
+		score float64
+	}
+	var candidates []ranked
+	for _, doc := range docs {
+		value, _ := pathValue(doc, strings.Split(field, ".")).(string)
+		score := jaccard(queryGrams, trigrams(value))
+		if containsAll(soundexCodes(value), queryCodes) {
+			score += 0.5
+		}
+		if score >= fuzzyThreshold {
+			candidates = append(candidates, ranked{doc: doc, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	ranking := make([]bson.D, len(candidates))
+	for i, candidate := range candidates {
+		ranking[i] = candidate.doc
+	}
+	return ranking
+}
+
+// trigrams returns the sequences of three letters of the normalized words of s, padded with spaces so short words
+// and the start and the end of words count.
+func trigrams(s string) map[string]bool {
+	grams := map[string]bool{}
+	for _, word := range strings.Fields(Normalize(s)) {
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = true
+		}
+	}
+	return grams
+}
+
+// jaccard returns the number of grams shared by a and b over the number of distinct grams of both.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range a {
+		if b[gram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// containsAll reports whether every code of want is in codes.
+func containsAll(codes bson.A, want bson.A) bool {
+	if len(want) == 0 {
+		return false
+	}
+	for _, code := range want {
+		found := false
+		for _, c := range codes {
+			if c == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
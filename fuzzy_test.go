@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSoundex(t *testing.T) {
+	tests := map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"Lee":      "L000",
+		"Müller":   "M460",
+		"42":       "",
+	}
+	for word, want := range tests {
+		if got := Soundex(word); got != want {
+			t.Errorf("Soundex(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	filter := fuzzyFilter("name", "Jon Smyth", true, false)
+	want := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "name" + PhoneticSuffix, Value: bson.D{{Key: "$in", Value: bson.A{"J500", "S530"}}}}},
+		bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: "jon|smy|myt|yth"}, {Key: "$options", Value: "i"}}}},
+	}}}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("Unexpected filter\n got %v\nwant %v", filter, want)
+	}
+}
+
+func TestRankFuzzy(t *testing.T) {
+	docs := []bson.D{
+		{{Key: "name", Value: "Joan Smithers"}},
+		{{Key: "name", Value: "John Smith"}},
+		{{Key: "name", Value: "Jon Smythe"}},
+	}
+	ranking := rankFuzzy(docs, "name", "Jon Smyth")
+	var names []interface{}
+	for _, doc := range ranking {
+		names = append(names, doc[0].Value)
+	}
+	if want := []interface{}{"Jon Smythe", "John Smith"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
@@ -0,0 +1,98 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Point is a GeoJSON point, store it in a field indexed with CreateGeoIndex:
+//
+//	type Store struct {
+//		Name     string      `bson:"name"`
+//		Location mongo.Point `bson:"location"`
+//	}
+type Point struct {
+	// Type is always "Point"
+	Type string `bson:"type"`
+
+	// Coordinates are the longitude and the latitude, in this order
+	Coordinates [2]float64 `bson:"coordinates"`
+}
+
+// NewPoint returns the Point at lon and lat, in degrees.
+func NewPoint(lon, lat float64) Point {
+	return Point{Type: "Point", Coordinates: [2]float64{lon, lat}}
+}
+
+// Polygon is a GeoJSON polygon, see FindWithinPolygon.
+type Polygon struct {
+	// Type is always "Polygon"
+	Type string `bson:"type"`
+
+	// Coordinates are the rings of the polygon, the exterior one first then the holes. A ring is a closed list of
+	// longitude and latitude pairs, its last position is its first.
+	Coordinates [][][2]float64 `bson:"coordinates"`
+}
+
+// NewPolygon returns the Polygon bounded by the longitude and latitude pairs of ring, without holes. The ring is
+// closed when its last position is not its first:
+//
+//	area := mongo.NewPolygon([2]float64{174.7, -36.9}, [2]float64{174.8, -36.9}, [2]float64{174.8, -36.8})
+func NewPolygon(ring ...[2]float64) Polygon {
+	closed := append([][2]float64{}, ring...)
+	if len(closed) > 0 && closed[0] != closed[len(closed)-1] {
+		closed = append(closed, closed[0])
+	}
+	return Polygon{Type: "Polygon", Coordinates: [][][2]float64{closed}}
+}
+
+// CreateGeoIndex creates the 2dsphere index on field of collectionName, used by FindNear and FindWithinPolygon.
+func (connectionDetails *Client) CreateGeoIndex(collectionName string, field string) (string, error) {
+	op := operation{name: "CreateGeoIndex", collection: collectionName}
+	var name string
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		index := Index{Keys: bson.D{{Key: field, Value: "2dsphere"}}}
+		var err error
+		name, err = db.Collection(collectionName).Indexes().CreateOne(ctx, index.model())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// FindNear finds the documents of collectionName whose Point in field is at most maxMeters from lon and lat, the
+// nearest first, and decodes them into result, a pointer to a slice. A maxMeters of 0 finds them at any distance,
+// use Limit to keep the nearest ones:
+//
+//	err := client.FindNear("stores", "location", 174.76, -36.85, 2000, &stores, mongo.Limit(10))
+func (connectionDetails *Client) FindNear(collectionName string, field string, lon, lat float64, maxMeters float64, result interface{}, opts ...Option) error {
+	near := bson.D{{Key: "$geometry", Value: NewPoint(lon, lat)}}
+	if maxMeters > 0 {
+		near = append(near, bson.E{Key: "$maxDistance", Value: maxMeters})
+	}
+	filter := bson.D{{Key: field, Value: bson.D{{Key: "$nearSphere", Value: near}}}}
+	return connectionDetails.findGeo("FindNear", collectionName, filter, result, opts)
+}
+
+// FindWithinPolygon finds the documents of collectionName whose geometry in field is inside polygon and decodes them
+// into result, a pointer to a slice.
+func (connectionDetails *Client) FindWithinPolygon(collectionName string, field string, polygon Polygon, result interface{}, opts ...Option) error {
+	filter := bson.D{{Key: field, Value: bson.D{{Key: "$geoWithin", Value: bson.D{{Key: "$geometry", Value: polygon}}}}}}
+	return connectionDetails.findGeo("FindWithinPolygon", collectionName, filter, result, opts)
+}
+
+// findGeo finds the documents matching the geospatial filter.
+func (connectionDetails *Client) findGeo(name string, collectionName string, filter bson.D, result interface{}, opts []Option) error {
+	op := operation{name: name, collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		cursor, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, filter, op.options.find())
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNewPolygon(t *testing.T) {
+	polygon := NewPolygon([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1})
+	want := [][][2]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}
+	if polygon.Type != "Polygon" || !reflect.DeepEqual(polygon.Coordinates, want) {
+		t.Errorf("Expected the closed ring %v, got %+v", want, polygon)
+	}
+	if closed := NewPolygon(want[0]...); len(closed.Coordinates[0]) != 4 {
+		t.Errorf("Expected a closed ring to be kept, got %v", closed.Coordinates)
+	}
+}
+
+func TestClient_FindNear(t *testing.T) {
+	_ = client.DropCollections([]string{"places"})
+	type place struct {
+		ID       string `bson:"_id"`
+		Location Point  `bson:"location"`
+	}
+	places := []interface{}{
+		place{ID: "sky tower", Location: NewPoint(174.7622, -36.8485)},
+		place{ID: "ferry building", Location: NewPoint(174.7681, -36.8432)},
+		place{ID: "one tree hill", Location: NewPoint(174.7830, -36.9000)},
+	}
+	if _, err := client.AddMany("places", places); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := client.CreateGeoIndex("places", "location"); err != nil {
+		t.Fatalf("CreateGeoIndex() error = %v", err)
+	}
+
+	var near []place
+	if err := client.FindNear("places", "location", 174.7622, -36.8485, 1000, &near); err != nil {
+		t.Fatalf("FindNear() error = %v", err)
+	}
+	if len(near) != 2 || near[0].ID != "sky tower" || near[1].ID != "ferry building" {
+		t.Errorf("Expected the places within 1km, the nearest first, got %+v", near)
+	}
+
+	var within []bson.M
+	area := NewPolygon([2]float64{174.7, -36.95}, [2]float64{174.9, -36.95}, [2]float64{174.9, -36.88}, [2]float64{174.7, -36.88})
+	if err := client.FindWithinPolygon("places", "location", area, &within); err != nil || len(within) != 1 || within[0]["_id"] != "one tree hill" {
+		t.Errorf("FindWithinPolygon() = %v, %v", within, err)
+	}
+}
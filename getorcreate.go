@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetOrCreate finds the document of collectionName matching filter, or inserts defaults when there is none, and
+// decodes it into result, a pointer:
+//
+//	var user User
+//	err := client.GetOrCreate("users", bson.M{"email": email}, User{Email: email, Plan: "free"}, &user)
+//
+// It is a single findOneAndUpdate upserting with $setOnInsert, an existing document is returned unchanged. The
+// inserted document has the equality fields of filter and the fields of defaults. Concurrent calls only create one
+// document when the fields of filter have a unique index, the call losing the race then returns the document of the
+// winner.
+func (connectionDetails *Client) GetOrCreate(collectionName string, filter interface{}, defaults interface{}, result interface{}, opts ...Option) error {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "GetOrCreate", collection: collectionName, filter: filter, documents: []interface{}{defaults},
+		options: newOperationOptions(opts)}
+	if err := connectionDetails.validate(op, defaults); err != nil {
+		return err
+	}
+	encoded, err := connectionDetails.encodedDocument(collectionName, defaults)
+	if err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	doc, err := toDocument(connectionDetails.shadowedDocument(collectionName, encoded))
+	if err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: doc}}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := connectionDetails.collection(db, collectionName, op.options)
+		found := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+		if len(op.options.projection) > 0 {
+			found.SetProjection(op.options.projection)
+		}
+		document := collection.FindOneAndUpdate(ctx, filter, update, found)
+		if mongo.IsDuplicateKeyError(document.Err()) {
+			// a concurrent call inserted the document first, it matches now
+			document = collection.FindOneAndUpdate(ctx, filter, update, found)
+		}
+		return connectionDetails.decodedResult(collectionName, document).Decode(result)
+	})
+}
@@ -0,0 +1,39 @@
+package mongo
+
+import (
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	_ = client.DropCollections([]string{"getorcreate"})
+	if _, err := client.CreateIndex("getorcreate", Index{Keys: bson.D{{Key: "name", Value: 1}}, Unique: true}); err != nil {
+		t.Fatalf("CreateIndex() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result bson.M
+			err := client.GetOrCreate("getorcreate", bson.M{"name": "Akshay"}, bson.M{"plan": "free"}, &result)
+			if err != nil || result["plan"] != "free" {
+				t.Errorf("GetOrCreate() = %v, %v", result, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var result bson.M
+	err := client.GetOrCreate("getorcreate", bson.M{"name": "Akshay"}, bson.M{"plan": "pro"}, &result)
+	if err != nil || result["plan"] != "free" {
+		t.Errorf("Expected the existing document, got %v, %v", result, err)
+	}
+	var documents []bson.M
+	if err := client.GetAllCustom("getorcreate", bson.M{"name": "Akshay"}, &documents); err != nil || len(documents) != 1 {
+		t.Errorf("Expected a single document, got %v, %v", documents, err)
+	}
+}
@@ -2,7 +2,10 @@ module github.com/akshaybabloo/mongo/v4
 
 go 1.20
 
-require go.mongodb.org/mongo-driver v1.16.1
+require (
+	go.mongodb.org/mongo-driver v1.16.1
+	golang.org/x/text v0.14.0
+)
 
 require (
 	github.com/golang/snappy v0.0.4 // indirect
@@ -14,5 +17,4 @@ require (
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
 	golang.org/x/crypto v0.22.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 )
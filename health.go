@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Topology types reported by HealthReport.
+const (
+	TopologyStandalone = "Standalone"
+	TopologyReplicaSet = "ReplicaSet"
+	TopologySharded    = "Sharded"
+)
+
+// HealthReport is the result of a HealthCheck.
+type HealthReport struct {
+	// Healthy is true when the deployment answered the check
+	Healthy bool
+
+	// Latency of a round trip to the server, measured on an established connection
+	Latency time.Duration
+
+	// ServerVersion, for example "7.0.12"
+	ServerVersion string
+
+	// Topology is one of TopologyStandalone, TopologyReplicaSet or TopologySharded
+	Topology string
+
+	// CheckedAt is when the check was made
+	CheckedAt time.Time
+
+	// Err is the reason the check failed, if any
+	Err error
+}
+
+// HealthCheck pings the deployment and reports its latency, version and topology.
+//
+// The returned error is also available as HealthReport.Err, the report is never nil.
+func (connectionDetails *Client) HealthCheck() (*HealthReport, error) {
+	report := &HealthReport{CheckedAt: time.Now()}
+	err := connectionDetails.run(operation{name: "HealthCheck"}, func(ctx context.Context, db *mongo.Database) error {
+		if err := db.Client().Ping(ctx, readpref.Primary()); err != nil {
+			return err
+		}
+		admin := db.Client().Database("admin")
+
+		var hello struct {
+			SetName string `bson:"setName"`
+			Msg     string `bson:"msg"`
+		}
+		start := time.Now()
+		if err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+			return err
+		}
+		report.Latency = time.Since(start)
+		switch {
+		case hello.Msg == "isdbgrid":
+			report.Topology = TopologySharded
+		case hello.SetName != "":
+			report.Topology = TopologyReplicaSet
+		default:
+			report.Topology = TopologyStandalone
+		}
+
+		var buildInfo struct {
+			Version string `bson:"version"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+			return err
+		}
+		report.ServerVersion = buildInfo.Version
+		return nil
+	})
+	report.Healthy = err == nil
+	report.Err = err
+	return report, err
+}
+
+// HealthMonitor runs a HealthCheck in the background, see Client.StartHealthMonitor.
+type HealthMonitor struct {
+	mu       sync.RWMutex
+	report   HealthReport
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// StartHealthMonitor runs a HealthCheck every interval until Stop is called or the Client context is done. Each check
+// times out after interval.
+//
+// onChange, if not nil, is called with the report whenever the deployment goes from healthy to unhealthy or back. The
+// first check is made before StartHealthMonitor returns.
+func (connectionDetails *Client) StartHealthMonitor(interval time.Duration, onChange func(HealthReport)) *HealthMonitor {
+	monitor := &HealthMonitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	check := func() {
+		ctx, cancel := context.WithTimeout(connectionDetails.Context, interval)
+		defer cancel()
+		report, _ := connectionDetails.WithContext(ctx).HealthCheck()
+
+		monitor.mu.Lock()
+		changed := monitor.report.CheckedAt.IsZero() || monitor.report.Healthy != report.Healthy
+		monitor.report = *report
+		monitor.mu.Unlock()
+
+		if changed && onChange != nil {
+			onChange(*report)
+		}
+	}
+
+	check()
+	go func() {
+		defer close(monitor.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-monitor.stop:
+				return
+			case <-connectionDetails.Context.Done():
+				return
+			case <-ticker.C:
+				check()
+			}
+		}
+	}()
+	return monitor
+}
+
+// Healthy reports whether the last check succeeded, it can be used as a readiness probe.
+func (monitor *HealthMonitor) Healthy() bool {
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	return monitor.report.Healthy
+}
+
+// Report returns the last HealthReport.
+func (monitor *HealthMonitor) Report() HealthReport {
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	return monitor.report
+}
+
+// Stop stops the monitor and waits for a running check to finish.
+func (monitor *HealthMonitor) Stop() {
+	monitor.stopOnce.Do(func() {
+		close(monitor.stop)
+	})
+	<-monitor.done
+}
@@ -0,0 +1,42 @@
+package mongo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_HealthCheck_unreachable(t *testing.T) {
+	unreachable := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+
+	report, err := unreachable.HealthCheck()
+	if err == nil {
+		t.Fatalf("Expected health check to fail")
+	}
+	if report.Healthy || report.Err != err || report.CheckedAt.IsZero() {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestClient_StartHealthMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	unreachable := NewMongoClient("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", ctx)
+
+	var changes atomic.Int32
+	monitor := unreachable.StartHealthMonitor(200*time.Millisecond, func(report HealthReport) {
+		changes.Add(1)
+	})
+	defer monitor.Stop()
+
+	if monitor.Healthy() {
+		t.Errorf("Expected monitor to be unhealthy")
+	}
+	if changes.Load() != 1 {
+		t.Errorf("Expected the first check to be reported, got %d changes", changes.Load())
+	}
+	if monitor.Report().Err == nil {
+		t.Errorf("Expected report to contain the error")
+	}
+}
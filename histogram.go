@@ -0,0 +1,112 @@
+package mongo
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// histogramOther is the bucket ID of the values outside of the boundaries of a Histogram.
+const histogramOther = "__other"
+
+// Buckets describes the buckets of a Histogram, set either Boundaries or Auto.
+type Buckets struct {
+	// Boundaries are the sorted bucket boundaries, there is one bucket between each of them. The lower boundary is
+	// inclusive and the upper one exclusive.
+	Boundaries []interface{}
+
+	// Auto is the number of buckets MongoDB picks evenly distributed boundaries for
+	Auto int
+
+	// Granularity is the preferred number series of the Auto boundaries, for example "R5" or "POWERSOF2"
+	Granularity string
+}
+
+// Bucket is a bucket of a Histogram.
+type Bucket struct {
+	// Min is the inclusive lower bound of the bucket
+	Min interface{}
+
+	// Max is the exclusive upper bound of the bucket, it is inclusive for the last Auto bucket
+	Max interface{}
+
+	// Count of documents in the bucket
+	Count int64
+
+	// Other is true for the bucket collecting the values outside of the Boundaries, its Min and Max are nil
+	Other bool
+}
+
+// Histogram counts the documents of collectionName matching filter by buckets of field, using $bucket with
+// Boundaries or $bucketAuto with Auto.
+func (connectionDetails *Client) Histogram(collectionName string, field string, buckets Buckets, filter interface{}, opts ...Option) ([]Bucket, error) {
+	op := operation{name: "Histogram", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	pipeline, err := histogramPipeline(field, buckets, filter)
+	if err != nil {
+		return nil, connectionDetails.wrapError(op, err)
+	}
+	var results []struct {
+		ID    interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}
+	if err := connectionDetails.aggregate(op, pipeline, &results); err != nil {
+		return nil, err
+	}
+
+	histogram := make([]Bucket, 0, len(results))
+	for _, result := range results {
+		histogram = append(histogram, histogramBucket(buckets, result.ID, result.Count))
+	}
+	return histogram, nil
+}
+
+// histogramPipeline returns the aggregation pipeline of a Histogram.
+func histogramPipeline(field string, buckets Buckets, filter interface{}) (mongo.Pipeline, error) {
+	pipeline := mongo.Pipeline{}
+	if !isEmptyFilter(filter) {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+
+	switch {
+	case len(buckets.Boundaries) > 1 && buckets.Auto == 0:
+		pipeline = append(pipeline, bson.D{{Key: "$bucket", Value: bson.D{
+			{Key: "groupBy", Value: "$" + field},
+			{Key: "boundaries", Value: buckets.Boundaries},
+			{Key: "default", Value: histogramOther},
+			{Key: "output", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}},
+		}}})
+	case buckets.Auto > 0 && len(buckets.Boundaries) == 0:
+		bucketAuto := bson.D{
+			{Key: "groupBy", Value: "$" + field},
+			{Key: "buckets", Value: buckets.Auto},
+		}
+		if buckets.Granularity != "" {
+			bucketAuto = append(bucketAuto, bson.E{Key: "granularity", Value: buckets.Granularity})
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$bucketAuto", Value: bucketAuto}})
+	default:
+		return nil, errors.New("mongo: histogram needs either at least two boundaries or a number of auto buckets")
+	}
+	return pipeline, nil
+}
+
+// histogramBucket converts a $bucket or $bucketAuto result to a Bucket.
+func histogramBucket(buckets Buckets, id interface{}, count int64) Bucket {
+	if bounds, ok := id.(bson.D); ok {
+		min, _ := lookupField(bounds, "min")
+		max, _ := lookupField(bounds, "max")
+		return Bucket{Min: min, Max: max, Count: count}
+	}
+	if id == histogramOther {
+		return Bucket{Count: count, Other: true}
+	}
+	bucket := Bucket{Min: id, Count: count}
+	for i := 0; i < len(buckets.Boundaries)-1; i++ {
+		if equalValues(buckets.Boundaries[i], id) {
+			bucket.Max = buckets.Boundaries[i+1]
+			break
+		}
+	}
+	return bucket
+}
@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestHistogramPipeline(t *testing.T) {
+	pipeline, err := histogramPipeline("total", Buckets{Boundaries: []interface{}{0, 10, 100}}, bson.M{"status": "paid"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "paid"}}},
+		{{Key: "$bucket", Value: bson.D{
+			{Key: "groupBy", Value: "$total"},
+			{Key: "boundaries", Value: []interface{}{0, 10, 100}},
+			{Key: "default", Value: histogramOther},
+			{Key: "output", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}},
+		}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("histogramPipeline() = %v, want %v", pipeline, want)
+	}
+
+	pipeline, err = histogramPipeline("latency", Buckets{Auto: 5, Granularity: "R5"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = mongo.Pipeline{
+		{{Key: "$bucketAuto", Value: bson.D{
+			{Key: "groupBy", Value: "$latency"},
+			{Key: "buckets", Value: 5},
+			{Key: "granularity", Value: "R5"},
+		}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("histogramPipeline() = %v, want %v", pipeline, want)
+	}
+
+	for _, buckets := range []Buckets{{}, {Boundaries: []interface{}{0}}, {Boundaries: []interface{}{0, 1}, Auto: 2}} {
+		if _, err := histogramPipeline("total", buckets, nil); err == nil {
+			t.Errorf("expected an error for %+v", buckets)
+		}
+	}
+}
+
+func TestHistogramBucket(t *testing.T) {
+	buckets := Buckets{Boundaries: []interface{}{0, 10, 100}}
+
+	tests := []struct {
+		name string
+		id   interface{}
+		want Bucket
+	}{
+		{"boundary", int32(10), Bucket{Min: int32(10), Max: 100, Count: 3}},
+		{"other", histogramOther, Bucket{Count: 3, Other: true}},
+		{"auto", bson.D{{Key: "min", Value: 1.5}, {Key: "max", Value: 2.5}}, Bucket{Min: 1.5, Max: 2.5, Count: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := histogramBucket(buckets, tt.id, 3); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("histogramBucket() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
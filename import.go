@@ -0,0 +1,271 @@
+package mongo
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ImportOptions configures Client.Import.
+type ImportOptions struct {
+	// BatchSize is the number of documents written per batch, defaults to 1000
+	BatchSize int
+
+	// Upsert replaces the documents with the same "_id" instead of failing on a duplicate key, documents without an
+	// "_id" are inserted
+	Upsert bool
+}
+
+// ImportReport describes the outcome of Client.Import.
+type ImportReport struct {
+	// Inserted is the number of new documents, including the upserted ones
+	Inserted int64
+
+	// Replaced is the number of existing documents replaced with Upsert
+	Replaced int64
+
+	// Errors are the lines that could not be parsed or written, in line order within each batch
+	Errors []ImportError
+}
+
+// ImportError is a line of an import that could not be parsed or written.
+type ImportError struct {
+	// Line is the 1 based line number, for CSV the line where the record starts
+	Line int
+	Err  error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e ImportError) Unwrap() error {
+	return e.Err
+}
+
+// importLine is a parsed document and its line number.
+type importLine struct {
+	line int
+	doc  bson.D
+}
+
+// Import streams the documents of r in format - ExportNDJSON, ExportCSV or ExportBSON - into collectionName, writing
+// them in unordered batches. Lines that cannot be parsed or written are listed in the report and do not stop the
+// import, an error is only returned when a batch cannot be written at all, like when the server is unreachable, the
+// report then covers the batches written before.
+//
+// NDJSON lines are relaxed or canonical extended JSON. A CSV starts with a header of field paths, nested fields use
+// dotted paths, the values are imported as strings and empty values are left out. The lines of a BSON import are the
+// 1 based document numbers.
+func (connectionDetails *Client) Import(collectionName string, r io.Reader, format ExportFormat, opts ImportOptions) (*ImportReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	op := operation{name: "Import", collection: collectionName}
+	report := &ImportReport{}
+
+	var batch []importLine
+	add := func(line int, doc bson.D) error {
+		batch = append(batch, importLine{line: line, doc: doc})
+		if len(batch) < opts.BatchSize {
+			return nil
+		}
+		err := connectionDetails.importBatch(op, batch, opts.Upsert, report)
+		batch = batch[:0]
+		return err
+	}
+
+	var err error
+	switch format {
+	case ExportNDJSON:
+		err = readNDJSON(r, report, add)
+	case ExportCSV:
+		err = readCSV(r, report, add)
+	case ExportBSON:
+		err = readBSON(r, report, add)
+	default:
+		err = fmt.Errorf("mongo: import format %d is not supported", format)
+	}
+	if err == nil && len(batch) > 0 {
+		err = connectionDetails.importBatch(op, batch, opts.Upsert, report)
+	}
+	if err != nil {
+		return report, connectionDetails.wrapError(op, err)
+	}
+	return report, nil
+}
+
+// readNDJSON parses every line of r, passing the documents to add.
+func readNDJSON(r io.Reader, report *ImportReport, add func(line int, doc bson.D) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Bytes()
+		if len(strings.TrimSpace(string(text))) == 0 {
+			continue
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(text, false, &doc); err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: line, Err: err})
+			continue
+		}
+		if err := add(line, doc); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// readBSON parses the documents of r, passing them to add. A document that cannot be read stops the import, the
+// following documents cannot be found.
+func readBSON(r io.Reader, report *ImportReport, add func(line int, doc bson.D) error) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		raw, err := readDocument(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("mongo: document %d: %w", line, err)
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: line, Err: err})
+			continue
+		}
+		if err := add(line, doc); err != nil {
+			return err
+		}
+	}
+}
+
+// readDocument reads a BSON document from r, io.EOF is returned at the end of r.
+func readDocument(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated document")
+		}
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(length[:])
+	if size < 5 || size > 16<<20+16<<10 {
+		return nil, fmt.Errorf("invalid document length %d", size)
+	}
+	doc := make([]byte, size)
+	copy(doc, length[:])
+	if _, err := io.ReadFull(r, doc[4:]); err != nil {
+		return nil, errors.New("truncated document")
+	}
+	if doc[size-1] != 0 {
+		return nil, errors.New("document is not terminated")
+	}
+	return doc, nil
+}
+
+// readCSV parses the records of r following its header, passing the documents to add.
+func readCSV(r io.Reader, report *ImportReport, add func(line int, doc bson.D) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	paths := make([][]string, len(header))
+	for i, field := range header {
+		paths[i] = strings.Split(field, ".")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, ImportError{Line: parseErr.StartLine, Err: err})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) > len(header) {
+			report.Errors = append(report.Errors, ImportError{Line: line, Err: fmt.Errorf("mongo: %d values for %d columns", len(record), len(header))})
+			continue
+		}
+		doc := bson.D{}
+		for i, value := range record {
+			if value != "" {
+				doc = setPath(doc, paths[i], value)
+			}
+		}
+		if err := add(line, doc); err != nil {
+			return err
+		}
+	}
+}
+
+// importBatch writes batch, counting the written documents and the failed lines in report.
+func (connectionDetails *Client) importBatch(op operation, batch []importLine, upsert bool, report *ImportReport) error {
+	docs := make([]bson.D, len(batch))
+	for i, item := range batch {
+		docs[i] = item.doc
+	}
+	result, writeErrs, err := connectionDetails.writeBatch(op, docs, upsert)
+	if result != nil {
+		report.Inserted += result.InsertedCount + result.UpsertedCount
+		report.Replaced += result.MatchedCount
+	}
+	for _, writeErr := range writeErrs {
+		report.Errors = append(report.Errors, ImportError{Line: batch[writeErr.Index].line, Err: errors.New(writeErr.Message)})
+	}
+	return err
+}
+
+// writeBatch writes docs with an unordered bulk write, replacing the documents with the same "_id" when upsert is set.
+// The write errors of single documents are returned apart, their index is within docs.
+func (connectionDetails *Client) writeBatch(op operation, docs []bson.D, upsert bool) (*mongo.BulkWriteResult, []mongo.BulkWriteError, error) {
+	models := make([]mongo.WriteModel, len(docs))
+	for i, doc := range docs {
+		id, ok := lookupField(doc, "_id")
+		if upsert && ok {
+			models[i] = mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetReplacement(doc).SetUpsert(true)
+		} else {
+			models[i] = mongo.NewInsertOneModel().SetDocument(doc)
+		}
+	}
+
+	var result *mongo.BulkWriteResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		result, err = connectionDetails.collection(db, op.collection, op.options).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		return err
+	})
+	var bulkErr mongo.BulkWriteException
+	if err != nil && !errors.As(err, &bulkErr) {
+		return nil, nil, err
+	}
+	var writeErrs []mongo.BulkWriteError
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index >= 0 && writeErr.Index < len(docs) {
+			writeErrs = append(writeErrs, writeErr)
+		}
+	}
+	if bulkErr.WriteConcernError != nil {
+		return result, writeErrs, err
+	}
+	return result, writeErrs, nil
+}
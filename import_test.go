@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReadNDJSON(t *testing.T) {
+	input := `{"_id": 1, "name": "Akshay"}
+
+not json
+{"_id": {"$numberLong": "2"}, "at": {"$date": "2024-01-02T03:04:05Z"}}
+`
+	report := &ImportReport{}
+	var lines []int
+	err := readNDJSON(strings.NewReader(input), report, func(line int, doc bson.D) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readNDJSON() error = %v", err)
+	}
+	if len(lines) != 2 || lines[0] != 1 || lines[1] != 4 {
+		t.Errorf("Expected documents on lines 1 and 4, got %v", lines)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("Expected an error on line 3, got %v", report.Errors)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	input := "_id,name,address.city\n1,Akshay,Auckland\n2,\"Raj, Jr\",\n3,a,b,c\n"
+	report := &ImportReport{}
+	var docs []bson.D
+	var lines []int
+	err := readCSV(strings.NewReader(input), report, func(line int, doc bson.D) error {
+		docs = append(docs, doc)
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readCSV() error = %v", err)
+	}
+	if len(docs) != 2 || lines[0] != 2 || lines[1] != 3 {
+		t.Fatalf("Expected 2 documents on lines 2 and 3, got %v %v", docs, lines)
+	}
+	if city := firstValue(lookupPath(docs[0], []string{"address", "city"})); city != "Auckland" {
+		t.Errorf("Expected a nested city, got %v", docs[0])
+	}
+	if _, ok := lookupField(docs[1], "address"); ok {
+		t.Errorf("Expected empty values to be left out, got %v", docs[1])
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Line != 4 {
+		t.Errorf("Expected an error on line 4, got %v", report.Errors)
+	}
+}
+
+func TestClient_Import_format(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	if _, err := client.Import("users", strings.NewReader("[]"), ExportJSON, ImportOptions{}); err == nil {
+		t.Errorf("Expected JSON arrays to be refused")
+	}
+}
+
+func TestClient_Import(t *testing.T) {
+	_ = client.DropCollection("import_collection")
+	input := `{"_id": "1", "name": "Akshay"}
+{"_id": "1", "name": "Duplicate"}
+{"_id": "2", "name": "Raj"}
+`
+	report, err := client.Import("import_collection", strings.NewReader(input), ExportNDJSON, ImportOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("Unable to import. %s", err)
+	}
+	if report.Inserted != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 2 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	report, err = client.Import("import_collection", strings.NewReader(`{"_id": "1", "name": "Replaced"}`), ExportNDJSON, ImportOptions{Upsert: true})
+	if err != nil || report.Replaced != 1 {
+		t.Errorf("Expected the document to be replaced, got %+v %v", report, err)
+	}
+}
@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexProgressInterval is how often CreateIndexWithProgress polls currentOp.
+const indexProgressInterval = time.Second
+
+// Index describes an index on a collection.
+type Index struct {
+	// Name of the index, MongoDB generates one from the keys when empty
+	Name string
+
+	// Keys of the index in order, for example bson.D{{Key: "name", Value: 1}}
+	Keys bson.D
+
+	// Unique rejects documents with duplicate keys
+	Unique bool
+
+	// Sparse only indexes documents that have the indexed fields
+	Sparse bool
+
+	// ExpireAfter makes it a TTL index, documents are removed once the indexed date is older than ExpireAfter
+	ExpireAfter time.Duration
+
+	// PartialFilter only indexes the documents matching the filter - bson.M{}, bson.D{} or Filter
+	PartialFilter interface{}
+}
+
+// model converts the index to a driver IndexModel.
+func (index Index) model() mongo.IndexModel {
+	opts := options.Index()
+	if index.Name != "" {
+		opts.SetName(index.Name)
+	}
+	if index.Unique {
+		opts.SetUnique(true)
+	}
+	if index.Sparse {
+		opts.SetSparse(true)
+	}
+	if index.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(index.ExpireAfter / time.Second))
+	}
+	if index.PartialFilter != nil {
+		opts.SetPartialFilterExpression(index.PartialFilter)
+	}
+	return mongo.IndexModel{Keys: index.Keys, Options: opts}
+}
+
+// IndexProgress reports the progress of an index build, see CreateIndexWithProgress.
+type IndexProgress struct {
+	// Phase of the build as reported by the server, for example "Index Build: scanning collection"
+	Phase string
+
+	// Done and Total are the units of work of the current phase
+	Done  int64
+	Total int64
+
+	// Percent of the current phase, 100 once the index is ready
+	Percent float64
+}
+
+// CreateIndex creates index on collectionName and returns its name.
+func (connectionDetails *Client) CreateIndex(collectionName string, index Index) (string, error) {
+	op := operation{name: "CreateIndex", collection: collectionName}
+	var name string
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		name, err = db.Collection(collectionName).Indexes().CreateOne(ctx, index.model())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// CreateIndexWithProgress creates index on collectionName like CreateIndex and, while the index builds, polls
+// currentOp every second reporting the progress of the build to progress. Once the index is ready progress is called
+// with Percent 100.
+//
+// Note: reading currentOp requires the "inprog" privilege, without it the build still completes but no intermediate
+// progress is reported.
+func (connectionDetails *Client) CreateIndexWithProgress(collectionName string, index Index, progress func(IndexProgress)) (string, error) {
+	op := operation{name: "CreateIndexWithProgress", collection: collectionName}
+	var name string
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		pollCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pollIndexProgress(pollCtx, db, collectionName, progress)
+		}()
+
+		var err error
+		name, err = db.Collection(collectionName).Indexes().CreateOne(ctx, index.model())
+		cancel()
+		wg.Wait()
+		if err != nil {
+			return err
+		}
+		progress(IndexProgress{Phase: "Index Build: done", Percent: 100})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// pollIndexProgress reports index builds on collectionName found in currentOp until ctx is done.
+func pollIndexProgress(ctx context.Context, db *mongo.Database, collectionName string, progress func(IndexProgress)) {
+	ticker := time.NewTicker(indexProgressInterval)
+	defer ticker.Stop()
+	command := bson.D{
+		{Key: "currentOp", Value: true},
+		{Key: "ns", Value: db.Name() + "." + collectionName},
+		{Key: "progress", Value: bson.M{"$exists": true}},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var result struct {
+			InProg []struct {
+				Msg      string `bson:"msg"`
+				Progress struct {
+					Done  int64 `bson:"done"`
+					Total int64 `bson:"total"`
+				} `bson:"progress"`
+			} `bson:"inprog"`
+		}
+		if err := db.Client().Database("admin").RunCommand(ctx, command).Decode(&result); err != nil {
+			continue
+		}
+		for _, op := range result.InProg {
+			report := IndexProgress{Phase: op.Msg, Done: op.Progress.Done, Total: op.Progress.Total}
+			if report.Total > 0 {
+				report.Percent = float64(report.Done) / float64(report.Total) * 100
+			}
+			progress(report)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndex_model(t *testing.T) {
+	model := Index{
+		Name:          "email_1",
+		Keys:          bson.D{{Key: "email", Value: 1}},
+		Unique:        true,
+		ExpireAfter:   time.Hour,
+		PartialFilter: F("active").Eq(true),
+	}.model()
+
+	if *model.Options.Name != "email_1" || !*model.Options.Unique || *model.Options.ExpireAfterSeconds != 3600 {
+		t.Errorf("Unexpected index options %+v", model.Options)
+	}
+	if model.Options.Sparse != nil {
+		t.Errorf("Expected sparse to be unset")
+	}
+}
+
+func TestClient_CreateIndexWithProgress(t *testing.T) {
+	var last IndexProgress
+	name, err := client.CreateIndexWithProgress("test_collection", Index{Keys: bson.D{{Key: "name", Value: 1}}}, func(progress IndexProgress) {
+		last = progress
+	})
+	if err != nil {
+		t.Fatalf("Unable to create index. %s", err)
+	}
+	if name != "name_1" || last.Percent != 100 {
+		t.Errorf("Unexpected index %s, progress %+v", name, last)
+	}
+}
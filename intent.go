@@ -0,0 +1,159 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Statuses of an Intent.
+const (
+	IntentPending   = "pending"
+	IntentCompleted = "completed"
+	IntentFailed    = "failed"
+	IntentResolved  = "resolved"
+)
+
+// Intent records a critical operation before it runs, see WithIntentLog.
+type Intent struct {
+	ID primitive.ObjectID `bson:"_id"`
+
+	// Operation is the Client method, for example "DeleteMany"
+	Operation string `bson:"operation"`
+
+	// Collection is the collection of the operation
+	Collection string `bson:"collection"`
+
+	// Filter is the filter of the operation, decoded as a bson.D
+	Filter interface{} `bson:"filter,omitempty"`
+
+	// Documents are the documents or the update of the operation, decoded as bson.D
+	Documents []interface{} `bson:"documents,omitempty"`
+
+	// Actor is the actor of the context of the Client, see WithActor
+	Actor string `bson:"actor,omitempty"`
+
+	// Status is IntentPending until the operation returns, IntentCompleted or IntentFailed after, and IntentResolved
+	// once reconciled with ResolveIntent
+	Status string `bson:"status"`
+
+	// Error is the error of a failed operation
+	Error string `bson:"error,omitempty"`
+
+	// StartedAt is when the intent was recorded, FinishedAt when the operation returned
+	StartedAt  time.Time `bson:"startedAt"`
+	FinishedAt time.Time `bson:"finishedAt,omitempty"`
+}
+
+// intentLog is the configuration of WithIntentLog.
+type intentLog struct {
+	collection string
+	operations map[string]bool
+}
+
+// WithIntentLog records an Intent in collectionName before each of operations runs and marks it completed, or
+// failed, once it returns. An operation is a Client method like "DeleteMany", or a method and a collection like
+// "UpdateMany orders" to only record it on that collection:
+//
+//	client := mongo.NewMongoClientDefault(uri, "shop",
+//		mongo.WithIntentLog("intents", "DeleteMany", "UpdateMany orders"))
+//
+// An intent left pending means the process stopped while the operation ran, it may or may not have been applied. Call
+// IncompleteIntents at startup to reconcile them. The operation does not run when its intent cannot be recorded,
+// failing to mark it finished is logged. A retried operation keeps its intent.
+func WithIntentLog(collectionName string, operations ...string) ClientOption {
+	return func(client *Client) {
+		client.intents = &intentLog{collection: collectionName, operations: map[string]bool{}}
+		for _, operation := range operations {
+			client.intents.operations[operation] = true
+		}
+	}
+}
+
+// logIntent calls fn, recording the intent of op around it when op is a critical operation.
+func (connectionDetails *Client) logIntent(ctx context.Context, db *mongo.Database, op operation, fn func(ctx context.Context, db *mongo.Database) error) error {
+	log := connectionDetails.intents
+	if !log.operations[op.name] && !log.operations[op.name+" "+op.collection] {
+		return fn(ctx, db)
+	}
+	collection := db.Collection(log.collection)
+	state := operationFromContext(ctx)
+	if state.intent.IsZero() {
+		intent := Intent{
+			ID:         primitive.NewObjectID(),
+			Operation:  op.name,
+			Collection: op.collection,
+			Filter:     op.filter,
+			Documents:  op.documents,
+			Actor:      actorFromContext(ctx),
+			Status:     IntentPending,
+			StartedAt:  time.Now(),
+		}
+		if _, err := collection.InsertOne(ctx, intent); err != nil {
+			return err
+		}
+		state.intent = intent.ID
+	}
+
+	err := fn(ctx, db)
+	finished := bson.D{{Key: "status", Value: IntentCompleted}, {Key: "finishedAt", Value: time.Now()}}
+	if err != nil {
+		finished[0].Value = IntentFailed
+		finished = append(finished, bson.E{Key: "error", Value: err.Error()})
+	}
+	if _, updateErr := collection.UpdateByID(ctx, state.intent, bson.D{{Key: "$set", Value: finished}}); updateErr != nil {
+		connectionDetails.log().Warn("mongo: unable to finish intent", "operation", op.name, "collection", op.collection,
+			"error", updateErr)
+	}
+	return err
+}
+
+// IncompleteIntents returns the intents recorded by WithIntentLog that are pending or failed, oldest first. A pending
+// intent is one whose operation was interrupted by a crash, check the state of the documents it targets and call
+// ResolveIntent once reconciled.
+func (connectionDetails *Client) IncompleteIntents(opts ...Option) ([]Intent, error) {
+	if connectionDetails.intents == nil {
+		return nil, nil
+	}
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{IntentPending, IntentFailed}}}}}
+	op := operation{name: "IncompleteIntents", collection: connectionDetails.intents.collection, filter: filter,
+		options: newOperationOptions(opts)}
+	var intents []Intent
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find := op.options.find().SetSort(bson.D{{Key: "startedAt", Value: 1}, {Key: "_id", Value: 1}})
+		cursor, err := db.Collection(op.collection).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &intents)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return intents, nil
+}
+
+// ResolveIntent marks the intent with the given id as reconciled, IncompleteIntents no longer returns it.
+// mongo.ErrNoDocuments is returned for an unknown id.
+func (connectionDetails *Client) ResolveIntent(id primitive.ObjectID, opts ...Option) error {
+	if connectionDetails.intents == nil {
+		return mongo.ErrNoDocuments
+	}
+	filter := bson.D{{Key: "_id", Value: id}}
+	op := operation{name: "ResolveIntent", collection: connectionDetails.intents.collection, filter: filter,
+		options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: IntentResolved}}}}
+		result, err := db.Collection(op.collection).UpdateOne(ctx, filter, update, op.options.update())
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_IncompleteIntents(t *testing.T) {
+	logged := NewMongoClient(client.ConnectionUrl, client.DatabaseName, client.Context, WithIntentLog("intents", "DeleteMany", "UpdateMany intended"))
+	_ = logged.DropCollections([]string{"intended", "intents"})
+	if _, err := logged.AddMany("intended", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := logged.DeleteMany("intended", bson.M{"_id": "1"}); err != nil {
+		t.Fatalf("Unable to delete data. %s", err)
+	}
+	if _, err := logged.UpdateMany("intended", bson.M{"_id": "2"}, Inc("name", 1)); err == nil {
+		t.Fatal("Expected $inc of a string to fail")
+	}
+
+	intents, err := logged.IncompleteIntents()
+	if err != nil || len(intents) != 1 {
+		t.Fatalf("IncompleteIntents() = %+v, %v", intents, err)
+	}
+	if intents[0].Operation != "UpdateMany" || intents[0].Status != IntentFailed || intents[0].Error == "" {
+		t.Errorf("Expected the failed UpdateMany, got %+v", intents[0])
+	}
+
+	if err := logged.ResolveIntent(intents[0].ID); err != nil {
+		t.Fatalf("ResolveIntent() error = %v", err)
+	}
+	if intents, err := logged.IncompleteIntents(); err != nil || len(intents) != 0 {
+		t.Errorf("Expected no incomplete intent once resolved, got %+v, %v", intents, err)
+	}
+}
@@ -0,0 +1,104 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Iterator streams the documents of a cursor decoded as T, see FindIter.
+type Iterator[T any] struct {
+	client  *Client
+	op      operation
+	ctx     context.Context
+	cursor  *mongo.Cursor
+	release func()
+	err     error
+}
+
+// FindIter finds the documents of collectionName matching filter - bson.M{}, bson.A{}, or bson.D{} - and returns an
+// Iterator decoding them one at a time as T, so large results are not loaded in memory like GetAllCustom does. Use
+// BatchSize to tune the number of documents fetched per round trip.
+//
+//	users, err := mongo.FindIter[User](client, "users", bson.M{"active": true})
+//	if err != nil {
+//		return err
+//	}
+//	defer users.Close()
+//	for user, ok := users.Next(); ok; user, ok = users.Next() {
+//		...
+//	}
+//	return users.Err()
+//
+// The Iterator holds a connection until it is exhausted or closed, Close must be called when it is not exhausted.
+func FindIter[T any](client *Client, collectionName string, filter interface{}, opts ...Option) (*Iterator[T], error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	op := operation{name: "FindIter", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	if err := client.checkFederation(op); err != nil {
+		return nil, client.wrapError(op, err)
+	}
+	conn, release, err := client.acquire()
+	if err != nil {
+		return nil, client.wrapError(op, err)
+	}
+
+	ctx := context.WithValue(client.Context, operationKey{}, &operationState{operation: op})
+	db := client.database(conn)
+	cursor, err := client.collection(db, collectionName, op.options).Find(ctx, filter, op.options.find())
+	if err != nil {
+		release()
+		err = client.wrapError(op, err)
+		client.log().Warn("mongo: operation failed", "operation", op.name, "collection", op.collection, "error", err)
+		return nil, err
+	}
+	return &Iterator[T]{client: client, op: op, ctx: ctx, cursor: cursor, release: release}, nil
+}
+
+// Next returns the following document, or false when the Iterator is exhausted or failed, see Err.
+func (it *Iterator[T]) Next() (T, bool) {
+	var value T
+	if it.cursor == nil {
+		return value, false
+	}
+	if !it.cursor.Next(it.ctx) {
+		it.fail(it.cursor.Err())
+		return value, false
+	}
+	raw, err := it.client.decodedRaw(it.op.collection, it.cursor.Current)
+	if err == nil {
+		err = bson.Unmarshal(raw, &value)
+	}
+	if err != nil {
+		it.fail(err)
+		return value, false
+	}
+	return value, true
+}
+
+// Err returns the error that stopped the Iterator, it is nil when every document was read.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the cursor and releases its connection. It is safe to call Close more than once.
+func (it *Iterator[T]) Close() error {
+	if it.cursor == nil {
+		return nil
+	}
+	err := it.cursor.Close(it.ctx)
+	it.cursor = nil
+	it.release()
+	return it.client.wrapError(it.op, err)
+}
+
+// fail records err and closes the Iterator.
+func (it *Iterator[T]) fail(err error) {
+	if err != nil {
+		it.err = it.client.wrapError(it.op, err)
+		it.client.log().Warn("mongo: operation failed", "operation", it.op.name, "collection", it.op.collection, "error", it.err)
+	}
+	_ = it.Close()
+}
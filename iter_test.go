@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFindIter_unreachable(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	if _, err := FindIter[data](client, "test_collection", nil); err == nil {
+		t.Errorf("Expected an error without a server")
+	}
+}
+
+func TestFindIter(t *testing.T) {
+	_ = client.DropCollection("iter_collection")
+	if _, err := client.AddMany("iter_collection", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}, data{ID: "3", Name: "Gollahalli"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	it, err := FindIter[data](client, "iter_collection", bson.M{}, BatchSize(1))
+	if err != nil {
+		t.Fatalf("Unable to find data. %s", err)
+	}
+	defer it.Close()
+	count := 0
+	for item, ok := it.Next(); ok; item, ok = it.Next() {
+		if item.ID == "" {
+			t.Errorf("Expected a decoded document, got %v", item)
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 documents, got %d", count)
+	}
+	if _, ok := it.Next(); ok {
+		t.Errorf("Expected the iterator to stay exhausted")
+	}
+}
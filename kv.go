@@ -0,0 +1,163 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrKeyNotFound is returned by KV.Get when the key does not exist.
+var ErrKeyNotFound = errors.New("mongo: key not found")
+
+// ErrVersionConflict is returned when a write expected a version of a document that is not the current one, because
+// it was changed or deleted since it was read.
+var ErrVersionConflict = errors.New("mongo: version conflict")
+
+// KV is a key value store in a collection, see Client.KV.
+type KV struct {
+	client     *Client
+	collection string
+}
+
+// kvEntry is the document of a KV key.
+type kvEntry struct {
+	Key       string        `bson:"_id"`
+	Value     bson.RawValue `bson:"value"`
+	Version   int64         `bson:"version"`
+	UpdatedAt time.Time     `bson:"updatedAt"`
+}
+
+// KV returns the key value store in collectionName, for configuration or feature flags. Each key is a document with
+// its value and a version incremented by every write, CompareAndSwap only writes when the version did not change
+// since it was read:
+//
+//	flags := client.KV("flags")
+//	enabled, version, err := mongo.KVGet[bool](flags, "checkout.v2")
+//	...
+//	_, err = flags.CompareAndSwap("checkout.v2", !enabled, version)
+//	if errors.Is(err, mongo.ErrVersionConflict) {
+//		// changed by someone else, read it again
+//	}
+func (connectionDetails *Client) KV(collectionName string) *KV {
+	return &KV{client: connectionDetails, collection: collectionName}
+}
+
+// Put stores value under key, replacing any previous value, and returns the new version of the key.
+func (kv *KV) Put(key string, value interface{}, opts ...Option) (int64, error) {
+	op := operation{name: "KVPut", collection: kv.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	var entry kvEntry
+	err := kv.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return kv.client.collection(db, kv.collection, op.options).FindOneAndUpdate(ctx, op.filter, kvUpdate(value),
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return entry.Version, nil
+}
+
+// CompareAndSwap stores value under key only when its current version is version, and returns the new version.
+// A version of 0 expects the key not to exist. ErrVersionConflict is returned when the key was written or deleted
+// since version was read.
+func (kv *KV) CompareAndSwap(key string, value interface{}, version int64, opts ...Option) (int64, error) {
+	op := operation{name: "KVCompareAndSwap", collection: kv.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	var entry kvEntry
+	err := kv.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := kv.client.collection(db, kv.collection, op.options)
+		if version == 0 {
+			entry = kvEntry{Version: 1}
+			_, err := collection.InsertOne(ctx, bson.D{{Key: "_id", Value: key}, {Key: "value", Value: value},
+				{Key: "version", Value: entry.Version}, {Key: "updatedAt", Value: time.Now()}})
+			if mongo.IsDuplicateKeyError(err) {
+				return ErrVersionConflict
+			}
+			return err
+		}
+		filter := bson.D{{Key: "_id", Value: key}, {Key: "version", Value: version}}
+		err := collection.FindOneAndUpdate(ctx, filter, kvUpdate(value), options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&entry)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrVersionConflict
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return entry.Version, nil
+}
+
+// Get decodes the value stored under key into result, a pointer, and returns its version. ErrKeyNotFound is returned
+// when the key does not exist.
+func (kv *KV) Get(key string, result interface{}, opts ...Option) (int64, error) {
+	op := operation{name: "KVGet", collection: kv.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	var entry kvEntry
+	err := kv.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		err := db.Collection(kv.collection).FindOne(ctx, op.filter, op.options.findOne()).Decode(&entry)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrKeyNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return entry.Version, entry.Value.Unmarshal(result)
+}
+
+// KVGet returns the value stored under key in kv decoded as T, and its version. ErrKeyNotFound is returned when the
+// key does not exist.
+func KVGet[T any](kv *KV, key string, opts ...Option) (T, int64, error) {
+	var value T
+	version, err := kv.Get(key, &value, opts...)
+	return value, version, err
+}
+
+// Delete removes key, a missing key is not an error.
+func (kv *KV) Delete(key string, opts ...Option) error {
+	op := operation{name: "KVDelete", collection: kv.collection, filter: bson.D{{Key: "_id", Value: key}}, options: newOperationOptions(opts)}
+	return kv.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := kv.client.collection(db, kv.collection, op.options).DeleteOne(ctx, op.filter, op.options.delete())
+		return err
+	})
+}
+
+// Keys returns the keys starting with prefix in order, every key when prefix is empty. The prefix is matched with an
+// anchored regular expression so it uses the "_id" index.
+func (kv *KV) Keys(prefix string, opts ...Option) ([]string, error) {
+	filter := bson.D{}
+	if prefix != "" {
+		filter = bson.D{{Key: "_id", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(prefix)}}}}
+	}
+	op := operation{name: "KVKeys", collection: kv.collection, filter: filter, options: newOperationOptions(opts)}
+	var keys []string
+	err := kv.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find := op.options.find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetProjection(bson.D{{Key: "_id", Value: 1}})
+		cursor, err := db.Collection(kv.collection).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		var entries []kvEntry
+		if err := cursor.All(ctx, &entries); err != nil {
+			return err
+		}
+		keys = make([]string, len(entries))
+		for i, entry := range entries {
+			keys[i] = entry.Key
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// kvUpdate is the update storing value and incrementing the version.
+func kvUpdate(value interface{}) bson.D {
+	return bson.D{
+		{Key: "$set", Value: bson.D{{Key: "value", Value: value}, {Key: "updatedAt", Value: time.Now()}}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: int64(1)}}},
+	}
+}
@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestKV(t *testing.T) {
+	_ = client.DropCollections([]string{"kv"})
+	kv := client.KV("kv")
+
+	if _, _, err := KVGet[bool](kv, "checkout.v2"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Expected ErrKeyNotFound, got %v", err)
+	}
+	version, err := kv.CompareAndSwap("checkout.v2", true, 0)
+	if err != nil || version != 1 {
+		t.Fatalf("CompareAndSwap() = %d, %v", version, err)
+	}
+	if _, err := kv.CompareAndSwap("checkout.v2", false, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for an existing key, got %v", err)
+	}
+
+	enabled, version, err := KVGet[bool](kv, "checkout.v2")
+	if err != nil || !enabled || version != 1 {
+		t.Errorf("KVGet() = %v, %d, %v", enabled, version, err)
+	}
+	if version, err = kv.Put("checkout.v2", false); err != nil || version != 2 {
+		t.Errorf("Put() = %d, %v", version, err)
+	}
+	if _, err := kv.CompareAndSwap("checkout.v2", true, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for a stale version, got %v", err)
+	}
+	if version, err = kv.CompareAndSwap("checkout.v2", true, 2); err != nil || version != 3 {
+		t.Errorf("CompareAndSwap() = %d, %v", version, err)
+	}
+
+	if _, err := kv.Put("checkout.v1", true); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := kv.Put("search.v1", true); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	keys, err := kv.Keys("checkout.")
+	if err != nil || !reflect.DeepEqual(keys, []string{"checkout.v1", "checkout.v2"}) {
+		t.Errorf("Keys() = %v, %v", keys, err)
+	}
+
+	if err := kv.Delete("checkout.v1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if keys, err = kv.Keys(""); err != nil || len(keys) != 2 {
+		t.Errorf("Keys() = %v, %v", keys, err)
+	}
+}
@@ -0,0 +1,59 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Logger is used by Client to report connections, failures and other events worth knowing about. *slog.Logger
+// satisfies this interface.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// nopLogger is used when no Logger is configured.
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Warn(string, ...interface{})  {}
+
+// WithLogger sets the Logger used by the Client, by default nothing is logged.
+func WithLogger(logger Logger) ClientOption {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+// log returns the configured Logger.
+func (connectionDetails *Client) log() Logger {
+	if connectionDetails.logger == nil {
+		return nopLogger{}
+	}
+	return connectionDetails.logger
+}
+
+// poolMonitor returns a driver pool monitor logging connection churn and tracking the exhaustion of the pool for
+// WithLoadShedding, or nil when neither a Logger nor load shedding is configured.
+func (connectionDetails *Client) poolMonitor() *event.PoolMonitor {
+	if connectionDetails.logger == nil && connectionDetails.shedding == nil {
+		return nil
+	}
+	logger := connectionDetails.log()
+	shedder := connectionDetails.shedding
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				logger.Debug("mongo: connection created", "address", evt.Address, "connectionID", evt.ConnectionID)
+			case event.ConnectionClosed:
+				logger.Debug("mongo: connection closed", "address", evt.Address, "connectionID", evt.ConnectionID, "reason", evt.Reason)
+			case event.PoolCleared:
+				logger.Warn("mongo: connection pool cleared", "address", evt.Address)
+			case event.GetSucceeded, event.GetFailed:
+				if shedder != nil && shedder.observe(evt.Duration) {
+					logger.Warn("mongo: connection pool exhausted, shedding low priority operations", "address", evt.Address, "wait", evt.Duration)
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	debug    []string
+	warnings []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug = append(l.debug, msg)
+}
+
+func (l *recordingLogger) Warn(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, msg)
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	loggingClient := NewMongoClientDefault("mongodb://localhost:27017", "test", WithLogger(logger))
+
+	_ = loggingClient.run(operation{name: "Add", collection: "test_collection"}, func(ctx context.Context, db *mongo.Database) error {
+		return errors.New("failed")
+	})
+
+	if len(logger.debug) == 0 || logger.debug[0] != "mongo: connected" {
+		t.Errorf("Expected connection to be logged, got %v", logger.debug)
+	}
+	if len(logger.warnings) != 1 || logger.warnings[0] != "mongo: operation failed" {
+		t.Errorf("Expected failure to be logged, got %v", logger.warnings)
+	}
+}
+
+func TestClient_poolMonitor(t *testing.T) {
+	if NewMongoClientDefault("mongodb://localhost:27017", "test").poolMonitor() != nil {
+		t.Errorf("Expected no pool monitor without a logger")
+	}
+	if NewMongoClientDefault("mongodb://localhost:27017", "test", WithLogger(&recordingLogger{})).poolMonitor() == nil {
+		t.Errorf("Expected a pool monitor with a logger")
+	}
+}
@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OperationMetrics describes a single operation made through Client, see WithMetricsHook.
+type OperationMetrics struct {
+	// Operation is the name of the Client method, for example "GetAllCustom"
+	Operation string
+
+	// Database the operation ran against
+	Database string
+
+	// Collection the operation ran against, empty for database level operations
+	Collection string
+
+	// Duration of the whole operation, including connecting to MongoDB
+	Duration time.Duration
+
+	// Attempts made, more than 1 when the operation was retried, see WithRetryPolicy
+	Attempts int
+
+	// Commands is the number of commands sent to the server
+	Commands int64
+
+	// RequestBytes is the BSON size of all the commands sent to the server
+	RequestBytes int64
+
+	// ResponseBytes is the BSON size of all the replies received from the server
+	ResponseBytes int64
+
+	// Err is the error returned by the operation, if any
+	Err error
+}
+
+// payloadCounter accumulates the size of the commands sent for one operation.
+type payloadCounter struct {
+	commands      atomic.Int64
+	requestBytes  atomic.Int64
+	responseBytes atomic.Int64
+}
+
+// reportMetrics calls the registered metrics hooks.
+func (connectionDetails *Client) reportMetrics(metrics OperationMetrics) {
+	for _, hook := range connectionDetails.metricsHooks {
+		hook(metrics)
+	}
+}
@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClient_commandMonitor_payload(t *testing.T) {
+	metricsClient := NewMongoClientDefault("mongodb://localhost:27017", "test", WithMetricsHook(func(OperationMetrics) {}))
+	state := &operationState{operation: operation{name: "GetCustom"}}
+	ctx := context.WithValue(context.Background(), operationKey{}, state)
+
+	command, _ := bson.Marshal(bson.M{"find": "test_collection"})
+	reply, _ := bson.Marshal(bson.M{"ok": 1, "cursor": bson.M{"id": 0}})
+
+	monitor := metricsClient.commandMonitor()
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: command})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{Reply: reply})
+	monitor.Started(context.Background(), &event.CommandStartedEvent{Command: command})
+
+	if state.payload.commands.Load() != 1 {
+		t.Errorf("Expected 1 command, got %d", state.payload.commands.Load())
+	}
+	if state.payload.requestBytes.Load() != int64(len(command)) {
+		t.Errorf("Expected %d request bytes, got %d", len(command), state.payload.requestBytes.Load())
+	}
+	if state.payload.responseBytes.Load() != int64(len(reply)) {
+		t.Errorf("Expected %d response bytes, got %d", len(reply), state.payload.responseBytes.Load())
+	}
+}
+
+func TestWithMetricsHook(t *testing.T) {
+	var got OperationMetrics
+	metricsClient := NewMongoClientDefault("mongodb://localhost:27017", "test", WithMetricsHook(func(metrics OperationMetrics) {
+		got = metrics
+	}))
+
+	failure := errors.New("failed")
+	err := metricsClient.run(operation{name: "GetCustom", collection: "test_collection"}, func(ctx context.Context, db *mongo.Database) error {
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected %s, got %s", failure, err)
+	}
+	if got.Operation != "GetCustom" || got.Collection != "test_collection" || got.Database != "test" {
+		t.Errorf("Unexpected metrics %+v", got)
+	}
+	if !errors.Is(got.Err, failure) {
+		t.Errorf("Expected metrics error %s, got %s", failure, got.Err)
+	}
+}
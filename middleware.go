@@ -0,0 +1,155 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrOperationName is returned by Do for an OperationInfo without a Name.
+var ErrOperationName = errors.New("mongo: operation name is required")
+
+// OperationInfo describes an operation of the Client to middleware, see Use.
+type OperationInfo struct {
+	// Name of the Client method, for example "Add" or "GetAllCustom"
+	Name string
+
+	// Database and Collection the operation runs on
+	Database   string
+	Collection string
+
+	// Filter of the operation, nil when it has none
+	Filter interface{}
+
+	// Documents written by inserts, or the data of updates and replaces
+	Documents []interface{}
+}
+
+// OperationFunc runs an operation described by info with ctx.
+type OperationFunc func(ctx context.Context, info OperationInfo) error
+
+// Middleware wraps the OperationFunc running every operation of a Client, see Use.
+type Middleware func(next OperationFunc) OperationFunc
+
+// BeforeInsertHook is implemented by models that need to run code, like setting a creation date, before Add and
+// AddMany write them. An error stops the operation. Hooks are called on the value given to the operation, pass a
+// pointer to the model when they have a pointer receiver. They are called by Client, not by FakeClient.
+type BeforeInsertHook interface {
+	BeforeInsert() error
+}
+
+// AfterInsertHook is implemented by models that need to run code after Add and AddMany wrote them.
+type AfterInsertHook interface {
+	AfterInsert()
+}
+
+// BeforeUpdateHook is implemented by models that need to run code, like setting a modification date, before Update,
+// UpdateCustom, UpdateMany, UpdateWithVersion, Replace and Save write them. An error stops the operation.
+type BeforeUpdateHook interface {
+	BeforeUpdate() error
+}
+
+// AfterUpdateHook is implemented by models that need to run code after Update, UpdateCustom, UpdateMany,
+// UpdateWithVersion, Replace and Save wrote them.
+type AfterUpdateHook interface {
+	AfterUpdate()
+}
+
+// insertOperations and updateOperations are the operations calling the insert and update hooks of their models.
+var (
+	insertOperations = map[string]bool{"Add": true, "AddMany": true}
+	updateOperations = map[string]bool{
+		"Update": true, "UpdateCustom": true, "UpdateMany": true, "UpdateWithVersion": true, "Replace": true, "Save": true,
+	}
+)
+
+// Use adds middleware around every operation of the Client, for cross-cutting concerns like tracing, tenancy checks
+// or metrics:
+//
+//	client.Use(func(next mongo.OperationFunc) mongo.OperationFunc {
+//		return func(ctx context.Context, info mongo.OperationInfo) error {
+//			ctx, span := tracer.Start(ctx, info.Name)
+//			defer span.End()
+//			return next(ctx, info)
+//		}
+//	})
+//
+// The first middleware added is the outermost. A middleware can return an error without calling next to stop the
+// operation, and pass a derived ctx to next, the operation then runs with it. info only describes the operation,
+// changing it does not change what runs. The operation retried by a RetryPolicy is a single call of next.
+//
+// Call Use before the Client is used, views created with WithContext or WithDatabase before the call do not see the
+// middleware.
+func (connectionDetails *Client) Use(middleware ...Middleware) {
+	connectionDetails.middleware = append(connectionDetails.middleware, middleware...)
+}
+
+// hookedModels returns the models written by op whose hooks are called, see fromModels.
+func (op operation) hookedModels() []interface{} {
+	if op.options != nil && op.options.models != nil {
+		return op.options.models
+	}
+	return op.documents
+}
+
+// info returns the OperationInfo of op.
+func (op operation) info(database string) OperationInfo {
+	return OperationInfo{Name: op.name, Database: database, Collection: op.collection, Filter: op.filter, Documents: op.documents}
+}
+
+// beforeHooks calls the before hooks of the models written by the operation name.
+func beforeHooks(name string, documents []interface{}) error {
+	for _, document := range documents {
+		switch {
+		case insertOperations[name]:
+			if hook, ok := document.(BeforeInsertHook); ok {
+				if err := hook.BeforeInsert(); err != nil {
+					return err
+				}
+			}
+		case updateOperations[name]:
+			if hook, ok := document.(BeforeUpdateHook); ok {
+				if err := hook.BeforeUpdate(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// afterHooks calls the after hooks of the models written by op.
+func afterHooks(op operation) {
+	for _, document := range op.hookedModels() {
+		switch {
+		case insertOperations[op.name]:
+			if hook, ok := document.(AfterInsertHook); ok {
+				hook.AfterInsert()
+			}
+		case updateOperations[op.name]:
+			if hook, ok := document.(AfterUpdateHook); ok {
+				hook.AfterUpdate()
+			}
+		}
+	}
+}
+
+// Do runs fn as an operation of the Client described by info, so a composite operation, like a repository method
+// making several calls, goes through the same middleware, metrics, retries, load shedding and command monitoring as
+// the built-in operations. fn receives the driver database, the commands it sends with ctx are attributed to the
+// operation:
+//
+//	err := client.Do(mongo.OperationInfo{Name: "ArchiveOrder", Collection: "orders"}, archiveOrder)
+//
+// info.Name is required, info.Database is ignored, the database of the Client is used. fn is called again when the
+// RetryPolicy retries the operation, it must be safe to repeat. Use a name no built-in operation has, the Client
+// maintains counts, caches and hooks for the names of its own writes.
+func (connectionDetails *Client) Do(info OperationInfo, fn func(ctx context.Context, db *mongo.Database) error, opts ...Option) error {
+	op := operation{name: info.Name, collection: info.Collection, filter: info.Filter, documents: info.Documents,
+		options: newOperationOptions(opts)}
+	if info.Name == "" {
+		return connectionDetails.wrapError(op, ErrOperationName)
+	}
+	return connectionDetails.run(op, fn)
+}
@@ -0,0 +1,244 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultMigrationCollection records the applied migrations, its lock is kept in the collection with the "_lock"
+// suffix.
+const DefaultMigrationCollection = "schema_migrations"
+
+// defaultMigrationLockTTL is how long a migration lock is held before another Migrator can take it over.
+const defaultMigrationLockTTL = 10 * time.Minute
+
+// Migration errors.
+var (
+	// ErrMigrationLocked is returned when another Migrator holds the migration lock
+	ErrMigrationLocked = errors.New("mongo: migrations are locked by another process")
+
+	// ErrIrreversibleMigration is returned by Rollback for a migration without Down
+	ErrIrreversibleMigration = errors.New("mongo: migration cannot be rolled back")
+
+	// ErrDuplicateMigration is returned when two migrations have the same version
+	ErrDuplicateMigration = errors.New("mongo: duplicate migration version")
+)
+
+// Migration is a versioned change to the database.
+type Migration struct {
+	// Version orders the migrations, it is usually a timestamp like 20240131120000
+	Version int64
+
+	// Description is stored with the applied migration
+	Description string
+
+	// Up applies the migration
+	Up func(client *Client) error
+
+	// Down reverts the migration, Rollback fails on migrations without Down
+	Down func(client *Client) error
+}
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// Collection records the applied migrations, defaults to DefaultMigrationCollection
+	Collection string
+
+	// LockTTL is how long the lock is held before another Migrator can take it over, defaults to 10 minutes
+	LockTTL time.Duration
+}
+
+// Migrator applies and rolls back migrations, at most one Migrator runs at a time for a database.
+type Migrator struct {
+	client     *Client
+	migrations []Migration
+	collection string
+	lockTTL    time.Duration
+	owner      string
+}
+
+// appliedMigration is the document recorded for each applied migration.
+type appliedMigration struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// NewMigrator returns a Migrator for migrations, they are applied in Version order.
+func NewMigrator(client *Client, opts MigratorOptions, migrations ...Migration) *Migrator {
+	if opts.Collection == "" {
+		opts.Collection = DefaultMigrationCollection
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = defaultMigrationLockTTL
+	}
+	sorted := append([]Migration(nil), migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		client:     client,
+		migrations: sorted,
+		collection: opts.Collection,
+		lockTTL:    opts.LockTTL,
+		owner:      hostname + ":" + strconv.Itoa(os.Getpid()) + ":" + strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// Migrate applies the migrations that have not been applied yet and returns their versions. It stops at the first
+// migration that fails, the migrations applied before it stay applied.
+func (migrator *Migrator) Migrate() ([]int64, error) {
+	for i := 1; i < len(migrator.migrations); i++ {
+		if migrator.migrations[i].Version == migrator.migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateMigration, migrator.migrations[i].Version)
+		}
+	}
+
+	var done []int64
+	err := migrator.locked(func() error {
+		applied, err := migrator.applied()
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrator.migrations {
+			if applied[migration.Version] {
+				continue
+			}
+			if migration.Up != nil {
+				if err := migration.Up(migrator.client); err != nil {
+					return fmt.Errorf("mongo: migration %d up: %w", migration.Version, err)
+				}
+			}
+			if err := migrator.record(migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Rollback reverts the last n applied migrations, newest first, and returns their versions.
+func (migrator *Migrator) Rollback(n int) ([]int64, error) {
+	var done []int64
+	err := migrator.locked(func() error {
+		applied, err := migrator.applied()
+		if err != nil {
+			return err
+		}
+		for i := len(migrator.migrations) - 1; i >= 0 && len(done) < n; i-- {
+			migration := migrator.migrations[i]
+			if !applied[migration.Version] {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("%w: %d", ErrIrreversibleMigration, migration.Version)
+			}
+			if err := migration.Down(migrator.client); err != nil {
+				return fmt.Errorf("mongo: migration %d down: %w", migration.Version, err)
+			}
+			if err := migrator.forget(migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Applied returns the versions of the applied migrations in order.
+func (migrator *Migrator) Applied() ([]int64, error) {
+	applied, err := migrator.applied()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
+
+// applied returns the set of applied versions.
+func (migrator *Migrator) applied() (map[int64]bool, error) {
+	op := operation{name: "Migrate", collection: migrator.collection}
+	applied := map[int64]bool{}
+	err := migrator.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		cursor, err := db.Collection(migrator.collection).Find(ctx, bson.D{})
+		if err != nil {
+			return err
+		}
+		var records []appliedMigration
+		if err := cursor.All(ctx, &records); err != nil {
+			return err
+		}
+		for _, record := range records {
+			applied[record.Version] = true
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// record marks migration as applied.
+func (migrator *Migrator) record(migration Migration) error {
+	op := operation{name: "Migrate", collection: migrator.collection}
+	return migrator.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		record := appliedMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()}
+		_, err := db.Collection(migrator.collection).ReplaceOne(ctx, bson.M{"_id": migration.Version}, record, options.Replace().SetUpsert(true))
+		return err
+	})
+}
+
+// forget marks migration as not applied.
+func (migrator *Migrator) forget(migration Migration) error {
+	op := operation{name: "Rollback", collection: migrator.collection}
+	return migrator.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(migrator.collection).DeleteOne(ctx, bson.M{"_id": migration.Version})
+		return err
+	})
+}
+
+// locked calls fn while holding the migration lock.
+func (migrator *Migrator) locked(fn func() error) error {
+	lockCollection := migrator.collection + "_lock"
+	op := operation{name: "MigrationLock", collection: lockCollection}
+	err := migrator.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		now := time.Now()
+		// an expired lock is taken over, a held one makes the upsert fail with a duplicate key
+		_, err := db.Collection(lockCollection).UpdateOne(ctx,
+			bson.M{"_id": "lock", "$or": bson.A{bson.M{"owner": migrator.owner}, bson.M{"expiresAt": bson.M{"$lt": now}}}},
+			bson.M{"$set": bson.M{"owner": migrator.owner, "expiresAt": now.Add(migrator.lockTTL)}},
+			options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrMigrationLocked
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := migrator.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": "lock", "owner": migrator.owner})
+			return err
+		})
+		if err != nil {
+			migrator.client.log().Warn("mongo: unable to release the migration lock", "error", err)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewMigrator(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	migrator := NewMigrator(client, MigratorOptions{}, Migration{Version: 3}, Migration{Version: 1}, Migration{Version: 2})
+
+	var versions []int64
+	for _, migration := range migrator.migrations {
+		versions = append(versions, migration.Version)
+	}
+	if !reflect.DeepEqual(versions, []int64{1, 2, 3}) {
+		t.Errorf("Expected migrations in version order, got %v", versions)
+	}
+	if migrator.collection != DefaultMigrationCollection || migrator.lockTTL != 10*time.Minute || migrator.owner == "" {
+		t.Errorf("Unexpected defaults %+v", migrator)
+	}
+
+	duplicate := NewMigrator(client, MigratorOptions{}, Migration{Version: 1}, Migration{Version: 1})
+	if _, err := duplicate.Migrate(); !errors.Is(err, ErrDuplicateMigration) {
+		t.Errorf("Expected ErrDuplicateMigration, got %v", err)
+	}
+}
+
+func TestMigrator_MigrateRollback(t *testing.T) {
+	opts := MigratorOptions{Collection: "test_migrations"}
+	_ = client.DropCollections([]string{"test_migrations", "test_migrations_lock"})
+
+	var steps []string
+	migrations := []Migration{
+		{Version: 1, Up: func(*Client) error { steps = append(steps, "up 1"); return nil }, Down: func(*Client) error { steps = append(steps, "down 1"); return nil }},
+		{Version: 2, Up: func(*Client) error { steps = append(steps, "up 2"); return nil }, Down: func(*Client) error { steps = append(steps, "down 2"); return nil }},
+	}
+
+	applied, err := NewMigrator(client, opts, migrations...).Migrate()
+	if err != nil {
+		t.Fatalf("Unable to migrate. %s", err)
+	}
+	if !reflect.DeepEqual(applied, []int64{1, 2}) {
+		t.Errorf("Unexpected applied migrations %v", applied)
+	}
+
+	if applied, err = NewMigrator(client, opts, migrations...).Migrate(); err != nil || len(applied) != 0 {
+		t.Errorf("Expected nothing to migrate, got %v %v", applied, err)
+	}
+
+	rolledBack, err := NewMigrator(client, opts, migrations...).Rollback(1)
+	if err != nil {
+		t.Fatalf("Unable to roll back. %s", err)
+	}
+	if !reflect.DeepEqual(rolledBack, []int64{2}) || !reflect.DeepEqual(steps, []string{"up 1", "up 2", "down 2"}) {
+		t.Errorf("Unexpected rollback %v, steps %v", rolledBack, steps)
+	}
+}
@@ -33,10 +33,12 @@ package mongo
 
 import (
 	"context"
+	"reflect"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Client takes in the
@@ -49,42 +51,91 @@ type Client struct {
 
 	// Highly recommend using timeout Context
 	Context context.Context
+
+	metricsHooks     []func(OperationMetrics)
+	commandMonitors  []CommandMonitor
+	redactedFields   []string
+	compressors      []string
+	appName          string
+	logger           Logger
+	retryPolicy      *RetryPolicy
+	safety           *SafetyProfile
+	maintainedCounts []MaintainedCount
+	validators       map[string][]func(interface{}) error
+	leaderboards     []Leaderboard
+	summaries        []Summary
+	access           *accessRecorder
+	writeConcerns    map[string]*writeconcern.WriteConcern
+	replay           *replayRecorder
+	federated        bool
+	records          *recordCache
+	queries          *queryRegistry
+	shedding         *loadShedder
+	dispatcher       *dispatcher
+	workers          *workerGroup
+	audited          map[string]bool
+	middleware       []Middleware
+	slow             *slowOperations
+	models           map[string]reflect.Type
+	intents          *intentLog
+	region           string
+	readPreference   *readpref.ReadPref
+	shadows          map[string]shadowFields
+	catalogs         map[string][]string
+	slos             *sloTracker
+	codecs           map[string]DocumentCodec
+	sequenced        map[string]bool
+	conn             *connection
 }
 
 // NewMongoClient returns Client and it's associated functions
-func NewMongoClient(connectionURL string, databaseName string, ctx context.Context) *Client {
-	return &Client{
+func NewMongoClient(connectionURL string, databaseName string, ctx context.Context, opts ...ClientOption) *Client {
+	client := &Client{
 		ConnectionUrl: connectionURL,
 		DatabaseName:  databaseName,
 		Context:       ctx,
+		conn:          &connection{},
+		queries:       &queryRegistry{},
+		workers:       &workerGroup{},
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	return client
 }
 
 // NewMongoClientDefault returns Client, and it's associated functions with default context
-func NewMongoClientDefault(connectionURL string, databaseName string) *Client {
-	return &Client{
-		ConnectionUrl: connectionURL,
-		DatabaseName:  databaseName,
-		Context:       context.Background(),
-	}
+func NewMongoClientDefault(connectionURL string, databaseName string, opts ...ClientOption) *Client {
+	return NewMongoClient(connectionURL, databaseName, context.Background(), opts...)
+}
+
+// WithContext returns a copy of the Client that uses ctx for its operations.
+func (connectionDetails *Client) WithContext(ctx context.Context) *Client {
+	client := *connectionDetails
+	client.Context = ctx
+	return &client
 }
 
 // Add can be used to add document to MongoDB
-func (connectionDetails *Client) Add(collectionName string, data interface{}) (*mongo.InsertOneResult, error) {
-	client, err := connectionDetails.client()
+func (connectionDetails *Client) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	op := operation{name: "Add", collection: collectionName, documents: []interface{}{data}, options: newOperationOptions(opts)}
+	if err := connectionDetails.validate(op, data); err != nil {
+		return nil, err
+	}
+	sequenced, err := connectionDetails.sequencedDocuments(collectionName, op.documents, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
+	var insertResult *mongo.InsertOneResult
+	err = connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		doc, err := connectionDetails.encodedDocument(collectionName, sequenced[0])
 		if err != nil {
-			return
+			return err
 		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	insertResult, err := collection.InsertOne(connectionDetails.Context, data)
+		doc = connectionDetails.shadowedDocument(collectionName, doc)
+		insertResult, err = connectionDetails.collection(db, collectionName, op.options).InsertOne(ctx, doc, op.options.insertOne())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -92,21 +143,25 @@ func (connectionDetails *Client) Add(collectionName string, data interface{}) (*
 }
 
 // AddMany can be used to add multiple documents to MongoDB
-func (connectionDetails *Client) AddMany(collectionName string, data []interface{}) (*mongo.InsertManyResult, error) {
-	client, err := connectionDetails.client()
+func (connectionDetails *Client) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	op := operation{name: "AddMany", collection: collectionName, documents: data, options: newOperationOptions(opts)}
+	if err := connectionDetails.validate(op, data...); err != nil {
+		return nil, err
+	}
+	sequenced, err := connectionDetails.sequencedDocuments(collectionName, data, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
+	var insertResult *mongo.InsertManyResult
+	err = connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		docs, err := connectionDetails.encodedDocuments(collectionName, sequenced)
 		if err != nil {
-			return
+			return err
 		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	insertResult, err := collection.InsertMany(connectionDetails.Context, data)
+		docs = connectionDetails.shadowedDocuments(collectionName, docs)
+		insertResult, err = connectionDetails.collection(db, collectionName, op.options).InsertMany(ctx, docs, op.options.insertMany())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -114,211 +169,208 @@ func (connectionDetails *Client) AddMany(collectionName string, data []interface
 }
 
 // Update can be used to update values by its ID
-func (connectionDetails *Client) Update(collectionName string, id string, data interface{}) (*mongo.UpdateResult, error) {
-	client, err := connectionDetails.client()
-	if err != nil {
+func (connectionDetails *Client) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := connectionDetails.validate(operation{name: "Update", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
+	return connectionDetails.updateOne("Update", collectionName, bson.M{"_id": id}, updateDocument(data), opts, data)
+}
 
-	collection := db.Collection(collectionName)
-	updateResult, err := collection.UpdateOne(connectionDetails.Context, bson.M{"_id": id}, bson.D{{"$set", data}})
-	if err != nil {
+// UpdateCustom can be used to update values by a filter - bson.M{}, bson.A{}, or bson.D{}
+func (connectionDetails *Client) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := connectionDetails.validate(operation{name: "UpdateCustom", collection: collectionName, filter: filter, options: newOperationOptions(opts)}, data); err != nil {
 		return nil, err
 	}
-	return updateResult, nil
+	return connectionDetails.updateOne("UpdateCustom", collectionName, filter, updateDocument(data), opts, data)
 }
 
-// UpdateCustom can be used to update values by a filter - bson.M{}, bson.A{}, or bson.D{}
-func (connectionDetails *Client) UpdateCustom(collectionName string, filter interface{}, data interface{}, updateOptions ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	client, err := connectionDetails.client()
-	if err != nil {
+// UpdateMany can be used to update values of all the documents matching a filter - bson.M{}, bson.A{}, or bson.D{}
+func (connectionDetails *Client) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "UpdateMany", collection: collectionName, filter: filter, documents: []interface{}{data}, options: newOperationOptions(opts)}
+	if err := connectionDetails.validate(op, data); err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	updateResult, err := collection.UpdateOne(connectionDetails.Context, filter, bson.D{{"$set", data}}, updateOptions...)
+	var updateResult *mongo.UpdateResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		update := connectionDetails.shadowedUpdate(collectionName, updateDocument(data))
+		updateResult, err = connectionDetails.collection(db, collectionName, op.options).UpdateMany(ctx, filter, update, op.options.update())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return updateResult, nil
 }
 
-// Delete deletes a document by ID only.
-func (connectionDetails *Client) Delete(collectionName string, id string) (*mongo.DeleteResult, error) {
-	client, err := connectionDetails.client()
-	if err != nil {
+// Replace replaces the whole document with the given ID, unlike Update the fields missing from data are removed.
+func (connectionDetails *Client) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := connectionDetails.validate(operation{name: "Replace", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
+	return connectionDetails.replaceOne("Replace", collectionName, bson.M{"_id": id}, data, opts, data)
+}
+
+// Save inserts data, or replaces the document with the same "_id". An ObjectID "_id" is generated when data has none,
+// it is returned as UpsertedID.
+func (connectionDetails *Client) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := connectionDetails.validate(operation{name: "Save", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	doc, id, err := withID(data)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "Save", collection: collectionName}, err)
+	}
+	return connectionDetails.replaceOne("Save", collectionName, bson.M{"_id": id}, doc, append(opts, Upsert()), data)
+}
 
-	collection := db.Collection(collectionName)
-	insertResult, err := collection.DeleteOne(connectionDetails.Context, bson.M{"_id": id})
+// updateOne applies the update document to the first document matching filter, documents are the models the update
+// was made from.
+func (connectionDetails *Client) updateOne(name string, collectionName string, filter interface{}, update interface{}, opts []Option, documents ...interface{}) (*mongo.UpdateResult, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	update = connectionDetails.shadowedUpdate(collectionName, update)
+	op := operation{name: name, collection: collectionName, filter: filter, documents: documents, options: newOperationOptions(opts)}
+	var updateResult *mongo.UpdateResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		updateResult, err = connectionDetails.collection(db, collectionName, op.options).UpdateOne(ctx, filter, update, op.options.update())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return insertResult, nil
+	return updateResult, nil
 }
 
-// DeleteCustom deletes a document by a filter - bson.M{}, bson.A{}, or bson.D{}
-func (connectionDetails *Client) DeleteCustom(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
-	client, err := connectionDetails.client()
+// replaceOne replaces the first document matching filter with doc, documents are the models doc was made from.
+func (connectionDetails *Client) replaceOne(name string, collectionName string, filter interface{}, doc interface{}, opts []Option, documents ...interface{}) (*mongo.UpdateResult, error) {
+	op := operation{name: name, collection: collectionName, filter: filter, documents: documents, options: newOperationOptions(opts)}
+	doc, err := connectionDetails.encodedDocument(collectionName, doc)
+	if err != nil {
+		return nil, connectionDetails.wrapError(op, err)
+	}
+	doc = connectionDetails.shadowedDocument(collectionName, doc)
+	var replaceResult *mongo.UpdateResult
+	err = connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		replaceResult, err = connectionDetails.collection(db, collectionName, op.options).ReplaceOne(ctx, filter, doc, op.options.replace())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
+	return replaceResult, nil
+}
 
-	collection := db.Collection(collectionName)
-	insertResult, err := collection.DeleteOne(connectionDetails.Context, filter)
+// Delete deletes a document by ID only.
+func (connectionDetails *Client) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	filter := bson.M{"_id": id}
+	op := operation{name: "Delete", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var deleteResult *mongo.DeleteResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		deleteResult, err = connectionDetails.collection(db, collectionName, op.options).DeleteOne(ctx, filter, op.options.delete())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return insertResult, nil
+	return deleteResult, nil
 }
 
-// DeleteMany deletes many documents - bson.M{}, bson.A{}, or bson.D{}
-func (connectionDetails *Client) DeleteMany(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
-	client, err := connectionDetails.client()
+// DeleteCustom deletes a document by a filter - bson.M{}, bson.A{}, or bson.D{}
+func (connectionDetails *Client) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "DeleteCustom", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var deleteResult *mongo.DeleteResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		deleteResult, err = connectionDetails.collection(db, collectionName, op.options).DeleteOne(ctx, filter, op.options.delete())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
+	return deleteResult, nil
+}
 
-	collection := db.Collection(collectionName)
-	insertResult, err := collection.DeleteMany(connectionDetails.Context, filter)
+// DeleteMany deletes many documents - bson.M{}, bson.A{}, or bson.D{}
+func (connectionDetails *Client) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "DeleteMany", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var deleteResult *mongo.DeleteResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		deleteResult, err = connectionDetails.collection(db, collectionName, op.options).DeleteMany(ctx, filter, op.options.delete())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return insertResult, nil
+	return deleteResult, nil
 }
 
 // Get finds one document based on "_id"
-func (connectionDetails *Client) Get(collectionName string, id string) (*mongo.SingleResult, error) {
-	client, err := connectionDetails.client()
+func (connectionDetails *Client) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	filter := bson.M{"_id": id}
+	op := operation{name: "Get", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var findOne *mongo.SingleResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		findOne = connectionDetails.collection(db, collectionName, op.options).FindOne(ctx, filter, op.options.findOne())
+		findOne = connectionDetails.decodedResult(collectionName, findOne)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	findOne := collection.FindOne(connectionDetails.Context, bson.M{"_id": id})
-
 	return findOne, nil
 }
 
 // GetCustom finds one document by a filter - bson.M{}, bson.A{}, or bson.D{}
-func (connectionDetails *Client) GetCustom(collectionName string, filter interface{}) (*mongo.SingleResult, error) {
-	client, err := connectionDetails.client()
+func (connectionDetails *Client) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "GetCustom", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var findOne *mongo.SingleResult
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		findOne = connectionDetails.collection(db, collectionName, op.options).FindOne(ctx, filter, op.options.findOne())
+		findOne = connectionDetails.decodedResult(collectionName, findOne)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
-		if err != nil {
-			return
-		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	findOne := collection.FindOne(connectionDetails.Context, filter)
-
 	return findOne, nil
 }
 
 // GetAll finds all documents by "_id".
 //
 // The 'result' parameter needs to be a pointer.
-func (connectionDetails *Client) GetAll(collectionName string, id string, result interface{}) error {
-	client, err := connectionDetails.client()
-	if err != nil {
-		return err
-	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
+func (connectionDetails *Client) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	filter := bson.M{"_id": id}
+	op := operation{name: "GetAll", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, filter, op.options.find())
 		if err != nil {
-			return
+			return err
 		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	find, err := collection.Find(connectionDetails.Context, bson.M{"_id": id})
-	if err != nil {
-		return err
-	}
-
-	if err = find.All(connectionDetails.Context, result); err != nil {
-		return err
-	}
-
-	return nil
+		return connectionDetails.decodeAll(ctx, collectionName, find, result)
+	})
 }
 
 // GetAllCustom finds all documents by filter - bson.M{}, bson.A{}, or bson.D{}.
 //
 // The 'result' parameter needs to be a pointer.
-func (connectionDetails *Client) GetAllCustom(collectionName string, filter interface{}, result interface{}) error {
-	client, err := connectionDetails.client()
-	if err != nil {
-		return err
-	}
-	defer func(client *mongo.Client, ctx context.Context) {
-		err := client.Disconnect(connectionDetails.Context)
+func (connectionDetails *Client) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "GetAllCustom", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, filter, op.options.find())
 		if err != nil {
-			return
+			return err
 		}
-	}(client, connectionDetails.Context)
-	db := client.Database(connectionDetails.DatabaseName)
-
-	collection := db.Collection(collectionName)
-	find, err := collection.Find(connectionDetails.Context, filter)
-	if err != nil {
-		return err
-	}
-
-	if err = find.All(connectionDetails.Context, result); err != nil {
-		return err
-	}
-
-	return nil
+		return connectionDetails.decodeAll(ctx, collectionName, find, result)
+	})
 }
 
 // Collection returns mongo.Collection
@@ -327,11 +379,11 @@ func (connectionDetails *Client) GetAllCustom(collectionName string, filter inte
 func (connectionDetails *Client) Collection(collectionName string) (*mongo.Collection, *mongo.Client, context.Context, error) {
 	client, err := connectionDetails.client()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, connectionDetails.wrapError(operation{name: "Collection", collection: collectionName}, err)
 	}
 	db := client.Database(connectionDetails.DatabaseName)
 
-	collection := db.Collection(collectionName)
+	collection := connectionDetails.collection(db, collectionName, nil)
 	return collection, client, connectionDetails.Context, nil
 }
 
@@ -339,7 +391,7 @@ func (connectionDetails *Client) Collection(collectionName string) (*mongo.Colle
 func (connectionDetails *Client) DB() (*mongo.Database, error) {
 	client, err := connectionDetails.client()
 	if err != nil {
-		return nil, err
+		return nil, connectionDetails.wrapError(operation{name: "DB"}, err)
 	}
 	defer func(client *mongo.Client, ctx context.Context) {
 		err := client.Disconnect(connectionDetails.Context)
@@ -354,16 +406,22 @@ func (connectionDetails *Client) DB() (*mongo.Database, error) {
 
 // RawClient returns mongo.Client
 func (connectionDetails *Client) RawClient() (*mongo.Client, error) {
-	return connectionDetails.client()
+	client, err := connectionDetails.client()
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "RawClient"}, err)
+	}
+	return client, nil
 }
 
 func (connectionDetails *Client) client() (*mongo.Client, error) {
 	// connectionDetails.Context, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	// defer cancel()
-	client, err := mongo.Connect(connectionDetails.Context, options.Client().ApplyURI(connectionDetails.ConnectionUrl))
+	client, err := mongo.Connect(connectionDetails.Context, connectionDetails.clientOptions())
 	if err != nil {
+		connectionDetails.log().Warn("mongo: unable to connect", "database", connectionDetails.DatabaseName, "error", err)
 		return nil, err
 	}
+	connectionDetails.log().Debug("mongo: connected", "database", connectionDetails.DatabaseName)
 
 	return client, nil
 }
@@ -0,0 +1,245 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Redacted replaces the value of sensitive fields in commands reported to a CommandMonitor.
+const Redacted = "REDACTED"
+
+// defaultRedactedFields are field names whose values are always redacted, matched case-insensitively.
+var defaultRedactedFields = []string{
+	"password", "pwd", "passwd", "secret", "token", "accesstoken", "refreshtoken", "apikey", "api_key",
+	"authorization", "credentials", "signature",
+}
+
+// sensitiveCommands are commands whose body is never reported.
+var sensitiveCommands = map[string]bool{
+	"authenticate":    true,
+	"saslstart":       true,
+	"saslcontinue":    true,
+	"getnonce":        true,
+	"createuser":      true,
+	"updateuser":      true,
+	"copydbgetnonce":  true,
+	"copydbsaslstart": true,
+	"copydb":          true,
+}
+
+// CommandMonitor receives the commands Client sends to MongoDB, see WithCommandMonitor.
+//
+// Any of the functions can be nil. The events of a command share its RequestID, so Started can be matched with
+// Succeeded or Failed.
+type CommandMonitor struct {
+	Started   func(CommandStartedEvent)
+	Succeeded func(CommandSucceededEvent)
+	Failed    func(CommandFailedEvent)
+}
+
+// CommandStartedEvent is reported when a command is sent to MongoDB.
+type CommandStartedEvent struct {
+	// Operation is the name of the Client method that sent the command
+	Operation   string
+	Database    string
+	CommandName string
+	RequestID   int64
+
+	// Filter of the Client method as extended JSON, redacted like Command and truncated like OperationError.Filter,
+	// empty when it has none
+	Filter string
+
+	// Command with the values of sensitive fields replaced by Redacted, nil for authentication commands
+	Command bson.D
+}
+
+// CommandSucceededEvent is reported when a command succeeds.
+type CommandSucceededEvent struct {
+	Operation   string
+	Database    string
+	CommandName string
+	RequestID   int64
+	Filter      string
+	Duration    time.Duration
+}
+
+// CommandFailedEvent is reported when a command fails.
+type CommandFailedEvent struct {
+	Operation   string
+	Database    string
+	CommandName string
+	RequestID   int64
+	Filter      string
+	Duration    time.Duration
+	Failure     string
+}
+
+// commandMonitor returns the driver monitor feeding the payload metrics and the registered CommandMonitors, or nil
+// when neither is used.
+func (connectionDetails *Client) commandMonitor() *event.CommandMonitor {
+	replay := connectionDetails.replay
+	if len(connectionDetails.metricsHooks) == 0 && len(connectionDetails.commandMonitors) == 0 && connectionDetails.logger == nil && replay == nil {
+		return nil
+	}
+	monitors := connectionDetails.commandMonitors
+	logger := connectionDetails.log()
+	redactor := newRedactor(connectionDetails.redactedFields)
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			state := operationFromContext(ctx)
+			if state != nil {
+				state.payload.commands.Add(1)
+				state.payload.requestBytes.Add(int64(len(evt.Command)))
+			}
+			if replay != nil {
+				replay.started(state.name(), evt.DatabaseName, evt.CommandName, evt.RequestID, evt.Command)
+			}
+			if len(monitors) == 0 {
+				return
+			}
+			started := CommandStartedEvent{
+				Operation:   state.name(),
+				Database:    evt.DatabaseName,
+				CommandName: evt.CommandName,
+				RequestID:   evt.RequestID,
+				Filter:      state.filter(redactor),
+				Command:     redactor.command(evt.CommandName, evt.Command),
+			}
+			for _, monitor := range monitors {
+				if monitor.Started != nil {
+					monitor.Started(started)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			state := operationFromContext(ctx)
+			if state != nil {
+				state.payload.responseBytes.Add(int64(len(evt.Reply)))
+			}
+			if replay != nil {
+				if err := replay.succeeded(evt.RequestID); err != nil {
+					logger.Warn("mongo: unable to write replay log", "operation", state.name(), "command", evt.CommandName, "error", err)
+				}
+			}
+			if len(monitors) == 0 {
+				return
+			}
+			succeeded := CommandSucceededEvent{
+				Operation:   state.name(),
+				Database:    evt.DatabaseName,
+				CommandName: evt.CommandName,
+				RequestID:   evt.RequestID,
+				Filter:      state.filter(redactor),
+				Duration:    evt.Duration,
+			}
+			for _, monitor := range monitors {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(succeeded)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			state := operationFromContext(ctx)
+			logger.Warn("mongo: command failed", "operation", state.name(), "command", evt.CommandName,
+				"database", evt.DatabaseName, "duration", evt.Duration, "failure", evt.Failure)
+			if replay != nil {
+				replay.failed(evt.RequestID)
+			}
+			if len(monitors) == 0 {
+				return
+			}
+			failed := CommandFailedEvent{
+				Operation:   state.name(),
+				Database:    evt.DatabaseName,
+				CommandName: evt.CommandName,
+				RequestID:   evt.RequestID,
+				Filter:      state.filter(redactor),
+				Duration:    evt.Duration,
+				Failure:     evt.Failure,
+			}
+			for _, monitor := range monitors {
+				if monitor.Failed != nil {
+					monitor.Failed(failed)
+				}
+			}
+		},
+	}
+}
+
+// redactor replaces the values of sensitive fields.
+type redactor struct {
+	fields map[string]bool
+}
+
+func newRedactor(extra []string) redactor {
+	fields := make(map[string]bool, len(defaultRedactedFields)+len(extra))
+	for _, field := range defaultRedactedFields {
+		fields[field] = true
+	}
+	for _, field := range extra {
+		fields[strings.ToLower(field)] = true
+	}
+	return redactor{fields: fields}
+}
+
+// command decodes and redacts a raw command.
+func (r redactor) command(name string, raw bson.Raw) bson.D {
+	if sensitiveCommands[strings.ToLower(name)] || len(raw) == 0 {
+		return nil
+	}
+	var command bson.D
+	if err := bson.Unmarshal(raw, &command); err != nil {
+		return nil
+	}
+	return r.value(command).(bson.D)
+}
+
+// value returns a copy of v with the values of sensitive fields replaced by Redacted.
+func (r redactor) value(v interface{}) interface{} {
+	switch doc := v.(type) {
+	case bson.D:
+		redacted := make(bson.D, len(doc))
+		for i, elem := range doc {
+			redacted[i] = bson.E{Key: elem.Key, Value: r.field(elem.Key, elem.Value)}
+		}
+		return redacted
+	case bson.M:
+		redacted := make(bson.M, len(doc))
+		for key, value := range doc {
+			redacted[key] = r.field(key, value)
+		}
+		return redacted
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(doc))
+		for key, value := range doc {
+			redacted[key] = r.field(key, value)
+		}
+		return redacted
+	case bson.A:
+		redacted := make(bson.A, len(doc))
+		for i, value := range doc {
+			redacted[i] = r.value(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(doc))
+		for i, value := range doc {
+			redacted[i] = r.value(value)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+func (r redactor) field(key string, value interface{}) interface{} {
+	if r.fields[strings.ToLower(key)] {
+		return Redacted
+	}
+	return r.value(value)
+}
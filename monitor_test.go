@@ -0,0 +1,73 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestRedactor_value(t *testing.T) {
+	r := newRedactor([]string{"SSN"})
+
+	redacted := r.value(bson.M{
+		"name":     "Akshay",
+		"Password": "secret",
+		"ssn":      "123",
+		"profile":  bson.D{{Key: "token", Value: "abc"}},
+		"sessions": bson.A{bson.M{"apiKey": "xyz"}},
+	}).(bson.M)
+
+	if redacted["name"] != "Akshay" {
+		t.Errorf("Expected name to be kept, got %v", redacted["name"])
+	}
+	if redacted["Password"] != Redacted || redacted["ssn"] != Redacted {
+		t.Errorf("Expected sensitive fields to be redacted, got %v", redacted)
+	}
+	if redacted["profile"].(bson.D)[0].Value != Redacted {
+		t.Errorf("Expected nested field to be redacted, got %v", redacted["profile"])
+	}
+	if redacted["sessions"].(bson.A)[0].(bson.M)["apiKey"] != Redacted {
+		t.Errorf("Expected field in array to be redacted, got %v", redacted["sessions"])
+	}
+}
+
+func TestRedactor_command(t *testing.T) {
+	r := newRedactor(nil)
+
+	raw, _ := bson.Marshal(bson.D{{Key: "saslStart", Value: 1}, {Key: "payload", Value: "abc"}})
+	if command := r.command("saslStart", raw); command != nil {
+		t.Errorf("Expected authentication command to be dropped, got %v", command)
+	}
+
+	raw, _ = bson.Marshal(bson.D{{Key: "insert", Value: "users"}, {Key: "documents", Value: bson.A{bson.M{"pwd": "x"}}}})
+	command := r.command("insert", raw)
+	documents := command[1].Value.(bson.A)
+	if documents[0].(bson.D)[0].Value != Redacted {
+		t.Errorf("Expected pwd to be redacted, got %v", command)
+	}
+}
+
+func TestWithCommandMonitor(t *testing.T) {
+	var started CommandStartedEvent
+	var failed CommandFailedEvent
+	monitorClient := NewMongoClientDefault("mongodb://localhost:27017", "test", WithCommandMonitor(CommandMonitor{
+		Started: func(evt CommandStartedEvent) { started = evt },
+		Failed:  func(evt CommandFailedEvent) { failed = evt },
+	}))
+	ctx := context.WithValue(context.Background(), operationKey{}, &operationState{operation: operation{name: "Add", filter: bson.M{"token": "abc"}}})
+
+	raw, _ := bson.Marshal(bson.D{{Key: "insert", Value: "users"}, {Key: "token", Value: "abc"}})
+	monitor := monitorClient.commandMonitor()
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: raw, CommandName: "insert", DatabaseName: "test"})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert"}, Failure: "boom"})
+
+	if started.Operation != "Add" || started.CommandName != "insert" || started.Command[1].Value != Redacted ||
+		started.Filter != `{"token":"REDACTED"}` {
+		t.Errorf("Unexpected started event %+v", started)
+	}
+	if failed.Operation != "Add" || failed.Failure != "boom" || failed.Filter != started.Filter {
+		t.Errorf("Unexpected failed event %+v", failed)
+	}
+}
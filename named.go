@@ -0,0 +1,112 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnknownQuery is returned by RunNamed for a name no query is registered with.
+var ErrUnknownQuery = errors.New("mongo: unknown named query")
+
+// NamedQuery is a query registered by name, see RegisterQuery. It decodes its documents in result, a pointer, opts
+// are the options RunNamed was called with, starting with a Comment of the query name.
+type NamedQuery func(client *Client, params Params, result interface{}, opts ...Option) error
+
+// queryRegistry holds the named queries of a Client and of its views.
+type queryRegistry struct {
+	mu      sync.RWMutex
+	queries map[string]NamedQuery
+}
+
+// WithNamedQueries registers queries by name, see RegisterQuery.
+func WithNamedQueries(queries map[string]NamedQuery) ClientOption {
+	return func(client *Client) {
+		for name, query := range queries {
+			client.RegisterQuery(name, query)
+		}
+	}
+}
+
+// RegisterQuery registers query as name so it can be run with RunNamed. Complex pipelines can then be defined in one
+// place, away from the business logic running them:
+//
+//	client.RegisterQuery("dailyRevenue", func(client *mongo.Client, params mongo.Params, result interface{}, opts ...mongo.Option) error {
+//		return client.Aggregate("orders", revenuePipeline(params["day"].(time.Time)), result, opts...)
+//	})
+//
+// FindQuery registers a filter template. Registering a name again replaces its query. The queries are shared by the
+// views of the Client, like the ones of WithContext and WithDatabase.
+func (connectionDetails *Client) RegisterQuery(name string, query NamedQuery) {
+	if connectionDetails.queries == nil {
+		connectionDetails.queries = &queryRegistry{}
+	}
+	registry := connectionDetails.queries
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.queries == nil {
+		registry.queries = map[string]NamedQuery{}
+	}
+	registry.queries[name] = query
+}
+
+// OverrideQuery replaces the query registered as name, typically with a stub in tests, until restore is called:
+//
+//	t.Cleanup(client.OverrideQuery("dailyRevenue", func(client *mongo.Client, params mongo.Params, result interface{}, opts ...mongo.Option) error {
+//		*result.(*[]Revenue) = []Revenue{{Total: 42}}
+//		return nil
+//	}))
+//
+// restore registers the previous query again, or removes name when it was not registered.
+func (connectionDetails *Client) OverrideQuery(name string, query NamedQuery) (restore func()) {
+	previous, ok := connectionDetails.namedQuery(name)
+	connectionDetails.RegisterQuery(name, query)
+	return func() {
+		if ok {
+			connectionDetails.RegisterQuery(name, previous)
+			return
+		}
+		registry := connectionDetails.queries
+		registry.mu.Lock()
+		delete(registry.queries, name)
+		registry.mu.Unlock()
+	}
+}
+
+// RunNamed runs the query registered as name with params, decoding its documents in result. An error wrapping
+// ErrUnknownQuery is returned when no query is registered as name.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) RunNamed(name string, params Params, result interface{}, opts ...Option) error {
+	query, ok := connectionDetails.namedQuery(name)
+	if !ok {
+		return connectionDetails.wrapError(operation{name: "RunNamed"}, fmt.Errorf("%w: %q", ErrUnknownQuery, name))
+	}
+	return query(connectionDetails, params, result, append([]Option{Comment(name)}, opts...)...)
+}
+
+// namedQuery returns the query registered as name.
+func (connectionDetails *Client) namedQuery(name string) (NamedQuery, bool) {
+	registry := connectionDetails.queries
+	if registry == nil {
+		return nil, false
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	query, ok := registry.queries[name]
+	return query, ok
+}
+
+// FindQuery returns a NamedQuery finding the documents of collectionName matching filterTemplate, a filter with Param
+// placeholders bound to the params of RunNamed, see Prepare. opts are applied before the options of RunNamed.
+func FindQuery(collectionName string, filterTemplate interface{}, opts ...Option) NamedQuery {
+	skeleton, err := toDocument(filterTemplate)
+	names := templateParams(skeleton)
+	return func(client *Client, params Params, result interface{}, runOpts ...Option) error {
+		if err != nil {
+			return client.wrapError(operation{name: "RunNamed", collection: collectionName}, err)
+		}
+		query := &PreparedQuery{Collection: collectionName, client: client, skeleton: skeleton, params: names, opts: opts}
+		return query.All(params, result, runOpts...)
+	}
+}
@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_RunNamed(t *testing.T) {
+	var comment string
+	offline := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithNamedQueries(map[string]NamedQuery{
+		"count": func(client *Client, params Params, result interface{}, opts ...Option) error {
+			comment = newOperationOptions(opts).comment
+			*result.(*int) = params["n"].(int)
+			return nil
+		},
+	}))
+
+	var got int
+	if err := offline.WithDatabase("other").RunNamed("count", Params{"n": 2}, &got); err != nil || got != 2 {
+		t.Fatalf("RunNamed() = %d, %v", got, err)
+	}
+	if comment != "count" {
+		t.Errorf("Expected the query name as comment, got %q", comment)
+	}
+
+	restore := offline.OverrideQuery("count", func(client *Client, params Params, result interface{}, opts ...Option) error {
+		*result.(*int) = 42
+		return nil
+	})
+	if err := offline.RunNamed("count", nil, &got); err != nil || got != 42 {
+		t.Errorf("Expected the override to run, got %d, %v", got, err)
+	}
+	restore()
+	if err := offline.RunNamed("count", Params{"n": 3}, &got); err != nil || got != 3 {
+		t.Errorf("Expected restore to register the query again, got %d, %v", got, err)
+	}
+
+	restore = offline.OverrideQuery("stub", func(client *Client, params Params, result interface{}, opts ...Option) error { return nil })
+	restore()
+	if err := offline.RunNamed("stub", nil, &got); !errors.Is(err, ErrUnknownQuery) {
+		t.Errorf("Expected ErrUnknownQuery after restoring a new name, got %v", err)
+	}
+}
+
+func TestFindQuery(t *testing.T) {
+	offline := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	offline.RegisterQuery("byName", FindQuery("users", bson.M{"name": Param("name")}))
+	if err := offline.RunNamed("byName", Params{}, &[]bson.M{}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a missing parameter, got %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package mongo
+
+import (
+	"regexp"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizedSuffix is appended to the name of a field of WithNormalizedFields to name its shadow field.
+const NormalizedSuffix = "_normalized"
+
+// WithNormalizedFields stores a normalized copy of the top level string fields of the documents of collectionName,
+// see Normalize, in shadow fields named after them with NormalizedSuffix. Add, AddMany, Update, UpdateCustom,
+// UpdateMany, Replace and Save write the shadow fields, and the filters of the reads, updates and deletes made with
+// the Normalized option are sent to them:
+//
+//	client := mongo.NewMongoClientDefault(uri, "shop", mongo.WithNormalizedFields("users", "name", "email"))
+//	client.GetAllCustom("users", bson.M{"name": "ZOË"}, &users, mongo.Normalized()) // matches "Zoe" and "zoë"
+//
+// Equality, $ne, $in, $nin and prefix regular expressions like "^jo" are routed, other conditions stay on the field.
+// Unlike a case-insensitive collation an ordinary index on the shadow field serves them, create one. Documents written
+// before the option was set have no shadow fields until they are written again, only queries without Normalized
+// match them.
+func WithNormalizedFields(collectionName string, fields ...string) ClientOption {
+	return withShadow(collectionName, fields, shadow{suffix: NormalizedSuffix, encode: func(value string) interface{} {
+		return Normalize(value)
+	}})
+}
+
+// shadow is a shadow field derived from a string field when it is written.
+type shadow struct {
+	suffix string
+	encode func(value string) interface{}
+}
+
+// shadowFields are the shadows of the fields of a collection, by field.
+type shadowFields map[string][]shadow
+
+// with returns the fields having the shadow named with suffix.
+func (fields shadowFields) with(suffix string) map[string]bool {
+	with := map[string]bool{}
+	for field, shadows := range fields {
+		for _, shadow := range shadows {
+			if shadow.suffix == suffix {
+				with[field] = true
+			}
+		}
+	}
+	return with
+}
+
+// withShadow adds shadow to fields of collectionName.
+func withShadow(collectionName string, fields []string, shadow shadow) ClientOption {
+	return func(client *Client) {
+		if client.shadows == nil {
+			client.shadows = map[string]shadowFields{}
+		}
+		if client.shadows[collectionName] == nil {
+			client.shadows[collectionName] = shadowFields{}
+		}
+		for _, field := range fields {
+			client.shadows[collectionName][field] = append(client.shadows[collectionName][field], shadow)
+		}
+	}
+}
+
+// Normalize folds the case of s and removes its diacritics, "Crème BRÛLÉE" becomes "creme brulee" and "Straße"
+// becomes "strasse".
+func Normalize(s string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		stripped = s
+	}
+	return cases.Fold().String(stripped)
+}
+
+// shadowedDocument returns data with the shadow fields of the fields of collectionName, data is returned as is when
+// the collection has none.
+func (connectionDetails *Client) shadowedDocument(collectionName string, data interface{}) interface{} {
+	fields := connectionDetails.shadows[collectionName]
+	if len(fields) == 0 {
+		return data
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		// the write fails on the same error
+		return data
+	}
+	return withShadowFields(fields, doc)
+}
+
+// shadowedDocuments is shadowedDocument for the documents of AddMany.
+func (connectionDetails *Client) shadowedDocuments(collectionName string, data []interface{}) []interface{} {
+	if len(connectionDetails.shadows[collectionName]) == 0 {
+		return data
+	}
+	docs := make([]interface{}, len(data))
+	for i, item := range data {
+		docs[i] = connectionDetails.shadowedDocument(collectionName, item)
+	}
+	return docs
+}
+
+// shadowedUpdate returns the update document with the shadow fields of the fields it sets or unsets.
+func (connectionDetails *Client) shadowedUpdate(collectionName string, update interface{}) interface{} {
+	fields := connectionDetails.shadows[collectionName]
+	if len(fields) == 0 {
+		return update
+	}
+	doc, err := toDocument(update)
+	if err != nil {
+		return update
+	}
+	for i, elem := range doc {
+		if elem.Key != "$set" && elem.Key != "$unset" {
+			continue
+		}
+		set, err := toDocument(elem.Value)
+		if err != nil {
+			return update
+		}
+		if elem.Key == "$set" {
+			doc[i].Value = withShadowFields(fields, set)
+			continue
+		}
+		for _, unset := range set {
+			for _, shadow := range fields[unset.Key] {
+				set = append(set, bson.E{Key: unset.Key + shadow.suffix, Value: ""})
+			}
+		}
+		doc[i].Value = set
+	}
+	return doc
+}
+
+// withShadowFields replaces the shadow fields of doc with the ones derived from its fields.
+func withShadowFields(fields shadowFields, doc bson.D) bson.D {
+	shadowed := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if !isShadowField(fields, elem.Key) {
+			shadowed = append(shadowed, elem)
+		}
+	}
+	for _, elem := range doc {
+		if value, ok := elem.Value.(string); ok {
+			for _, shadow := range fields[elem.Key] {
+				shadowed = append(shadowed, bson.E{Key: elem.Key + shadow.suffix, Value: shadow.encode(value)})
+			}
+		}
+	}
+	return shadowed
+}
+
+// isShadowField reports whether key names a shadow field of fields.
+func isShadowField(fields shadowFields, key string) bool {
+	for field, shadows := range fields {
+		for _, shadow := range shadows {
+			if key == field+shadow.suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Normalized sends the conditions of a filter on the fields of WithNormalizedFields to their shadow fields, so they
+// match the values whatever their case and diacritics. Without it the filter is sent as is.
+func Normalized() Option {
+	return func(o *operationOptions) {
+		o.normalized = true
+	}
+}
+
+// normalizedFilter returns filter with the conditions on the normalized fields of collectionName sent to their shadow
+// fields when opts has Normalized, filter is returned as is otherwise or when the collection has none.
+func (connectionDetails *Client) normalizedFilter(collectionName string, filter interface{}, opts []Option) interface{} {
+	fields := connectionDetails.shadows[collectionName].with(NormalizedSuffix)
+	if len(fields) == 0 || filter == nil || !newOperationOptions(opts).normalized {
+		return filter
+	}
+	doc, err := toDocument(filter)
+	if err != nil {
+		return filter
+	}
+	return routeNormalized(fields, doc)
+}
+
+// routeNormalized sends the conditions of doc on fields to their shadow fields, following $and, $or and $nor.
+func routeNormalized(fields map[string]bool, doc bson.D) bson.D {
+	routed := make(bson.D, len(doc))
+	for i, elem := range doc {
+		routed[i] = elem
+		switch {
+		case elem.Key == "$and" || elem.Key == "$or" || elem.Key == "$nor":
+			clauses, ok := elem.Value.(bson.A)
+			if !ok {
+				continue
+			}
+			routedClauses := make(bson.A, len(clauses))
+			for j, clause := range clauses {
+				if clause, ok := clause.(bson.D); ok {
+					routedClauses[j] = routeNormalized(fields, clause)
+				} else {
+					routedClauses[j] = clause
+				}
+			}
+			routed[i].Value = routedClauses
+		case fields[elem.Key]:
+			if condition, ok := normalizedCondition(elem.Value); ok {
+				routed[i] = bson.E{Key: elem.Key + NormalizedSuffix, Value: condition}
+			}
+		}
+	}
+	return routed
+}
+
+// normalizedCondition returns the condition on a shadow field equivalent to condition, false when it cannot be
+// expressed on the normalized values.
+func normalizedCondition(condition interface{}) (interface{}, bool) {
+	switch condition := condition.(type) {
+	case string:
+		return Normalize(condition), true
+	case primitive.Regex:
+		pattern, ok := normalizedPrefix(condition.Pattern)
+		return primitive.Regex{Pattern: pattern, Options: condition.Options}, ok
+	case bson.D:
+		normalized := make(bson.D, len(condition))
+		for i, elem := range condition {
+			normalized[i].Key = elem.Key
+			switch value := elem.Value.(type) {
+			case string:
+				if elem.Key == "$regex" {
+					pattern, ok := normalizedPrefix(value)
+					if !ok {
+						return nil, false
+					}
+					normalized[i].Value = pattern
+				} else if elem.Key == "$eq" || elem.Key == "$ne" {
+					normalized[i].Value = Normalize(value)
+				} else if elem.Key == "$options" {
+					normalized[i].Value = value
+				} else {
+					return nil, false
+				}
+			case bson.A:
+				if elem.Key != "$in" && elem.Key != "$nin" {
+					return nil, false
+				}
+				values := make(bson.A, len(value))
+				for j, v := range value {
+					s, ok := v.(string)
+					if !ok {
+						return nil, false
+					}
+					values[j] = Normalize(s)
+				}
+				normalized[i].Value = values
+			case primitive.Regex:
+				pattern, ok := normalizedPrefix(value.Pattern)
+				if elem.Key != "$regex" || !ok {
+					return nil, false
+				}
+				normalized[i].Value = primitive.Regex{Pattern: pattern, Options: value.Options}
+			default:
+				return nil, false
+			}
+		}
+		return normalized, true
+	default:
+		return nil, false
+	}
+}
+
+// normalizedPrefix returns the regular expression matching the normalized values starting with the literal prefix
+// of pattern, false when pattern is not an anchored literal prefix like "^jo".
+func normalizedPrefix(pattern string) (string, bool) {
+	if len(pattern) < 2 || pattern[0] != '^' || regexp.QuoteMeta(pattern[1:]) != pattern[1:] {
+		return "", false
+	}
+	return "^" + regexp.QuoteMeta(Normalize(pattern[1:])), true
+}
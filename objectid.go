@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrInvalidObjectID is returned by the ObjectID variants of the ID-based operations for an id that is neither a
+// primitive.ObjectID nor its hex string.
+var ErrInvalidObjectID = errors.New("mongo: invalid ObjectID")
+
+// GetByObjectID finds one document by its ObjectID "_id", id is a primitive.ObjectID or its hex string like the one
+// received in a URL. Get matches a string "_id" and never finds these documents.
+func (connectionDetails *Client) GetByObjectID(collectionName string, id interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	filter, err := objectIDFilter(id)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "GetCustom", collection: collectionName}, err)
+	}
+	return connectionDetails.GetCustom(collectionName, filter, opts...)
+}
+
+// UpdateByObjectID updates the values of the document with the ObjectID "_id" id, see GetByObjectID.
+func (connectionDetails *Client) UpdateByObjectID(collectionName string, id interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	filter, err := objectIDFilter(id)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "UpdateCustom", collection: collectionName}, err)
+	}
+	return connectionDetails.UpdateCustom(collectionName, filter, data, opts...)
+}
+
+// ReplaceByObjectID replaces the whole document with the ObjectID "_id" id, see GetByObjectID and Replace.
+func (connectionDetails *Client) ReplaceByObjectID(collectionName string, id interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	filter, err := objectIDFilter(id)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "Replace", collection: collectionName}, err)
+	}
+	if err := connectionDetails.validate(operation{name: "Replace", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	return connectionDetails.replaceOne("Replace", collectionName, filter, data, opts, data)
+}
+
+// DeleteByObjectID deletes the document with the ObjectID "_id" id, see GetByObjectID.
+func (connectionDetails *Client) DeleteByObjectID(collectionName string, id interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	filter, err := objectIDFilter(id)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "DeleteCustom", collection: collectionName}, err)
+	}
+	return connectionDetails.DeleteCustom(collectionName, filter, opts...)
+}
+
+// objectIDFilter returns the filter of the document with the ObjectID "_id" id, a primitive.ObjectID or its hex string.
+func objectIDFilter(id interface{}) (bson.D, error) {
+	switch id := id.(type) {
+	case primitive.ObjectID:
+		return bson.D{{Key: "_id", Value: id}}, nil
+	case string:
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidObjectID, id)
+		}
+		return bson.D{{Key: "_id", Value: objectID}}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidObjectID, id)
+	}
+}
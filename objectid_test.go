@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestObjectIDFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	tests := []struct {
+		name string
+		id   interface{}
+		want bson.D
+		err  error
+	}{
+		{"ObjectID", id, bson.D{{Key: "_id", Value: id}}, nil},
+		{"hex", id.Hex(), bson.D{{Key: "_id", Value: id}}, nil},
+		{"invalid hex", "akshay", nil, ErrInvalidObjectID},
+		{"other type", 42, nil, ErrInvalidObjectID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := objectIDFilter(tt.id)
+			if !errors.Is(err, tt.err) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("objectIDFilter() = %v, %v, want %v, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestGetByObjectID_invalid(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	if _, err := client.GetByObjectID("users", "not-an-id"); !errors.Is(err, ErrInvalidObjectID) {
+		t.Errorf("Expected ErrInvalidObjectID, got %v", err)
+	}
+	if _, err := client.DeleteByObjectID("users", 1); !errors.Is(err, ErrInvalidObjectID) {
+		t.Errorf("Expected ErrInvalidObjectID, got %v", err)
+	}
+}
+
+func TestObjectIDOperations(t *testing.T) {
+	_ = client.DropCollections([]string{"objectids"})
+	result, err := client.Add("objectids", bson.M{"name": "Akshay"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	id := result.InsertedID.(primitive.ObjectID)
+
+	if _, err := client.UpdateByObjectID("objectids", id.Hex(), bson.M{"name": "Raj"}); err != nil {
+		t.Fatalf("UpdateByObjectID() error = %v", err)
+	}
+	found, err := client.GetByObjectID("objectids", id.Hex())
+	if err != nil {
+		t.Fatalf("GetByObjectID() error = %v", err)
+	}
+	var doc bson.M
+	if err := found.Decode(&doc); err != nil || doc["name"] != "Raj" {
+		t.Errorf("GetByObjectID() = %v, %v", doc, err)
+	}
+	deleted, err := client.DeleteByObjectID("objectids", id)
+	if err != nil || deleted.DeletedCount != 1 {
+		t.Errorf("DeleteByObjectID() = %v, %v", deleted, err)
+	}
+}
@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OpCounter counts the operations made with a context, see WithOpCounter.
+type OpCounter struct {
+	mu    sync.Mutex
+	stats OpStats
+}
+
+// OpStats are the operations counted by an OpCounter.
+type OpStats struct {
+	// Operations is the number of operations made, Errors how many of them failed
+	Operations int64
+	Errors     int64
+
+	// Duration is the time spent in the operations, operations running concurrently add up
+	Duration time.Duration
+
+	// ByOperation counts the operations by Client method and collection, like "GetAllCustom users". A method called
+	// many times on the same collection often is an N+1 query.
+	ByOperation map[string]int64
+}
+
+type opCounterKey struct{}
+
+// WithOpCounter returns a copy of ctx with a new OpCounter counting the operations of the Clients using it, usually
+// for the duration of a request:
+//
+//	ctx, counter := mongo.WithOpCounter(r.Context())
+//	handle(w, r.WithContext(ctx)) // uses client.WithContext(r.Context())
+//	stats := counter.Stats()
+//	log.Printf("%s: %d queries, %s in MongoDB", r.URL.Path, stats.Operations, stats.Duration)
+//
+// Operations made with a context derived from ctx are counted too.
+func WithOpCounter(ctx context.Context) (context.Context, *OpCounter) {
+	counter := &OpCounter{}
+	return context.WithValue(ctx, opCounterKey{}, counter), counter
+}
+
+// opCounterFromContext returns the OpCounter of ctx, or nil.
+func opCounterFromContext(ctx context.Context) *OpCounter {
+	if ctx == nil {
+		return nil
+	}
+	counter, _ := ctx.Value(opCounterKey{}).(*OpCounter)
+	return counter
+}
+
+// Stats returns a copy of the statistics counted so far.
+func (counter *OpCounter) Stats() OpStats {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	stats := counter.stats
+	stats.ByOperation = make(map[string]int64, len(counter.stats.ByOperation))
+	for key, count := range counter.stats.ByOperation {
+		stats.ByOperation[key] = count
+	}
+	return stats
+}
+
+// add counts op, which took elapsed and returned err.
+func (counter *OpCounter) add(op operation, elapsed time.Duration, err error) {
+	key := op.name
+	if op.collection != "" {
+		key += " " + op.collection
+	}
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	counter.stats.Operations++
+	if err != nil {
+		counter.stats.Errors++
+	}
+	counter.stats.Duration += elapsed
+	if counter.stats.ByOperation == nil {
+		counter.stats.ByOperation = map[string]int64{}
+	}
+	counter.stats.ByOperation[key]++
+}
@@ -0,0 +1,39 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWithOpCounter(t *testing.T) {
+	if opCounterFromContext(context.Background()) != nil {
+		t.Error("Expected no counter by default")
+	}
+	ctx, counter := WithOpCounter(context.Background())
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test").WithContext(ctx)
+
+	for i := 0; i < 3; i++ {
+		_ = client.Do(OperationInfo{Name: "LoadUser", Collection: "users"}, func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		})
+	}
+	_ = client.Do(OperationInfo{Name: "Ping"}, func(ctx context.Context, db *mongo.Database) error {
+		return errors.New("boom")
+	})
+
+	stats := counter.Stats()
+	if stats.Operations != 4 || stats.Errors != 1 {
+		t.Errorf("Expected 4 operations and 1 error, got %+v", stats)
+	}
+	if want := map[string]int64{"LoadUser users": 3, "Ping": 1}; !reflect.DeepEqual(stats.ByOperation, want) {
+		t.Errorf("Expected %v, got %v", want, stats.ByOperation)
+	}
+	stats.ByOperation["Ping"] = 10
+	if counter.Stats().ByOperation["Ping"] != 1 {
+		t.Error("Expected Stats to return a copy")
+	}
+}
@@ -0,0 +1,216 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// operation describes a single call made through Client.
+type operation struct {
+	name       string
+	collection string
+	filter     interface{}
+	documents  []interface{}
+	options    *operationOptions
+}
+
+// operationState is carried in the context of a running operation so command monitoring can attribute commands to
+// it.
+type operationState struct {
+	operation
+	payload payloadCounter
+
+	// summaryParents are the parents whose summaries the operation may change, by summary name
+	summaryParents map[string][]interface{}
+
+	// intent is the Intent recorded for the operation, kept across retries
+	intent primitive.ObjectID
+}
+
+type operationKey struct{}
+
+// operationFromContext returns the state of the operation running with ctx, or nil.
+func operationFromContext(ctx context.Context) *operationState {
+	state, _ := ctx.Value(operationKey{}).(*operationState)
+	return state
+}
+
+// name returns the operation name, it is safe to call on a nil state.
+func (state *operationState) name() string {
+	if state == nil {
+		return ""
+	}
+	return state.operation.name
+}
+
+// filter returns the redacted summary of the filter of the operation, or an empty string.
+func (state *operationState) filter(r redactor) string {
+	if state == nil {
+		return ""
+	}
+	return summarizeFilter(r, state.operation.filter)
+}
+
+// run connects to MongoDB and calls fn with the configured database. Every Client operation goes through run.
+func (connectionDetails *Client) run(op operation, fn func(ctx context.Context, db *mongo.Database) error) error {
+	if len(connectionDetails.middleware) == 0 {
+		return connectionDetails.runOperation(connectionDetails.Context, op, fn)
+	}
+	next := func(ctx context.Context, info OperationInfo) error {
+		return connectionDetails.runOperation(ctx, op, fn)
+	}
+	for i := len(connectionDetails.middleware) - 1; i >= 0; i-- {
+		next = connectionDetails.middleware[i](next)
+	}
+	return connectionDetails.wrapError(op, next(connectionDetails.Context, op.info(connectionDetails.DatabaseName)))
+}
+
+// runOperation runs op with ctx, see run.
+func (connectionDetails *Client) runOperation(ctx context.Context, op operation, fn func(ctx context.Context, db *mongo.Database) error) error {
+	if err := connectionDetails.checkFederation(op); err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	if connectionDetails.shedding != nil && connectionDetails.shedding.shed(priorityFromContext(ctx)) {
+		return connectionDetails.wrapError(op, ErrLoadShed)
+	}
+	state := &operationState{operation: op}
+	ctx = context.WithValue(ctx, operationKey{}, state)
+
+	start := time.Now()
+	policy := connectionDetails.retryPolicy
+	attempt := 1
+	call := func(ctx context.Context, db *mongo.Database) error {
+		if err := connectionDetails.checkSafety(ctx, db, op); err != nil {
+			return err
+		}
+		write := fn
+		if len(connectionDetails.audited) > 0 {
+			write = func(ctx context.Context, db *mongo.Database) error {
+				return connectionDetails.audit(ctx, db, op, fn)
+			}
+		}
+		if connectionDetails.intents != nil {
+			return connectionDetails.logIntent(ctx, db, op, write)
+		}
+		return write(ctx, db)
+	}
+	if dispatcher := connectionDetails.dispatcher; dispatcher != nil {
+		if err := dispatcher.acquire(ctx, priorityFromContext(ctx)); err != nil {
+			return connectionDetails.wrapError(op, err)
+		}
+		defer dispatcher.release()
+	}
+	if len(connectionDetails.summaries) > 0 {
+		connectionDetails.captureSummaryParents(ctx, state)
+	}
+	err := connectionDetails.exec(ctx, call)
+	for ; err != nil && attempt < policy.attempts() && policy.retryable(err); attempt++ {
+		backoff := policy.backoff(attempt)
+		connectionDetails.log().Warn("mongo: retrying operation", "operation", op.name, "collection", op.collection,
+			"attempt", attempt+1, "backoff", backoff, "error", err)
+		if sleepErr := sleep(ctx, backoff); sleepErr != nil {
+			break
+		}
+		err = connectionDetails.exec(ctx, call)
+	}
+	err = connectionDetails.wrapError(op, err)
+	if err != nil {
+		connectionDetails.log().Warn("mongo: operation failed", "operation", op.name, "collection", op.collection, "error", err)
+	}
+	connectionDetails.maintain(ctx, state, err)
+	if err == nil {
+		afterHooks(op)
+	}
+
+	elapsed := time.Since(start)
+	if connectionDetails.slow != nil {
+		connectionDetails.reportSlow(op, elapsed, err)
+	}
+	if connectionDetails.slos != nil {
+		connectionDetails.slos.record(op, elapsed, err)
+	}
+	if counter := opCounterFromContext(ctx); counter != nil {
+		counter.add(op, elapsed, err)
+	}
+	if len(connectionDetails.metricsHooks) > 0 {
+		connectionDetails.reportMetrics(OperationMetrics{
+			Operation:     op.name,
+			Database:      connectionDetails.DatabaseName,
+			Collection:    op.collection,
+			Duration:      elapsed,
+			Attempts:      attempt,
+			Commands:      state.payload.commands.Load(),
+			RequestBytes:  state.payload.requestBytes.Load(),
+			ResponseBytes: state.payload.responseBytes.Load(),
+			Err:           err,
+		})
+	}
+	return err
+}
+
+// maintain updates the maintained counts, leaderboards, catalogs and summaries and invalidates the record cache after
+// an operation, err is the error it returned.
+func (connectionDetails *Client) maintain(ctx context.Context, state *operationState, err error) {
+	if len(connectionDetails.maintainedCounts) > 0 {
+		connectionDetails.maintainCounts(ctx, state.operation, err)
+	}
+	if len(connectionDetails.leaderboards) > 0 {
+		connectionDetails.maintainLeaderboards(ctx, state.operation, err)
+	}
+	if len(connectionDetails.catalogs) > 0 {
+		connectionDetails.maintainCatalogs(ctx, state.operation, err)
+	}
+	if len(connectionDetails.summaries) > 0 {
+		connectionDetails.maintainSummaries(ctx, state)
+	}
+	if connectionDetails.records != nil {
+		connectionDetails.invalidateRecords(state.operation)
+	}
+}
+
+// exec calls fn with a connection to the configured database.
+func (connectionDetails *Client) exec(ctx context.Context, fn func(ctx context.Context, db *mongo.Database) error) error {
+	client, release, err := connectionDetails.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return fn(ctx, connectionDetails.database(client))
+}
+
+// database returns the configured database of client, reading with the read preference of NearestRegionClient.
+func (connectionDetails *Client) database(client *mongo.Client) *mongo.Database {
+	if connectionDetails.readPreference != nil {
+		opts := options.Database().SetReadPreference(connectionDetails.readPreference)
+		return client.Database(connectionDetails.DatabaseName, opts)
+	}
+	return client.Database(connectionDetails.DatabaseName)
+}
+
+// collection returns the named collection, configured with the write concern of the operation options o, see
+// writeConcern.
+func (connectionDetails *Client) collection(db *mongo.Database, collectionName string, o *operationOptions) *mongo.Collection {
+	if concern := connectionDetails.writeConcern(collectionName, o); concern != nil {
+		return db.Collection(collectionName, options.Collection().SetWriteConcern(concern))
+	}
+	return db.Collection(collectionName)
+}
+
+// writeConcern returns the write concern of a write to collectionName, or nil for the default one of the Client. A
+// SafetyProfile requiring "majority" wins, then the WriteConcern option of the operation, then the default of the
+// collection set with WithCollectionWriteConcern.
+func (connectionDetails *Client) writeConcern(collectionName string, o *operationOptions) *writeconcern.WriteConcern {
+	if connectionDetails.safety.requiresMajority(collectionName) {
+		return writeconcern.Majority()
+	}
+	if o != nil && o.writeConcern != nil {
+		return o.writeConcern
+	}
+	return connectionDetails.writeConcerns[collectionName]
+}
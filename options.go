@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ClientOption configures optional behaviour of a Client, see NewMongoClient.
+type ClientOption func(*Client)
+
+// WithMetricsHook registers fn to be called with the OperationMetrics of every operation made through the Client.
+//
+// More than one hook can be registered, they are called in the order they were added.
+func WithMetricsHook(fn func(OperationMetrics)) ClientOption {
+	return func(client *Client) {
+		client.metricsHooks = append(client.metricsHooks, fn)
+	}
+}
+
+// WithCompressors enables wire protocol compression using the given compressors - "snappy", "zlib" or "zstd".
+//
+// Note: OperationMetrics always report the uncompressed BSON size of a payload.
+func WithCompressors(compressors ...string) ClientOption {
+	return func(client *Client) {
+		client.compressors = compressors
+	}
+}
+
+// WithCommandMonitor registers monitor to receive every command sent to MongoDB, with the filter of the Client method
+// that sent it and its duration, for example to log or trace them:
+//
+//	client := mongo.NewMongoClient(url, "app", ctx, mongo.WithCommandMonitor(mongo.CommandMonitor{
+//		Succeeded: func(evt mongo.CommandSucceededEvent) {
+//			log.Printf("%s %s %s took %s", evt.Operation, evt.CommandName, evt.Filter, evt.Duration)
+//		},
+//	}))
+//
+// The values of sensitive fields like "password" or "token" are replaced with Redacted, use WithRedactedFields to
+// redact more fields. The functions are called synchronously by the driver, they should return quickly.
+func WithCommandMonitor(monitor CommandMonitor) ClientOption {
+	return func(client *Client) {
+		client.commandMonitors = append(client.commandMonitors, monitor)
+	}
+}
+
+// WithRedactedFields adds field names, matched case-insensitively, whose values are redacted from reported commands.
+func WithRedactedFields(fields ...string) ClientOption {
+	return func(client *Client) {
+		client.redactedFields = append(client.redactedFields, fields...)
+	}
+}
+
+// WithCollectionWriteConcern sets the default write concern of the writes to collectionName, see WriteConcern. An
+// operation can still override it.
+func WithCollectionWriteConcern(collectionName string, w interface{}, journal bool, timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		if client.writeConcerns == nil {
+			client.writeConcerns = map[string]*writeconcern.WriteConcern{}
+		}
+		client.writeConcerns[collectionName] = newWriteConcern(w, journal, timeout)
+	}
+}
+
+// WithAppName sets the application name sent to MongoDB when connecting, it shows up as "appName" in currentOp, the
+// slow query log and Atlas monitoring.
+//
+// By default the appName from the connection URL is used, or the name of the running binary if there is none.
+func WithAppName(name string) ClientOption {
+	return func(client *Client) {
+		client.appName = name
+	}
+}
+
+// defaultAppName returns the name of the running binary.
+func defaultAppName() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// clientOptions builds the driver options used to connect to MongoDB.
+func (connectionDetails *Client) clientOptions() *options.ClientOptions {
+	opts := options.Client().ApplyURI(connectionDetails.ConnectionUrl)
+	if connectionDetails.appName != "" {
+		opts.SetAppName(connectionDetails.appName)
+	} else if opts.AppName == nil {
+		if name := defaultAppName(); name != "" {
+			opts.SetAppName(name)
+		}
+	}
+	if len(connectionDetails.compressors) > 0 {
+		opts.SetCompressors(connectionDetails.compressors)
+	}
+	if monitor := connectionDetails.commandMonitor(); monitor != nil {
+		opts.SetMonitor(monitor)
+	}
+	if monitor := connectionDetails.poolMonitor(); monitor != nil {
+		opts.SetPoolMonitor(monitor)
+	}
+	return opts
+}
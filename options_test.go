@@ -0,0 +1,33 @@
+package mongo
+
+import (
+	"testing"
+)
+
+func TestClient_clientOptions_appName(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts []ClientOption
+		want string
+	}{
+		{"default", "mongodb://localhost:27017", nil, defaultAppName()},
+		{"url", "mongodb://localhost:27017/?appName=billing", nil, "billing"},
+		{"option", "mongodb://localhost:27017/?appName=billing", []ClientOption{WithAppName("orders")}, "orders"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewMongoClientDefault(tt.url, "test", tt.opts...).clientOptions()
+			if opts.AppName == nil || *opts.AppName != tt.want {
+				t.Errorf("Expected appName %s, got %v", tt.want, opts.AppName)
+			}
+		})
+	}
+}
+
+func TestClient_clientOptions_compressors(t *testing.T) {
+	opts := NewMongoClientDefault("mongodb://localhost:27017", "test", WithCompressors("zstd", "snappy")).clientOptions()
+	if len(opts.Compressors) != 2 || opts.Compressors[0] != "zstd" {
+		t.Errorf("Expected compressors to be set, got %v", opts.Compressors)
+	}
+}
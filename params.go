@@ -0,0 +1,168 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// ErrInvalidParams is returned when the parameters bound to a template are missing or unknown.
+var ErrInvalidParams = errors.New("mongo: invalid parameters")
+
+// Params are the values bound to the placeholders of a template, by name.
+type Params map[string]interface{}
+
+// Parameter is a placeholder of a filter or pipeline template, see Param.
+type Parameter struct {
+	Name string
+}
+
+// Param returns a placeholder for the parameter called name, replaced by its value when the template is bound:
+//
+//	filter := bson.M{"org": mongo.Param("org"), "active": true}
+//
+// A placeholder is stored as the document {"$param": name}, which can be written as is in templates kept as JSON.
+// Values are substituted as BSON values, never as text. Documents or arrays holding an operator like {"$ne": null} and
+// strings starting with "$", which an expression reads as a field path like "$owner" or a variable like "$$ROOT", are
+// refused at any depth, so a value cannot change the meaning of the template.
+func Param(name string) Parameter {
+	return Parameter{Name: name}
+}
+
+// MarshalBSONValue marshals the placeholder as {"$param": name}.
+func (p Parameter) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(bson.D{{Key: "$param", Value: p.Name}})
+}
+
+// paramName returns the name of a {"$param": name} placeholder.
+func paramName(value interface{}) (string, bool) {
+	doc, ok := value.(bson.D)
+	if !ok || len(doc) != 1 || doc[0].Key != "$param" {
+		return "", false
+	}
+	name, ok := doc[0].Value.(string)
+	return name, ok
+}
+
+// templateParams returns the sorted names of the placeholders of a normalized template.
+func templateParams(value interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		if name, ok := paramName(value); ok {
+			seen[name] = true
+			return
+		}
+		switch v := value.(type) {
+		case bson.D:
+			for _, elem := range v {
+				walk(elem.Value)
+			}
+		case bson.A:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkParams returns an error wrapping ErrInvalidParams when params is missing one of names, has another one, or has
+// a value holding an operator or a field path.
+func checkParams(names []string, params Params) error {
+	for _, name := range names {
+		if _, ok := params[name]; !ok {
+			return fmt.Errorf("%w: %q is missing", ErrInvalidParams, name)
+		}
+	}
+	for name, value := range params {
+		operator, err := hasOperator(value)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidParams, name, err)
+		}
+		if operator {
+			return fmt.Errorf("%w: %q holds an operator or a field path", ErrInvalidParams, name)
+		}
+	}
+	if len(params) > len(names) {
+		known := map[string]bool{}
+		for _, name := range names {
+			known[name] = true
+		}
+		for name := range params {
+			if !known[name] {
+				return fmt.Errorf("%w: %q is unknown", ErrInvalidParams, name)
+			}
+		}
+	}
+	return nil
+}
+
+// hasOperator reports whether value, marshalled to BSON, holds at any depth a key starting with "$", like {"$ne": null},
+// or a string starting with "$", like "$owner" or "$$ROOT", which $expr, $group or $project read as a field path or a
+// variable. Binding one where a template expects a value would change the meaning of the template. Any Go type is
+// checked by its BSON form, maps, bson.Raw and structs with "$" tags alike.
+func hasOperator(value interface{}) (bool, error) {
+	kind, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return false, err
+	}
+	return rawHasOperator(bson.RawValue{Type: kind, Value: data})
+}
+
+// rawHasOperator is hasOperator for a marshalled value.
+func rawHasOperator(value bson.RawValue) (bool, error) {
+	if value.Type == bsontype.String {
+		return strings.HasPrefix(value.StringValue(), "$"), nil
+	}
+	if value.Type != bsontype.EmbeddedDocument && value.Type != bsontype.Array {
+		return false, nil
+	}
+	elements, err := bson.Raw(value.Value).Elements()
+	if err != nil {
+		return false, err
+	}
+	for _, element := range elements {
+		if value.Type == bsontype.EmbeddedDocument && strings.HasPrefix(element.Key(), "$") {
+			return true, nil
+		}
+		if operator, err := rawHasOperator(element.Value()); operator || err != nil {
+			return operator, err
+		}
+	}
+	return false, nil
+}
+
+// bindParams returns a copy of a normalized template with its placeholders replaced by their value in params. The
+// parts of the template without placeholders are shared, not copied.
+func bindParams(value interface{}, params Params) interface{} {
+	if name, ok := paramName(value); ok {
+		return params[name]
+	}
+	switch v := value.(type) {
+	case bson.D:
+		bound := make(bson.D, len(v))
+		for i, elem := range v {
+			bound[i] = bson.E{Key: elem.Key, Value: bindParams(elem.Value, params)}
+		}
+		return bound
+	case bson.A:
+		bound := make(bson.A, len(v))
+		for i, item := range v {
+			bound[i] = bindParams(item, params)
+		}
+		return bound
+	default:
+		return value
+	}
+}
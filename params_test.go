@@ -0,0 +1,72 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParam(t *testing.T) {
+	template, err := toDocument(bson.M{"org": Param("org"), "age": bson.M{"$in": bson.A{Param("min"), Param("max")}}})
+	if err != nil {
+		t.Fatalf("toDocument() error = %v", err)
+	}
+	if names := templateParams(template); !reflect.DeepEqual(names, []string{"max", "min", "org"}) {
+		t.Errorf("templateParams() = %v", names)
+	}
+	bound := bindParams(template, Params{"org": "acme", "min": 1, "max": 2}).(bson.D)
+	if org, _ := lookupField(bound, "org"); org != "acme" {
+		t.Errorf("Expected org to be bound, got %v", bound)
+	}
+	if _, ok := paramName(lookupPath(template, []string{"org"})[0]); !ok {
+		t.Errorf("Expected the template to be left unchanged, got %v", template)
+	}
+}
+
+func TestBindParams_injection(t *testing.T) {
+	template, _ := toDocument(bson.M{"name": Param("name")})
+	bound := bindParams(template, Params{"name": `{"$ne": null}`}).(bson.D)
+	if name, _ := lookupField(bound, "name"); name != `{"$ne": null}` {
+		t.Errorf("Expected the value to be bound as a string, got %v", bound)
+	}
+}
+
+func TestCheckParams(t *testing.T) {
+	operatorRaw, err := bson.Marshal(bson.M{"$ne": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{"all", Params{"a": 1, "b": 2}, false},
+		{"missing", Params{"a": 1}, true},
+		{"unknown", Params{"a": 1, "b": 2, "c": 3}, true},
+		{"operator", Params{"a": 1, "b": bson.M{"$ne": nil}}, true},
+		{"document", Params{"a": 1, "b": bson.M{"name": "x"}}, false},
+		{"nested operator", Params{"a": 1, "b": bson.M{"name": bson.A{bson.D{{Key: "$gt", Value: 1}}}}}, true},
+		{"string map", Params{"a": 1, "b": map[string]string{"$ne": ""}}, true},
+		{"raw", Params{"a": 1, "b": bson.Raw(operatorRaw)}, true},
+		{"tagged struct", Params{"a": 1, "b": struct {
+			Gt int `bson:"$gt"`
+		}{1}}, true},
+		{"struct", Params{"a": 1, "b": data{ID: "1"}}, false},
+		{"array", Params{"a": 1, "b": []string{"x", "y"}}, false},
+		{"field path", Params{"a": 1, "b": "$owner"}, true},
+		{"variable", Params{"a": 1, "b": "$$ROOT"}, true},
+		{"nested field path", Params{"a": 1, "b": bson.M{"name": bson.A{"x", "$secret"}}}, true},
+		{"dollar inside", Params{"a": 1, "b": "costs $5"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkParams([]string{"a", "b"}, tt.params)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidParams)) {
+				t.Errorf("checkParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// unsupportedExpressionCodes are the server error codes of an unknown accumulator or expression, returned by servers
+// older than MongoDB 7.0 for $percentile.
+var unsupportedExpressionCodes = []int{15952, 168, 40324}
+
+// Percentiles returns the percentiles of the numeric values of field in the documents of collectionName matching
+// filter, in the order of percentiles. Each percentile is between 0 and 1, use 0.5 for the median.
+//
+// $percentile is used on MongoDB 7.0 and later, older servers fall back to a sorted find per percentile which is only
+// fast with an index on field. mongo.ErrNoDocuments is returned when no document has a numeric field.
+func (connectionDetails *Client) Percentiles(collectionName string, field string, percentiles []float64, filter interface{}, opts ...Option) ([]float64, error) {
+	op := operation{name: "Percentiles", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	for _, p := range percentiles {
+		if p < 0 || p > 1 || math.IsNaN(p) {
+			return nil, connectionDetails.wrapError(op, fmt.Errorf("mongo: percentile %v is not between 0 and 1", p))
+		}
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	var values []float64
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := connectionDetails.collection(db, collectionName, op.options)
+		var err error
+		values, err = percentileAggregate(ctx, collection, field, percentiles, filter, op.options)
+		var serverErr mongo.ServerError
+		if err != nil && errors.As(err, &serverErr) {
+			for _, code := range unsupportedExpressionCodes {
+				if serverErr.HasErrorCode(code) {
+					values, err = percentileFind(ctx, collection, field, percentiles, filter, op.options)
+					break
+				}
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// percentileAggregate computes the percentiles with $percentile.
+func percentileAggregate(ctx context.Context, collection *mongo.Collection, field string, percentiles []float64, filter interface{}, o *operationOptions) ([]float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$isNumber", Value: "$" + field}}, 1, 0}}}}}},
+			{Key: "values", Value: bson.D{{Key: "$percentile", Value: bson.D{
+				{Key: "input", Value: "$" + field},
+				{Key: "p", Value: percentiles},
+				{Key: "method", Value: "approximate"},
+			}}}},
+		}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline, o.aggregate())
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Count  int64     `bson:"count"`
+		Values []float64 `bson:"values"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 || results[0].Count == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	return results[0].Values, nil
+}
+
+// percentileFind computes each percentile with a find sorted on field, skipping to its nearest rank.
+func percentileFind(ctx context.Context, collection *mongo.Collection, field string, percentiles []float64, filter interface{}, o *operationOptions) ([]float64, error) {
+	numeric := bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: field, Value: bson.D{{Key: "$type", Value: "number"}}}}}}}
+	count, err := collection.CountDocuments(ctx, numeric)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	values := make([]float64, len(percentiles))
+	for i, p := range percentiles {
+		find := o.findOne().SetSort(bson.D{{Key: field, Value: 1}}).SetSkip(nearestRank(p, count)).
+			SetProjection(bson.D{{Key: field, Value: 1}})
+		var doc bson.D
+		if err := collection.FindOne(ctx, numeric, find).Decode(&doc); err != nil {
+			return nil, err
+		}
+		values[i], _ = toFloat(firstValue(lookupPath(doc, strings.Split(field, "."))))
+	}
+	return values, nil
+}
+
+// nearestRank returns the 0 based index of percentile p among count sorted values.
+func nearestRank(p float64, count int64) int64 {
+	rank := int64(math.Ceil(p*float64(count))) - 1
+	if rank < 0 {
+		return 0
+	}
+	if rank >= count {
+		return count - 1
+	}
+	return rank
+}
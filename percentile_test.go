@@ -0,0 +1,32 @@
+package mongo
+
+import (
+	"testing"
+)
+
+func TestNearestRank(t *testing.T) {
+	tests := []struct {
+		p     float64
+		count int64
+		want  int64
+	}{
+		{0, 10, 0},
+		{0.5, 10, 4},
+		{0.9, 10, 8},
+		{0.99, 10, 9},
+		{1, 10, 9},
+		{0.5, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := nearestRank(tt.p, tt.count); got != tt.want {
+			t.Errorf("nearestRank(%v, %d) = %d, want %d", tt.p, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestClient_Percentiles_invalid(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	if _, err := client.Percentiles("orders", "total", []float64{0.5, 1.5}, nil); err == nil {
+		t.Errorf("Expected an error for a percentile above 1")
+	}
+}
@@ -0,0 +1,90 @@
+package mongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PipelineTemplate is an aggregation pipeline with Param placeholders, see NewPipelineTemplate.
+type PipelineTemplate struct {
+	stages []bson.D
+	params []string
+}
+
+// NewPipelineTemplate returns a PipelineTemplate for pipeline - mongo.Pipeline{}, bson.A{} or []bson.D{} - whose
+// values can be Param placeholders:
+//
+//	template, err := mongo.NewPipelineTemplate(mongo.Pipeline{
+//		{{Key: "$match", Value: bson.M{"day": mongo.Param("day")}}},
+//		{{Key: "$group", Value: bson.M{"_id": "$store", "total": bson.M{"$sum": "$amount"}}}},
+//	})
+//
+// ParsePipelineTemplate reads templates kept as JSON.
+func NewPipelineTemplate(pipeline interface{}) (*PipelineTemplate, error) {
+	stages, err := toStages(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	return &PipelineTemplate{stages: stages, params: templateParams(toArray(stages))}, nil
+}
+
+// ParsePipelineTemplate parses a PipelineTemplate from a JSON array of stages in relaxed or canonical extended JSON,
+// so pipelines can be maintained in configuration. Placeholders are written as {"$param": "name"}:
+//
+//	[
+//	  {"$match": {"day": {"$param": "day"}, "status": "paid"}},
+//	  {"$group": {"_id": "$store", "total": {"$sum": "$amount"}}}
+//	]
+func ParsePipelineTemplate(data []byte) (*PipelineTemplate, error) {
+	var wrapper struct {
+		Pipeline []bson.D `bson:"pipeline"`
+	}
+	document := append(append([]byte(`{"pipeline":`), data...), '}')
+	if err := bson.UnmarshalExtJSON(document, false, &wrapper); err != nil {
+		return nil, fmt.Errorf("mongo: invalid pipeline template: %w", err)
+	}
+	return &PipelineTemplate{stages: wrapper.Pipeline, params: templateParams(toArray(wrapper.Pipeline))}, nil
+}
+
+// Params returns the sorted names of the parameters of the template.
+func (template *PipelineTemplate) Params() []string {
+	return append([]string(nil), template.params...)
+}
+
+// Bind returns the pipeline with params bound. Values are substituted as BSON values, a string stays a string even
+// when it looks like JSON. An error wrapping ErrInvalidParams is returned when a parameter is missing or unknown, or
+// when a value holds an operator, like {"$ne": null}, or a string starting with "$", like "$secret", which an
+// expression would read as a field path or a variable, see Param.
+func (template *PipelineTemplate) Bind(params Params) (mongo.Pipeline, error) {
+	if err := checkParams(template.params, params); err != nil {
+		return nil, err
+	}
+	pipeline := make(mongo.Pipeline, len(template.stages))
+	for i, stage := range template.stages {
+		pipeline[i] = bindParams(stage, params).(bson.D)
+	}
+	return pipeline, nil
+}
+
+// AggregateQuery returns a NamedQuery running template on collectionName with the params of RunNamed. opts are
+// applied before the options of RunNamed.
+func AggregateQuery(collectionName string, template *PipelineTemplate, opts ...Option) NamedQuery {
+	return func(client *Client, params Params, result interface{}, runOpts ...Option) error {
+		pipeline, err := template.Bind(params)
+		if err != nil {
+			return client.wrapError(operation{name: "Aggregate", collection: collectionName}, err)
+		}
+		return client.Aggregate(collectionName, pipeline, result, append(opts[:len(opts):len(opts)], runOpts...)...)
+	}
+}
+
+// toArray returns stages as a bson.A.
+func toArray(stages []bson.D) bson.A {
+	array := make(bson.A, len(stages))
+	for i, stage := range stages {
+		array[i] = stage
+	}
+	return array
+}
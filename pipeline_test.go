@@ -0,0 +1,77 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParsePipelineTemplate(t *testing.T) {
+	template, err := ParsePipelineTemplate([]byte(`[
+		{"$match": {"day": {"$param": "day"}, "status": "paid"}},
+		{"$limit": {"$param": "limit"}}
+	]`))
+	if err != nil {
+		t.Fatalf("ParsePipelineTemplate() error = %v", err)
+	}
+	if !reflect.DeepEqual(template.Params(), []string{"day", "limit"}) {
+		t.Errorf("Params() = %v", template.Params())
+	}
+	pipeline, err := template.Bind(Params{"day": "2024-01-01", "limit": 10})
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	match := pipeline[0][0].Value.(bson.D)
+	if day, _ := lookupField(match, "day"); day != "2024-01-01" {
+		t.Errorf("Expected day to be bound, got %v", match)
+	}
+	if limit := pipeline[1][0].Value; limit != 10 {
+		t.Errorf("Expected limit to be bound, got %v", limit)
+	}
+
+	if _, err := template.Bind(Params{"day": bson.M{"$ne": nil}, "limit": 10}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an operator document, got %v", err)
+	}
+	if _, err := ParsePipelineTemplate([]byte(`{"$match": {}}`)); err == nil {
+		t.Errorf("Expected an error for a template that is not an array")
+	}
+}
+
+func TestNewPipelineTemplate(t *testing.T) {
+	template, err := NewPipelineTemplate(mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"store": Param("store")}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPipelineTemplate() error = %v", err)
+	}
+	pipeline, err := template.Bind(Params{"store": "auckland"})
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	want := mongo.Pipeline{{{Key: "$match", Value: bson.D{{Key: "store", Value: "auckland"}}}}}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("Bind() = %v, want %v", pipeline, want)
+	}
+}
+
+func TestPipelineTemplate_Bind_fieldPath(t *testing.T) {
+	template, err := NewPipelineTemplate(mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$owner", Param("owner")}}}}},
+		{{Key: "$project", Value: bson.M{"label": Param("label")}}},
+	})
+	if err != nil {
+		t.Fatalf("NewPipelineTemplate() error = %v", err)
+	}
+	if _, err := template.Bind(Params{"owner": "$owner", "label": "name"}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a field path in $expr, got %v", err)
+	}
+	if _, err := template.Bind(Params{"owner": "akshay", "label": "$secret"}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a field path in $project, got %v", err)
+	}
+	if _, err := template.Bind(Params{"owner": "akshay", "label": "name"}); err != nil {
+		t.Errorf("Bind() error = %v", err)
+	}
+}
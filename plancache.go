@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PlanCacheEntry is a cached query plan of a collection, see PlanCache.
+type PlanCacheEntry struct {
+	// QueryHash identifies the query shape, it is logged with slow queries
+	QueryHash string `bson:"queryHash"`
+
+	// PlanCacheKey identifies the query shape and the indexes available for it
+	PlanCacheKey string `bson:"planCacheKey"`
+
+	// IsActive is false while the entry is only a candidate that has not been used yet
+	IsActive bool `bson:"isActive"`
+
+	// Works is the amount of work needed to evaluate the plan, inactive entries are replaced when a query needs more
+	Works int64 `bson:"works"`
+
+	// CreatedFromQuery is the query shape, its filter, sort and projection
+	CreatedFromQuery bson.M `bson:"createdFromQuery"`
+
+	// Raw is the whole entry as returned by the server, its fields depend on the server version
+	Raw bson.Raw `bson:"-"`
+}
+
+// PlanCache lists the cached query plans of collectionName using the $planCacheStats stage, which replaced the
+// planCacheListPlans command removed in MongoDB 4.4.
+func (connectionDetails *Client) PlanCache(collectionName string, opts ...Option) ([]PlanCacheEntry, error) {
+	op := operation{name: "PlanCache", collection: collectionName, options: newOperationOptions(opts)}
+	var entries []PlanCacheEntry
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		cursor, err := db.Collection(collectionName).Aggregate(ctx, mongo.Pipeline{{{Key: "$planCacheStats", Value: bson.D{}}}})
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		entries = nil
+		for cursor.Next(ctx) {
+			var entry PlanCacheEntry
+			if err := cursor.Decode(&entry); err != nil {
+				return err
+			}
+			entry.Raw = append(bson.Raw(nil), cursor.Current...)
+			entries = append(entries, entry)
+		}
+		return cursor.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ClearPlanCache removes cached query plans of collectionName with planCacheClear. A nil query removes every plan,
+// otherwise only the plans of its shape are removed - for example bson.M{"name": "x"} clears every query on name.
+func (connectionDetails *Client) ClearPlanCache(collectionName string, query interface{}, opts ...Option) error {
+	op := operation{name: "ClearPlanCache", collection: collectionName, filter: query, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		return db.RunCommand(ctx, planCacheClearCommand(collectionName, query)).Err()
+	})
+}
+
+// planCacheClearCommand returns the planCacheClear command for collectionName and an optional query shape.
+func planCacheClearCommand(collectionName string, query interface{}) bson.D {
+	command := bson.D{{Key: "planCacheClear", Value: collectionName}}
+	if query != nil {
+		command = append(command, bson.E{Key: "query", Value: query})
+	}
+	return command
+}
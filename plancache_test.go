@@ -0,0 +1,26 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPlanCacheClearCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		query interface{}
+		want  bson.D
+	}{
+		{"all", nil, bson.D{{Key: "planCacheClear", Value: "users"}}},
+		{"shape", bson.M{"name": "x"}, bson.D{{Key: "planCacheClear", Value: "users"}, {Key: "query", Value: bson.M{"name": "x"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := planCacheClearCommand("users", tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planCacheClearCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PreparedQuery is a named filter template of a collection, see Client.Prepare.
+type PreparedQuery struct {
+	// Name of the query, it is the default comment of its executions
+	Name string
+
+	// Collection the query runs against
+	Collection string
+
+	client   *Client
+	skeleton bson.D
+	params   []string
+	opts     []Option
+}
+
+// Prepare returns a PreparedQuery on collectionName for filterTemplate, a filter with Param placeholders. The template
+// is normalized once, executions only substitute the parameters, and every execution gets the same options, commented
+// with name unless opts has a Comment:
+//
+//	activeUsersByOrg, err := client.Prepare("activeUsersByOrg", "users", bson.M{"org": mongo.Param("org"), "active": true})
+//	...
+//	err = activeUsersByOrg.All(mongo.Params{"org": orgID}, &users)
+//
+// Keeping the queries of an application as PreparedQuery values centralizes their definitions, and gives the
+// profiler and currentOp a stable comment to group them by.
+func (connectionDetails *Client) Prepare(name string, collectionName string, filterTemplate interface{}, opts ...Option) (*PreparedQuery, error) {
+	skeleton, err := toDocument(filterTemplate)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "Prepare", collection: collectionName}, err)
+	}
+	return &PreparedQuery{
+		Name:       name,
+		Collection: collectionName,
+		client:     connectionDetails,
+		skeleton:   skeleton,
+		params:     templateParams(skeleton),
+		opts:       append([]Option{Comment(name)}, opts...),
+	}, nil
+}
+
+// Params returns the sorted names of the parameters of the query.
+func (query *PreparedQuery) Params() []string {
+	return append([]string(nil), query.params...)
+}
+
+// Filter returns the filter of the query with params bound. An error wrapping ErrInvalidParams is returned when a
+// parameter is missing or unknown, or when a value holds an operator or a string starting with "$", see Param.
+func (query *PreparedQuery) Filter(params Params) (bson.D, error) {
+	if err := checkParams(query.params, params); err != nil {
+		return nil, err
+	}
+	return bindParams(query.skeleton, params).(bson.D), nil
+}
+
+// One finds the first document matching the query like Client.GetCustom, opts are applied after the options of the
+// query.
+func (query *PreparedQuery) One(params Params, opts ...Option) (*mongo.SingleResult, error) {
+	filter, err := query.Filter(params)
+	if err != nil {
+		return nil, query.client.wrapError(operation{name: "GetCustom", collection: query.Collection}, err)
+	}
+	return query.client.GetCustom(query.Collection, filter, append(query.opts[:len(query.opts):len(query.opts)], opts...)...)
+}
+
+// All finds the documents matching the query like Client.GetAllCustom, opts are applied after the options of the
+// query.
+//
+// The 'result' parameter needs to be a pointer.
+func (query *PreparedQuery) All(params Params, result interface{}, opts ...Option) error {
+	filter, err := query.Filter(params)
+	if err != nil {
+		return query.client.wrapError(operation{name: "GetAllCustom", collection: query.Collection}, err)
+	}
+	return query.client.GetAllCustom(query.Collection, filter, result, append(query.opts[:len(query.opts):len(query.opts)], opts...)...)
+}
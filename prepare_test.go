@@ -0,0 +1,44 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_Prepare(t *testing.T) {
+	offline := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	query, err := offline.Prepare("activeUsersByOrg", "users", bson.M{"org": Param("org"), "active": true}, Comment("users by org"))
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	filter, err := query.Filter(Params{"org": "acme"})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if org, _ := lookupField(filter, "org"); org != "acme" {
+		t.Errorf("Filter() = %v", filter)
+	}
+	if o := newOperationOptions(query.opts); o.comment != "users by org" {
+		t.Errorf("Expected the Comment option to replace the name, got %q", o.comment)
+	}
+	if err := query.All(Params{}, &[]bson.M{}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a missing parameter, got %v", err)
+	}
+}
+
+func TestPreparedQuery_All(t *testing.T) {
+	_ = client.DropCollection("prepared")
+	if _, err := client.AddMany("prepared", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Raj"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	query, err := client.Prepare("byName", "prepared", bson.M{"name": Param("name")})
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	var result []data
+	if err := query.All(Params{"name": "Raj"}, &result); err != nil || len(result) != 1 || result[0].ID != "2" {
+		t.Errorf("All() = %v, %v", result, err)
+	}
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// primeBatchSize is the cursor batch size used by Prime.
+const primeBatchSize = 1000
+
+// PrimeResult reports what Prime loaded into the cache.
+type PrimeResult struct {
+	// Indexes scanned
+	Indexes int
+
+	// IndexEntries read from the scanned indexes
+	IndexEntries int64
+
+	// Documents read
+	Documents int64
+
+	// Duration of the whole warm up
+	Duration time.Duration
+}
+
+// Prime pulls collectionName into the WiredTiger cache, typically after a failover or restart. Each regular index is
+// read with a covered scan, then the documents matching filter are read - pass bson.M{} to read every document, or nil
+// to only prime the indexes.
+//
+// Nothing is decoded or returned to the caller, the documents are fetched by the server but only empty documents are
+// sent over the network.
+func (connectionDetails *Client) Prime(collectionName string, filter interface{}, opts ...Option) (*PrimeResult, error) {
+	op := operation{name: "Prime", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	result := &PrimeResult{}
+	start := time.Now()
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		*result = PrimeResult{}
+		collection := db.Collection(collectionName)
+
+		cursor, err := collection.Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		var indexes []struct {
+			Name string `bson:"name"`
+			Key  bson.D `bson:"key"`
+		}
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return err
+		}
+		for _, index := range indexes {
+			projection, ok := coveredProjection(index.Key)
+			if !ok {
+				continue
+			}
+			find := op.options.find().SetHint(index.Name).SetProjection(projection).SetBatchSize(primeBatchSize)
+			entries, err := drain(ctx, collection, bson.D{}, find)
+			if err != nil {
+				return err
+			}
+			result.Indexes++
+			result.IndexEntries += entries
+		}
+
+		if filter != nil {
+			find := op.options.find().SetProjection(bson.D{{Key: "_id", Value: 0}, {Key: "__prime", Value: 1}}).SetBatchSize(primeBatchSize)
+			documents, err := drain(ctx, collection, filter, find)
+			if err != nil {
+				return err
+			}
+			result.Documents = documents
+		}
+		return nil
+	})
+	result.Duration = time.Since(start)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// coveredProjection returns a projection of only the index keys, it is false for special indexes like text or
+// 2dsphere that cannot be scanned this way.
+func coveredProjection(keys bson.D) (bson.D, bool) {
+	projection := bson.D{}
+	hasID := false
+	for _, key := range keys {
+		if _, ok := toFloat(key.Value); !ok {
+			return nil, false
+		}
+		if key.Key == "_id" {
+			hasID = true
+		}
+		projection = append(projection, bson.E{Key: key.Key, Value: 1})
+	}
+	if !hasID {
+		projection = append(projection, bson.E{Key: "_id", Value: 0})
+	}
+	return projection, true
+}
+
+// drain reads every document of a find without decoding them and returns how many were read.
+func drain(ctx context.Context, collection *mongo.Collection, filter interface{}, find *options.FindOptions) (int64, error) {
+	cursor, err := collection.Find(ctx, filter, find)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var count int64
+	for cursor.Next(ctx) {
+		count++
+	}
+	return count, cursor.Err()
+}
@@ -0,0 +1,20 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCoveredProjection(t *testing.T) {
+	projection, ok := coveredProjection(bson.D{{Key: "name", Value: int32(1)}, {Key: "age", Value: int32(-1)}})
+	want := bson.D{{Key: "name", Value: 1}, {Key: "age", Value: 1}, {Key: "_id", Value: 0}}
+	if !ok || !reflect.DeepEqual(projection, want) {
+		t.Errorf("coveredProjection() = %v, want %v", projection, want)
+	}
+
+	if _, ok := coveredProjection(bson.D{{Key: "description", Value: "text"}}); ok {
+		t.Errorf("Expected text indexes to be skipped")
+	}
+}
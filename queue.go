@@ -0,0 +1,226 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrEmptyQueue is returned by Dequeue when no message is visible.
+var ErrEmptyQueue = errors.New("mongo: queue is empty")
+
+// ErrClaimExpired is returned by Ack and Nack when the visibility timeout of the message expired and it was
+// dequeued again, or it was already acknowledged.
+var ErrClaimExpired = errors.New("mongo: message claim expired")
+
+// QueueOptions configures a Queue.
+type QueueOptions struct {
+	// VisibilityTimeout is how long a dequeued message is hidden from other consumers before it is dequeued again,
+	// defaults to 30 seconds
+	VisibilityTimeout time.Duration
+
+	// MaxAttempts is the number of times a message is dequeued before it is moved to the dead letter collection,
+	// defaults to 5
+	MaxAttempts int
+
+	// DeadLetterCollection receives the messages that failed MaxAttempts times, defaults to the queue collection with
+	// a "_dead" suffix
+	DeadLetterCollection string
+}
+
+// Queue is a work queue stored in a collection, see Client.Queue.
+type Queue struct {
+	client     *Client
+	collection string
+	options    QueueOptions
+}
+
+// Message is a message of a Queue.
+type Message struct {
+	ID primitive.ObjectID `bson:"_id"`
+
+	// Payload is the value given to Enqueue, see Decode
+	Payload bson.RawValue `bson:"payload"`
+
+	// Attempts is the number of times the message was dequeued, including this one
+	Attempts int `bson:"attempts"`
+
+	// EnqueuedAt is when the message was enqueued
+	EnqueuedAt time.Time `bson:"enqueuedAt"`
+
+	// Claim identifies the Dequeue holding the message, Ack and Nack only apply while it holds it
+	Claim primitive.ObjectID `bson:"claim,omitempty"`
+}
+
+// Decode decodes the payload of the message into v, a pointer.
+func (message *Message) Decode(v interface{}) error {
+	return message.Payload.Unmarshal(v)
+}
+
+// Queue returns the work queue stored in collectionName. Each message is a document that Dequeue claims with a
+// findOneAndUpdate hiding it for the visibility timeout, Ack deletes it once processed. A message that is not
+// acknowledged in time, because its consumer crashed, becomes visible again and is dequeued by another consumer, so
+// consumers must be idempotent. After MaxAttempts dequeues a message is moved to the dead letter collection.
+//
+//	queue := client.Queue("emails", mongo.QueueOptions{VisibilityTimeout: time.Minute})
+//	message, err := queue.Dequeue()
+//	if errors.Is(err, mongo.ErrEmptyQueue) {
+//		...
+//	}
+//	if err := send(message); err != nil {
+//		return queue.Nack(message, 10*time.Second)
+//	}
+//	return queue.Ack(message)
+//
+// Visibility is based on the clock of the consumers, keep them synchronized. Use EnsureIndexes once so claims do not
+// scan the collection.
+func (connectionDetails *Client) Queue(collectionName string, opts QueueOptions) *Queue {
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.DeadLetterCollection == "" {
+		opts.DeadLetterCollection = collectionName + "_dead"
+	}
+	return &Queue{client: connectionDetails, collection: collectionName, options: opts}
+}
+
+// EnsureIndexes creates the index used to claim messages.
+func (queue *Queue) EnsureIndexes() error {
+	_, err := queue.client.CreateIndex(queue.collection, Index{Keys: bson.D{{Key: "visibleAt", Value: 1}}})
+	return err
+}
+
+// Enqueue adds a message with payload, visible immediately, and returns its ID.
+func (queue *Queue) Enqueue(payload interface{}, opts ...Option) (primitive.ObjectID, error) {
+	now := time.Now()
+	id := primitive.NewObjectID()
+	doc := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "payload", Value: payload},
+		{Key: "attempts", Value: 0},
+		{Key: "enqueuedAt", Value: now},
+		{Key: "visibleAt", Value: now},
+	}
+	op := operation{name: "Enqueue", collection: queue.collection, documents: []interface{}{doc}, options: newOperationOptions(opts)}
+	err := queue.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := queue.client.collection(db, queue.collection, op.options).InsertOne(ctx, doc)
+		return err
+	})
+	return id, err
+}
+
+// Dequeue claims the oldest visible message, hiding it for the visibility timeout. ErrEmptyQueue is returned when no
+// message is visible. Messages dequeued more than MaxAttempts times are moved to the dead letter collection instead
+// of being returned.
+func (queue *Queue) Dequeue(opts ...Option) (*Message, error) {
+	op := operation{name: "Dequeue", collection: queue.collection, options: newOperationOptions(opts)}
+	var message *Message
+	err := queue.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		message = nil
+		collection := queue.client.collection(db, queue.collection, op.options)
+		for {
+			now := time.Now()
+			claim := primitive.NewObjectID()
+			update := bson.D{
+				{Key: "$set", Value: bson.D{{Key: "visibleAt", Value: now.Add(queue.options.VisibilityTimeout)}, {Key: "claim", Value: claim}}},
+				{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+			}
+			found := options.FindOneAndUpdate().SetSort(bson.D{{Key: "visibleAt", Value: 1}}).SetReturnDocument(options.After)
+			var claimed Message
+			err := collection.FindOneAndUpdate(ctx, bson.D{{Key: "visibleAt", Value: bson.D{{Key: "$lte", Value: now}}}}, update, found).Decode(&claimed)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if claimed.Attempts <= queue.options.MaxAttempts {
+				message = &claimed
+				return nil
+			}
+			if err := queue.deadLetter(ctx, db, &claimed, "visibility timeout expired"); err != nil && !errors.Is(err, ErrClaimExpired) {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, ErrEmptyQueue
+	}
+	return message, nil
+}
+
+// Ack deletes a processed message. ErrClaimExpired is returned when the message was dequeued again after its
+// visibility timeout expired.
+func (queue *Queue) Ack(message *Message, opts ...Option) error {
+	op := operation{name: "Ack", collection: queue.collection, options: newOperationOptions(opts)}
+	return queue.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		result, err := queue.client.collection(db, queue.collection, op.options).DeleteOne(ctx, bson.D{{Key: "_id", Value: message.ID}, {Key: "claim", Value: message.Claim}})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return ErrClaimExpired
+		}
+		return nil
+	})
+}
+
+// Nack releases a message that could not be processed, it becomes visible again after delay. A message that reached
+// MaxAttempts is moved to the dead letter collection. ErrClaimExpired is returned when the message was dequeued again
+// after its visibility timeout expired.
+func (queue *Queue) Nack(message *Message, delay time.Duration, opts ...Option) error {
+	op := operation{name: "Nack", collection: queue.collection, options: newOperationOptions(opts)}
+	return queue.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		if message.Attempts >= queue.options.MaxAttempts {
+			return queue.deadLetter(ctx, db, message, "not acknowledged")
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "visibleAt", Value: time.Now().Add(delay)}}},
+			{Key: "$unset", Value: bson.D{{Key: "claim", Value: ""}}},
+		}
+		result, err := queue.client.collection(db, queue.collection, op.options).UpdateOne(ctx, bson.D{{Key: "_id", Value: message.ID}, {Key: "claim", Value: message.Claim}}, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrClaimExpired
+		}
+		return nil
+	})
+}
+
+// deadLetter moves message to the dead letter collection with reason. The copy is written first so a failure in
+// between leaves the message in both collections rather than in none.
+func (queue *Queue) deadLetter(ctx context.Context, db *mongo.Database, message *Message, reason string) error {
+	doc := bson.D{
+		{Key: "_id", Value: message.ID},
+		{Key: "payload", Value: message.Payload},
+		{Key: "attempts", Value: message.Attempts},
+		{Key: "enqueuedAt", Value: message.EnqueuedAt},
+		{Key: "deadLetteredAt", Value: time.Now()},
+		{Key: "reason", Value: reason},
+	}
+	_, err := db.Collection(queue.options.DeadLetterCollection).ReplaceOne(ctx, bson.D{{Key: "_id", Value: message.ID}}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+	result, err := db.Collection(queue.collection).DeleteOne(ctx, bson.D{{Key: "_id", Value: message.ID}, {Key: "claim", Value: message.Claim}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrClaimExpired
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_Queue_defaults(t *testing.T) {
+	queue := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test").Queue("jobs", QueueOptions{})
+	if queue.options.VisibilityTimeout != 30*time.Second || queue.options.MaxAttempts != 5 || queue.options.DeadLetterCollection != "jobs_dead" {
+		t.Errorf("Unexpected defaults %+v", queue.options)
+	}
+	if _, err := queue.Dequeue(); err == nil || errors.Is(err, ErrEmptyQueue) {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	_ = client.DropCollections([]string{"queue", "queue_dead"})
+	queue := client.Queue("queue", QueueOptions{VisibilityTimeout: time.Minute, MaxAttempts: 2})
+	if _, err := queue.Enqueue(data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	message, err := queue.Dequeue()
+	if err != nil {
+		t.Fatalf("Dequeue() error = %v", err)
+	}
+	var payload data
+	if err := message.Decode(&payload); err != nil || payload.Name != "Akshay" || message.Attempts != 1 {
+		t.Errorf("Unexpected message %+v, %v", message, err)
+	}
+	if _, err := queue.Dequeue(); !errors.Is(err, ErrEmptyQueue) {
+		t.Errorf("Expected the claimed message to be hidden, got %v", err)
+	}
+
+	if err := queue.Nack(message, 0); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+	message, err = queue.Dequeue()
+	if err != nil || message.Attempts != 2 {
+		t.Fatalf("Expected the message again, got %+v, %v", message, err)
+	}
+	if err := queue.Nack(message, 0); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+	if _, err := queue.Dequeue(); !errors.Is(err, ErrEmptyQueue) {
+		t.Errorf("Expected the message to be dead lettered, got %v", err)
+	}
+	dead, err := client.GetCustom("queue_dead", bson.M{"_id": message.ID})
+	if err != nil || dead.Err() != nil {
+		t.Errorf("Expected the message in the dead letter collection, got %v", err)
+	}
+	if err := queue.Ack(message); !errors.Is(err, ErrClaimExpired) {
+		t.Errorf("Expected ErrClaimExpired, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"sync"
+)
+
+// recordCache holds decoded documents of the collections configured with WithRecordCache. Each entry remembers the
+// generations of its document and collection when it was read, writes bump them so stale entries are never returned.
+type recordCache struct {
+	mu          sync.Mutex
+	collections map[string]bool
+	entries     map[string]recordEntry
+	documents   map[string]uint64
+	generations map[string]uint64
+}
+
+// recordEntry is a cached decoded document.
+type recordEntry struct {
+	value      interface{}
+	document   uint64
+	collection uint64
+}
+
+// WithRecordCache caches the documents of collections read with GetCached, decoded, so hot reference data like
+// product catalogs is neither fetched nor decoded again on every read.
+//
+// Writes made through the Client invalidate the cache: a write by "_id" invalidates that document, any other write
+// invalidates the whole collection. Writes made elsewhere are not seen, use InvalidateCached after them. The cache is
+// not bounded, keep it to collections of reference data.
+func WithRecordCache(collections ...string) ClientOption {
+	return func(client *Client) {
+		if client.records == nil {
+			client.records = &recordCache{
+				collections: map[string]bool{},
+				entries:     map[string]recordEntry{},
+				documents:   map[string]uint64{},
+				generations: map[string]uint64{},
+			}
+		}
+		for _, collection := range collections {
+			client.records.collections[collection] = true
+		}
+	}
+}
+
+// GetCached finds a document by "_id" like Get and decodes it as T, returning the cached value when the collection is
+// configured with WithRecordCache and the document was not written since it was cached. The returned value is shared
+// with the cache and later GetCached calls, it must not be modified through its maps, slices or pointers.
+func GetCached[T any](client *Client, collectionName string, id string, opts ...Option) (T, error) {
+	var value T
+	cache := client.records
+	if cache == nil || !cache.collections[collectionName] {
+		result, err := client.Get(collectionName, id, opts...)
+		if err != nil {
+			return value, err
+		}
+		return value, client.wrapError(operation{name: "Get", collection: collectionName}, result.Decode(&value))
+	}
+
+	collection := client.DatabaseName + "." + collectionName
+	key, err := idKey(id)
+	if err != nil {
+		return value, err
+	}
+	key = collection + "\x00" + key
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	document, generation := cache.documents[key], cache.generations[collection]
+	cache.mu.Unlock()
+	if cached, isT := entry.value.(T); ok && isT && entry.document == document && entry.collection == generation {
+		return cached, nil
+	}
+
+	// the generations are read before the document, a write racing the read leaves the entry stale
+	result, err := client.Get(collectionName, id, opts...)
+	if err != nil {
+		return value, err
+	}
+	if err := result.Decode(&value); err != nil {
+		return value, client.wrapError(operation{name: "Get", collection: collectionName}, err)
+	}
+	cache.mu.Lock()
+	cache.entries[key] = recordEntry{value: value, document: document, collection: generation}
+	cache.mu.Unlock()
+	return value, nil
+}
+
+// InvalidateCached drops the cached documents of collectionName with the given ids, or every cached document of the
+// collection when no id is given. Use it after writes that are not made through the Client, like the ones seen by a
+// change stream.
+func (connectionDetails *Client) InvalidateCached(collectionName string, ids ...string) {
+	if connectionDetails.records == nil {
+		return
+	}
+	if len(ids) == 0 {
+		connectionDetails.records.invalidate(connectionDetails.DatabaseName+"."+collectionName, nil)
+		return
+	}
+	for _, id := range ids {
+		connectionDetails.records.invalidate(connectionDetails.DatabaseName+"."+collectionName, id)
+	}
+}
+
+// invalidateRecords bumps the generations written by op.
+func (connectionDetails *Client) invalidateRecords(op operation) {
+	if !connectionDetails.records.collections[op.collection] || !staleWrite(op.name) {
+		return
+	}
+	var id interface{}
+	if query, err := toDocument(op.filter); err == nil {
+		id, _ = idEquality(query)
+	}
+	connectionDetails.records.invalidate(connectionDetails.DatabaseName+"."+op.collection, id)
+}
+
+// invalidate bumps the generation of the document with the given id, or of the whole collection when id is nil.
+func (cache *recordCache) invalidate(collection string, id interface{}) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if id == nil {
+		cache.generations[collection]++
+		for key := range cache.entries {
+			if len(key) > len(collection) && key[:len(collection)+1] == collection+"\x00" {
+				delete(cache.entries, key)
+			}
+		}
+		return
+	}
+	key, err := idKey(id)
+	if err != nil {
+		cache.generations[collection]++
+		return
+	}
+	key = collection + "\x00" + key
+	cache.documents[key]++
+	delete(cache.entries, key)
+}
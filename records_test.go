@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"testing"
+)
+
+func TestRecordCache_invalidate(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithRecordCache("products"))
+	cache := client.records
+	key := func(id string) string {
+		k, _ := idKey(id)
+		return "test.products\x00" + k
+	}
+	cache.entries[key("1")] = recordEntry{value: data{ID: "1"}}
+	cache.entries[key("2")] = recordEntry{value: data{ID: "2"}}
+
+	client.invalidateRecords(operation{name: "Update", collection: "products", filter: map[string]interface{}{"_id": "1"}})
+	if _, ok := cache.entries[key("1")]; ok || cache.documents[key("1")] != 1 {
+		t.Errorf("Expected a write by id to invalidate its document")
+	}
+	if _, ok := cache.entries[key("2")]; !ok {
+		t.Errorf("Expected a write by id to keep the other documents")
+	}
+
+	client.invalidateRecords(operation{name: "GetAll", collection: "products"})
+	client.invalidateRecords(operation{name: "UpdateMany", collection: "users"})
+	if _, ok := cache.entries[key("2")]; !ok {
+		t.Errorf("Expected reads and writes to other collections to keep the cache")
+	}
+
+	client.invalidateRecords(operation{name: "UpdateMany", collection: "products", filter: map[string]interface{}{"price": 1}})
+	if len(cache.entries) != 0 || cache.generations["test.products"] != 1 {
+		t.Errorf("Expected a write by filter to invalidate the collection")
+	}
+}
+
+func TestGetCached(t *testing.T) {
+	cached := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithRecordCache("products"))
+	k, _ := idKey("1")
+	cached.records.entries["test.products\x00"+k] = recordEntry{value: data{ID: "1", Name: "Akshay"}}
+
+	got, err := GetCached[data](cached, "products", "1")
+	if err != nil || got.Name != "Akshay" {
+		t.Errorf("GetCached() = %v, %v, want the cached value", got, err)
+	}
+
+	// a value cached with another type is read again
+	if _, err := GetCached[map[string]interface{}](cached, "products", "1"); err == nil {
+		t.Errorf("Expected GetCached to read the document for another type")
+	}
+
+	cached.InvalidateCached("products", "1")
+	if _, err := GetCached[data](cached, "products", "1"); err == nil {
+		t.Errorf("Expected GetCached to read an invalidated document")
+	}
+}
+
+func TestClient_GetCached(t *testing.T) {
+	cached := client.WithContext(client.Context)
+	WithRecordCache("records")(cached)
+	_ = cached.DropCollection("records")
+	if _, err := cached.Add("records", data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	first, err := GetCached[data](cached, "records", "1")
+	if err != nil || first.Name != "Akshay" {
+		t.Fatalf("GetCached() = %v, %v", first, err)
+	}
+	if _, err := cached.Update("records", "1", data{ID: "1", Name: "Raj"}); err != nil {
+		t.Fatalf("Unable to update data. %s", err)
+	}
+	second, err := GetCached[data](cached, "records", "1")
+	if err != nil || second.Name != "Raj" {
+		t.Errorf("Expected the update to invalidate the cache, got %v, %v", second, err)
+	}
+}
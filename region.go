@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+// RegionTag is the replica set tag naming the region of a member, Atlas tags every node with its cloud region like
+// "EU_WEST_1".
+const RegionTag = "region"
+
+// WithRegion sets the region the application runs in, as named by the RegionTag of the members of the deployment. See
+// NearestRegionClient and InRegion.
+func WithRegion(region string) ClientOption {
+	return func(client *Client) {
+		client.region = region
+	}
+}
+
+// NearestRegionClient returns a view of the Client reading from the members of its region set with WithRegion, or
+// from the member with the lowest latency when the region has none or no region is set. Reads may then be stale, use
+// the Client itself for the reads that must see the latest writes.
+//
+// Writes go to the primary, or with a zone sharded cluster like an Atlas Global Cluster to the shards of the zone of
+// the document, see InRegion to write in the zone of the region.
+func (connectionDetails *Client) NearestRegionClient() *Client {
+	client := *connectionDetails
+	if client.region != "" {
+		// the empty tag set falls back to any member
+		client.readPreference, _ = readpref.New(readpref.NearestMode,
+			readpref.WithTagSets(tag.Set{{Name: RegionTag, Value: client.region}}, tag.Set{}))
+	} else {
+		client.readPreference = readpref.Nearest()
+	}
+	return &client
+}
+
+// InRegion returns a Store whose operations target the zone of region in a zone sharded cluster, like an Atlas Global
+// Cluster, where zoneField prefixes the shard key and the zones map its values to the shards of each region. Every
+// written document carries zoneField set to region, and every filter is restricted to it so mongos sends the operation
+// to the shards of that zone only:
+//
+//	eu := client.InRegion("location", "EU")
+//	eu.Add("sessions", session)
+//
+// Documents of another region are not visible through the Store. An empty region is the one set with WithRegion.
+func (connectionDetails *Client) InRegion(zoneField string, region string) Store {
+	if region == "" {
+		region = connectionDetails.region
+	}
+	return &tenantStore{client: connectionDetails.NearestRegionClient(), field: zoneField, tenantID: region}
+}
@@ -0,0 +1,31 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+func TestClient_NearestRegionClient(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1", "test", WithRegion("EU_WEST_1"))
+	if client.readPreference != nil {
+		t.Errorf("Expected the Client to keep the default read preference, got %v", client.readPreference)
+	}
+
+	nearest := client.NearestRegionClient()
+	want := []tag.Set{{{Name: RegionTag, Value: "EU_WEST_1"}}, {}}
+	if nearest.readPreference.Mode() != readpref.NearestMode || !reflect.DeepEqual(nearest.readPreference.TagSets(), want) {
+		t.Errorf("Expected nearest with tag sets %v, got %v", want, nearest.readPreference)
+	}
+	anywhere := NewMongoClientDefault("mongodb://localhost:1", "test").NearestRegionClient()
+	if len(anywhere.readPreference.TagSets()) != 0 {
+		t.Errorf("Expected no tag set without a region, got %v", anywhere.readPreference.TagSets())
+	}
+
+	store, ok := client.InRegion("location", "").(*tenantStore)
+	if !ok || store.field != "location" || store.tenantID != "EU_WEST_1" || store.client.readPreference == nil {
+		t.Errorf("Expected a Store stamping the region of the Client, got %+v", store)
+	}
+}
@@ -0,0 +1,147 @@
+package mongo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// replayCommands are the commands recorded in a replay log.
+var replayCommands = map[string]bool{
+	"insert":        true,
+	"update":        true,
+	"delete":        true,
+	"findAndModify": true,
+	"create":        true,
+	"drop":          true,
+	"collMod":       true,
+	"createIndexes": true,
+	"dropIndexes":   true,
+}
+
+// replaySessionFields are the command fields bound to the session or the connection that sent it, they are not
+// recorded.
+var replaySessionFields = map[string]bool{
+	"lsid":             true,
+	"txnNumber":        true,
+	"autocommit":       true,
+	"startTransaction": true,
+}
+
+// ReplayEntry is a write command recorded in a replay log, see WithReplayLog.
+type ReplayEntry struct {
+	Time time.Time `bson:"time"`
+
+	// Operation is the name of the Client method that sent the command
+	Operation  string `bson:"operation"`
+	Database   string `bson:"database"`
+	Collection string `bson:"collection"`
+	Command    bson.D `bson:"command"`
+}
+
+// WithReplayLog writes every write command that succeeded to w, one canonical extended JSON ReplayEntry per line, so
+// it can be applied to another database with Replay. It is meant for debugging, like reproducing a production bug
+// locally, as the documents are written in full.
+//
+// Inserts, updates, deletes, findAndModify and the collection and index commands are recorded, including the writes
+// of maintained counts, leaderboards and summaries. Entries are written in the order the commands succeeded.
+func WithReplayLog(w io.Writer) ClientOption {
+	return func(client *Client) {
+		client.replay = &replayRecorder{w: w, pending: map[int64]ReplayEntry{}}
+	}
+}
+
+// replayRecorder writes the write commands that succeeded to a replay log.
+type replayRecorder struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending map[int64]ReplayEntry
+}
+
+// started keeps a write command until it succeeds.
+func (recorder *replayRecorder) started(operationName string, database string, commandName string, requestID int64, raw bson.Raw) {
+	if !replayCommands[commandName] {
+		return
+	}
+	var command bson.D
+	if err := bson.Unmarshal(raw, &command); err != nil || len(command) == 0 {
+		return
+	}
+	entry := ReplayEntry{Operation: operationName, Database: database, Command: make(bson.D, 0, len(command))}
+	entry.Collection, _ = command[0].Value.(string)
+	for _, elem := range command {
+		if !replaySessionFields[elem.Key] && !strings.HasPrefix(elem.Key, "$") {
+			entry.Command = append(entry.Command, elem)
+		}
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.pending[requestID] = entry
+}
+
+// succeeded writes the command sent with requestID to the replay log.
+func (recorder *replayRecorder) succeeded(requestID int64) error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	entry, ok := recorder.pending[requestID]
+	if !ok {
+		return nil
+	}
+	delete(recorder.pending, requestID)
+
+	entry.Time = time.Now().UTC()
+	line, err := bson.MarshalExtJSON(entry, true, false)
+	if err != nil {
+		return err
+	}
+	_, err = recorder.w.Write(append(line, '\n'))
+	return err
+}
+
+// failed forgets the command sent with requestID.
+func (recorder *replayRecorder) failed(requestID int64) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	delete(recorder.pending, requestID)
+}
+
+// Replay applies the entries of a replay log written by WithReplayLog to the Client database, in order, whatever the
+// database they were recorded on. It returns the number of applied entries.
+//
+// Replay stops at the first command that fails, the write errors of a command are ignored as they were part of the
+// recorded outcome, like a duplicate key in an unordered insert.
+func (connectionDetails *Client) Replay(r io.Reader, opts ...Option) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	applied := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var entry ReplayEntry
+		if err := bson.UnmarshalExtJSON(line, true, &entry); err != nil {
+			return applied, connectionDetails.wrapError(operation{name: "Replay"}, fmt.Errorf("mongo: invalid replay entry %d: %w", applied+1, err))
+		}
+		op := operation{name: "Replay", collection: entry.Collection, options: newOperationOptions(opts)}
+		err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+			return db.RunCommand(ctx, entry.Command).Err()
+		})
+		if err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	if err := scanner.Err(); err != nil {
+		return applied, connectionDetails.wrapError(operation{name: "Replay"}, err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReplayRecorder(t *testing.T) {
+	var log bytes.Buffer
+	recorder := &replayRecorder{w: &log, pending: map[int64]ReplayEntry{}}
+	command := func(doc bson.D) bson.Raw {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	recorder.started("Add", "test", "insert", 1, command(bson.D{
+		{Key: "insert", Value: "users"},
+		{Key: "documents", Value: bson.A{bson.D{{Key: "_id", Value: 1}}}},
+		{Key: "lsid", Value: bson.D{{Key: "id", Value: "session"}}},
+		{Key: "$db", Value: "test"},
+	}))
+	recorder.started("Get", "test", "find", 2, command(bson.D{{Key: "find", Value: "users"}}))
+	recorder.started("Delete", "test", "delete", 3, command(bson.D{{Key: "delete", Value: "users"}}))
+	recorder.failed(3)
+	for id := int64(1); id <= 3; id++ {
+		if err := recorder.succeeded(id); err != nil {
+			t.Fatalf("Unable to write replay log. %s", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the succeeded insert to be recorded, got %q", lines)
+	}
+	var entry ReplayEntry
+	if err := bson.UnmarshalExtJSON([]byte(lines[0]), true, &entry); err != nil {
+		t.Fatalf("Unable to read replay entry. %s", err)
+	}
+	if entry.Operation != "Add" || entry.Collection != "users" || entry.Database != "test" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	for _, elem := range entry.Command {
+		if elem.Key == "lsid" || elem.Key == "$db" {
+			t.Errorf("Expected %s not to be recorded", elem.Key)
+		}
+	}
+	if len(recorder.pending) != 0 {
+		t.Errorf("Expected no pending command, got %d", len(recorder.pending))
+	}
+}
+
+func TestClient_Replay_invalid(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	applied, err := client.Replay(strings.NewReader("not json\n"))
+	if err == nil || applied != 0 {
+		t.Errorf("Replay() = %d, %v, want an error", applied, err)
+	}
+}
+
+func TestClient_Replay(t *testing.T) {
+	var log bytes.Buffer
+	recorded := NewMongoClient(client.ConnectionUrl, client.DatabaseName, client.Context, WithReplayLog(&log))
+	_ = recorded.DropCollection("replay_collection")
+	if _, err := recorded.Add("replay_collection", data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := recorded.UpdateCustom("replay_collection", bson.M{"_id": "1"}, bson.M{"name": "Gollahalli"}); err != nil {
+		t.Fatalf("Unable to update data. %s", err)
+	}
+
+	target := client.WithDatabase(client.DatabaseName + "_replay")
+	_ = target.DropCollection("replay_collection")
+	applied, err := target.Replay(&log)
+	if err != nil {
+		t.Fatalf("Unable to replay. %s", err)
+	}
+	if applied < 2 {
+		t.Errorf("Expected at least 2 applied entries, got %d", applied)
+	}
+	result, err := target.Get("replay_collection", "1")
+	if err != nil {
+		t.Fatalf("Unable to get replayed data. %s", err)
+	}
+	var got data
+	if err := result.Decode(&got); err != nil || got.Name != "Gollahalli" {
+		t.Errorf("Expected the replayed update, got %v %v", got, err)
+	}
+}
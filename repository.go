@@ -0,0 +1,154 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Page selects the documents returned by Repository.List, Number counts from 1 and Size 0 returns every document.
+type Page struct {
+	Number int64
+	Size   int64
+}
+
+// options returns the options reading the page.
+func (page Page) options() []Option {
+	if page.Size <= 0 {
+		return nil
+	}
+	number := page.Number
+	if number < 1 {
+		number = 1
+	}
+	return []Option{Skip((number - 1) * page.Size), Limit(page.Size)}
+}
+
+// RepositoryOptions configures a Repository.
+type RepositoryOptions[T any] struct {
+	// IDField is the field GetByID, Update and Delete find a model by, defaults to "_id". Index a custom field as
+	// unique, and tag the "_id" of T with omitempty so Update does not set it.
+	IDField string
+
+	// BeforeCreate and BeforeUpdate are called with the model before it is written, an error stops the write
+	BeforeCreate func(model *T) error
+	BeforeUpdate func(id string, model *T) error
+
+	// BeforeDelete is called before the model with id is deleted, an error stops the delete
+	BeforeDelete func(id string) error
+
+	// AfterCreate, AfterUpdate and AfterDelete are called once the write succeeded
+	AfterCreate func(model *T)
+	AfterUpdate func(id string, model *T)
+	AfterDelete func(id string)
+}
+
+// Repository is the data access layer of the model T stored in a collection, for services that want a conventional
+// repository per model instead of the Client methods:
+//
+//	type UserRepository struct {
+//		*mongo.Repository[User]
+//	}
+//
+//	users := UserRepository{mongo.NewRepository[User](client, "users", mongo.RepositoryOptions[User]{
+//		BeforeCreate: func(user *User) error { user.CreatedAt = time.Now(); return nil },
+//	})}
+//	_, err := users.Create(&User{ID: "akshay", Name: "Akshay"})
+//
+// It works on any Store, a FakeClient in tests. The BeforeInsertHook and BeforeUpdateHook of T are called by the
+// Client as usual, after the hooks of the repository.
+type Repository[T any] struct {
+	store      Store
+	collection string
+	options    RepositoryOptions[T]
+}
+
+// NewRepository returns the Repository of T stored in collectionName of store.
+func NewRepository[T any](store Store, collectionName string, opts RepositoryOptions[T]) *Repository[T] {
+	if opts.IDField == "" {
+		opts.IDField = "_id"
+	}
+	return &Repository[T]{store: store, collection: collectionName, options: opts}
+}
+
+// Create inserts model and returns its "_id", the one generated when model has none.
+func (repository *Repository[T]) Create(model *T, opts ...Option) (interface{}, error) {
+	if hook := repository.options.BeforeCreate; hook != nil {
+		if err := hook(model); err != nil {
+			return nil, err
+		}
+	}
+	result, err := repository.store.Add(repository.collection, model, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if hook := repository.options.AfterCreate; hook != nil {
+		hook(model)
+	}
+	return result.InsertedID, nil
+}
+
+// GetByID returns the model with id, mongo.ErrNoDocuments when there is none.
+func (repository *Repository[T]) GetByID(id string, opts ...Option) (T, error) {
+	var model T
+	result, err := repository.store.GetCustom(repository.collection, repository.filter(id), opts...)
+	if err != nil {
+		return model, err
+	}
+	if err := result.Decode(&model); err != nil {
+		return model, err
+	}
+	return model, nil
+}
+
+// List returns the page of the models matching filter, nil matches every model. Sort them with SortAsc or SortDesc so
+// the pages are stable.
+func (repository *Repository[T]) List(filter interface{}, page Page, opts ...Option) ([]T, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	models := []T{}
+	err := repository.store.GetAllCustom(repository.collection, filter, &models, append(opts, page.options()...)...)
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// Update sets the fields of model on the model with id.
+func (repository *Repository[T]) Update(id string, model *T, opts ...Option) (*mongo.UpdateResult, error) {
+	if hook := repository.options.BeforeUpdate; hook != nil {
+		if err := hook(id, model); err != nil {
+			return nil, err
+		}
+	}
+	result, err := repository.store.UpdateCustom(repository.collection, repository.filter(id), model, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if hook := repository.options.AfterUpdate; hook != nil {
+		hook(id, model)
+	}
+	return result, nil
+}
+
+// Delete deletes the model with id.
+func (repository *Repository[T]) Delete(id string, opts ...Option) (*mongo.DeleteResult, error) {
+	if hook := repository.options.BeforeDelete; hook != nil {
+		if err := hook(id); err != nil {
+			return nil, err
+		}
+	}
+	result, err := repository.store.DeleteCustom(repository.collection, repository.filter(id), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if hook := repository.options.AfterDelete; hook != nil {
+		hook(id)
+	}
+	return result, nil
+}
+
+// filter returns the filter of the model with id.
+func (repository *Repository[T]) filter(id string) bson.D {
+	return bson.D{{Key: repository.options.IDField, Value: id}}
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRepository(t *testing.T) {
+	var events []string
+	repository := NewRepository[data](NewFakeClient(), "users", RepositoryOptions[data]{
+		BeforeCreate: func(model *data) error {
+			if model.Name == "" {
+				return errors.New("name is required")
+			}
+			return nil
+		},
+		AfterCreate: func(model *data) { events = append(events, "created "+model.ID) },
+		AfterUpdate: func(id string, model *data) { events = append(events, "updated "+id) },
+		AfterDelete: func(id string) { events = append(events, "deleted "+id) },
+	})
+
+	if _, err := repository.Create(&data{ID: "1"}); err == nil {
+		t.Error("Expected the BeforeCreate error")
+	}
+	for _, model := range []data{{ID: "1", Name: "Akshay"}, {ID: "2", Name: "Raj"}} {
+		model := model
+		if _, err := repository.Create(&model); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := repository.Update("2", &data{ID: "2", Name: "Raju"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repository.GetByID("2"); err != nil || got.Name != "Raju" {
+		t.Errorf("GetByID() = %v, %v, want Raju", got, err)
+	}
+	got, err := repository.List(bson.M{"name": "Akshay"}, Page{})
+	if err != nil || !reflect.DeepEqual(got, []data{{ID: "1", Name: "Akshay"}}) {
+		t.Errorf("List() = %v, %v", got, err)
+	}
+	if _, err := repository.Delete("1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repository.GetByID("1"); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("Expected mongo.ErrNoDocuments, got %v", err)
+	}
+
+	want := []string{"created 1", "created 2", "updated 2", "deleted 1"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Expected hooks %v, got %v", want, events)
+	}
+}
+
+func TestRepository_idField(t *testing.T) {
+	type user struct {
+		Email string `bson:"email"`
+		Name  string `bson:"name"`
+	}
+	repository := NewRepository[user](NewFakeClient(), "users", RepositoryOptions[user]{IDField: "email"})
+	if _, err := repository.Create(&user{Email: "a@example.com", Name: "Akshay"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repository.GetByID("a@example.com"); err != nil || got.Name != "Akshay" {
+		t.Errorf("GetByID() = %v, %v, want Akshay", got, err)
+	}
+}
+
+func TestPage_options(t *testing.T) {
+	tests := []struct {
+		page        Page
+		skip, limit int64
+	}{
+		{Page{}, 0, 0},
+		{Page{Number: 1, Size: 10}, 0, 10},
+		{Page{Number: 3, Size: 10}, 20, 10},
+		{Page{Number: 0, Size: 5}, 0, 5},
+	}
+	for _, tt := range tests {
+		o := newOperationOptions(tt.page.options())
+		if o.skip != tt.skip || o.limit != tt.limit {
+			t.Errorf("%+v: expected skip %d and limit %d, got %d and %d", tt.page, tt.skip, tt.limit, o.skip, o.limit)
+		}
+	}
+}
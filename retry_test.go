@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"not primary", mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, true},
+		{"network", mongo.CommandError{Labels: []string{"NetworkError"}}, true},
+		{"retryable write", mongo.WriteException{Labels: []string{"RetryableWriteError"}}, true},
+		{"duplicate key", mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, false},
+		{"other", errors.New("failed"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	if backoff := policy.backoff(1); backoff != 100*time.Millisecond {
+		t.Errorf("Expected 100ms, got %s", backoff)
+	}
+	if backoff := policy.backoff(2); backoff != 200*time.Millisecond {
+		t.Errorf("Expected 200ms, got %s", backoff)
+	}
+	if backoff := policy.backoff(10); backoff != 300*time.Millisecond {
+		t.Errorf("Expected 300ms, got %s", backoff)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var attempts int
+	retryClient := NewMongoClientDefault("mongodb://localhost:27017", "test",
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		WithMetricsHook(func(metrics OperationMetrics) {
+			attempts = metrics.Attempts
+		}))
+
+	calls := 0
+	err := retryClient.run(operation{name: "Add"}, func(ctx context.Context, db *mongo.Database) error {
+		calls++
+		if calls < 3 {
+			return mongo.CommandError{Code: 189, Name: "PrimarySteppedDown"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected operation to succeed, got %s", err)
+	}
+	if calls != 3 || attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d calls and %d attempts", calls, attempts)
+	}
+
+	calls = 0
+	err = retryClient.run(operation{name: "Add"}, func(ctx context.Context, db *mongo.Database) error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected a single attempt for a permanent error, got %d", calls)
+	}
+}
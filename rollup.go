@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Accumulator computes a value over the documents of a group, see RollupByTime.
+type Accumulator struct {
+	operator   string
+	expression interface{}
+}
+
+// Count counts the documents.
+func Count() Accumulator {
+	return Accumulator{operator: "$sum", expression: 1}
+}
+
+// Sum adds the values of field.
+func Sum(field string) Accumulator {
+	return Accumulator{operator: "$sum", expression: "$" + field}
+}
+
+// Avg averages the values of field.
+func Avg(field string) Accumulator {
+	return Accumulator{operator: "$avg", expression: "$" + field}
+}
+
+// Min is the lowest value of field.
+func Min(field string) Accumulator {
+	return Accumulator{operator: "$min", expression: "$" + field}
+}
+
+// Max is the highest value of field.
+func Max(field string) Accumulator {
+	return Accumulator{operator: "$max", expression: "$" + field}
+}
+
+// First is the value of field in the first document.
+func First(field string) Accumulator {
+	return Accumulator{operator: "$first", expression: "$" + field}
+}
+
+// Last is the value of field in the last document.
+func Last(field string) Accumulator {
+	return Accumulator{operator: "$last", expression: "$" + field}
+}
+
+// document returns the accumulator expression.
+func (accumulator Accumulator) document() bson.D {
+	return bson.D{{Key: accumulator.operator, Value: accumulator.expression}}
+}
+
+// RollupByTime groups the documents of collectionName matching filter by timeField truncated to granularity with
+// $dateTrunc, and computes the aggregations of each group. granularity is a $dateTrunc unit: "year", "quarter",
+// "month", "week", "day", "hour", "minute" or "second". Requires MongoDB 5.0.
+//
+// Each result holds timeField, the start of the period, and the aggregations by name, sorted by time:
+//
+//	var hourly []struct {
+//		At    time.Time `bson:"at"`
+//		Total float64   `bson:"total"`
+//		Count int       `bson:"count"`
+//	}
+//	err := client.RollupByTime("orders", "at", "hour", map[string]mongo.Accumulator{
+//		"total": mongo.Sum("amount"),
+//		"count": mongo.Count(),
+//	}, bson.M{"status": "paid"}, &hourly)
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) RollupByTime(collectionName string, timeField string, granularity string, aggregations map[string]Accumulator, filter interface{}, result interface{}, opts ...Option) error {
+	op := operation{name: "RollupByTime", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.aggregate(op, rollupPipeline(timeField, granularity, "", aggregations, filter), result)
+}
+
+// rollupPipeline groups by timeField truncated to unit and by groupField when it is not empty.
+func rollupPipeline(timeField string, unit string, groupField string, aggregations map[string]Accumulator, filter interface{}) mongo.Pipeline {
+	pipeline := mongo.Pipeline{}
+	if !isEmptyFilter(filter) {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+
+	period := bson.D{{Key: "$dateTrunc", Value: bson.D{{Key: "date", Value: "$" + timeField}, {Key: "unit", Value: unit}}}}
+	id := bson.D{{Key: "time", Value: period}}
+	if groupField != "" {
+		id = append(id, bson.E{Key: "group", Value: "$" + groupField})
+	}
+	group := bson.D{{Key: "_id", Value: id}}
+	names := make([]string, 0, len(aggregations))
+	for name := range aggregations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group = append(group, bson.E{Key: name, Value: aggregations[name].document()})
+	}
+
+	fields := bson.D{{Key: timeField, Value: "$_id.time"}}
+	if groupField != "" {
+		fields = append(fields, bson.E{Key: groupField, Value: "$_id.group"})
+	}
+	sortBy := bson.D{{Key: "_id.time", Value: 1}}
+	if groupField != "" {
+		sortBy = append(sortBy, bson.E{Key: "_id.group", Value: 1})
+	}
+	return append(pipeline,
+		bson.D{{Key: "$group", Value: group}},
+		bson.D{{Key: "$sort", Value: sortBy}},
+		bson.D{{Key: "$addFields", Value: fields}},
+		bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}}}},
+	)
+}
+
+// aggregate runs pipeline on the collection of op and decodes every document into result.
+func (connectionDetails *Client) aggregate(op operation, pipeline interface{}, result interface{}) error {
+	if err := connectionDetails.checkFederatedPipeline(pipeline); err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		cursor, err := connectionDetails.collection(db, op.collection, op.options).Aggregate(ctx, pipeline, op.options.aggregate())
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
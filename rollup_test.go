@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRollupPipeline(t *testing.T) {
+	pipeline := rollupPipeline("at", "hour", "", map[string]Accumulator{
+		"total": Sum("amount"),
+		"count": Count(),
+	}, bson.M{"status": "paid"})
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "paid"}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "time", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{{Key: "date", Value: "$at"}, {Key: "unit", Value: "hour"}}}}}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: "$amount"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.time", Value: 1}}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "at", Value: "$_id.time"}}}},
+		{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("rollupPipeline() = %v, want %v", pipeline, want)
+	}
+
+	grouped := rollupPipeline("at", "day", "sensor", map[string]Accumulator{"avg": Avg("value")}, nil)
+	if len(grouped) != 4 {
+		t.Fatalf("Expected no $match stage without a filter, got %v", grouped)
+	}
+	wantID := bson.D{
+		{Key: "time", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{{Key: "date", Value: "$at"}, {Key: "unit", Value: "day"}}}}},
+		{Key: "group", Value: "$sensor"},
+	}
+	if id := grouped[0][0].Value.(bson.D)[0].Value; !reflect.DeepEqual(id, wantID) {
+		t.Errorf("Unexpected group ID %v", id)
+	}
+}
@@ -0,0 +1,143 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrUnsafeOperation is returned when the SafetyProfile of a Client refuses an operation.
+var ErrUnsafeOperation = errors.New("mongo: operation refused by the safety profile")
+
+// ErrTooManyDocuments is returned by DeleteManyLimited when the filter matches more documents than allowed.
+var ErrTooManyDocuments = errors.New("mongo: filter matches too many documents")
+
+// dropOperations and manyOperations are the operations guarded by a SafetyProfile.
+var (
+	dropOperations = map[string]bool{"DropDatabase": true, "DropCollection": true, "DropCollections": true}
+	manyOperations = map[string]bool{"DeleteMany": true, "DeleteManyLimited": true, "UpdateMany": true}
+)
+
+// SafetyProfile is a set of protections against destructive mistakes, see WithSafetyProfile.
+type SafetyProfile struct {
+	// ForbidDrops refuses to drop databases and collections
+	ForbidDrops bool
+
+	// RequireFilter refuses DeleteMany and UpdateMany with an empty filter
+	RequireFilter bool
+
+	// MaxDocuments refuses DeleteMany and UpdateMany matching more documents, 0 means no limit
+	MaxDocuments int64
+
+	// MajorityCollections are collections whose writes always use the "majority" write concern
+	MajorityCollections []string
+}
+
+// Production forbids drops, requires filters and limits DeleteMany and UpdateMany to 10000 documents.
+var Production = SafetyProfile{
+	ForbidDrops:   true,
+	RequireFilter: true,
+	MaxDocuments:  10000,
+}
+
+// WithSafetyProfile refuses operations that violate profile with ErrUnsafeOperation. Use AllowUnsafe to override the
+// profile for a single operation.
+func WithSafetyProfile(profile SafetyProfile) ClientOption {
+	return func(client *Client) {
+		client.safety = &profile
+	}
+}
+
+// AllowUnsafe lets an operation bypass the SafetyProfile of the Client, reason is logged as a warning.
+func AllowUnsafe(reason string) Option {
+	return func(o *operationOptions) {
+		o.unsafeReason = reason
+	}
+}
+
+// checkSafety returns an error wrapping ErrUnsafeOperation when op violates the SafetyProfile.
+func (connectionDetails *Client) checkSafety(ctx context.Context, db *mongo.Database, op operation) error {
+	profile := connectionDetails.safety
+	if profile == nil {
+		return nil
+	}
+	if op.options != nil && op.options.unsafeReason != "" {
+		connectionDetails.log().Warn("mongo: safety profile overridden", "operation", op.name, "collection", op.collection,
+			"reason", op.options.unsafeReason)
+		return nil
+	}
+
+	if profile.ForbidDrops && dropOperations[op.name] {
+		return fmt.Errorf("%w: %s is forbidden", ErrUnsafeOperation, op.name)
+	}
+	if !manyOperations[op.name] {
+		return nil
+	}
+	if profile.RequireFilter && isEmptyFilter(op.filter) {
+		return fmt.Errorf("%w: %s requires a filter", ErrUnsafeOperation, op.name)
+	}
+	if profile.MaxDocuments > 0 {
+		count, err := db.Collection(op.collection).CountDocuments(ctx, op.filter)
+		if err != nil {
+			return err
+		}
+		if count > profile.MaxDocuments {
+			return fmt.Errorf("%w: %s matches %d documents, the limit is %d", ErrUnsafeOperation, op.name, count, profile.MaxDocuments)
+		}
+	}
+	return nil
+}
+
+// DeleteManyLimited deletes the documents matching filter like DeleteMany, only when there are at most maxDocuments
+// of them. The number of matching documents is returned either way, with an error wrapping ErrTooManyDocuments when
+// nothing was deleted because of the limit:
+//
+//	result, matched, err := client.DeleteManyLimited("sessions", bson.M{"expiresAt": bson.M{"$lt": cutoff}}, 5000)
+//	if errors.Is(err, mongo.ErrTooManyDocuments) {
+//		log.Printf("refusing to delete %d sessions", matched)
+//	}
+//
+// The documents are counted before they are deleted, documents matching filter in between are deleted too.
+func (connectionDetails *Client) DeleteManyLimited(collectionName string, filter interface{}, maxDocuments int64, opts ...Option) (*mongo.DeleteResult, int64, error) {
+	filter = connectionDetails.normalizedFilter(collectionName, filter, opts)
+	op := operation{name: "DeleteManyLimited", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	var deleteResult *mongo.DeleteResult
+	var matched int64
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := connectionDetails.collection(db, collectionName, op.options)
+		var err error
+		if matched, err = collection.CountDocuments(ctx, filter); err != nil {
+			return err
+		}
+		if matched > maxDocuments {
+			return fmt.Errorf("%w: %d documents, the limit is %d", ErrTooManyDocuments, matched, maxDocuments)
+		}
+		deleteResult, err = collection.DeleteMany(ctx, filter, op.options.delete())
+		return err
+	})
+	if err != nil {
+		return nil, matched, err
+	}
+	return deleteResult, matched, nil
+}
+
+// requiresMajority reports whether writes to collectionName must use the "majority" write concern.
+func (profile *SafetyProfile) requiresMajority(collectionName string) bool {
+	if profile == nil {
+		return false
+	}
+	for _, name := range profile.MajorityCollections {
+		if name == collectionName {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyFilter reports whether filter matches every document.
+func isEmptyFilter(filter interface{}) bool {
+	doc, err := toDocument(filter)
+	return err == nil && len(doc) == 0
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_checkSafety(t *testing.T) {
+	safeClient := NewMongoClientDefault("mongodb://localhost:27017", "test",
+		WithSafetyProfile(SafetyProfile{ForbidDrops: true, RequireFilter: true}))
+
+	tests := []struct {
+		name string
+		op   operation
+		want error
+	}{
+		{"drop", operation{name: "DropDatabase"}, ErrUnsafeOperation},
+		{"empty filter", operation{name: "DeleteMany", filter: bson.M{}}, ErrUnsafeOperation},
+		{"nil filter", operation{name: "UpdateMany"}, ErrUnsafeOperation},
+		{"filter", operation{name: "DeleteMany", filter: bson.M{"status": "stale"}}, nil},
+		{"single document", operation{name: "DeleteCustom", filter: bson.M{}}, nil},
+		{"override", operation{name: "DeleteMany", filter: bson.M{}, options: newOperationOptions([]Option{AllowUnsafe("cleanup")})}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := safeClient.checkSafety(context.Background(), nil, tt.op)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("checkSafety() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafetyProfile_requiresMajority(t *testing.T) {
+	profile := &SafetyProfile{MajorityCollections: []string{"payments"}}
+	if !profile.requiresMajority("payments") || profile.requiresMajority("logs") {
+		t.Errorf("Expected only payments to require majority writes")
+	}
+	var none *SafetyProfile
+	if none.requiresMajority("payments") {
+		t.Errorf("Expected no requirement without a profile")
+	}
+}
+
+func TestClient_DeleteManyLimited(t *testing.T) {
+	_ = client.DropCollections([]string{"limited"})
+	if _, err := client.AddMany("limited", []interface{}{data{ID: "1", Name: "Akshay"}, data{ID: "2", Name: "Akshay"}, data{ID: "3", Name: "Raj"}}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	result, matched, err := client.DeleteManyLimited("limited", bson.M{"name": "Akshay"}, 1)
+	if !errors.Is(err, ErrTooManyDocuments) || matched != 2 || result != nil {
+		t.Errorf("Expected ErrTooManyDocuments with 2 matches, got %v, %d, %v", result, matched, err)
+	}
+	result, matched, err = client.DeleteManyLimited("limited", bson.M{"name": "Akshay"}, 2)
+	if err != nil || matched != 2 || result.DeletedCount != 2 {
+		t.Errorf("Expected 2 documents to be deleted, got %v, %d, %v", result, matched, err)
+	}
+}
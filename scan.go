@@ -0,0 +1,200 @@
+package mongo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// scanSamplesPerPartition is the number of "_id" sampled per partition to choose the partition boundaries.
+const scanSamplesPerPartition = 20
+
+// ScanOptions configures Client.ScanParallel.
+type ScanOptions struct {
+	// Workers is the number of partitions scanned at the same time, defaults to 4
+	Workers int
+
+	// Partitions is the number of "_id" ranges the collection is split into, defaults to 4 per worker
+	Partitions int
+
+	// Filter selects the documents to scan - bson.M{}, bson.D{} or Filter, defaults to every document
+	Filter interface{}
+
+	// Progress is called after every partition, never concurrently
+	Progress func(ScanProgress)
+}
+
+// ScanProgress reports how far a parallel scan went.
+type ScanProgress struct {
+	Partitions int
+	Completed  int
+
+	// Processed is the number of documents passed to fn
+	Processed int64
+
+	// Elapsed since the scan started
+	Elapsed time.Duration
+}
+
+// ScanParallel calls fn with every document of collectionName, scanning "_id" ranges concurrently. The boundaries of
+// the ranges are chosen from a random sample of "_id", so partitions hold about the same number of documents.
+//
+// fn is called concurrently by the workers. The first error returned by fn or by a partition stops the scan.
+//
+// Range queries only match "_id" of the same type as the boundaries, a collection mixing "_id" types in the sample is
+// scanned as a single partition.
+func (connectionDetails *Client) ScanParallel(collectionName string, opts ScanOptions, fn func(document bson.Raw) error) (*ScanProgress, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.Partitions <= 0 {
+		opts.Partitions = opts.Workers * 4
+	}
+	op := operation{name: "ScanParallel", collection: collectionName, filter: opts.Filter}
+	base, err := toDocument(opts.Filter)
+	if err != nil {
+		return nil, connectionDetails.wrapError(op, err)
+	}
+
+	var boundaries []interface{}
+	err = connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		boundaries, err = scanBoundaries(ctx, db.Collection(collectionName), opts.Partitions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(connectionDetails.Context)
+	defer cancel()
+	scanner := connectionDetails.WithContext(ctx)
+	start := time.Now()
+	progress := &ScanProgress{Partitions: len(boundaries) + 1}
+	var processed atomic.Int64
+	var mu sync.Mutex
+	var firstErr error
+
+	partitions := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < opts.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range partitions {
+				filter := Filter{doc: base}
+				if partition > 0 {
+					filter = filter.And(F("_id").Gte(boundaries[partition-1]))
+				}
+				if partition < len(boundaries) {
+					filter = filter.And(F("_id").Lt(boundaries[partition]))
+				}
+				err := scanner.run(operation{name: "ScanParallel", collection: collectionName, filter: filter}, func(ctx context.Context, db *mongo.Database) error {
+					cursor, err := db.Collection(collectionName).Find(ctx, filter)
+					if err != nil {
+						return err
+					}
+					defer cursor.Close(ctx)
+					for cursor.Next(ctx) {
+						if err := fn(cursor.Current); err != nil {
+							return err
+						}
+						processed.Add(1)
+					}
+					return cursor.Err()
+				})
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				if err == nil {
+					progress.Completed++
+					progress.Processed = processed.Load()
+					progress.Elapsed = time.Since(start)
+					if opts.Progress != nil {
+						opts.Progress(*progress)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for partition := 0; partition < progress.Partitions && ctx.Err() == nil; partition++ {
+		select {
+		case partitions <- partition:
+		case <-ctx.Done():
+		}
+	}
+	close(partitions)
+	wg.Wait()
+
+	progress.Processed = processed.Load()
+	progress.Elapsed = time.Since(start)
+	if firstErr != nil {
+		return progress, firstErr
+	}
+	if err := connectionDetails.Context.Err(); err != nil {
+		return progress, connectionDetails.wrapError(op, err)
+	}
+	return progress, nil
+}
+
+// scanBoundaries samples the "_id" of collection and returns the sorted boundaries splitting it in at most partitions
+// ranges, none when the sample mixes "_id" types.
+func scanBoundaries(ctx context.Context, collection *mongo.Collection, partitions int) ([]interface{}, error) {
+	if partitions <= 1 {
+		return nil, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: partitions * scanSamplesPerPartition}}}},
+		{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var samples []struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &samples); err != nil {
+		return nil, err
+	}
+	ids := make([]interface{}, len(samples))
+	for i, sample := range samples {
+		ids[i] = sample.ID
+	}
+	return partitionBoundaries(ids, partitions), nil
+}
+
+// partitionBoundaries returns the distinct quantiles of ids splitting them in at most partitions ranges.
+func partitionBoundaries(ids []interface{}, partitions int) []interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	for _, id := range ids[1:] {
+		if typeRank(id) != typeRank(ids[0]) {
+			return nil
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return compareValues(ids[i], ids[j]) < 0 })
+
+	var boundaries []interface{}
+	for i := 1; i < partitions; i++ {
+		boundary := ids[i*len(ids)/partitions]
+		if len(boundaries) > 0 && compareValues(boundaries[len(boundaries)-1], boundary) == 0 {
+			continue
+		}
+		if compareValues(boundary, ids[0]) == 0 {
+			continue
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	return boundaries
+}
@@ -0,0 +1,65 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPartitionBoundaries(t *testing.T) {
+	var ids []interface{}
+	for i := 99; i >= 0; i-- {
+		ids = append(ids, int32(i))
+	}
+	boundaries := partitionBoundaries(ids, 4)
+	want := []interface{}{int32(25), int32(50), int32(75)}
+	if len(boundaries) != len(want) {
+		t.Fatalf("partitionBoundaries() = %v, want %v", boundaries, want)
+	}
+	for i := range want {
+		if boundaries[i] != want[i] {
+			t.Errorf("partitionBoundaries() = %v, want %v", boundaries, want)
+		}
+	}
+
+	if got := partitionBoundaries([]interface{}{int32(1), int32(1), int32(1), int32(1)}, 4); len(got) != 0 {
+		t.Errorf("Expected no boundary for equal ids, got %v", got)
+	}
+	if got := partitionBoundaries([]interface{}{int32(1), "a", int32(3), "b"}, 2); got != nil {
+		t.Errorf("Expected no boundary for mixed types, got %v", got)
+	}
+}
+
+func TestClient_ScanParallel(t *testing.T) {
+	_ = client.DropCollection("scan_collection")
+	var docs []interface{}
+	for i := 0; i < 200; i++ {
+		docs = append(docs, data{ID: fmt.Sprintf("%03d", i), Name: "Akshay"})
+	}
+	if _, err := client.AddMany("scan_collection", docs); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	progress, err := client.ScanParallel("scan_collection", ScanOptions{Workers: 3}, func(document bson.Raw) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[document.Lookup("_id").StringValue()] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unable to scan. %s", err)
+	}
+	if len(seen) != 200 || progress.Processed != 200 || progress.Completed != progress.Partitions {
+		t.Errorf("Expected every document once, got %d documents, progress %+v", len(seen), progress)
+	}
+
+	errStop := errors.New("stop")
+	if _, err := client.ScanParallel("scan_collection", ScanOptions{}, func(bson.Raw) error { return errStop }); !errors.Is(err, errStop) {
+		t.Errorf("ScanParallel() = %v, want the error of fn", err)
+	}
+}
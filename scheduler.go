@@ -0,0 +1,341 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchedulerOptions configures a Scheduler.
+type SchedulerOptions struct {
+	// PollInterval is how often a started Scheduler looks for due jobs, defaults to 1 second
+	PollInterval time.Duration
+
+	// LockTimeout is how long a job stays locked by the instance running it, another instance runs it again when the
+	// lock expires, defaults to 5 minutes. Handlers must return before it expires.
+	LockTimeout time.Duration
+
+	// RetryDelay is how long a one-off job waits before running again after its handler failed, defaults to 1 minute
+	RetryDelay time.Duration
+
+	// MaxAttempts is the number of times a one-off job runs before it is marked as failed, defaults to 3
+	MaxAttempts int
+
+	// Location is the time zone of cron expressions, defaults to UTC
+	Location *time.Location
+}
+
+// Job is a job of a Scheduler.
+type Job struct {
+	ID interface{} `bson:"_id"`
+
+	// Handler is the name of the handler running the job
+	Handler string `bson:"handler"`
+
+	// Payload is the value given when scheduling the job, see Decode
+	Payload bson.RawValue `bson:"payload"`
+
+	// Cron is the cron expression of a recurring job, it is empty for a one-off job
+	Cron string `bson:"cron,omitempty"`
+
+	// RunAt is when the job is due
+	RunAt time.Time `bson:"runAt"`
+
+	// Attempts is the number of times a one-off job ran, including this one
+	Attempts int `bson:"attempts"`
+
+	// LastError is the error of the previous run, if it failed
+	LastError string `bson:"lastError,omitempty"`
+}
+
+// Decode decodes the payload of the job into v, a pointer.
+func (job *Job) Decode(v interface{}) error {
+	return job.Payload.Unmarshal(v)
+}
+
+// Scheduler runs jobs stored in a collection at a given time or on a cron schedule, see Client.Scheduler.
+type Scheduler struct {
+	client     *Client
+	collection string
+	options    SchedulerOptions
+	owner      primitive.ObjectID
+
+	mu       sync.RWMutex
+	handlers map[string]func(job *Job) error
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Scheduler returns a Scheduler of the jobs stored in collectionName. Jobs are documents with the time they are due,
+// which every instance of the application polls. An instance claims a due job by locking it with a
+// findOneAndUpdate, so each run of a job happens on a single instance, then calls the handler registered for it:
+//
+//	scheduler := client.Scheduler("jobs", mongo.SchedulerOptions{})
+//	scheduler.Handle("reminder", sendReminder)
+//	scheduler.Handle("report", sendDailyReport)
+//	_, err := scheduler.ScheduleAt("reminder", time.Now().Add(24*time.Hour), reminder)
+//	err = scheduler.ScheduleCron("daily-report", "report", "0 6 * * *", nil)
+//	scheduler.Start()
+//	defer scheduler.Stop()
+//
+// A job whose instance crashes runs again once its lock expires, handlers must be idempotent. Instances only claim the
+// jobs they have a handler for. Use EnsureIndexes once so polling does not scan the collection.
+func (connectionDetails *Client) Scheduler(collectionName string, opts SchedulerOptions) *Scheduler {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 5 * time.Minute
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &Scheduler{
+		client:     connectionDetails,
+		collection: collectionName,
+		options:    opts,
+		owner:      primitive.NewObjectID(),
+		handlers:   map[string]func(job *Job) error{},
+	}
+}
+
+// EnsureIndexes creates the index used to find due jobs.
+func (scheduler *Scheduler) EnsureIndexes() error {
+	_, err := scheduler.client.CreateIndex(scheduler.collection, Index{Keys: bson.D{{Key: "runAt", Value: 1}}})
+	return err
+}
+
+// Handle registers fn as the handler called handler. A job is retried when fn returns an error.
+func (scheduler *Scheduler) Handle(handler string, fn func(job *Job) error) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	scheduler.handlers[handler] = fn
+}
+
+// ScheduleAt schedules a one-off job of handler with payload at runAt, it is deleted once it ran successfully.
+func (scheduler *Scheduler) ScheduleAt(handler string, runAt time.Time, payload interface{}, opts ...Option) (primitive.ObjectID, error) {
+	id := primitive.NewObjectID()
+	doc := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "handler", Value: handler},
+		{Key: "payload", Value: payload},
+		{Key: "runAt", Value: runAt},
+		{Key: "attempts", Value: 0},
+	}
+	op := operation{name: "Schedule", collection: scheduler.collection, documents: []interface{}{doc}, options: newOperationOptions(opts)}
+	err := scheduler.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := scheduler.client.collection(db, scheduler.collection, op.options).InsertOne(ctx, doc)
+		return err
+	})
+	return id, err
+}
+
+// ScheduleCron schedules the recurring job id of handler with payload on the cron expression. Expressions have five
+// fields - minute, hour, day of month, month and day of week - with "*", lists, ranges and steps, like
+// "*/15 9-17 * * 1-5", or are one of the @yearly, @monthly, @weekly, @daily and @hourly shorthands.
+//
+// Scheduling an existing id replaces its handler, schedule and payload, so it is safe to call on every start of the
+// application.
+func (scheduler *Scheduler) ScheduleCron(id string, handler string, expression string, payload interface{}, opts ...Option) error {
+	op := operation{name: "Schedule", collection: scheduler.collection, filter: bson.M{"_id": id}, options: newOperationOptions(opts)}
+	schedule, err := parseCron(expression)
+	if err != nil {
+		return scheduler.client.wrapError(op, err)
+	}
+	runAt := schedule.next(time.Now().In(scheduler.options.Location))
+	if runAt.IsZero() {
+		return scheduler.client.wrapError(op, fmt.Errorf("mongo: cron expression %q never matches", expression))
+	}
+	return scheduler.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "handler", Value: handler},
+			{Key: "payload", Value: payload},
+			{Key: "cron", Value: expression},
+			{Key: "runAt", Value: runAt},
+			{Key: "attempts", Value: 0},
+		}}}
+		_, err := scheduler.client.collection(db, scheduler.collection, op.options).UpdateOne(ctx, bson.M{"_id": id}, update, options.Update().SetUpsert(true))
+		return err
+	})
+}
+
+// Cancel deletes the job with the given id, a job that is running finishes its run.
+func (scheduler *Scheduler) Cancel(id interface{}, opts ...Option) error {
+	op := operation{name: "CancelJob", collection: scheduler.collection, filter: bson.M{"_id": id}, options: newOperationOptions(opts)}
+	return scheduler.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		_, err := scheduler.client.collection(db, scheduler.collection, op.options).DeleteOne(ctx, bson.M{"_id": id})
+		return err
+	})
+}
+
+// Start polls for due jobs every PollInterval until Stop is called or the Client context is done. To manage the
+// polling with the other background work of the Client, register Worker instead.
+func (scheduler *Scheduler) Start() {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	if scheduler.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(scheduler.client.Context)
+	done := make(chan struct{})
+	scheduler.cancel, scheduler.done = cancel, done
+	go func() {
+		defer close(done)
+		_ = scheduler.poll(ctx)
+	}()
+}
+
+// Worker returns a Worker polling for due jobs every PollInterval, for Client.RegisterWorker. Use it instead of
+// Start and Stop.
+func (scheduler *Scheduler) Worker() Worker {
+	return Worker{Name: "scheduler:" + scheduler.collection, Run: scheduler.poll}
+}
+
+// poll runs the due jobs every PollInterval until ctx is done.
+func (scheduler *Scheduler) poll(ctx context.Context) error {
+	ticker := time.NewTicker(scheduler.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := scheduler.RunDue(); err != nil {
+			scheduler.client.log().Warn("mongo: unable to run due jobs", "collection", scheduler.collection, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops polling and waits for the running jobs to finish.
+func (scheduler *Scheduler) Stop() {
+	scheduler.mu.Lock()
+	cancel, done := scheduler.cancel, scheduler.done
+	scheduler.cancel = nil
+	scheduler.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// RunDue runs the due jobs this instance has a handler for, one at a time, and returns how many ran. Start calls it
+// on every poll, it can also be called directly, for example from a test or an external timer.
+func (scheduler *Scheduler) RunDue() (int, error) {
+	ran := 0
+	for {
+		job, err := scheduler.claim()
+		if err != nil || job == nil {
+			return ran, err
+		}
+		scheduler.mu.RLock()
+		handler := scheduler.handlers[job.Handler]
+		scheduler.mu.RUnlock()
+		runErr := fmt.Errorf("mongo: no handler %q", job.Handler)
+		if handler != nil {
+			runErr = handler(job)
+		}
+		if runErr != nil {
+			scheduler.client.log().Warn("mongo: job failed", "collection", scheduler.collection, "job", job.ID, "handler", job.Handler, "error", runErr)
+		}
+		if err := scheduler.complete(job, runErr); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+}
+
+// claim locks a due job of a registered handler, it returns nil when there is none.
+func (scheduler *Scheduler) claim() (*Job, error) {
+	scheduler.mu.RLock()
+	handlers := make([]string, 0, len(scheduler.handlers))
+	for handler := range scheduler.handlers {
+		handlers = append(handlers, handler)
+	}
+	scheduler.mu.RUnlock()
+	if len(handlers) == 0 {
+		return nil, nil
+	}
+
+	op := operation{name: "RunDue", collection: scheduler.collection}
+	var job *Job
+	err := scheduler.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		job = nil
+		now := time.Now()
+		filter := bson.D{
+			{Key: "runAt", Value: bson.D{{Key: "$lte", Value: now}}},
+			{Key: "handler", Value: bson.D{{Key: "$in", Value: handlers}}},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "lockedUntil", Value: bson.D{{Key: "$exists", Value: false}}}},
+				bson.D{{Key: "lockedUntil", Value: bson.D{{Key: "$lte", Value: now}}}},
+			}},
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "lockedUntil", Value: now.Add(scheduler.options.LockTimeout)}, {Key: "lockedBy", Value: scheduler.owner}}},
+			{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+		}
+		found := options.FindOneAndUpdate().SetSort(bson.D{{Key: "runAt", Value: 1}}).SetReturnDocument(options.After)
+		var claimed Job
+		err := scheduler.client.collection(db, scheduler.collection, nil).FindOneAndUpdate(ctx, filter, update, found).Decode(&claimed)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		job = &claimed
+		return nil
+	})
+	return job, err
+}
+
+// complete records the run of job and releases its lock. A successful one-off job is deleted, a failed one is retried
+// after RetryDelay until MaxAttempts, and a recurring job is scheduled at its next time.
+func (scheduler *Scheduler) complete(job *Job, runErr error) error {
+	op := operation{name: "RunDue", collection: scheduler.collection, filter: bson.M{"_id": job.ID}}
+	return scheduler.client.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := scheduler.client.collection(db, scheduler.collection, nil)
+		filter := bson.D{{Key: "_id", Value: job.ID}, {Key: "lockedBy", Value: scheduler.owner}}
+		if job.Cron == "" && runErr == nil {
+			_, err := collection.DeleteOne(ctx, filter)
+			return err
+		}
+
+		set := bson.D{}
+		unset := bson.D{{Key: "lockedUntil", Value: ""}, {Key: "lockedBy", Value: ""}}
+		if runErr != nil {
+			set = append(set, bson.E{Key: "lastError", Value: runErr.Error()})
+		} else {
+			unset = append(unset, bson.E{Key: "lastError", Value: ""})
+		}
+		switch {
+		case job.Cron != "":
+			schedule, err := parseCron(job.Cron)
+			if err != nil {
+				return err
+			}
+			set = append(set, bson.E{Key: "runAt", Value: schedule.next(time.Now().In(scheduler.options.Location))}, bson.E{Key: "attempts", Value: 0})
+		case job.Attempts >= scheduler.options.MaxAttempts:
+			// a failed job is kept for inspection, without a runAt it is never due again
+			set = append(set, bson.E{Key: "failed", Value: true})
+			unset = append(unset, bson.E{Key: "runAt", Value: ""})
+		default:
+			set = append(set, bson.E{Key: "runAt", Value: time.Now().Add(scheduler.options.RetryDelay)})
+		}
+		_, err := collection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: set}, {Key: "$unset", Value: unset}})
+		return err
+	})
+}
@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClient_Scheduler_invalidCron(t *testing.T) {
+	scheduler := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test").Scheduler("jobs", SchedulerOptions{})
+	if err := scheduler.ScheduleCron("report", "report", "0 25 * * *", nil); err == nil {
+		t.Errorf("Expected an error for an invalid cron expression")
+	}
+	if ran, err := scheduler.RunDue(); ran != 0 || err != nil {
+		t.Errorf("Expected nothing to run without handlers, got %d, %v", ran, err)
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	_ = client.DropCollection("scheduler")
+	scheduler := client.Scheduler("scheduler", SchedulerOptions{MaxAttempts: 2, RetryDelay: time.Millisecond})
+	var names []string
+	scheduler.Handle("greet", func(job *Job) error {
+		var payload data
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		names = append(names, payload.Name)
+		return nil
+	})
+	scheduler.Handle("fail", func(job *Job) error {
+		return errors.New("boom")
+	})
+
+	if _, err := scheduler.ScheduleAt("greet", time.Now().Add(-time.Second), data{Name: "Akshay"}); err != nil {
+		t.Fatalf("ScheduleAt() error = %v", err)
+	}
+	if _, err := scheduler.ScheduleAt("greet", time.Now().Add(time.Hour), data{Name: "Later"}); err != nil {
+		t.Fatalf("ScheduleAt() error = %v", err)
+	}
+	failing, err := scheduler.ScheduleAt("fail", time.Now().Add(-time.Second), nil)
+	if err != nil {
+		t.Fatalf("ScheduleAt() error = %v", err)
+	}
+	if err := scheduler.ScheduleCron("hourly", "greet", "@hourly", data{Name: "Cron"}); err != nil {
+		t.Fatalf("ScheduleCron() error = %v", err)
+	}
+
+	ran, err := scheduler.RunDue()
+	if err != nil || ran != 2 || len(names) != 1 || names[0] != "Akshay" {
+		t.Errorf("RunDue() = %d, %v, names %v", ran, err, names)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ran, err := scheduler.RunDue(); err != nil || ran != 1 {
+		t.Errorf("Expected the failed job to be retried, got %d, %v", ran, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ran, err := scheduler.RunDue(); err != nil || ran != 0 {
+		t.Errorf("Expected the job to fail after MaxAttempts, got %d, %v", ran, err)
+	}
+	if err := scheduler.Cancel(failing); err != nil {
+		t.Errorf("Cancel() error = %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// namespaceNotFound is the server error code returned by collMod for a collection that does not exist.
+const namespaceNotFound = 26
+
+// bsonTypes maps the Go types with a dedicated BSON type to their $jsonSchema bsonType.
+var bsonTypes = map[reflect.Type]string{
+	reflect.TypeOf(time.Time{}):            "date",
+	reflect.TypeOf(primitive.DateTime(0)):  "date",
+	reflect.TypeOf(primitive.ObjectID{}):   "objectId",
+	reflect.TypeOf(primitive.Decimal128{}): "decimal",
+	reflect.TypeOf(primitive.Binary{}):     "binData",
+	reflect.TypeOf(primitive.Timestamp{}):  "timestamp",
+	reflect.TypeOf(primitive.Regex{}):      "regex",
+	reflect.TypeOf([]byte(nil)):            "binData",
+}
+
+// GenerateSchema returns a $jsonSchema describing model, a struct or a pointer to one, from its bson tags and field
+// types. Fields are required unless they are pointers or tagged omitempty, pointers also accept null.
+//
+//	schema, err := mongo.GenerateSchema(User{})
+//	err = client.SetCollectionValidator("users", schema)
+func GenerateSchema(model interface{}) (bson.D, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mongo: unable to generate a schema for %T, expected a struct", model)
+	}
+	return structSchema(t), nil
+}
+
+// structSchema returns the object schema of a struct type.
+func structSchema(t reflect.Type) bson.D {
+	properties := bson.D{}
+	var required bson.A
+	addFields(t, &properties, &required)
+
+	schema := bson.D{{Key: "bsonType", Value: "object"}}
+	if len(required) > 0 {
+		schema = append(schema, bson.E{Key: "required", Value: required})
+	}
+	return append(schema, bson.E{Key: "properties", Value: properties})
+}
+
+// addFields adds the exported fields of t to properties and required, inline fields are flattened.
+func addFields(t reflect.Type, properties *bson.D, required *bson.A) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, tagOptions, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(tagOptions, "inline") {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				addFields(fieldType, properties, required)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		*properties = append(*properties, bson.E{Key: name, Value: typeSchema(field.Type)})
+		if field.Type.Kind() != reflect.Ptr && !strings.Contains(tagOptions, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// typeSchema returns the schema of a Go type, interfaces accept any value.
+func typeSchema(t reflect.Type) bson.D {
+	if t.Kind() == reflect.Ptr {
+		schema := typeSchema(t.Elem())
+		if len(schema) > 0 && schema[0].Key == "bsonType" {
+			schema[0].Value = withNull(schema[0].Value)
+		}
+		return schema
+	}
+	if bsonType, ok := bsonTypes[t]; ok {
+		return bson.D{{Key: "bsonType", Value: bsonType}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return bson.D{{Key: "bsonType", Value: "string"}}
+	case reflect.Bool:
+		return bson.D{{Key: "bsonType", Value: "bool"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return bson.D{{Key: "bsonType", Value: "int"}}
+	case reflect.Int64:
+		return bson.D{{Key: "bsonType", Value: "long"}}
+	case reflect.Int, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		// encoded as an int when the value fits in 32 bits
+		return bson.D{{Key: "bsonType", Value: bson.A{"int", "long"}}}
+	case reflect.Float32, reflect.Float64:
+		return bson.D{{Key: "bsonType", Value: "double"}}
+	case reflect.Slice, reflect.Array:
+		return bson.D{{Key: "bsonType", Value: "array"}, {Key: "items", Value: typeSchema(t.Elem())}}
+	case reflect.Map:
+		return bson.D{{Key: "bsonType", Value: "object"}}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return bson.D{}
+	}
+}
+
+// withNull adds "null" to a bsonType.
+func withNull(bsonType interface{}) bson.A {
+	if types, ok := bsonType.(bson.A); ok {
+		return append(types, "null")
+	}
+	return bson.A{bsonType, "null"}
+}
+
+// SetCollectionValidator makes the server validate the documents written to collectionName against schema, a
+// $jsonSchema document as returned by GenerateSchema. The collection is created when it does not exist.
+func (connectionDetails *Client) SetCollectionValidator(collectionName string, schema bson.D, opts ...Option) error {
+	op := operation{name: "SetCollectionValidator", collection: collectionName, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		validator := bson.D{{Key: "$jsonSchema", Value: schema}}
+		err := db.RunCommand(ctx, bson.D{{Key: "collMod", Value: collectionName}, {Key: "validator", Value: validator}}).Err()
+		var serverErr mongo.ServerError
+		if err != nil && errors.As(err, &serverErr) && serverErr.HasErrorCode(namespaceNotFound) {
+			return db.CreateCollection(ctx, collectionName, options.CreateCollection().SetValidator(validator))
+		}
+		return err
+	})
+}
+
+// CollectionValidator returns the $jsonSchema validating collectionName, it is nil when the collection has none.
+func (connectionDetails *Client) CollectionValidator(collectionName string, opts ...Option) (bson.D, error) {
+	op := operation{name: "CollectionValidator", collection: collectionName, options: newOperationOptions(opts)}
+	var schema bson.D
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		schema, err = collectionSchema(ctx, db, collectionName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// collectionSchema reads the $jsonSchema of collectionName from its options.
+func collectionSchema(ctx context.Context, db *mongo.Database, collectionName string) (bson.D, error) {
+	specs, err := db.ListCollectionSpecifications(ctx, bson.M{"name": collectionName})
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+	return specificationSchema(specs[0])
+}
+
+// specificationSchema reads the $jsonSchema of a collection from its specification.
+func specificationSchema(spec *mongo.CollectionSpecification) (bson.D, error) {
+	if spec.Options == nil {
+		return nil, nil
+	}
+	var collectionOptions struct {
+		Validator struct {
+			JSONSchema bson.D `bson:"$jsonSchema"`
+		} `bson:"validator"`
+	}
+	if err := bson.Unmarshal(spec.Options, &collectionOptions); err != nil {
+		return nil, err
+	}
+	return collectionOptions.Validator.JSONSchema, nil
+}
+
+// SchemaDiff lists the fields that differ between two $jsonSchema documents, nested fields use dotted paths.
+type SchemaDiff struct {
+	// Added fields are only in the new schema
+	Added []string
+
+	// Removed fields are only in the old schema
+	Removed []string
+
+	// Changed fields have a different type, constraint or are no longer or newly required
+	Changed []string
+}
+
+// Empty reports whether the schemas are the same.
+func (diff *SchemaDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+// DiffSchema compares the current and desired $jsonSchema documents.
+func DiffSchema(current, desired bson.D) (*SchemaDiff, error) {
+	currentDoc, err := toDocument(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredDoc, err := toDocument(desired)
+	if err != nil {
+		return nil, err
+	}
+	diff := &SchemaDiff{}
+	diffSchema(diff, "", currentDoc, desiredDoc)
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// diffSchema adds the differences between the properties of two object schemas to diff.
+func diffSchema(diff *SchemaDiff, prefix string, current, desired bson.D) {
+	currentProperties := schemaProperties(current)
+	desiredProperties := schemaProperties(desired)
+	currentRequired := schemaRequired(current)
+	desiredRequired := schemaRequired(desired)
+
+	for _, property := range desiredProperties {
+		path := prefix + property.Key
+		existing, ok := lookupField(currentProperties, property.Key)
+		if !ok {
+			diff.Added = append(diff.Added, path)
+			continue
+		}
+		currentField, _ := existing.(bson.D)
+		desiredField, _ := property.Value.(bson.D)
+		if currentRequired[property.Key] != desiredRequired[property.Key] ||
+			!equalValues(withoutField(currentField, "properties"), withoutField(desiredField, "properties")) {
+			diff.Changed = append(diff.Changed, path)
+		}
+		diffSchema(diff, path+".", currentField, desiredField)
+	}
+	for _, property := range currentProperties {
+		if _, ok := lookupField(desiredProperties, property.Key); !ok {
+			diff.Removed = append(diff.Removed, prefix+property.Key)
+		}
+	}
+}
+
+// schemaProperties returns the properties of an object schema.
+func schemaProperties(schema bson.D) bson.D {
+	value, _ := lookupField(schema, "properties")
+	properties, _ := value.(bson.D)
+	return properties
+}
+
+// schemaRequired returns the required fields of an object schema.
+func schemaRequired(schema bson.D) map[string]bool {
+	value, _ := lookupField(schema, "required")
+	names, _ := value.(bson.A)
+	required := make(map[string]bool, len(names))
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			required[s] = true
+		}
+	}
+	return required
+}
+
+// ApplyCollectionSchema compares schema with the current validator of collectionName and replaces it when they
+// differ. With dryRun the difference is only returned.
+func (connectionDetails *Client) ApplyCollectionSchema(collectionName string, schema bson.D, dryRun bool, opts ...Option) (*SchemaDiff, error) {
+	current, err := connectionDetails.CollectionValidator(collectionName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffSchema(current, schema)
+	if err != nil {
+		return nil, connectionDetails.wrapError(operation{name: "ApplyCollectionSchema", collection: collectionName}, err)
+	}
+	if dryRun || (diff.Empty() && current != nil) {
+		return diff, nil
+	}
+	if err := connectionDetails.SetCollectionValidator(collectionName, schema, opts...); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
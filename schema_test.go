@@ -0,0 +1,100 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type schemaAddress struct {
+	City string `bson:"city"`
+}
+
+type schemaUser struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Name     string             `bson:"name"`
+	Age      int32              `bson:"age,omitempty"`
+	Score    float64            `bson:"score"`
+	Tags     []string           `bson:"tags"`
+	Address  *schemaAddress     `bson:"address"`
+	Created  time.Time          `bson:"created"`
+	Extra    interface{}        `bson:"extra,omitempty"`
+	Ignored  string             `bson:"-"`
+	internal string
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(&schemaUser{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"_id", "name", "score", "tags", "created"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "bsonType", Value: "objectId"}}},
+			{Key: "name", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "age", Value: bson.D{{Key: "bsonType", Value: "int"}}},
+			{Key: "score", Value: bson.D{{Key: "bsonType", Value: "double"}}},
+			{Key: "tags", Value: bson.D{{Key: "bsonType", Value: "array"}, {Key: "items", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+			{Key: "address", Value: bson.D{
+				{Key: "bsonType", Value: bson.A{"object", "null"}},
+				{Key: "required", Value: bson.A{"city"}},
+				{Key: "properties", Value: bson.D{{Key: "city", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+			}},
+			{Key: "created", Value: bson.D{{Key: "bsonType", Value: "date"}}},
+			{Key: "extra", Value: bson.D{}},
+		}},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("GenerateSchema() = %v, want %v", schema, want)
+	}
+
+	if _, err := GenerateSchema("user"); err == nil {
+		t.Errorf("expected an error for a non struct model")
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	current := bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"name"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "name", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "age", Value: bson.D{{Key: "bsonType", Value: "int"}}},
+			{Key: "nickname", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "address", Value: bson.D{{Key: "bsonType", Value: "object"}, {Key: "properties", Value: bson.D{
+				{Key: "city", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			}}}},
+		}},
+	}
+	desired := bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"name", "email"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "name", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "age", Value: bson.D{{Key: "bsonType", Value: "long"}}},
+			{Key: "email", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "address", Value: bson.D{{Key: "bsonType", Value: "object"}, {Key: "properties", Value: bson.D{
+				{Key: "city", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+				{Key: "zip", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			}}}},
+		}},
+	}
+
+	diff, err := DiffSchema(current, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &SchemaDiff{Added: []string{"address.zip", "email"}, Removed: []string{"nickname"}, Changed: []string{"age"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffSchema() = %+v, want %+v", diff, want)
+	}
+
+	if diff, _ := DiffSchema(desired, desired); !diff.Empty() {
+		t.Errorf("expected no difference, got %+v", diff)
+	}
+}
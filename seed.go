@@ -0,0 +1,80 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrMissingSeedID is returned by EnsureSeedData for a document without an "_id".
+var ErrMissingSeedID = errors.New("mongo: seed document has no _id")
+
+// SeedData is the reference documents of EnsureSeedData by collection. Each document needs a fixed "_id", like the
+// name of a role, so it is recognized on every start.
+type SeedData map[string][]interface{}
+
+// EnsureSeedData inserts the documents of seed that do not exist yet, matched by their "_id", and returns the number
+// inserted by collection. Run it at startup so a fresh environment gets its roles and default settings:
+//
+//	inserted, err := client.EnsureSeedData(mongo.SeedData{
+//		"roles":    {Role{ID: "admin", Permissions: all}, Role{ID: "viewer", Permissions: read}},
+//		"settings": {bson.M{"_id": "signup", "enabled": true}},
+//	})
+//
+// Each document is upserted with $setOnInsert, an existing document is left as is even when it differs so changes made
+// since are kept, and concurrent starts insert it once.
+func (connectionDetails *Client) EnsureSeedData(seed SeedData, opts ...Option) (map[string]int64, error) {
+	names := make([]string, 0, len(seed))
+	for name := range seed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inserted := map[string]int64{}
+	for _, name := range names {
+		models := make([]mongo.WriteModel, len(seed[name]))
+		for i, document := range seed[name] {
+			model, err := seedModel(document)
+			if err != nil {
+				return inserted, &ValidationError{Collection: name, Index: i, Err: err}
+			}
+			models[i] = model
+		}
+		if len(models) == 0 {
+			continue
+		}
+
+		op := operation{name: "EnsureSeedData", collection: name, documents: seed[name], options: newOperationOptions(opts)}
+		err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+			collection := connectionDetails.collection(db, name, op.options)
+			result, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+			if err != nil {
+				return err
+			}
+			inserted[name] = result.UpsertedCount
+			return nil
+		})
+		if err != nil {
+			return inserted, err
+		}
+	}
+	return inserted, nil
+}
+
+// seedModel returns the upsert inserting document when its "_id" does not exist.
+func seedModel(document interface{}) (mongo.WriteModel, error) {
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	id, ok := lookupField(doc, "_id")
+	if !ok {
+		return nil, ErrMissingSeedID
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: doc}}
+	return mongo.NewUpdateOneModel().SetFilter(bson.D{{Key: "_id", Value: id}}).SetUpdate(update).SetUpsert(true), nil
+}
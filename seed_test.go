@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_EnsureSeedData(t *testing.T) {
+	_ = client.DropCollections([]string{"seeded"})
+	if _, err := client.Add("seeded", data{ID: "admin", Name: "Renamed by an administrator"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	seed := SeedData{"seeded": {data{ID: "admin", Name: "Admin"}, bson.M{"_id": "viewer", "name": "Viewer"}}}
+
+	for i, want := range []int64{1, 0} {
+		inserted, err := client.EnsureSeedData(seed)
+		if err != nil || inserted["seeded"] != want {
+			t.Errorf("EnsureSeedData() run %d = %v, %v, expected %d inserted", i+1, inserted, err, want)
+		}
+	}
+	var admin data
+	result, _ := client.Get("seeded", "admin")
+	if err := result.Decode(&admin); err != nil || admin.Name != "Renamed by an administrator" {
+		t.Errorf("Expected the existing document to be kept, got %+v, %v", admin, err)
+	}
+
+	_, err := client.EnsureSeedData(SeedData{"seeded": {bson.M{"name": "Anonymous"}}})
+	var validationErr *ValidationError
+	if !errors.Is(err, ErrMissingSeedID) || !errors.As(err, &validationErr) {
+		t.Errorf("Expected ErrMissingSeedID in a ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kinds of SelfCheckProblem.
+const (
+	ProblemServerVersion     = "server version"
+	ProblemTopology          = "topology"
+	ProblemMissingCollection = "missing collection"
+	ProblemMissingIndex      = "missing index"
+	ProblemSchemaDrift       = "schema drift"
+)
+
+// SelfCheckOptions lists what SelfCheck expects from the deployment.
+type SelfCheckOptions struct {
+	// MinServerVersion is the oldest supported server version, for example "6.0"
+	MinServerVersion string
+
+	// RequireReplicaSet requires a replica set or a sharded cluster, for transactions and change streams
+	RequireReplicaSet bool
+
+	// Collections are the collections that must exist
+	Collections []CollectionRequirement
+}
+
+// CollectionRequirement is a collection SelfCheck expects, with its indexes and validator.
+type CollectionRequirement struct {
+	Name string
+
+	// Indexes the collection must have, matched by their keys, Unique and ExpireAfter
+	Indexes []Index
+
+	// Schema is the $jsonSchema the collection must be validated with, generated from Model when it is nil
+	Schema bson.D
+
+	// Model is a struct the validator of the collection must match, see GenerateSchema
+	Model interface{}
+}
+
+// SelfCheckProblem is a difference between the deployment and what SelfCheck expects.
+type SelfCheckProblem struct {
+	// Kind is one of ProblemServerVersion, ProblemTopology, ProblemMissingCollection, ProblemMissingIndex or
+	// ProblemSchemaDrift
+	Kind string
+
+	// Collection is the collection of the problem, empty for the deployment
+	Collection string
+
+	// Detail describes the problem
+	Detail string
+}
+
+// SelfCheckReport is the result of SelfCheck.
+type SelfCheckReport struct {
+	// OK is true when no problem was found
+	OK bool
+
+	// ServerVersion and Topology of the deployment, see HealthReport
+	ServerVersion string
+	Topology      string
+
+	// Problems found, in the order they were checked
+	Problems []SelfCheckProblem
+
+	// CheckedAt is when the check was made
+	CheckedAt time.Time
+}
+
+// SelfCheck compares the deployment with what the application expects - server version and topology, collections,
+// their indexes and validators - as well as with the configuration of the Client: the history collections of
+// WithAuditTrail must index "documentId". Run it in a deploy pipeline or at startup to catch drift before serving
+// traffic:
+//
+//	report, err := client.SelfCheck(mongo.SelfCheckOptions{
+//		MinServerVersion: "6.0",
+//		Collections: []mongo.CollectionRequirement{
+//			{Name: "orders", Model: Order{}, Indexes: []mongo.Index{{Keys: bson.D{{Key: "customerId", Value: 1}}}}},
+//		},
+//	})
+//	if err == nil && !report.OK {
+//		log.Fatalf("deployment drifted: %+v", report.Problems)
+//	}
+//
+// The error is only set when the check could not be made, problems are reported in SelfCheckReport.
+func (connectionDetails *Client) SelfCheck(selfCheckOptions SelfCheckOptions) (*SelfCheckReport, error) {
+	health, err := connectionDetails.HealthCheck()
+	if err != nil {
+		return nil, err
+	}
+	report := &SelfCheckReport{ServerVersion: health.ServerVersion, Topology: health.Topology, CheckedAt: health.CheckedAt}
+	if min := selfCheckOptions.MinServerVersion; min != "" && compareVersions(health.ServerVersion, min) < 0 {
+		report.Problems = append(report.Problems, SelfCheckProblem{Kind: ProblemServerVersion,
+			Detail: fmt.Sprintf("server version %s is older than %s", health.ServerVersion, min)})
+	}
+	if selfCheckOptions.RequireReplicaSet && health.Topology == TopologyStandalone {
+		report.Problems = append(report.Problems, SelfCheckProblem{Kind: ProblemTopology,
+			Detail: "a replica set or a sharded cluster is required, the server is standalone"})
+	}
+
+	requirements := append([]CollectionRequirement(nil), selfCheckOptions.Collections...)
+	for collection := range connectionDetails.audited {
+		requirements = append(requirements, CollectionRequirement{
+			Name:    collection + HistorySuffix,
+			Indexes: []Index{{Keys: bson.D{{Key: "documentId", Value: 1}}}},
+		})
+	}
+
+	err = connectionDetails.run(operation{name: "SelfCheck"}, func(ctx context.Context, db *mongo.Database) error {
+		names, err := db.ListCollectionNames(ctx, bson.D{})
+		if err != nil {
+			return err
+		}
+		existing := map[string]bool{}
+		for _, name := range names {
+			existing[name] = true
+		}
+		for _, requirement := range requirements {
+			if !existing[requirement.Name] {
+				report.Problems = append(report.Problems, SelfCheckProblem{Kind: ProblemMissingCollection, Collection: requirement.Name,
+					Detail: "the collection does not exist"})
+				continue
+			}
+			problems, err := checkCollection(ctx, db, requirement)
+			if err != nil {
+				return err
+			}
+			report.Problems = append(report.Problems, problems...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.OK = len(report.Problems) == 0
+	return report, nil
+}
+
+// checkCollection compares the indexes and the validator of an existing collection with requirement.
+func checkCollection(ctx context.Context, db *mongo.Database, requirement CollectionRequirement) ([]SelfCheckProblem, error) {
+	var problems []SelfCheckProblem
+	if len(requirement.Indexes) > 0 {
+		cursor, err := db.Collection(requirement.Name).Indexes().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var indexes []bson.D
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, err
+		}
+		for _, index := range requirement.Indexes {
+			if !hasIndex(indexes, index) {
+				keys, _ := bson.MarshalExtJSON(index.Keys, false, false)
+				problems = append(problems, SelfCheckProblem{Kind: ProblemMissingIndex, Collection: requirement.Name,
+					Detail: fmt.Sprintf("no index on %s", keys)})
+			}
+		}
+	}
+
+	schema := requirement.Schema
+	if schema == nil && requirement.Model != nil {
+		var err error
+		if schema, err = GenerateSchema(requirement.Model); err != nil {
+			return nil, err
+		}
+	}
+	if schema != nil {
+		current, err := collectionSchema(ctx, db, requirement.Name)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := DiffSchema(current, schema)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			problems = append(problems, SelfCheckProblem{Kind: ProblemSchemaDrift, Collection: requirement.Name,
+				Detail: "the collection has no validator"})
+		} else if !diff.Empty() {
+			problems = append(problems, SelfCheckProblem{Kind: ProblemSchemaDrift, Collection: requirement.Name,
+				Detail: fmt.Sprintf("validator differs, added %v, removed %v, changed %v", diff.Added, diff.Removed, diff.Changed)})
+		}
+	}
+	return problems, nil
+}
+
+// hasIndex reports whether one of the index specifications of a collection matches the keys, Unique and ExpireAfter
+// of index.
+func hasIndex(indexes []bson.D, index Index) bool {
+	for _, spec := range indexes {
+		unique, _ := lookupField(spec, "unique")
+		if !sameKeys(spec, index.Keys) || (unique == true) != index.Unique {
+			continue
+		}
+		if index.ExpireAfter > 0 {
+			expire, _ := toFloat(pathValue(spec, []string{"expireAfterSeconds"}))
+			if int64(expire) != int64(index.ExpireAfter/time.Second) {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compareVersions compares two dotted version numbers like "6.0.12", missing parts count as 0.
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(strings.SplitN(aParts[i], "-", 2)[0])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(strings.SplitN(bParts[i], "-", 2)[0])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"6.0.12", "6.0", 1},
+		{"5.0.3", "6.0", -1},
+		{"7.0", "7.0.0", 0},
+		{"8.0.0-rc1", "8.0", 0},
+		{"10.0", "9.0", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHasIndex(t *testing.T) {
+	indexes := []bson.D{
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}},
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: bson.D{{Key: "email", Value: int32(1)}}}, {Key: "name", Value: "email_1"}, {Key: "unique", Value: true}},
+		{{Key: "v", Value: int32(2)}, {Key: "key", Value: bson.D{{Key: "expiresAt", Value: int32(1)}}}, {Key: "name", Value: "expiresAt_1"}, {Key: "expireAfterSeconds", Value: int32(3600)}},
+	}
+	tests := []struct {
+		name  string
+		index Index
+		want  bool
+	}{
+		{"keys", Index{Keys: bson.D{{Key: "_id", Value: 1}}}, true},
+		{"unique", Index{Keys: bson.D{{Key: "email", Value: 1}}, Unique: true}, true},
+		{"not unique", Index{Keys: bson.D{{Key: "email", Value: 1}}}, false},
+		{"direction", Index{Keys: bson.D{{Key: "email", Value: -1}}, Unique: true}, false},
+		{"ttl", Index{Keys: bson.D{{Key: "expiresAt", Value: 1}}, ExpireAfter: time.Hour}, true},
+		{"other ttl", Index{Keys: bson.D{{Key: "expiresAt", Value: 1}}, ExpireAfter: time.Minute}, false},
+		{"missing", Index{Keys: bson.D{{Key: "name", Value: 1}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasIndex(indexes, tt.index); got != tt.want {
+				t.Errorf("hasIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_SelfCheck(t *testing.T) {
+	_ = client.DropCollections([]string{"selfcheck"})
+	if _, err := client.Add("selfcheck", data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	report, err := client.SelfCheck(SelfCheckOptions{
+		MinServerVersion: "99.0",
+		Collections: []CollectionRequirement{
+			{Name: "selfcheck", Model: data{}, Indexes: []Index{{Keys: bson.D{{Key: "name", Value: 1}}}}},
+			{Name: "selfcheck_missing"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SelfCheck() error = %v", err)
+	}
+	kinds := map[string]bool{}
+	for _, problem := range report.Problems {
+		kinds[problem.Kind] = true
+	}
+	if report.OK || !kinds[ProblemServerVersion] || !kinds[ProblemMissingIndex] || !kinds[ProblemSchemaDrift] || !kinds[ProblemMissingCollection] {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultSequenceCollection holds the counters of NextSequence, one document per sequence.
+const DefaultSequenceCollection = "sequences"
+
+// NextSequence increments the sequence name and returns its new value, 1 the first time, for human friendly numbers
+// like invoice numbers:
+//
+//	number, err := client.NextSequence("invoices")
+//
+// The counter is incremented atomically, concurrent calls never get the same value. A value is lost when the write
+// using it fails, or when the increment is retried after a network error, so a sequence can have gaps.
+func (connectionDetails *Client) NextSequence(name string, opts ...Option) (int64, error) {
+	return connectionDetails.reserveSequence(name, 1, opts)
+}
+
+// WithSequenceIDs makes Add and AddMany assign the next value of the sequence named after the collection, see
+// NextSequence, to the "_id" of the documents of collections that have none. Tag a numeric "_id" of a model with
+// omitempty so a zero value is left out:
+//
+//	type Invoice struct {
+//		ID    int64   `bson:"_id,omitempty"`
+//		Total float64 `bson:"total"`
+//	}
+//
+// AddMany reserves the values of its documents with a single increment.
+func WithSequenceIDs(collections ...string) ClientOption {
+	return func(client *Client) {
+		if client.sequenced == nil {
+			client.sequenced = map[string]bool{}
+		}
+		for _, collection := range collections {
+			client.sequenced[collection] = true
+		}
+	}
+}
+
+// reserveSequence increments the sequence name by n and returns its new value, the last of the n reserved values.
+func (connectionDetails *Client) reserveSequence(name string, n int64, opts []Option) (int64, error) {
+	filter := bson.D{{Key: "_id", Value: name}}
+	op := operation{name: "NextSequence", collection: DefaultSequenceCollection, filter: filter, options: newOperationOptions(opts)}
+	var sequence struct {
+		Value int64 `bson:"value"`
+	}
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "value", Value: n}}}}
+		found := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+		collection := connectionDetails.collection(db, DefaultSequenceCollection, op.options)
+		return collection.FindOneAndUpdate(ctx, filter, update, found).Decode(&sequence)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sequence.Value, nil
+}
+
+// sequencedDocuments returns data with the values of the sequence of collectionName assigned to the documents without
+// an "_id", data is returned as is when the collection is not one of WithSequenceIDs or every document has one.
+func (connectionDetails *Client) sequencedDocuments(collectionName string, data []interface{}, opts []Option) ([]interface{}, error) {
+	if !connectionDetails.sequenced[collectionName] {
+		return data, nil
+	}
+	var missing []int
+	docs := make([]bson.D, len(data))
+	for i, item := range data {
+		doc, err := toDocument(item)
+		if err != nil {
+			// the write fails on the same error
+			return data, nil
+		}
+		if _, ok := lookupField(doc, "_id"); !ok {
+			missing = append(missing, i)
+		}
+		docs[i] = doc
+	}
+	if len(missing) == 0 {
+		return data, nil
+	}
+
+	last, err := connectionDetails.reserveSequence(collectionName, int64(len(missing)), opts)
+	if err != nil {
+		return nil, err
+	}
+	sequenced := append([]interface{}{}, data...)
+	next := last - int64(len(missing)) + 1
+	for _, i := range missing {
+		sequenced[i] = append(bson.D{{Key: "_id", Value: next}}, docs[i]...)
+		next++
+	}
+	return sequenced, nil
+}
@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSequencedDocuments_unchanged(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithSequenceIDs("invoices"))
+	documents := []interface{}{bson.M{"total": 10}}
+	if got, err := client.sequencedDocuments("users", documents, nil); err != nil || !reflect.DeepEqual(got, documents) {
+		t.Errorf("Expected a collection without sequence to be left as is, got %v, %v", got, err)
+	}
+	documents = []interface{}{data{ID: "1"}, bson.M{"_id": 2}}
+	if got, err := client.sequencedDocuments("invoices", documents, nil); err != nil || !reflect.DeepEqual(got, documents) {
+		t.Errorf("Expected documents with an _id to be left as is, got %v, %v", got, err)
+	}
+	if _, err := client.sequencedDocuments("invoices", []interface{}{bson.M{"total": 10}}, nil); err == nil {
+		t.Error("Expected the error of the sequence")
+	}
+}
+
+func TestNextSequence(t *testing.T) {
+	sequenced := client.WithContext(client.Context)
+	WithSequenceIDs("invoices")(sequenced)
+	_ = sequenced.DropCollections([]string{DefaultSequenceCollection, "invoices"})
+
+	for want := int64(1); want <= 2; want++ {
+		if got, err := sequenced.NextSequence("tickets"); err != nil || got != want {
+			t.Errorf("NextSequence() = %d, %v, want %d", got, err, want)
+		}
+	}
+
+	if result, err := sequenced.Add("invoices", bson.M{"total": 10}); err != nil || result.InsertedID != int64(1) {
+		t.Errorf("Add() = %v, %v, want the _id 1", result, err)
+	}
+	result, err := sequenced.AddMany("invoices", []interface{}{bson.M{"total": 20}, bson.M{"_id": "manual"}, bson.M{"total": 30}})
+	if err != nil || !reflect.DeepEqual(result.InsertedIDs, []interface{}{int64(2), "manual", int64(3)}) {
+		t.Errorf("AddMany() = %v, %v", result, err)
+	}
+}
@@ -0,0 +1,42 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithSession calls fn with a view of the Client bound to a causally consistent session. Reads made through the view
+// see the writes made before them through the view, even when they are served by a secondary.
+//
+//	err := client.WithSession(func(session *mongo.Client) error {
+//		if _, err := session.Add("orders", order); err != nil {
+//			return err
+//		}
+//		_, err := session.Get("orders", order.ID)
+//		return err
+//	})
+//
+// The view shares the connection of the Client, it must not be used concurrently, nor after fn returns. The error of
+// fn is returned as is.
+func (connectionDetails *Client) WithSession(fn func(session *Client) error) error {
+	op := operation{name: "WithSession"}
+	if err := connectionDetails.checkFederation(op); err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	client, release, err := connectionDetails.acquire()
+	if err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	defer release()
+
+	session, err := client.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	defer session.EndSession(connectionDetails.Context)
+
+	view := *connectionDetails
+	view.Context = mongo.NewSessionContext(connectionDetails.Context, session)
+	view.conn = &connection{client: client}
+	return fn(&view)
+}
@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClient_WithSession_view(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	errDone := errors.New("done")
+
+	err := client.WithSession(func(session *Client) error {
+		if mongo.SessionFromContext(session.Context) == nil {
+			t.Errorf("Expected the view context to carry a session")
+		}
+		if session.conn == client.conn {
+			t.Errorf("Expected the view to be bound to the session connection")
+		}
+		return errDone
+	})
+	if !errors.Is(err, errDone) {
+		t.Errorf("WithSession() = %v, want the error of fn", err)
+	}
+	if mongo.SessionFromContext(client.Context) != nil {
+		t.Errorf("Expected the Client context to be left untouched")
+	}
+}
+
+func TestClient_WithSession(t *testing.T) {
+	testData := data{ID: "session", Name: "Akshay"}
+	defer client.Delete("test_collection", testData.ID)
+
+	err := client.WithSession(func(session *Client) error {
+		if _, err := session.Add("test_collection", testData); err != nil {
+			return err
+		}
+		result, err := session.Get("test_collection", testData.ID)
+		if err != nil {
+			return err
+		}
+		var got data
+		if err := result.Decode(&got); err != nil {
+			return err
+		}
+		if got != testData {
+			t.Errorf("Expected to read %v, got %v", testData, got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Unable to read own write. %s", err)
+	}
+}
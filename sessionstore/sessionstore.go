@@ -0,0 +1,181 @@
+// Package sessionstore stores HTTP sessions in MongoDB through a mongo.Client, so web applications using the client
+// do not need a separate session backend:
+//
+//	store := sessionstore.New(client, sessionstore.Options{TTL: 24 * time.Hour, Secure: true})
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		session, err := store.Get(w, r)
+//		...
+//		session.Values["user"] = userID
+//		err = store.Save(w, session)
+//	}
+//
+// The session ID is kept in a cookie, the values in a document of the collection expiring after TTL. Expiration is
+// sliding: saving a session, or reading one past half of its TTL, extends it by TTL.
+package sessionstore
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	mongo "github.com/akshaybabloo/mongo/v4"
+)
+
+// Options configures a Store.
+type Options struct {
+	// Collection storing the sessions, defaults to "sessions"
+	Collection string
+
+	// TTL is how long a session lives after it was last saved or renewed, defaults to 24 hours
+	TTL time.Duration
+
+	// CookieName is the name of the cookie holding the session ID, defaults to "session"
+	CookieName string
+
+	// Path and Domain of the cookie, Path defaults to "/"
+	Path   string
+	Domain string
+
+	// Secure only sends the cookie over HTTPS
+	Secure bool
+
+	// SameSite of the cookie, defaults to http.SameSiteLaxMode
+	SameSite http.SameSite
+}
+
+// Session is an HTTP session, change its Values and call Store.Save to keep them.
+type Session struct {
+	// ID of the session, empty until the session is saved
+	ID string
+
+	// Values of the session. Nested documents are decoded as bson.D, store structs as a single value to keep their
+	// type
+	Values map[string]interface{}
+
+	// ExpiresAt is when the session expires unless it is saved or renewed before
+	ExpiresAt time.Time
+
+	// IsNew is true when the request had no valid session
+	IsNew bool
+}
+
+// record is the value stored in the cache for a session.
+type record struct {
+	Values    map[string]interface{} `bson:"values"`
+	ExpiresAt time.Time              `bson:"expiresAt"`
+}
+
+// Store stores sessions in a collection, see New.
+type Store struct {
+	cache   *mongo.Cache
+	options Options
+}
+
+// New returns a Store keeping sessions in a collection of client. The collection gets a TTL index on first use.
+func New(client *mongo.Client, opts Options) *Store {
+	if opts.Collection == "" {
+		opts.Collection = "sessions"
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.CookieName == "" {
+		opts.CookieName = "session"
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	if opts.SameSite == 0 {
+		opts.SameSite = http.SameSiteLaxMode
+	}
+	return &Store{cache: client.Cache(opts.Collection), options: opts}
+}
+
+// Get returns the session of r, or a new empty session when r has none or it expired. A session past half of its TTL
+// is renewed, its cookie is then set on w, so call Get before writing the response.
+func (store *Store) Get(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(store.options.CookieName)
+	if err != nil || cookie.Value == "" {
+		return store.newSession(), nil
+	}
+	var stored record
+	err = store.cache.Get(cookie.Value, &stored)
+	if errors.Is(err, mongo.ErrCacheMiss) {
+		return store.newSession(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.Values == nil {
+		stored.Values = map[string]interface{}{}
+	}
+	session := &Session{ID: cookie.Value, Values: stored.Values, ExpiresAt: stored.ExpiresAt}
+	if time.Until(session.ExpiresAt) < store.options.TTL/2 {
+		if err := store.Save(w, session); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// Save stores the values of session for TTL and sets its cookie on w. A new session gets its ID.
+func (store *Store) Save(w http.ResponseWriter, session *Session) error {
+	if session.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		session.ID = id
+	}
+	expiresAt := time.Now().Add(store.options.TTL)
+	if err := store.cache.Set(session.ID, record{Values: session.Values, ExpiresAt: expiresAt}, store.options.TTL); err != nil {
+		return err
+	}
+	session.ExpiresAt = expiresAt
+	http.SetCookie(w, store.cookie(session.ID, int(store.options.TTL/time.Second)))
+	return nil
+}
+
+// Destroy deletes session and clears its cookie on w, for example on logout.
+func (store *Store) Destroy(w http.ResponseWriter, session *Session) error {
+	if session.ID != "" {
+		if err := store.cache.Delete(session.ID); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(w, store.cookie("", -1))
+	session.ID = ""
+	session.Values = map[string]interface{}{}
+	return nil
+}
+
+// newSession returns an empty session that is not stored yet.
+func (store *Store) newSession() *Session {
+	return &Session{Values: map[string]interface{}{}, IsNew: true}
+}
+
+// cookie returns the session cookie with value, a negative maxAge deletes it.
+func (store *Store) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     store.options.CookieName,
+		Value:    value,
+		Path:     store.options.Path,
+		Domain:   store.options.Domain,
+		MaxAge:   maxAge,
+		Secure:   store.options.Secure,
+		HttpOnly: true,
+		SameSite: store.options.SameSite,
+	}
+}
+
+// newID returns a random session ID of 256 bits.
+func newID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
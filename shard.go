@@ -0,0 +1,364 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// shardVirtualNodes is the number of points of each shard on the hash ring, more points spread the keys more evenly.
+const shardVirtualNodes = 128
+
+// ErrUnroutable is returned by a ShardRouter for an upsert whose filter does not select an "_id".
+var ErrUnroutable = errors.New("mongo: filter does not select an _id, the shard cannot be chosen")
+
+// Shard is a Client holding a part of the documents of a ShardRouter.
+type Shard struct {
+	// Name identifies the shard on the hash ring, renaming a shard moves its documents to other shards
+	Name string
+
+	Client *Client
+}
+
+// ShardRouter spreads documents across the Clients of several deployments or databases by consistent hashing of their
+// "_id", for deployments that are not sharded by MongoDB. Adding a shard only moves the documents that hash to it.
+//
+// ShardRouter implements Store. Operations selecting an "_id" - Add, Get, Update, the filters with an "_id" equality -
+// run on its shard. Other filters are sent to every shard: GetAllCustom gathers the documents of every shard,
+// UpdateMany and DeleteMany sum the results, and UpdateCustom, DeleteCustom and GetCustom stop at the first shard with
+// a matching document.
+type ShardRouter struct {
+	shards []Shard
+	ring   []shardPoint
+}
+
+// shardPoint is a point of the hash ring.
+type shardPoint struct {
+	hash  uint32
+	shard int
+}
+
+// NewShardRouter returns a ShardRouter across shards.
+func NewShardRouter(shards ...Shard) *ShardRouter {
+	router := &ShardRouter{shards: shards}
+	for i, shard := range shards {
+		for node := 0; node < shardVirtualNodes; node++ {
+			router.ring = append(router.ring, shardPoint{hash: shardHash(fmt.Sprintf("%s#%d", shard.Name, node)), shard: i})
+		}
+	}
+	sort.Slice(router.ring, func(i, j int) bool { return router.ring[i].hash < router.ring[j].hash })
+	return router
+}
+
+// shardHash hashes a ring point or a key.
+func shardHash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Shard returns the Client holding the document with the given "_id".
+func (router *ShardRouter) Shard(id interface{}) *Client {
+	return router.shards[router.shardIndex(id)].Client
+}
+
+// shardIndex returns the index of the shard of id, the first point of the ring following its hash.
+func (router *ShardRouter) shardIndex(id interface{}) int {
+	hash := shardHash(fmt.Sprint(id))
+	i := sort.Search(len(router.ring), func(i int) bool { return router.ring[i].hash >= hash })
+	if i == len(router.ring) {
+		i = 0
+	}
+	return router.ring[i].shard
+}
+
+// route returns the Client of the "_id" selected by filter, or nil when it does not select a single "_id".
+func (router *ShardRouter) route(filter interface{}) (*Client, error) {
+	doc, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := lookupField(doc, "_id")
+	if !ok {
+		return nil, nil
+	}
+	if condition, isDoc := value.(bson.D); isDoc {
+		if len(condition) != 1 || condition[0].Key != "$eq" {
+			return nil, nil
+		}
+		value = condition[0].Value
+	}
+	return router.Shard(value), nil
+}
+
+func (router *ShardRouter) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	_, id, err := withID(data)
+	if err != nil {
+		return nil, err
+	}
+	shard := router.Shard(id)
+	if err := shard.validate(operation{name: "Add", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	// the document is made after the before hooks, which may change data
+	doc, err := documentWithID(data, id)
+	if err != nil {
+		return nil, err
+	}
+	return shard.Add(collectionName, doc, append(opts, validated(), fromModels(data))...)
+}
+
+// AddMany inserts every document in its shard, the inserted IDs are in the order of data. The shards are written one
+// after the other in their order, on a failure the IDs inserted before it are returned with the error.
+func (router *ShardRouter) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	docs := make([][]interface{}, len(router.shards))
+	models := make([][]interface{}, len(router.shards))
+	positions := make([][]int, len(router.shards))
+	for i, item := range data {
+		_, id, err := withID(item)
+		if err != nil {
+			return nil, err
+		}
+		index := router.shardIndex(id)
+		if err := router.shards[index].Client.validate(operation{name: "AddMany", collection: collectionName, options: newOperationOptions(opts)}, item); err != nil {
+			return nil, err
+		}
+		doc, err := documentWithID(item, id)
+		if err != nil {
+			return nil, err
+		}
+		docs[index] = append(docs[index], doc)
+		models[index] = append(models[index], item)
+		positions[index] = append(positions[index], i)
+	}
+
+	insertedIDs := make([]interface{}, len(data))
+	var err error
+	for index, shardDocs := range docs {
+		if len(shardDocs) == 0 {
+			continue
+		}
+		var inserted *mongo.InsertManyResult
+		inserted, err = router.shards[index].Client.AddMany(collectionName, shardDocs,
+			append(opts, validated(), fromModels(models[index]...))...)
+		if inserted != nil {
+			for i, id := range inserted.InsertedIDs {
+				insertedIDs[positions[index][i]] = id
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		result := &mongo.InsertManyResult{}
+		for _, id := range insertedIDs {
+			if id != nil {
+				result.InsertedIDs = append(result.InsertedIDs, id)
+			}
+		}
+		return result, err
+	}
+	return &mongo.InsertManyResult{InsertedIDs: insertedIDs}, nil
+}
+
+func (router *ShardRouter) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Update(collectionName, id, data, opts...)
+}
+
+func (router *ShardRouter) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.updateOne(filter, opts, func(shard *Client) (*mongo.UpdateResult, error) {
+		return shard.UpdateCustom(collectionName, filter, data, opts...)
+	})
+}
+
+func (router *ShardRouter) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	shard, err := router.route(filter)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		return shard.UpdateMany(collectionName, filter, data, opts...)
+	}
+	if upsert := newOperationOptions(opts).upsert; upsert != nil && *upsert {
+		return nil, ErrUnroutable
+	}
+	total := &mongo.UpdateResult{}
+	for _, shard := range router.shards {
+		result, err := shard.Client.UpdateMany(collectionName, filter, data, opts...)
+		if err != nil {
+			return nil, err
+		}
+		total.MatchedCount += result.MatchedCount
+		total.ModifiedCount += result.ModifiedCount
+	}
+	return total, nil
+}
+
+func (router *ShardRouter) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Replace(collectionName, id, data, opts...)
+}
+
+func (router *ShardRouter) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	_, id, err := withID(data)
+	if err != nil {
+		return nil, err
+	}
+	shard := router.Shard(id)
+	if err := shard.validate(operation{name: "Save", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	doc, err := documentWithID(data, id)
+	if err != nil {
+		return nil, err
+	}
+	return shard.Save(collectionName, doc, append(opts, validated(), fromModels(data))...)
+}
+
+func (router *ShardRouter) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Increment(collectionName, id, field, delta, opts...)
+}
+
+func (router *ShardRouter) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Push(collectionName, id, field, value, opts...)
+}
+
+func (router *ShardRouter) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Pull(collectionName, id, field, value, opts...)
+}
+
+func (router *ShardRouter) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).AddToSet(collectionName, id, field, value, opts...)
+}
+
+func (router *ShardRouter) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	return router.Shard(id).Unset(collectionName, id, fields, opts...)
+}
+
+func (router *ShardRouter) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return router.Shard(id).Delete(collectionName, id, opts...)
+}
+
+func (router *ShardRouter) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	shard, err := router.route(filter)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		return shard.DeleteCustom(collectionName, filter, opts...)
+	}
+	for _, shard := range router.shards {
+		result, err := shard.Client.DeleteCustom(collectionName, filter, opts...)
+		if err != nil || result.DeletedCount > 0 {
+			return result, err
+		}
+	}
+	return &mongo.DeleteResult{}, nil
+}
+
+func (router *ShardRouter) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	shard, err := router.route(filter)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		return shard.DeleteMany(collectionName, filter, opts...)
+	}
+	total := &mongo.DeleteResult{}
+	for _, shard := range router.shards {
+		result, err := shard.Client.DeleteMany(collectionName, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		total.DeletedCount += result.DeletedCount
+	}
+	return total, nil
+}
+
+func (router *ShardRouter) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return router.Shard(id).Get(collectionName, id, opts...)
+}
+
+func (router *ShardRouter) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	shard, err := router.route(filter)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		return shard.GetCustom(collectionName, filter, opts...)
+	}
+	var result *mongo.SingleResult
+	for _, shard := range router.shards {
+		result, err = shard.Client.GetCustom(collectionName, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if !errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+func (router *ShardRouter) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return router.Shard(id).GetAll(collectionName, id, result, opts...)
+}
+
+// GetAllCustom finds the documents matching filter on every shard concurrently and decodes all of them into result,
+// the documents of each shard follow the ones of the previous shard.
+func (router *ShardRouter) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	shard, err := router.route(filter)
+	if err != nil {
+		return err
+	}
+	if shard != nil {
+		return shard.GetAllCustom(collectionName, filter, result, opts...)
+	}
+
+	docs := make([][]bson.D, len(router.shards))
+	errs := make([]error, len(router.shards))
+	var wg sync.WaitGroup
+	for i, shard := range router.shards {
+		wg.Add(1)
+		go func(i int, shard *Client) {
+			defer wg.Done()
+			errs[i] = shard.GetAllCustom(collectionName, filter, &docs[i], opts...)
+		}(i, shard.Client)
+	}
+	wg.Wait()
+
+	var all []bson.D
+	for i := range router.shards {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		all = append(all, docs[i]...)
+	}
+	return decodeDocuments(all, result)
+}
+
+// updateOne runs update on the shard selected by filter, or on every shard until one has a matching document.
+func (router *ShardRouter) updateOne(filter interface{}, opts []Option, update func(shard *Client) (*mongo.UpdateResult, error)) (*mongo.UpdateResult, error) {
+	shard, err := router.route(filter)
+	if err != nil {
+		return nil, err
+	}
+	if shard != nil {
+		return update(shard)
+	}
+	if upsert := newOperationOptions(opts).upsert; upsert != nil && *upsert {
+		return nil, ErrUnroutable
+	}
+	for _, shard := range router.shards {
+		result, err := update(shard.Client)
+		if err != nil || result.MatchedCount > 0 {
+			return result, err
+		}
+	}
+	return &mongo.UpdateResult{}, nil
+}
@@ -0,0 +1,146 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestShardRouter_Shard(t *testing.T) {
+	shards := []Shard{
+		{Name: "a", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_a")},
+		{Name: "b", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_b")},
+	}
+	router := NewShardRouter(shards...)
+	grown := NewShardRouter(append(shards, Shard{Name: "c", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_c")})...)
+
+	counts := map[string]int{}
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("user-%d", i)
+		before := router.Shard(id).DatabaseName
+		if router.Shard(id) != router.Shard(id) {
+			t.Fatalf("Expected %s to always map to the same shard", id)
+		}
+		counts[before]++
+		if after := grown.Shard(id).DatabaseName; after != before {
+			if after != "shard_c" {
+				t.Errorf("Expected %s to move to the new shard only, got %s", id, after)
+			}
+			moved++
+		}
+	}
+	if counts["shard_a"] < 300 || counts["shard_b"] < 300 {
+		t.Errorf("Expected keys to be spread across shards, got %v", counts)
+	}
+	if moved == 0 || moved > 500 {
+		t.Errorf("Expected about a third of the keys to move, got %d", moved)
+	}
+}
+
+func TestShardRouter_route(t *testing.T) {
+	router := NewShardRouter(
+		Shard{Name: "a", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_a")},
+		Shard{Name: "b", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_b")},
+	)
+	tests := []struct {
+		name   string
+		filter interface{}
+		routed bool
+	}{
+		{"equality", bson.M{"_id": "1"}, true},
+		{"$eq", F("_id").Eq("1"), true},
+		{"$eq operator", bson.D{{Key: "_id", Value: bson.D{{Key: "$eq", Value: "1"}}}}, true},
+		{"range", bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: "1"}}}}, false},
+		{"other field", bson.M{"name": "Akshay"}, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shard, err := router.route(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (shard != nil) != tt.routed {
+				t.Errorf("route() = %v, want routed %v", shard, tt.routed)
+			}
+			if shard != nil && shard != router.Shard("1") {
+				t.Errorf("Expected the shard of the _id")
+			}
+		})
+	}
+}
+
+func TestShardRouter_UpdateCustom_unroutable(t *testing.T) {
+	router := NewShardRouter(Shard{Name: "a", Client: NewMongoClientDefault("mongodb://localhost:27017", "shard_a")})
+	if _, err := router.UpdateCustom("users", bson.M{"name": "Akshay"}, bson.M{"age": 1}, Upsert()); err != ErrUnroutable {
+		t.Errorf("UpdateCustom() = %v, want ErrUnroutable", err)
+	}
+}
+
+func TestShardRouter_AddMany_order(t *testing.T) {
+	errDown := errors.New("shard down")
+	var calls []string
+	shards := make([]Shard, 3)
+	for i, name := range []string{"a", "b", "c"} {
+		name := name
+		client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "shard_"+name)
+		client.Use(func(next OperationFunc) OperationFunc {
+			return func(ctx context.Context, info OperationInfo) error {
+				calls = append(calls, name)
+				if name == "b" {
+					return errDown
+				}
+				return nil
+			}
+		})
+		shards[i] = Shard{Name: name, Client: client}
+	}
+	router := NewShardRouter(shards...)
+
+	var docs []interface{}
+	for i := 0; i < 30; i++ {
+		docs = append(docs, data{ID: fmt.Sprintf("user-%d", i)})
+	}
+	result, err := router.AddMany("users", docs)
+	if !errors.Is(err, errDown) {
+		t.Fatalf("Expected the error of shard b, got %v", err)
+	}
+	if result == nil {
+		t.Error("Expected the result inserted before the failure")
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected the shards to be written in order until the failure, got %v", calls)
+	}
+}
+
+func TestShardRouter_GetAllCustom(t *testing.T) {
+	router := NewShardRouter(
+		Shard{Name: "a", Client: client.WithDatabase(client.DatabaseName + "_shard_a")},
+		Shard{Name: "b", Client: client.WithDatabase(client.DatabaseName + "_shard_b")},
+	)
+	_, _ = router.DeleteMany("shard_collection", bson.M{})
+	var docs []interface{}
+	for i := 0; i < 20; i++ {
+		docs = append(docs, data{ID: fmt.Sprint(i), Name: "Akshay"})
+	}
+	if _, err := router.AddMany("shard_collection", docs); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	var all []data
+	if err := router.GetAllCustom("shard_collection", bson.M{"name": "Akshay"}, &all); err != nil {
+		t.Fatalf("Unable to get data. %s", err)
+	}
+	if len(all) != 20 {
+		t.Errorf("Expected 20 documents across shards, got %d", len(all))
+	}
+	result, err := router.Get("shard_collection", "7")
+	if err != nil || result.Err() != nil {
+		t.Errorf("Expected to get document 7 from its shard, got %v %v", err, result.Err())
+	}
+}
@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrLoadShed is returned, without contacting MongoDB, by operations refused because the connection pool is
+// exhausted and their priority is below LoadShedding.MinPriority.
+var ErrLoadShed = errors.New("mongo: operation shed, connection pool exhausted")
+
+// Priority of the operations made with a context, see WithPriority.
+type Priority int
+
+const (
+	// PriorityLow is for work that can wait or be retried later, like backfills and reports
+	PriorityLow Priority = iota - 1
+
+	// PriorityNormal is the priority of operations whose context has none
+	PriorityNormal
+
+	// PriorityHigh is for interactive work that must go through, like checkouts
+	PriorityHigh
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying priority, the operations of a Client using it have that priority.
+// Priorities order the operations waiting for WithConcurrencyLimit and decide which are shed by WithLoadShedding:
+//
+//	reports := client.WithContext(mongo.WithPriority(ctx, mongo.PriorityLow))
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priorityFromContext returns the priority carried by ctx, PriorityNormal when there is none.
+func priorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityNormal
+	}
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// LoadShedding configures WithLoadShedding.
+type LoadShedding struct {
+	// WaitThreshold is the wait for a connection of the pool above which the pool is considered exhausted, defaults
+	// to 1 second
+	WaitThreshold time.Duration
+
+	// Cooldown is how long the pool stays exhausted after a wait above WaitThreshold, defaults to 5 seconds
+	Cooldown time.Duration
+
+	// MinPriority is the lowest priority still allowed through while the pool is exhausted, defaults to
+	// PriorityNormal so only PriorityLow operations are shed
+	MinPriority Priority
+}
+
+// loadShedder tracks the exhaustion of the connection pool for WithLoadShedding.
+type loadShedder struct {
+	LoadShedding
+
+	// exhaustedUntil is the Unix time in nanoseconds until which the pool is exhausted
+	exhaustedUntil atomic.Int64
+}
+
+// WithLoadShedding fails operations below shedding.MinPriority fast with ErrLoadShed while the connection pool is
+// exhausted, so they stop competing for connections with higher priority operations, which keep waiting for a
+// connection as usual. The pool is exhausted for Cooldown after a connection checkout waited more than WaitThreshold.
+// Priorities come from the context of the Client, see WithPriority.
+//
+// Only the connection pool opened by Connect is monitored.
+func WithLoadShedding(shedding LoadShedding) ClientOption {
+	return func(client *Client) {
+		if shedding.WaitThreshold <= 0 {
+			shedding.WaitThreshold = time.Second
+		}
+		if shedding.Cooldown <= 0 {
+			shedding.Cooldown = 5 * time.Second
+		}
+		client.shedding = &loadShedder{LoadShedding: shedding}
+	}
+}
+
+// observe records the wait of a connection checkout, it reports whether the pool just became exhausted.
+func (shedder *loadShedder) observe(wait time.Duration) bool {
+	if wait <= shedder.WaitThreshold {
+		return false
+	}
+	now := time.Now().UnixNano()
+	previous := shedder.exhaustedUntil.Swap(now + int64(shedder.Cooldown))
+	return previous < now
+}
+
+// shed reports whether an operation with priority must be refused.
+func (shedder *loadShedder) shed(priority Priority) bool {
+	return priority < shedder.MinPriority && time.Now().UnixNano() < shedder.exhaustedUntil.Load()
+}
@@ -0,0 +1,65 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	if got := priorityFromContext(context.Background()); got != PriorityNormal {
+		t.Errorf("Expected PriorityNormal by default, got %d", got)
+	}
+	if got := priorityFromContext(WithPriority(context.Background(), PriorityHigh)); got != PriorityHigh {
+		t.Errorf("Expected PriorityHigh, got %d", got)
+	}
+}
+
+func TestWithLoadShedding(t *testing.T) {
+	logger := &recordingLogger{}
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithLogger(logger), WithLoadShedding(LoadShedding{WaitThreshold: 50 * time.Millisecond}))
+	if client.shedding.Cooldown != 5*time.Second {
+		t.Errorf("Expected a default cooldown of 5s, got %s", client.shedding.Cooldown)
+	}
+	low := client.WithContext(WithPriority(context.Background(), PriorityLow))
+
+	monitor := client.poolMonitor()
+	monitor.Event(&event.PoolEvent{Type: event.GetSucceeded, Duration: 10 * time.Millisecond})
+	if client.shedding.shed(PriorityLow) {
+		t.Error("Expected no shedding below the threshold")
+	}
+
+	monitor.Event(&event.PoolEvent{Type: event.GetSucceeded, Duration: time.Second})
+	monitor.Event(&event.PoolEvent{Type: event.GetFailed, Duration: time.Second})
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected one warning when the pool becomes exhausted, got %v", logger.warnings)
+	}
+	if _, err := low.Get("test", "1"); !errors.Is(err, ErrLoadShed) {
+		t.Errorf("Expected ErrLoadShed for a low priority operation, got %v", err)
+	}
+	if _, err := client.Get("test", "1"); errors.Is(err, ErrLoadShed) {
+		t.Errorf("Expected a normal priority operation to go through, got %v", err)
+	}
+
+	client.shedding.exhaustedUntil.Store(time.Now().UnixNano())
+	if client.shedding.shed(PriorityLow) {
+		t.Error("Expected shedding to stop after the cooldown")
+	}
+}
+
+func TestWithLoadShedding_defaults(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithLoadShedding(LoadShedding{}))
+	if client.shedding.WaitThreshold != time.Second {
+		t.Errorf("Expected a default wait threshold of 1s, got %s", client.shedding.WaitThreshold)
+	}
+
+	client.poolMonitor().Event(&event.PoolEvent{Type: event.GetSucceeded, Duration: 10 * time.Millisecond})
+	if client.shedding.shed(PriorityLow) {
+		t.Error("Expected no shedding under normal load with a zero value configuration")
+	}
+}
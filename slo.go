@@ -0,0 +1,251 @@
+package mongo
+
+import (
+	"sync"
+	"time"
+)
+
+// sloSlots is the number of slots the rolling window of an SLO is divided in.
+const sloSlots = 60
+
+// sloBuckets are the upper bounds of the buckets of an SLOStatus.Histogram, as multiples of the SLO latency.
+var sloBuckets = []float64{0.25, 0.5, 1, 2, 4}
+
+// SLO is a latency objective of the operations of the Client, see WithSLOs.
+type SLO struct {
+	// Name of the objective, defaults to Collection
+	Name string
+
+	// Collection and Operations select the operations of the objective, any collection when empty and any operation
+	// when nil
+	Collection string
+	Operations []string
+
+	// Latency an operation must succeed within to count as good, Objective the fraction of good operations, for
+	// example 0.999
+	Latency   time.Duration
+	Objective float64
+
+	// Window is the rolling window the objective is measured over, defaults to 1 hour
+	Window time.Duration
+
+	// BurnThresholds are the burn rates reported to the callback of WithSLOs when crossed, defaults to 1, the rate
+	// that exhausts the error budget in exactly Window. MinOperations is the number of operations in Window below
+	// which nothing is reported, so a single slow call is not an alert.
+	BurnThresholds []float64
+	MinOperations  int64
+}
+
+// SLOStatus is the state of an SLO over its rolling window, see Client.SLOStatus.
+type SLOStatus struct {
+	// Name of the SLO
+	Name string
+
+	// Operations is the number of operations in the window, Good how many of them succeeded within the latency
+	Operations int64
+	Good       int64
+
+	// BurnRate is how fast the error budget is spent, the fraction of bad operations divided by the allowed fraction.
+	// Above 1 the budget runs out before the end of the window.
+	BurnRate float64
+
+	// Histogram counts the operations in the window by duration
+	Histogram []LatencyBucket
+}
+
+// LatencyBucket is a bucket of an SLOStatus.Histogram.
+type LatencyBucket struct {
+	// Max is the exclusive upper bound of the bucket, 0 for the last bucket collecting the slower operations
+	Max   time.Duration
+	Count int64
+}
+
+// SLOBurn is reported to the callback of WithSLOs when the burn rate of an SLO crosses one of its BurnThresholds.
+type SLOBurn struct {
+	// Threshold is the crossed threshold
+	Threshold float64
+
+	// Status of the SLO when the threshold was crossed
+	Status SLOStatus
+}
+
+// sloTracker measures the SLOs of WithSLOs.
+type sloTracker struct {
+	mu       sync.Mutex
+	trackers []*sloWindow
+	fn       func(SLOBurn)
+}
+
+// sloWindow is the rolling window of an SLO.
+type sloWindow struct {
+	slo   SLO
+	slots [sloSlots]sloSlot
+
+	// crossed are the thresholds crossed and not yet recovered from
+	crossed map[float64]bool
+}
+
+// sloSlot counts the operations of a slot of a window.
+type sloSlot struct {
+	start     time.Time
+	total     int64
+	good      int64
+	histogram [6]int64
+}
+
+// WithSLOs measures the latency objectives of slos over their rolling window, see Client.SLOStatus, and calls fn when
+// the burn rate of one crosses one of its BurnThresholds upwards, for alerting:
+//
+//	client := mongo.NewMongoClient(url, "shop", ctx, mongo.WithSLOs(func(burn mongo.SLOBurn) {
+//		pager.Alert(fmt.Sprintf("%s burns its error budget %.1fx", burn.Status.Name, burn.Status.BurnRate))
+//	}, mongo.SLO{Collection: "orders", Latency: 100 * time.Millisecond, Objective: 0.999,
+//		BurnThresholds: []float64{2, 10}, MinOperations: 100}))
+//
+// An operation is bad when it fails or takes longer than Latency, including its retries. A threshold is reported again
+// once the burn rate went back below it. fn is called on the goroutine of the operation, keep it fast, it may be nil.
+// Each instance of the application measures its own operations.
+func WithSLOs(fn func(SLOBurn), slos ...SLO) ClientOption {
+	return func(client *Client) {
+		if client.slos == nil {
+			client.slos = &sloTracker{}
+		}
+		client.slos.fn = fn
+		for _, slo := range slos {
+			if slo.Name == "" {
+				slo.Name = slo.Collection
+			}
+			if slo.Window <= 0 {
+				slo.Window = time.Hour
+			}
+			if len(slo.BurnThresholds) == 0 {
+				slo.BurnThresholds = []float64{1}
+			}
+			client.slos.trackers = append(client.slos.trackers, &sloWindow{slo: slo, crossed: map[float64]bool{}})
+		}
+	}
+}
+
+// SLOStatus returns the state of the SLOs of WithSLOs, in the order they were configured.
+func (connectionDetails *Client) SLOStatus() []SLOStatus {
+	if connectionDetails.slos == nil {
+		return nil
+	}
+	tracker := connectionDetails.slos
+	now := time.Now()
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	statuses := make([]SLOStatus, len(tracker.trackers))
+	for i, window := range tracker.trackers {
+		statuses[i] = window.status(now)
+	}
+	return statuses
+}
+
+// record measures op, which took elapsed and returned err, and reports the crossed thresholds.
+func (tracker *sloTracker) record(op operation, elapsed time.Duration, err error) {
+	now := time.Now()
+	var burns []SLOBurn
+	tracker.mu.Lock()
+	for _, window := range tracker.trackers {
+		if !window.slo.matches(op) {
+			continue
+		}
+		window.add(now, elapsed, err == nil && elapsed <= window.slo.Latency)
+		burns = append(burns, window.crossings(now)...)
+	}
+	tracker.mu.Unlock()
+
+	if tracker.fn == nil {
+		return
+	}
+	for _, burn := range burns {
+		tracker.fn(burn)
+	}
+}
+
+// matches reports whether op is measured by the SLO.
+func (slo SLO) matches(op operation) bool {
+	if slo.Collection != "" && slo.Collection != op.collection {
+		return false
+	}
+	if len(slo.Operations) == 0 {
+		return true
+	}
+	for _, name := range slo.Operations {
+		if name == op.name {
+			return true
+		}
+	}
+	return false
+}
+
+// slotDuration returns the duration of a slot of the window.
+func (window *sloWindow) slotDuration() time.Duration {
+	duration := window.slo.Window / sloSlots
+	if duration <= 0 {
+		duration = time.Nanosecond
+	}
+	return duration
+}
+
+// add counts an operation that took elapsed at now.
+func (window *sloWindow) add(now time.Time, elapsed time.Duration, good bool) {
+	duration := window.slotDuration()
+	start := now.Truncate(duration)
+	slot := &window.slots[(start.UnixNano()/int64(duration))%sloSlots]
+	if !slot.start.Equal(start) {
+		*slot = sloSlot{start: start}
+	}
+	slot.total++
+	if good {
+		slot.good++
+	}
+	bucket := len(sloBuckets)
+	for i, max := range sloBuckets {
+		if elapsed < time.Duration(max*float64(window.slo.Latency)) {
+			bucket = i
+			break
+		}
+	}
+	slot.histogram[bucket]++
+}
+
+// status returns the state of the window at now.
+func (window *sloWindow) status(now time.Time) SLOStatus {
+	status := SLOStatus{Name: window.slo.Name, Histogram: make([]LatencyBucket, len(sloBuckets)+1)}
+	for i, max := range sloBuckets {
+		status.Histogram[i].Max = time.Duration(max * float64(window.slo.Latency))
+	}
+	since := now.Add(-window.slo.Window)
+	for _, slot := range window.slots {
+		if !slot.start.After(since) {
+			continue
+		}
+		status.Operations += slot.total
+		status.Good += slot.good
+		for i, count := range slot.histogram {
+			status.Histogram[i].Count += count
+		}
+	}
+	if budget := 1 - window.slo.Objective; status.Operations > 0 && budget > 0 {
+		bad := float64(status.Operations-status.Good) / float64(status.Operations)
+		status.BurnRate = bad / budget
+	}
+	return status
+}
+
+// crossings returns the thresholds of the window crossed at now, and forgets the ones recovered from.
+func (window *sloWindow) crossings(now time.Time) []SLOBurn {
+	status := window.status(now)
+	var burns []SLOBurn
+	for _, threshold := range window.slo.BurnThresholds {
+		switch {
+		case status.BurnRate < threshold:
+			delete(window.crossed, threshold)
+		case !window.crossed[threshold] && status.Operations >= window.slo.MinOperations:
+			window.crossed[threshold] = true
+			burns = append(burns, SLOBurn{Threshold: threshold, Status: status})
+		}
+	}
+	return burns
+}
@@ -0,0 +1,73 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLOWindow(t *testing.T) {
+	window := &sloWindow{
+		slo: SLO{Name: "orders", Latency: 100 * time.Millisecond, Objective: 0.9, Window: time.Minute,
+			BurnThresholds: []float64{1, 5}},
+		crossed: map[float64]bool{},
+	}
+	now := time.Now()
+	for i := 0; i < 18; i++ {
+		window.add(now, 10*time.Millisecond, true)
+	}
+	window.add(now, 150*time.Millisecond, false)
+	if burns := window.crossings(now); len(burns) != 0 {
+		t.Errorf("Expected no crossing at a burn rate of 0.5, got %v", burns)
+	}
+	window.add(now, 500*time.Millisecond, false)
+	burns := window.crossings(now)
+	if len(burns) != 1 || burns[0].Threshold != 1 {
+		t.Fatalf("Expected the threshold 1 to be crossed, got %v", burns)
+	}
+
+	status := burns[0].Status
+	if status.Operations != 20 || status.Good != 18 || status.BurnRate < 0.99 || status.BurnRate > 1.01 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if status.Histogram[0].Count != 18 || status.Histogram[3].Count != 1 || status.Histogram[5].Count != 1 {
+		t.Errorf("Unexpected histogram %+v", status.Histogram)
+	}
+	if burns := window.crossings(now); len(burns) != 0 {
+		t.Errorf("Expected the crossed threshold to be reported once, got %v", burns)
+	}
+
+	if status := window.status(now.Add(2 * time.Minute)); status.Operations != 0 || status.BurnRate != 0 {
+		t.Errorf("Expected the operations to leave the window, got %+v", status)
+	}
+}
+
+func TestSLO_matches(t *testing.T) {
+	slo := SLO{Collection: "orders", Operations: []string{"Get"}}
+	if !slo.matches(operation{name: "Get", collection: "orders"}) {
+		t.Error("Expected Get orders to match")
+	}
+	if slo.matches(operation{name: "Add", collection: "orders"}) || slo.matches(operation{name: "Get", collection: "users"}) {
+		t.Error("Expected other operations and collections not to match")
+	}
+	if !(SLO{}).matches(operation{name: "Add", collection: "users"}) {
+		t.Error("Expected an SLO without collection and operations to match every operation")
+	}
+}
+
+func TestWithSLOs(t *testing.T) {
+	var burns []SLOBurn
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithSLOs(func(burn SLOBurn) { burns = append(burns, burn) },
+			SLO{Collection: "orders", Latency: time.Second, Objective: 0.99}))
+
+	if _, err := client.Add("orders", data{ID: "1"}); err == nil {
+		t.Fatal("Expected a connection error")
+	}
+	status := client.SLOStatus()
+	if len(status) != 1 || status[0].Name != "orders" || status[0].Operations != 1 || status[0].Good != 0 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if len(burns) != 1 || burns[0].Threshold != 1 {
+		t.Errorf("Expected the default threshold to be crossed, got %v", burns)
+	}
+}
@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"time"
+)
+
+// SlowOperation is an operation that took longer than the threshold of WithSlowOperationThreshold.
+type SlowOperation struct {
+	// Operation is the name of the Client method, for example "GetAllCustom"
+	Operation string
+
+	// Database and Collection the operation ran against
+	Database   string
+	Collection string
+
+	// Filter of the operation as extended JSON, redacted and truncated like OperationError.Filter
+	Filter string
+
+	// Duration of the whole operation, including retries
+	Duration time.Duration
+
+	// Err is the error returned by the operation, if any
+	Err error
+}
+
+// slowOperations reports the operations slower than threshold to fn, see WithSlowOperationThreshold.
+type slowOperations struct {
+	threshold time.Duration
+	fn        func(SlowOperation)
+}
+
+// WithSlowOperationThreshold reports every operation taking longer than threshold to fn, or logs it as a warning with
+// the Logger of the Client when fn is nil. Slow operations usually point at a missing index:
+//
+//	client := mongo.NewMongoClient(url, "app", ctx, mongo.WithLogger(logger),
+//		mongo.WithSlowOperationThreshold(200*time.Millisecond, nil))
+func WithSlowOperationThreshold(threshold time.Duration, fn func(SlowOperation)) ClientOption {
+	return func(client *Client) {
+		client.slow = &slowOperations{threshold: threshold, fn: fn}
+	}
+}
+
+// reportSlow reports op when it took longer than the threshold, err is the error it returned.
+func (connectionDetails *Client) reportSlow(op operation, elapsed time.Duration, err error) {
+	slow := connectionDetails.slow
+	if slow == nil || elapsed <= slow.threshold {
+		return
+	}
+	report := SlowOperation{
+		Operation:  op.name,
+		Database:   connectionDetails.DatabaseName,
+		Collection: op.collection,
+		Filter:     summarizeFilter(newRedactor(connectionDetails.redactedFields), op.filter),
+		Duration:   elapsed,
+		Err:        err,
+	}
+	if slow.fn == nil {
+		connectionDetails.log().Warn("mongo: slow operation", "operation", report.Operation, "collection", report.Collection,
+			"filter", report.Filter, "duration", report.Duration)
+		return
+	}
+	slow.fn(report)
+}
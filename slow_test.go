@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReportSlow(t *testing.T) {
+	var reports []SlowOperation
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithSlowOperationThreshold(100*time.Millisecond, func(op SlowOperation) { reports = append(reports, op) }))
+	op := operation{name: "GetAllCustom", collection: "users", filter: bson.D{{Key: "password", Value: "hunter2"}}}
+
+	client.reportSlow(op, 50*time.Millisecond, nil)
+	if len(reports) != 0 {
+		t.Fatalf("Expected no report below the threshold, got %+v", reports)
+	}
+	client.reportSlow(op, 150*time.Millisecond, nil)
+	want := SlowOperation{Operation: "GetAllCustom", Database: "test", Collection: "users",
+		Filter: `{"password":"REDACTED"}`, Duration: 150 * time.Millisecond}
+	if len(reports) != 1 || reports[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, reports)
+	}
+
+	logger := &recordingLogger{}
+	client = NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithLogger(logger), WithSlowOperationThreshold(100*time.Millisecond, nil))
+	client.reportSlow(op, time.Second, nil)
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected the slow operation to be logged, got %v", logger.warnings)
+	}
+}
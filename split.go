@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReadFromWriter sends a read of a SplitClient to its write Client, for reads that must see the writes just made.
+func ReadFromWriter() Option {
+	return func(o *operationOptions) {
+		o.readFromWriter = true
+	}
+}
+
+// SplitClient sends reads to one Client and writes to another, like an analytics replica or Atlas Data Federation for
+// the reads and the primary deployment for the writes. Use ReadFromWriter to send a read to the write Client.
+//
+// SplitClient implements Store.
+type SplitClient struct {
+	writer *Client
+	reader *Client
+}
+
+// NewSplitClient returns a SplitClient writing with writeClient and reading with readClient.
+func NewSplitClient(writeClient *Client, readClient *Client) *SplitClient {
+	return &SplitClient{writer: writeClient, reader: readClient}
+}
+
+// Writer returns the Client used for writes.
+func (split *SplitClient) Writer() *Client {
+	return split.writer
+}
+
+// Reader returns the Client used for reads.
+func (split *SplitClient) Reader() *Client {
+	return split.reader
+}
+
+// read returns the Client of a read made with opts.
+func (split *SplitClient) read(opts []Option) *Client {
+	if newOperationOptions(opts).readFromWriter {
+		return split.writer
+	}
+	return split.reader
+}
+
+func (split *SplitClient) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	return split.writer.Add(collectionName, data, opts...)
+}
+
+func (split *SplitClient) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	return split.writer.AddMany(collectionName, data, opts...)
+}
+
+func (split *SplitClient) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Update(collectionName, id, data, opts...)
+}
+
+func (split *SplitClient) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.UpdateCustom(collectionName, filter, data, opts...)
+}
+
+func (split *SplitClient) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.UpdateMany(collectionName, filter, data, opts...)
+}
+
+func (split *SplitClient) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Replace(collectionName, id, data, opts...)
+}
+
+func (split *SplitClient) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Save(collectionName, data, opts...)
+}
+
+func (split *SplitClient) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Increment(collectionName, id, field, delta, opts...)
+}
+
+func (split *SplitClient) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Push(collectionName, id, field, value, opts...)
+}
+
+func (split *SplitClient) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Pull(collectionName, id, field, value, opts...)
+}
+
+func (split *SplitClient) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.AddToSet(collectionName, id, field, value, opts...)
+}
+
+func (split *SplitClient) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	return split.writer.Unset(collectionName, id, fields, opts...)
+}
+
+func (split *SplitClient) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return split.writer.Delete(collectionName, id, opts...)
+}
+
+func (split *SplitClient) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return split.writer.DeleteCustom(collectionName, filter, opts...)
+}
+
+func (split *SplitClient) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return split.writer.DeleteMany(collectionName, filter, opts...)
+}
+
+func (split *SplitClient) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return split.read(opts).Get(collectionName, id, opts...)
+}
+
+func (split *SplitClient) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	return split.read(opts).GetCustom(collectionName, filter, opts...)
+}
+
+func (split *SplitClient) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return split.read(opts).GetAll(collectionName, id, result, opts...)
+}
+
+func (split *SplitClient) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	return split.read(opts).GetAllCustom(collectionName, filter, result, opts...)
+}
@@ -0,0 +1,36 @@
+package mongo
+
+import "testing"
+
+func TestSplitClient_read(t *testing.T) {
+	writer := NewMongoClientDefault("mongodb://localhost:27017", "primary")
+	reader := NewMongoClientDefault("mongodb://localhost:27017", "analytics")
+	split := NewSplitClient(writer, reader)
+
+	if split.read(nil) != reader {
+		t.Errorf("Expected reads to go to the read Client")
+	}
+	if split.read([]Option{ReadFromWriter()}) != writer {
+		t.Errorf("Expected ReadFromWriter to send reads to the write Client")
+	}
+	if split.Writer() != writer || split.Reader() != reader {
+		t.Errorf("Unexpected Clients")
+	}
+}
+
+func TestSplitClient(t *testing.T) {
+	reader := client.WithDatabase(client.DatabaseName + "_split_read")
+	split := NewSplitClient(client, reader)
+	defer client.Delete("test_collection", "split")
+
+	if _, err := split.Add("test_collection", data{ID: "split", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if result, _ := split.Get("test_collection", "split"); result == nil || result.Err() == nil {
+		t.Errorf("Expected the read Client not to have the document")
+	}
+	result, err := split.Get("test_collection", "split", ReadFromWriter())
+	if err != nil || result.Err() != nil {
+		t.Errorf("Expected the write Client to have the document, got %v", err)
+	}
+}
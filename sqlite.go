@@ -0,0 +1,533 @@
+package mongo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrUnsupportedBySQLite is matched with errors.Is by the UnsupportedError of a SQLiteStore.
+var ErrUnsupportedBySQLite = errors.New("mongo: not supported by the SQLite store")
+
+// UnsupportedError is returned by SQLiteStore for a filter, update or option it does not implement, before anything
+// is read or written.
+type UnsupportedError struct {
+	// Operation is the name of the Store method, for example "UpdateMany"
+	Operation string
+
+	// Feature is the unsupported query operator, update operator or option, for example "$size"
+	Feature string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("mongo: %s does not support %s on SQLite", e.Operation, e.Feature)
+}
+
+// Is reports whether target is ErrUnsupportedBySQLite.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedBySQLite
+}
+
+// sqliteSchema creates the table of a SQLiteStore.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS documents (
+	collection TEXT NOT NULL,
+	id         TEXT NOT NULL,
+	document   TEXT NOT NULL,
+	PRIMARY KEY (collection, id)
+)`
+
+// SQLiteStore is a Store keeping documents in a SQLite database, for edge deployments that cannot run MongoDB. It
+// works with any database/sql SQLite driver, which is left to the application so this module does not depend on one:
+//
+//	db, err := sql.Open("sqlite", "data.db") // modernc.org/sqlite, or "sqlite3" with github.com/mattn/go-sqlite3
+//	if err != nil {
+//		return err
+//	}
+//	store, err := mongo.NewSQLiteStore(db)
+//
+// Documents are stored as canonical extended JSON in a single "documents" table keyed by collection and "_id", so
+// every BSON type round trips. Filters with an "_id" equality use the primary key, other filters scan the collection
+// and are evaluated like FakeClient does. SQLiteStore supports:
+//
+//   - the filters of FakeClient, on fields and dotted paths with equality, comparison, $in, $exists, $regex and logical
+//     operators
+//   - the $set, $unset, $inc, $push, $pull and $addToSet update operators
+//   - the Upsert and Unordered options
+//
+// Other query and update operators and ArrayFilters return an *UnsupportedError matching ErrUnsupportedBySQLite.
+// Options with no meaning for SQLite, like WriteConcern and Comment, are ignored. Each call is one transaction. There
+// are no secondary indexes, aggregations, change streams or sessions.
+type SQLiteStore struct {
+	mu sync.Mutex
+	db *sql.DB
+}
+
+// NewSQLiteStore returns a SQLiteStore using db, creating its table when it does not exist.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Add can be used to add document to the store
+func (store *SQLiteStore) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var id interface{}
+	err = store.write(func(tx *sql.Tx) error {
+		id, err = store.insert(tx, collectionName, doc)
+		return err
+	})
+	var writeErr mongo.WriteError
+	if errors.As(err, &writeErr) {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{writeErr}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: id}, nil
+}
+
+// AddMany can be used to add multiple documents to the store
+func (store *SQLiteStore) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	if err := validateDocuments(collectionName, nil, data); err != nil {
+		return nil, err
+	}
+	o := newOperationOptions(opts)
+	ordered := o.ordered == nil || *o.ordered
+
+	result := &mongo.InsertManyResult{}
+	var bulkErrors []mongo.BulkWriteError
+	err := store.write(func(tx *sql.Tx) error {
+		for i, item := range data {
+			doc, err := toDocument(item)
+			if err != nil {
+				return err
+			}
+			id, err := store.insert(tx, collectionName, doc)
+			var writeErr mongo.WriteError
+			if errors.As(err, &writeErr) {
+				writeErr.Index = i
+				bulkErrors = append(bulkErrors, mongo.BulkWriteError{WriteError: writeErr})
+				if ordered {
+					break
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			result.InsertedIDs = append(result.InsertedIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bulkErrors) > 0 {
+		return result, mongo.BulkWriteException{WriteErrors: bulkErrors}
+	}
+	return result, nil
+}
+
+// Update can be used to update values by its ID
+func (store *SQLiteStore) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	return store.update("Update", collectionName, bson.M{"_id": id}, updateDocument(data), newOperationOptions(opts), false)
+}
+
+// UpdateCustom updates the first document matching filter with the fields of data.
+func (store *SQLiteStore) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	return store.update("UpdateCustom", collectionName, filter, updateDocument(data), newOperationOptions(opts), false)
+}
+
+// UpdateMany updates every document matching filter with the fields of data.
+func (store *SQLiteStore) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	return store.update("UpdateMany", collectionName, filter, updateDocument(data), newOperationOptions(opts), true)
+}
+
+// Replace replaces the whole document with the given ID.
+func (store *SQLiteStore) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.replace(collectionName, id, doc, newOperationOptions(opts))
+}
+
+// Save inserts data, or replaces the document with the same "_id".
+func (store *SQLiteStore) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := validateDocuments(collectionName, nil, []interface{}{data}); err != nil {
+		return nil, err
+	}
+	doc, id, err := withID(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.replace(collectionName, id, doc, newOperationOptions(append(opts, Upsert())))
+}
+
+// Increment adds delta to field of the document with the given id.
+func (store *SQLiteStore) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("Increment", collectionName, bson.M{"_id": id}, bson.D{{Key: "$inc", Value: bson.D{{Key: field, Value: delta}}}}, newOperationOptions(opts), false)
+}
+
+// Push appends value to the array field of the document with the given id.
+func (store *SQLiteStore) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("Push", collectionName, bson.M{"_id": id}, bson.D{{Key: "$push", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// Pull removes value from the array field of the document with the given id.
+func (store *SQLiteStore) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("Pull", collectionName, bson.M{"_id": id}, bson.D{{Key: "$pull", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// AddToSet appends value to the array field of the document with the given id unless it is already present.
+func (store *SQLiteStore) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("AddToSet", collectionName, bson.M{"_id": id}, bson.D{{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}}}, newOperationOptions(opts), false)
+}
+
+// Unset removes fields from the document with the given id.
+func (store *SQLiteStore) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	unset := bson.D{}
+	for _, field := range fields {
+		unset = append(unset, bson.E{Key: field, Value: ""})
+	}
+	return store.update("Unset", collectionName, bson.M{"_id": id}, bson.D{{Key: "$unset", Value: unset}}, newOperationOptions(opts), false)
+}
+
+// Delete deletes the document with the given id.
+func (store *SQLiteStore) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return store.delete("Delete", collectionName, bson.M{"_id": id}, 1)
+}
+
+// DeleteCustom deletes the first document matching filter.
+func (store *SQLiteStore) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return store.delete("DeleteCustom", collectionName, filter, 1)
+}
+
+// DeleteMany deletes every document matching filter.
+func (store *SQLiteStore) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	return store.delete("DeleteMany", collectionName, filter, -1)
+}
+
+// Get finds one document by its "_id".
+func (store *SQLiteStore) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return store.get("Get", collectionName, bson.M{"_id": id})
+}
+
+// GetCustom finds one document by a filter - bson.M{}, or bson.D{}
+func (store *SQLiteStore) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	return store.get("GetCustom", collectionName, filter)
+}
+
+// GetAll finds all documents by "_id".
+//
+// The 'result' parameter needs to be a pointer.
+func (store *SQLiteStore) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return store.getAll("GetAll", collectionName, bson.M{"_id": id}, result)
+}
+
+// GetAllCustom finds all documents by filter - bson.M{}, or bson.D{}.
+//
+// The 'result' parameter needs to be a pointer.
+func (store *SQLiteStore) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	return store.getAll("GetAllCustom", collectionName, filter, result)
+}
+
+// get returns the first document matching filter.
+func (store *SQLiteStore) get(op string, collectionName string, filter interface{}) (*mongo.SingleResult, error) {
+	query, err := sqliteQuery(op, filter)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := store.find(store.db, collectionName, query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil), nil
+	}
+	return mongo.NewSingleResultFromDocument(docs[0], nil, nil), nil
+}
+
+// getAll decodes the documents matching filter into result.
+func (store *SQLiteStore) getAll(op string, collectionName string, filter interface{}, result interface{}) error {
+	query, err := sqliteQuery(op, filter)
+	if err != nil {
+		return err
+	}
+	docs, err := store.find(store.db, collectionName, query, -1)
+	if err != nil {
+		return err
+	}
+	return decodeDocuments(docs, result)
+}
+
+// update applies the update document to the first, or every, document matching filter.
+func (store *SQLiteStore) update(op string, collectionName string, filter interface{}, update bson.D, o *operationOptions, many bool) (*mongo.UpdateResult, error) {
+	query, err := sqliteQuery(op, filter)
+	if err != nil {
+		return nil, err
+	}
+	operators, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range operators {
+		if !updateOperators[operator.Key] {
+			return nil, &UnsupportedError{Operation: op, Feature: operator.Key}
+		}
+	}
+	if len(o.arrayFilters) > 0 {
+		return nil, &UnsupportedError{Operation: op, Feature: "ArrayFilters"}
+	}
+	limit := 1
+	if many {
+		limit = -1
+	}
+
+	result := &mongo.UpdateResult{}
+	err = store.write(func(tx *sql.Tx) error {
+		docs, err := store.find(tx, collectionName, query, limit)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			updated, err := applyUpdate(doc, operators)
+			if err != nil {
+				return err
+			}
+			id, _ := lookupField(doc, "_id")
+			if err := store.put(tx, collectionName, id, updated); err != nil {
+				return err
+			}
+			result.MatchedCount++
+			if !equalValues(doc, updated) {
+				result.ModifiedCount++
+			}
+		}
+
+		if result.MatchedCount > 0 || o.upsert == nil || !*o.upsert {
+			return nil
+		}
+		doc, err := applyUpdate(equalityFields(query), operators)
+		if err != nil {
+			return err
+		}
+		id, err := store.insert(tx, collectionName, doc)
+		if err != nil {
+			return err
+		}
+		result.UpsertedCount, result.UpsertedID = 1, id
+		return nil
+	})
+	var writeErr mongo.WriteError
+	if errors.As(err, &writeErr) {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{writeErr}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// replace replaces the document with the given id by doc.
+func (store *SQLiteStore) replace(collectionName string, id interface{}, doc bson.D, o *operationOptions) (*mongo.UpdateResult, error) {
+	doc = append(bson.D{{Key: "_id", Value: id}}, withoutField(doc, "_id")...)
+
+	result := &mongo.UpdateResult{}
+	err := store.write(func(tx *sql.Tx) error {
+		existing, err := store.find(tx, collectionName, bson.D{{Key: "_id", Value: id}}, 1)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			result.MatchedCount = 1
+			if !equalValues(existing[0], doc) {
+				result.ModifiedCount = 1
+			}
+			return store.put(tx, collectionName, id, doc)
+		}
+		if o.upsert == nil || !*o.upsert {
+			return nil
+		}
+		if _, err := store.insert(tx, collectionName, doc); err != nil {
+			return err
+		}
+		result.UpsertedCount, result.UpsertedID = 1, id
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// delete removes up to limit documents matching filter, all of them if limit is negative.
+func (store *SQLiteStore) delete(op string, collectionName string, filter interface{}, limit int) (*mongo.DeleteResult, error) {
+	query, err := sqliteQuery(op, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &mongo.DeleteResult{}
+	err = store.write(func(tx *sql.Tx) error {
+		docs, err := store.find(tx, collectionName, query, limit)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			id, _ := lookupField(doc, "_id")
+			key, err := idKey(id)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`DELETE FROM documents WHERE collection = ? AND id = ?`, collectionName, key); err != nil {
+				return err
+			}
+			result.DeletedCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sqlQuerier is implemented by *sql.DB and *sql.Tx.
+type sqlQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// find returns up to limit documents of collectionName matching query, all of them if limit is negative, in insertion
+// order. The primary key is used when query has an "_id" equality.
+func (store *SQLiteStore) find(q sqlQuerier, collectionName string, query bson.D, limit int) ([]bson.D, error) {
+	statement := `SELECT document FROM documents WHERE collection = ?`
+	args := []interface{}{collectionName}
+	if id, ok := idEquality(query); ok {
+		key, err := idKey(id)
+		if err != nil {
+			return nil, err
+		}
+		statement += ` AND id = ?`
+		args = append(args, key)
+	}
+	rows, err := q.Query(statement+` ORDER BY rowid`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []bson.D
+	for rows.Next() && (limit < 0 || len(docs) < limit) {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON([]byte(value), true, &doc); err != nil {
+			return nil, err
+		}
+		if matches(doc, query) {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, rows.Err()
+}
+
+// insert inserts doc, adding an ObjectID "_id" when missing. A taken "_id" returns a mongo.WriteError with the
+// duplicate key code.
+func (store *SQLiteStore) insert(tx *sql.Tx, collectionName string, doc bson.D) (interface{}, error) {
+	id, ok := lookupField(doc, "_id")
+	if !ok {
+		id = primitive.NewObjectID()
+		doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+	}
+	key, err := idKey(id)
+	if err != nil {
+		return nil, err
+	}
+	var exists int
+	err = tx.QueryRow(`SELECT 1 FROM documents WHERE collection = ? AND id = ?`, collectionName, key).Scan(&exists)
+	if err == nil {
+		return nil, mongo.WriteError{Code: 11000, Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: _id_ dup key: { _id: %v }", collectionName, id)}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	value, err := bson.MarshalExtJSON(doc, true, false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`INSERT INTO documents (collection, id, document) VALUES (?, ?, ?)`, collectionName, key, string(value)); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// put overwrites the document with the given id, keeping its "_id" when doc changed it.
+func (store *SQLiteStore) put(tx *sql.Tx, collectionName string, id interface{}, doc bson.D) error {
+	key, err := idKey(id)
+	if err != nil {
+		return err
+	}
+	value, err := bson.MarshalExtJSON(append(bson.D{{Key: "_id", Value: id}}, withoutField(doc, "_id")...), true, false)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE documents SET document = ? WHERE collection = ? AND id = ?`, string(value), collectionName, key)
+	return err
+}
+
+// write runs fn in a transaction, committed when fn returns nil. Writes are serialized as SQLite has a single writer.
+func (store *SQLiteStore) write(fn func(tx *sql.Tx) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteQuery converts filter to a document, returning an *UnsupportedError when it has an operator matches cannot
+// evaluate.
+func sqliteQuery(op string, filter interface{}) (bson.D, error) {
+	query, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+	if operator := unsupportedQueryOperator(query); operator != "" {
+		return nil, &UnsupportedError{Operation: op, Feature: operator}
+	}
+	return query, nil
+}
@@ -0,0 +1,42 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSQLiteStore_unsupported(t *testing.T) {
+	// unsupported features are refused before the database is used
+	store := &SQLiteStore{}
+	tests := []struct {
+		name    string
+		call    func() error
+		feature string
+	}{
+		{"query operator", func() error {
+			_, err := store.UpdateMany("users", bson.M{"tags": bson.M{"$size": 2}}, bson.M{"adult": true})
+			return err
+		}, "$size"},
+		{"top level operator", func() error {
+			return store.GetAllCustom("users", bson.M{"$where": "true"}, &[]bson.M{})
+		}, "$where"},
+		{"array filters", func() error {
+			_, err := store.UpdateCustom("users", bson.M{}, bson.M{"a.$[x]": 1}, ArrayFilters(bson.M{"x": 1}))
+			return err
+		}, "ArrayFilters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var unsupported *UnsupportedError
+			if !errors.As(err, &unsupported) || unsupported.Feature != tt.feature {
+				t.Fatalf("Expected an UnsupportedError for %s, got %v", tt.feature, err)
+			}
+			if !errors.Is(err, ErrUnsupportedBySQLite) {
+				t.Errorf("Expected the error to match ErrUnsupportedBySQLite")
+			}
+		})
+	}
+}
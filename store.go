@@ -0,0 +1,39 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Store is the set of document operations implemented by Client.
+//
+// Depend on Store instead of *Client to use FakeClient in unit tests.
+type Store interface {
+	Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error)
+	AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error)
+	Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error)
+	Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error)
+	Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error)
+	DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error)
+	DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error)
+	Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error)
+	GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error)
+	GetAll(collectionName string, id string, result interface{}, opts ...Option) error
+	GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error
+}
+
+var (
+	_ Store = (*Client)(nil)
+	_ Store = (*FakeClient)(nil)
+	_ Store = (*ShardRouter)(nil)
+	_ Store = (*SplitClient)(nil)
+	_ Store = (*FileStore)(nil)
+	_ Store = (*SQLiteStore)(nil)
+)
@@ -0,0 +1,238 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrUnknownSummary is returned by RecomputeSummary for a summary that was not configured with WithSummaries.
+var ErrUnknownSummary = errors.New("mongo: unknown summary")
+
+// Summary is a field of the documents of a parent collection computed from their children in another collection,
+// like the total of an order or the number of comments of a post, see WithSummaries.
+type Summary struct {
+	// Name of the summary
+	Name string
+
+	// Parent collection holding the summary field
+	Parent string
+
+	// Field of the parent documents holding the summary
+	Field string
+
+	// Child collection the summary is computed from
+	Child string
+
+	// ForeignKey is the field of the child documents holding the "_id" of their parent
+	ForeignKey string
+
+	// Accumulator computes the summary from the children of a parent, for example Count() or Sum("amount")
+	Accumulator Accumulator
+}
+
+// WithSummaries keeps the summaries up to date when their child collection is written through the Client.
+//
+// After Add and AddMany the parents of the added children are recomputed. Before any other write the parents of the
+// matching children are read with distinct, they are recomputed after the write together with the new parents of
+// updated children. Writes that are not made through the Client are not seen, use RecomputeSummary after them.
+func WithSummaries(summaries ...Summary) ClientOption {
+	return func(client *Client) {
+		client.summaries = append(client.summaries, summaries...)
+	}
+}
+
+// RecomputeSummary recomputes the summary called name for the given parents, or for every parent when parentIDs is
+// empty.
+func (connectionDetails *Client) RecomputeSummary(name string, parentIDs ...interface{}) error {
+	summary, ok := connectionDetails.summary(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownSummary, name)
+	}
+	op := operation{name: "RecomputeSummary", collection: summary.Parent}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		if len(parentIDs) > 0 {
+			return summary.recompute(ctx, db, parentIDs)
+		}
+		return summary.recomputeAll(ctx, db)
+	})
+}
+
+// summary returns the summary called name.
+func (connectionDetails *Client) summary(name string) (Summary, bool) {
+	for _, summary := range connectionDetails.summaries {
+		if summary.Name == name {
+			return summary, true
+		}
+	}
+	return Summary{}, false
+}
+
+// zero is the summary of a parent without children.
+func (summary Summary) zero() interface{} {
+	if summary.Accumulator.operator == "$sum" {
+		return 0
+	}
+	return nil
+}
+
+// recompute recomputes the summary of the given parents.
+func (summary Summary) recompute(ctx context.Context, db *mongo.Database, parentIDs []interface{}) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: summary.ForeignKey, Value: bson.D{{Key: "$in", Value: parentIDs}}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + summary.ForeignKey},
+			{Key: "value", Value: summary.Accumulator.document()},
+		}}},
+	}
+	cursor, err := db.Collection(summary.Child).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		ID    interface{} `bson:"_id"`
+		Value interface{} `bson:"value"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(parentIDs))
+	for _, id := range parentIDs {
+		value := summary.zero()
+		for _, group := range groups {
+			if equalValues(group.ID, id) {
+				value = group.Value
+				break
+			}
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: id}}).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: summary.Field, Value: value}}}}))
+	}
+	_, err = db.Collection(summary.Parent).BulkWrite(ctx, models)
+	return err
+}
+
+// recomputeAll resets the summary of every parent, then merges the summaries computed from every child.
+func (summary Summary) recomputeAll(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(summary.Parent).UpdateMany(ctx, bson.D{},
+		bson.D{{Key: "$set", Value: bson.D{{Key: summary.Field, Value: summary.zero()}}}})
+	if err != nil {
+		return err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + summary.ForeignKey},
+			{Key: "value", Value: summary.Accumulator.document()},
+		}}},
+		{{Key: "$merge", Value: bson.D{
+			{Key: "into", Value: summary.Parent},
+			{Key: "on", Value: "_id"},
+			{Key: "whenMatched", Value: bson.A{bson.D{{Key: "$set", Value: bson.D{{Key: summary.Field, Value: "$$new.value"}}}}}},
+			{Key: "whenNotMatched", Value: "discard"},
+		}}},
+	}
+	cursor, err := db.Collection(summary.Child).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// captureSummaryParents reads the parents of the children op is about to write, for the summaries of its
+// collection.
+func (connectionDetails *Client) captureSummaryParents(ctx context.Context, state *operationState) {
+	if !staleWrite(state.name()) {
+		return
+	}
+	for _, summary := range connectionDetails.summaries {
+		if summary.Child != state.collection {
+			continue
+		}
+		parents, err := connectionDetails.distinctParents(ctx, summary, state.filter)
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to read summary parents", "summary", summary.Name, "operation", state.name(), "error", err)
+			continue
+		}
+		if state.summaryParents == nil {
+			state.summaryParents = map[string][]interface{}{}
+		}
+		state.summaryParents[summary.Name] = parents
+	}
+}
+
+// maintainSummaries recomputes the summaries of the parents of the children written by an operation.
+func (connectionDetails *Client) maintainSummaries(ctx context.Context, state *operationState) {
+	for _, summary := range connectionDetails.summaries {
+		if summary.Child != state.collection {
+			continue
+		}
+		var parents []interface{}
+		switch {
+		case state.name() == "Add" || state.name() == "AddMany":
+			for _, document := range state.documents {
+				if doc, err := toDocument(document); err == nil {
+					parents = append(parents, lookupPath(doc, strings.Split(summary.ForeignKey, "."))...)
+				}
+			}
+		case staleWrite(state.name()):
+			parents = state.summaryParents[summary.Name]
+			if !strings.HasPrefix(state.name(), "Delete") {
+				// updated children may have moved to another parent
+				moved, err := connectionDetails.distinctParents(ctx, summary, state.filter)
+				if err != nil {
+					connectionDetails.log().Warn("mongo: unable to read summary parents", "summary", summary.Name, "operation", state.name(), "error", err)
+				}
+				parents = append(parents, moved...)
+			}
+		}
+		parents = uniqueValues(parents)
+		if len(parents) == 0 {
+			continue
+		}
+
+		err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+			return summary.recompute(ctx, db, parents)
+		})
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to maintain summary", "summary", summary.Name, "operation", state.name(), "error", err)
+		}
+	}
+}
+
+// distinctParents returns the parents of the children matching filter.
+func (connectionDetails *Client) distinctParents(ctx context.Context, summary Summary, filter interface{}) ([]interface{}, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	var parents []interface{}
+	err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		parents, err = db.Collection(summary.Child).Distinct(ctx, summary.ForeignKey, filter)
+		return err
+	})
+	return parents, err
+}
+
+// uniqueValues removes the duplicated values.
+func uniqueValues(values []interface{}) []interface{} {
+	unique := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		duplicate := false
+		for _, seen := range unique {
+			if equalValues(seen, value) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,109 @@
+package mongo
+
+import (
+	"errors"
+	"time"
+)
+
+// CacheStats counts the reads of a Cache, see Cache.Stats.
+type CacheStats struct {
+	// Hits, Stale and Misses count the reads that found a fresh value, a stale value and no value. Get and GetOrLoad
+	// do not tell fresh and stale values apart, they count both as hits.
+	Hits   int64
+	Stale  int64
+	Misses int64
+
+	// Refreshes counts the stale values GetOrRevalidate refreshed in the background, RefreshErrors the refreshes that
+	// failed
+	Refreshes     int64
+	RefreshErrors int64
+}
+
+// Stats returns the reads of the cache counted since it was created, each instance counts its own reads.
+func (cache *Cache) Stats() CacheStats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.stats
+}
+
+// count increments the counter of the stats of the cache.
+func (cache *Cache) count(counter *int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	*counter++
+}
+
+// GetOrRevalidate is GetOrLoad serving stale values, for reads that need to be fast more than fresh, like product
+// pages. A value is fresh for fresh and kept for ttl:
+//
+//	err := cache.GetOrRevalidate("product:"+id, &product, time.Minute, time.Hour, func() (interface{}, error) {
+//		return loadProduct(id)
+//	})
+//
+// A fresh value is returned as is. A stale value is returned immediately too, and load is called in the background to
+// store a fresh one, a single refresh of a key runs at a time in the instance. A missing or expired value is loaded
+// before returning like GetOrLoad. A failed refresh is logged, the stale value is served until the next read tries
+// again.
+//
+// The refresh runs with the context of the Client, do not use a Client made with WithContext from a request context
+// that ends with the request.
+func (cache *Cache) GetOrRevalidate(key string, result interface{}, fresh time.Duration, ttl time.Duration,
+	load func() (interface{}, error), opts ...Option) error {
+	entry, err := cache.entry(key, opts)
+	if errors.Is(err, ErrCacheMiss) {
+		cache.count(&cache.stats.Misses)
+		value, err := load()
+		if err != nil {
+			return err
+		}
+		if err := cache.set(key, value, fresh, ttl, opts); err != nil {
+			cache.client.log().Warn("mongo: unable to cache loaded value", "collection", cache.collection, "key", key,
+				"error", err)
+		}
+		return decodeValue(value, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if entry.StaleAt.IsZero() || entry.StaleAt.After(time.Now()) {
+		cache.count(&cache.stats.Hits)
+	} else {
+		cache.count(&cache.stats.Stale)
+		cache.revalidate(key, fresh, ttl, load, opts)
+	}
+	return entry.Value.Unmarshal(result)
+}
+
+// revalidate stores a fresh value of key loaded in the background, unless a refresh of key is already running.
+func (cache *Cache) revalidate(key string, fresh time.Duration, ttl time.Duration, load func() (interface{}, error),
+	opts []Option) {
+	cache.mu.Lock()
+	if cache.refreshing[key] {
+		cache.mu.Unlock()
+		return
+	}
+	if cache.refreshing == nil {
+		cache.refreshing = map[string]bool{}
+	}
+	cache.refreshing[key] = true
+	cache.stats.Refreshes++
+	cache.mu.Unlock()
+
+	go func() {
+		defer func() {
+			cache.mu.Lock()
+			delete(cache.refreshing, key)
+			cache.mu.Unlock()
+		}()
+		value, err := load()
+		if err == nil {
+			err = cache.set(key, value, fresh, ttl, opts)
+		}
+		if err != nil {
+			cache.count(&cache.stats.RefreshErrors)
+			cache.client.log().Warn("mongo: unable to refresh cached value", "collection", cache.collection, "key", key,
+				"error", err)
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_revalidate(t *testing.T) {
+	cache := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test").Cache("cache")
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (interface{}, error) {
+		loads.Add(1)
+		<-release
+		return nil, errors.New("unavailable")
+	}
+	cache.revalidate("1", time.Minute, time.Hour, load, nil)
+	cache.revalidate("1", time.Minute, time.Hour, load, nil)
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats().RefreshErrors == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := cache.Stats(); stats.Refreshes != 1 || stats.RefreshErrors != 1 || loads.Load() != 1 {
+		t.Errorf("Expected a single failed refresh, got %+v and %d loads", stats, loads.Load())
+	}
+}
+
+func TestCache_GetOrRevalidate(t *testing.T) {
+	_ = client.DropCollections([]string{"cache"})
+	cache := client.Cache("cache")
+
+	var name atomic.Value
+	name.Store("Akshay")
+	load := func() (interface{}, error) {
+		return data{ID: "1", Name: name.Load().(string)}, nil
+	}
+	var result data
+	if err := cache.GetOrRevalidate("1", &result, 10*time.Millisecond, time.Minute, load); err != nil || result.Name != "Akshay" {
+		t.Fatalf("GetOrRevalidate() = %+v, %v", result, err)
+	}
+
+	name.Store("Raj")
+	time.Sleep(20 * time.Millisecond)
+	if err := cache.GetOrRevalidate("1", &result, time.Minute, time.Minute, load); err != nil || result.Name != "Akshay" {
+		t.Errorf("Expected the stale value, got %+v, %v", result, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for result.Name != "Raj" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if err := cache.GetOrRevalidate("1", &result, time.Minute, time.Minute, load); err != nil {
+			t.Fatalf("GetOrRevalidate() error = %v", err)
+		}
+	}
+	if result.Name != "Raj" {
+		t.Error("Expected the stale value to be refreshed")
+	}
+	if stats := cache.Stats(); stats.Misses != 1 || stats.Stale < 1 || stats.Refreshes != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tailRestartInterval is how long a Tail waits before opening a new cursor after the previous one died.
+const tailRestartInterval = time.Second
+
+// CreateCappedCollection creates a fixed size collection of maxSize bytes, and at most maxDocuments documents when it
+// is not 0. The oldest documents are removed to make room for new ones, see TailCollection.
+func (connectionDetails *Client) CreateCappedCollection(collectionName string, maxSize int64, maxDocuments int64, opts ...Option) error {
+	return connectionDetails.CreateCollection(collectionName, CollectionOptions{Capped: true, MaxSize: maxSize, MaxDocuments: maxDocuments}, opts...)
+}
+
+// Tail streams the documents of a capped collection, see TailCollection.
+type Tail struct {
+	documents chan bson.Raw
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// TailCollection streams the documents of the capped collection collectionName matching filter, starting with the
+// existing ones, in insertion order. The stream is backed by a tailable await cursor that is reopened after the last
+// streamed "_id" when it dies or fails with a retryable error.
+//
+// The Documents channel is closed when Stop is called, the Client context is done, or on an error that is not
+// retryable, like tailing a collection that is not capped. Err returns that error.
+func (connectionDetails *Client) TailCollection(collectionName string, filter interface{}, opts ...Option) *Tail {
+	ctx, cancel := context.WithCancel(connectionDetails.Context)
+	tail := &Tail{
+		documents: make(chan bson.Raw),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	op := operation{name: "TailCollection", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+
+	go func() {
+		defer close(tail.done)
+		defer close(tail.documents)
+
+		var lastID interface{}
+		for ctx.Err() == nil {
+			err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+				query := filter
+				if lastID != nil {
+					doc, err := toDocument(filter)
+					if err != nil {
+						return err
+					}
+					query = And(Filter{doc: doc}, F("_id").Gt(lastID))
+				}
+				find := op.options.find().SetCursorType(options.TailableAwait)
+				cursor, err := db.Collection(collectionName).Find(ctx, query, find)
+				if err != nil {
+					return err
+				}
+				defer cursor.Close(ctx)
+				for cursor.Next(ctx) {
+					document := append(bson.Raw(nil), cursor.Current...)
+					select {
+					case tail.documents <- document:
+					case <-ctx.Done():
+						return nil
+					}
+					if id, err := document.LookupErr("_id"); err == nil {
+						var value interface{}
+						if err := id.Unmarshal(&value); err == nil {
+							lastID = value
+						}
+					}
+				}
+				return cursor.Err()
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil && !IsRetryable(err) {
+				tail.mu.Lock()
+				tail.err = connectionDetails.wrapError(op, err)
+				tail.mu.Unlock()
+				connectionDetails.log().Warn("mongo: tail stopped", "collection", collectionName, "error", err)
+				return
+			}
+			if sleep(ctx, tailRestartInterval) != nil {
+				return
+			}
+		}
+	}()
+	return tail
+}
+
+// Documents returns the channel of streamed documents.
+func (tail *Tail) Documents() <-chan bson.Raw {
+	return tail.documents
+}
+
+// Err returns the error that stopped the Tail, it is nil after Stop or when the Client context is done.
+func (tail *Tail) Err() error {
+	tail.mu.Lock()
+	defer tail.mu.Unlock()
+	return tail.err
+}
+
+// Stop stops streaming and waits for the Documents channel to be closed.
+func (tail *Tail) Stop() {
+	tail.cancel()
+	<-tail.done
+}
@@ -0,0 +1,58 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTail_Stop(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	tail := client.TailCollection("events", nil)
+
+	stopped := make(chan struct{})
+	go func() {
+		tail.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+	if _, ok := <-tail.Documents(); ok {
+		t.Errorf("Expected the documents channel to be closed")
+	}
+	if err := tail.Err(); err != nil {
+		t.Errorf("Expected no error after Stop, got %v", err)
+	}
+}
+
+func TestClient_TailCollection(t *testing.T) {
+	_ = client.DropCollection("tail_collection")
+	if err := client.CreateCappedCollection("tail_collection", 1<<20, 0); err != nil {
+		t.Fatalf("Unable to create capped collection. %s", err)
+	}
+	if _, err := client.Add("tail_collection", bson.M{"_id": 1, "message": "first"}); err != nil {
+		t.Fatalf("Unable to add document. %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tail := client.WithContext(ctx).TailCollection("tail_collection", nil)
+	defer tail.Stop()
+
+	first := <-tail.Documents()
+	if first.Lookup("message").StringValue() != "first" {
+		t.Errorf("Unexpected document %s", first)
+	}
+	if _, err := client.Add("tail_collection", bson.M{"_id": 2, "message": "second"}); err != nil {
+		t.Fatalf("Unable to add document. %s", err)
+	}
+	second := <-tail.Documents()
+	if second.Lookup("message").StringValue() != "second" {
+		t.Errorf("Unexpected document %s", second)
+	}
+}
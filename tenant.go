@@ -0,0 +1,280 @@
+package mongo
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrMissingTenant is returned by TenantManager.Tenant for an empty tenant ID.
+var ErrMissingTenant = errors.New("mongo: missing tenant ID")
+
+// TenantStrategy selects how a TenantManager isolates tenants.
+type TenantStrategy int
+
+const (
+	// DatabasePerTenant stores every tenant in its own database
+	DatabasePerTenant TenantStrategy = iota
+
+	// TenantFieldInjection stores every tenant in the same collections, a tenant field is added to every document
+	// written and to every filter
+	TenantFieldInjection
+)
+
+// DefaultTenantField is the field used by TenantFieldInjection when TenantConfig.Field is empty.
+const DefaultTenantField = "tenantId"
+
+// TenantConfig configures a TenantManager.
+type TenantConfig struct {
+	Strategy TenantStrategy
+
+	// Field holding the tenant ID with TenantFieldInjection, defaults to DefaultTenantField
+	Field string
+
+	// DatabasePrefix is prepended to the tenant ID to name its database with DatabasePerTenant, defaults to the
+	// Client database name followed by "_"
+	DatabasePrefix string
+}
+
+// TenantManager returns Stores scoped to a single tenant.
+type TenantManager struct {
+	client *Client
+	config TenantConfig
+}
+
+// NewTenantManager returns a TenantManager isolating tenants of client according to config.
+func NewTenantManager(client *Client, config TenantConfig) *TenantManager {
+	if config.Field == "" {
+		config.Field = DefaultTenantField
+	}
+	if config.DatabasePrefix == "" {
+		config.DatabasePrefix = client.DatabaseName + "_"
+	}
+	return &TenantManager{client: client, config: config}
+}
+
+// Tenant returns a Store that only reads and writes the data of tenantID.
+//
+// With DatabasePerTenant it is a view of the Client bound to the tenant database, see Client.WithDatabase. With
+// TenantFieldInjection every filter is restricted to the tenant and every written document carries the tenant field.
+func (manager *TenantManager) Tenant(tenantID string) (Store, error) {
+	if tenantID == "" {
+		return nil, ErrMissingTenant
+	}
+	if manager.config.Strategy == DatabasePerTenant {
+		return manager.client.WithDatabase(manager.config.DatabasePrefix + tenantID), nil
+	}
+	return &tenantStore{client: manager.client, field: manager.config.Field, tenantID: tenantID}, nil
+}
+
+// tenantStore injects the tenant field in every operation of client, InRegion uses it with the zone field.
+type tenantStore struct {
+	client   *Client
+	field    string
+	tenantID string
+}
+
+// scope restricts filter to the tenant.
+func (store *tenantStore) scope(filter interface{}) (Filter, error) {
+	doc, err := toDocument(filter)
+	if err != nil {
+		return Filter{}, err
+	}
+	return And(Filter{doc: doc}, F(store.field).Eq(store.tenantID)), nil
+}
+
+// stamp converts data to a document carrying the tenant field.
+func (store *tenantStore) stamp(data interface{}) (bson.D, error) {
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(withoutField(doc, store.field), bson.E{Key: store.field, Value: store.tenantID}), nil
+}
+
+// stampUpdate is stamp for the data of an update, Updates set the tenant field instead of changing it.
+func (store *tenantStore) stampUpdate(data interface{}) (interface{}, error) {
+	if updates, ok := data.(Updates); ok {
+		return updates.without(store.field).Set(store.field, store.tenantID), nil
+	}
+	return store.stamp(data)
+}
+
+func (store *tenantStore) Add(collectionName string, data interface{}, opts ...Option) (*mongo.InsertOneResult, error) {
+	if err := store.client.validate(operation{name: "Add", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	doc, err := store.stamp(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.Add(collectionName, doc, append(opts, validated(), fromModels(data))...)
+}
+
+func (store *tenantStore) AddMany(collectionName string, data []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	if err := store.client.validate(operation{name: "AddMany", collection: collectionName, options: newOperationOptions(opts)}, data...); err != nil {
+		return nil, err
+	}
+	docs := make([]interface{}, len(data))
+	for i, item := range data {
+		doc, err := store.stamp(item)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+	return store.client.AddMany(collectionName, docs, append(opts, validated(), fromModels(data...))...)
+}
+
+func (store *tenantStore) Update(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("Update", collectionName, bson.M{"_id": id}, data, opts)
+}
+
+func (store *tenantStore) UpdateCustom(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.update("UpdateCustom", collectionName, filter, data, opts)
+}
+
+func (store *tenantStore) UpdateMany(collectionName string, filter interface{}, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := store.client.validate(operation{name: "UpdateMany", collection: collectionName, filter: filter, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := store.stampUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.UpdateMany(collectionName, scoped, doc, append(opts, validated(), fromModels(data))...)
+}
+
+func (store *tenantStore) Replace(collectionName string, id string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := store.client.validate(operation{name: "Replace", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	scoped, err := store.scope(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	doc, err := store.stamp(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.replaceOne("Replace", collectionName, scoped, doc, opts, data)
+}
+
+func (store *tenantStore) Save(collectionName string, data interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	if err := store.client.validate(operation{name: "Save", collection: collectionName, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	doc, id, err := withID(data)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = store.stamp(doc); err != nil {
+		return nil, err
+	}
+	scoped, err := store.scope(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	return store.client.replaceOne("Save", collectionName, scoped, doc, append(opts, Upsert()), data)
+}
+
+func (store *tenantStore) Increment(collectionName string, id string, field string, delta interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.updateByID("Increment", collectionName, id, bson.D{{Key: "$inc", Value: bson.D{{Key: field, Value: delta}}}}, opts)
+}
+
+func (store *tenantStore) Push(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.updateByID("Push", collectionName, id, bson.D{{Key: "$push", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+func (store *tenantStore) Pull(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.updateByID("Pull", collectionName, id, bson.D{{Key: "$pull", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+func (store *tenantStore) AddToSet(collectionName string, id string, field string, value interface{}, opts ...Option) (*mongo.UpdateResult, error) {
+	return store.updateByID("AddToSet", collectionName, id, bson.D{{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}}}, opts)
+}
+
+func (store *tenantStore) Unset(collectionName string, id string, fields []string, opts ...Option) (*mongo.UpdateResult, error) {
+	unset := bson.D{}
+	for _, field := range fields {
+		if field != store.field {
+			unset = append(unset, bson.E{Key: field, Value: ""})
+		}
+	}
+	return store.updateByID("Unset", collectionName, id, bson.D{{Key: "$unset", Value: unset}}, opts)
+}
+
+func (store *tenantStore) Delete(collectionName string, id string, opts ...Option) (*mongo.DeleteResult, error) {
+	return store.DeleteCustom(collectionName, bson.M{"_id": id}, opts...)
+}
+
+func (store *tenantStore) DeleteCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.DeleteCustom(collectionName, scoped, opts...)
+}
+
+func (store *tenantStore) DeleteMany(collectionName string, filter interface{}, opts ...Option) (*mongo.DeleteResult, error) {
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.DeleteMany(collectionName, scoped, opts...)
+}
+
+func (store *tenantStore) Get(collectionName string, id string, opts ...Option) (*mongo.SingleResult, error) {
+	return store.GetCustom(collectionName, bson.M{"_id": id}, opts...)
+}
+
+func (store *tenantStore) GetCustom(collectionName string, filter interface{}, opts ...Option) (*mongo.SingleResult, error) {
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.GetCustom(collectionName, scoped, opts...)
+}
+
+func (store *tenantStore) GetAll(collectionName string, id string, result interface{}, opts ...Option) error {
+	return store.GetAllCustom(collectionName, bson.M{"_id": id}, result, opts...)
+}
+
+func (store *tenantStore) GetAllCustom(collectionName string, filter interface{}, result interface{}, opts ...Option) error {
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return err
+	}
+	return store.client.GetAllCustom(collectionName, scoped, result, opts...)
+}
+
+// update applies data like Client.Update to the first tenant document matching filter, the tenant field cannot be
+// changed.
+func (store *tenantStore) update(name string, collectionName string, filter interface{}, data interface{}, opts []Option) (*mongo.UpdateResult, error) {
+	if err := store.client.validate(operation{name: name, collection: collectionName, filter: filter, options: newOperationOptions(opts)}, data); err != nil {
+		return nil, err
+	}
+	scoped, err := store.scope(filter)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := store.stampUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	return store.client.updateOne(name, collectionName, scoped, updateDocument(doc), opts, data)
+}
+
+// updateByID applies the update document to the tenant document with the given ID.
+func (store *tenantStore) updateByID(name string, collectionName string, id string, update bson.D, opts []Option) (*mongo.UpdateResult, error) {
+	scoped, err := store.scope(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	return store.client.updateOne(name, collectionName, scoped, update, opts)
+}
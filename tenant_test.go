@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTenantManager_Tenant(t *testing.T) {
+	base := NewMongoClientDefault("mongodb://localhost:27017", "test")
+
+	if _, err := NewTenantManager(base, TenantConfig{}).Tenant(""); !errors.Is(err, ErrMissingTenant) {
+		t.Errorf("expected ErrMissingTenant, got %v", err)
+	}
+
+	store, err := NewTenantManager(base, TenantConfig{Strategy: DatabasePerTenant}).Tenant("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant, ok := store.(*Client); !ok || tenant.DatabaseName != "test_acme" {
+		t.Errorf("expected client for database test_acme, got %#v", store)
+	}
+
+	store, err = NewTenantManager(base, TenantConfig{Strategy: DatabasePerTenant, DatabasePrefix: "tenant-"}).Tenant("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant := store.(*Client); tenant.DatabaseName != "tenant-acme" {
+		t.Errorf("expected database tenant-acme, got %s", tenant.DatabaseName)
+	}
+
+	store, err = NewTenantManager(base, TenantConfig{Strategy: TenantFieldInjection}).Tenant("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant, ok := store.(*tenantStore); !ok || tenant.field != DefaultTenantField || tenant.tenantID != "acme" {
+		t.Errorf("expected tenant store for acme, got %#v", store)
+	}
+}
+
+func TestTenantStore_scope(t *testing.T) {
+	store := &tenantStore{client: NewMongoClient("mongodb://localhost:27017", "test", context.Background()), field: "org", tenantID: "acme"}
+
+	tests := []struct {
+		name   string
+		filter interface{}
+		want   bson.D
+	}{
+		{"empty", bson.M{}, bson.D{{Key: "org", Value: "acme"}}},
+		{"id", bson.M{"_id": "1"}, bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "_id", Value: "1"}},
+			bson.D{{Key: "org", Value: "acme"}},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.scope(tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.BSON(), tt.want) {
+				t.Errorf("scope() = %v, want %v", got.BSON(), tt.want)
+			}
+		})
+	}
+
+	if _, err := store.scope(42); err == nil {
+		t.Errorf("expected error for invalid filter")
+	}
+}
+
+func TestTenantStore_stamp(t *testing.T) {
+	store := &tenantStore{field: "org", tenantID: "acme"}
+
+	got, err := store.stamp(data{ID: "1", Name: "Akshay"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := bson.D{{Key: "_id", Value: "1"}, {Key: "name", Value: "Akshay"}, {Key: "org", Value: "acme"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stamp() = %v, want %v", got, want)
+	}
+
+	got, err = store.stamp(bson.M{"org": "other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (bson.D{{Key: "org", Value: "acme"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected tenant field to be overwritten, got %v", got)
+	}
+}
@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TextScoreField is the field SearchText stores the relevance of a document in, add it to the result model:
+//
+//	type Article struct {
+//		Title string  `bson:"title"`
+//		Score float64 `bson:"score"`
+//	}
+const TextScoreField = "score"
+
+// CreateTextIndex creates the text index of collectionName on fields, used by SearchText. A collection has at most one
+// text index, list every searched field in it.
+func (connectionDetails *Client) CreateTextIndex(collectionName string, fields ...string) (string, error) {
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+	}
+	op := operation{name: "CreateTextIndex", collection: collectionName}
+	var name string
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		var err error
+		name, err = db.Collection(collectionName).Indexes().CreateOne(ctx, Index{Keys: keys}.model())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// SearchText finds the documents of collectionName matching query with the text index, the most relevant first, and
+// decodes them into result, a pointer to a slice. The relevance is stored in TextScoreField. query follows the $text
+// syntax, words match any of them, "quoted phrases" must match and -word excludes:
+//
+//	err := client.SearchText("articles", `mongodb "change streams" -legacy`, &articles, mongo.Limit(10))
+//
+// SortAsc and SortDesc break ties between documents with the same relevance.
+func (connectionDetails *Client) SearchText(collectionName string, query string, result interface{}, opts ...Option) error {
+	filter := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}}}
+	op := operation{name: "SearchText", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		score := bson.D{{Key: "$meta", Value: "textScore"}}
+		find := op.options.find()
+		projection := append(bson.D{}, op.options.projection...)
+		find.SetProjection(append(projection, bson.E{Key: TextScoreField, Value: score}))
+		find.SetSort(append(bson.D{{Key: TextScoreField, Value: score}}, op.options.sort...))
+		cursor, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
@@ -0,0 +1,31 @@
+package mongo
+
+import (
+	"testing"
+)
+
+func TestClient_SearchText(t *testing.T) {
+	_ = client.DropCollections([]string{"articles"})
+	articles := []interface{}{
+		data{ID: "1", Name: "Change streams in MongoDB"},
+		data{ID: "2", Name: "MongoDB indexes and change streams, change everything"},
+		data{ID: "3", Name: "Cooking pasta"},
+	}
+	if _, err := client.AddMany("articles", articles); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+	if _, err := client.CreateTextIndex("articles", "name"); err != nil {
+		t.Fatalf("CreateTextIndex() error = %v", err)
+	}
+
+	var results []struct {
+		ID    string  `bson:"_id"`
+		Score float64 `bson:"score"`
+	}
+	if err := client.SearchText("articles", "change streams -pasta", &results, SortAsc("_id")); err != nil {
+		t.Fatalf("SearchText() error = %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "2" || results[0].Score <= results[1].Score {
+		t.Errorf("Expected the articles about change streams, the most relevant first, got %+v", results)
+	}
+}
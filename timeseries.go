@@ -0,0 +1,52 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CreateTimeSeriesCollection creates a time series collection, measurements older than expireAfter are removed by
+// the server when it is not 0. Requires MongoDB 5.0.
+func (connectionDetails *Client) CreateTimeSeriesCollection(collectionName string, series TimeSeriesOptions, expireAfter time.Duration, opts ...Option) error {
+	return connectionDetails.CreateCollection(collectionName, CollectionOptions{TimeSeries: &series, ExpireAfter: expireAfter}, opts...)
+}
+
+// AddMeasurements inserts measurements into a time series collection. The inserts are unordered so the server can
+// batch them by series, a failed measurement does not stop the others.
+func (connectionDetails *Client) AddMeasurements(collectionName string, measurements []interface{}, opts ...Option) (*mongo.InsertManyResult, error) {
+	return connectionDetails.AddMany(collectionName, measurements, append([]Option{Unordered()}, opts...)...)
+}
+
+// Measurements finds the documents of collectionName with timeField in [from, to) and matching filter, oldest first.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) Measurements(collectionName string, timeField string, from, to time.Time, filter interface{}, result interface{}, opts ...Option) error {
+	doc, err := toDocument(filter)
+	if err != nil {
+		return connectionDetails.wrapError(operation{name: "Measurements", collection: collectionName, filter: filter}, err)
+	}
+	rangeFilter := And(Filter{doc: doc}, F(timeField).Between(from, to))
+
+	op := operation{name: "Measurements", collection: collectionName, filter: rangeFilter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		find := op.options.find().SetSort(bson.D{{Key: timeField, Value: 1}})
+		cursor, err := connectionDetails.collection(db, collectionName, op.options).Find(ctx, rangeFilter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
+
+// Downsample reduces the measurements of collectionName matching filter to one document per series and period of
+// unit, see RollupByTime for the units and aggregations. Each result holds the TimeField and MetaField of series and
+// the aggregations by name, sorted by time then series.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) Downsample(collectionName string, series TimeSeriesOptions, unit string, aggregations map[string]Accumulator, filter interface{}, result interface{}, opts ...Option) error {
+	op := operation{name: "Downsample", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.aggregate(op, rollupPipeline(series.TimeField, unit, series.MetaField, aggregations, filter), result)
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_TimeSeries(t *testing.T) {
+	series := TimeSeriesOptions{TimeField: "at", MetaField: "sensor", Granularity: "minutes"}
+	_ = client.DropCollection("timeseries_collection")
+	if err := client.CreateTimeSeriesCollection("timeseries_collection", series, 0); err != nil {
+		t.Fatalf("Unable to create time series collection. %s", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var measurements []interface{}
+	for i := 0; i < 6; i++ {
+		measurements = append(measurements, bson.M{"at": start.Add(time.Duration(i) * 30 * time.Minute), "sensor": "a", "value": i})
+	}
+	if _, err := client.AddMeasurements("timeseries_collection", measurements); err != nil {
+		t.Fatalf("Unable to add measurements. %s", err)
+	}
+
+	var found []bson.M
+	if err := client.Measurements("timeseries_collection", "at", start, start.Add(time.Hour), nil, &found); err != nil {
+		t.Fatalf("Unable to find measurements. %s", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("Expected 2 measurements in the first hour, got %d", len(found))
+	}
+
+	var hourly []struct {
+		At     time.Time `bson:"at"`
+		Sensor string    `bson:"sensor"`
+		Total  int       `bson:"total"`
+	}
+	if err := client.Downsample("timeseries_collection", series, "hour", map[string]Accumulator{"total": Sum("value")}, nil, &hourly); err != nil {
+		t.Fatalf("Unable to downsample. %s", err)
+	}
+	if len(hourly) != 3 || hourly[0].Total != 1 || hourly[2].Total != 9 || hourly[0].Sensor != "a" {
+		t.Errorf("Unexpected downsampled measurements %+v", hourly)
+	}
+}
@@ -0,0 +1,250 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultLeaderboardCollection holds the documents of maintained leaderboards.
+const DefaultLeaderboardCollection = "leaderboards"
+
+// ErrUnknownLeaderboard is returned by Leaderboard for a leaderboard that was not configured with WithLeaderboards.
+var ErrUnknownLeaderboard = errors.New("mongo: unknown leaderboard")
+
+// TopK finds the k documents of collectionName matching filter with the highest scoreField, highest first. Without a
+// filter the query is hinted to an index starting with scoreField when there is one, so the documents are read in
+// order instead of sorted in memory.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) TopK(collectionName string, scoreField string, k int64, filter interface{}, result interface{}, opts ...Option) error {
+	op := operation{name: "TopK", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		collection := connectionDetails.collection(db, collectionName, op.options)
+		find := op.options.find().SetSort(bson.D{{Key: scoreField, Value: -1}}).SetLimit(k)
+		if isEmptyFilter(filter) {
+			filter = bson.D{}
+			hint, err := scoreIndex(ctx, collection, scoreField)
+			if err != nil {
+				return err
+			}
+			if hint != "" {
+				find.SetHint(hint)
+			}
+		}
+		cursor, err := collection.Find(ctx, filter, find)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
+
+// scoreIndex returns the name of an index of collection whose first key is scoreField, or "" when there is none.
+func scoreIndex(ctx context.Context, collection *mongo.Collection, scoreField string) (string, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return "", err
+	}
+	var indexes []struct {
+		Name string `bson:"name"`
+		Key  bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return "", err
+	}
+	for _, index := range indexes {
+		if len(index.Key) == 0 || index.Key[0].Key != scoreField {
+			continue
+		}
+		if _, ok := toFloat(index.Key[0].Value); ok {
+			return index.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// Leaderboard is a maintained list of the Size documents of Collection with the highest ScoreField, see
+// WithLeaderboards.
+type Leaderboard struct {
+	// Name of the leaderboard, it is the ID of its document
+	Name string
+
+	// Collection whose documents are ranked
+	Collection string
+
+	// ScoreField the documents are ranked by, highest first
+	ScoreField string
+
+	// Size is the number of documents kept
+	Size int
+
+	// Fields copied to the leaderboard entries with "_id" and ScoreField
+	Fields []string
+}
+
+// WithLeaderboards keeps a document for each of boards in DefaultLeaderboardCollection.
+//
+// Documents added with Add and AddMany are ranked into the leaderboards directly, any other write to the collection
+// marks its leaderboards as stale and the next call to Leaderboard ranks the documents again with TopK.
+func WithLeaderboards(boards ...Leaderboard) ClientOption {
+	return func(client *Client) {
+		client.leaderboards = append(client.leaderboards, boards...)
+	}
+}
+
+// Leaderboard decodes the entries of the leaderboard called name into result, highest score first. Each entry holds
+// the "_id", the score and the Fields of a ranked document.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) Leaderboard(name string, result interface{}, opts ...Option) error {
+	board, ok := connectionDetails.leaderboard(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLeaderboard, name)
+	}
+	op := operation{name: "Leaderboard", collection: board.Collection, options: newOperationOptions(opts)}
+	var entries []bson.D
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		boards := db.Collection(DefaultLeaderboardCollection)
+		var stored struct {
+			Entries []bson.D `bson:"entries"`
+			Stale   bool     `bson:"stale"`
+		}
+		err := boards.FindOne(ctx, bson.M{"_id": board.Name}).Decode(&stored)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		if err == nil && !stored.Stale {
+			entries = stored.Entries
+			return nil
+		}
+
+		find := op.options.find().SetSort(bson.D{{Key: board.ScoreField, Value: -1}}).
+			SetLimit(int64(board.Size)).SetProjection(board.projection())
+		cursor, err := db.Collection(board.Collection).Find(ctx, bson.D{}, find)
+		if err != nil {
+			return err
+		}
+		entries = nil
+		if err := cursor.All(ctx, &entries); err != nil {
+			return err
+		}
+		_, err = boards.UpdateOne(ctx, bson.M{"_id": board.Name}, bson.M{"$set": bson.M{
+			"collection": board.Collection,
+			"entries":    entries,
+			"stale":      false,
+			"updatedAt":  time.Now(),
+		}}, options.Update().SetUpsert(true))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return connectionDetails.wrapError(op, decodeDocuments(entries, result))
+}
+
+// leaderboard returns the leaderboard called name.
+func (connectionDetails *Client) leaderboard(name string) (Leaderboard, bool) {
+	for _, board := range connectionDetails.leaderboards {
+		if board.Name == name {
+			return board, true
+		}
+	}
+	return Leaderboard{}, false
+}
+
+// projection returns the projection of the leaderboard entries.
+func (board Leaderboard) projection() bson.D {
+	projection := bson.D{{Key: "_id", Value: 1}, {Key: board.ScoreField, Value: 1}}
+	for _, field := range board.Fields {
+		projection = append(projection, bson.E{Key: field, Value: 1})
+	}
+	return projection
+}
+
+// entry returns the leaderboard entry of document, it is false when document has no "_id" to identify the entry.
+// Documents without a numeric score are not ranked and return a nil entry.
+func (board Leaderboard) entry(document interface{}) (bson.D, bool) {
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, false
+	}
+	id, ok := lookupField(doc, "_id")
+	if !ok {
+		return nil, false
+	}
+	score := firstValue(lookupPath(doc, strings.Split(board.ScoreField, ".")))
+	if _, ok := toFloat(score); !ok {
+		return nil, true
+	}
+	entry := bson.D{{Key: "_id", Value: id}}
+	entry = setPath(entry, strings.Split(board.ScoreField, "."), score)
+	for _, field := range board.Fields {
+		if values := lookupPath(doc, strings.Split(field, ".")); len(values) > 0 {
+			entry = setPath(entry, strings.Split(field, "."), values[0])
+		}
+	}
+	return entry, true
+}
+
+// maintainLeaderboards ranks the documents added by op, or marks the leaderboards of its collection as stale. opErr is
+// the error op returned.
+func (connectionDetails *Client) maintainLeaderboards(ctx context.Context, op operation, opErr error) {
+	for _, board := range connectionDetails.leaderboards {
+		if board.Collection != op.collection {
+			continue
+		}
+		var update bson.M
+		switch {
+		case op.name == "Add" || op.name == "AddMany":
+			if opErr != nil {
+				if op.name == "AddMany" {
+					update = staleUpdate()
+				}
+				break
+			}
+			entries := bson.A{}
+			for _, document := range op.documents {
+				entry, ok := board.entry(document)
+				if !ok {
+					update = staleUpdate()
+					break
+				}
+				if entry != nil {
+					entries = append(entries, entry)
+				}
+			}
+			if update == nil && len(entries) > 0 {
+				update = bson.M{
+					"$push": bson.M{"entries": bson.M{
+						"$each":  entries,
+						"$sort":  bson.M{board.ScoreField: -1},
+						"$slice": board.Size,
+					}},
+					"$set": bson.M{"updatedAt": time.Now()},
+				}
+			}
+		case staleWrite(op.name):
+			update = staleUpdate()
+		}
+		if update == nil {
+			continue
+		}
+
+		err := connectionDetails.exec(ctx, func(ctx context.Context, db *mongo.Database) error {
+			// entries are only pushed to an existing leaderboard, a missing one is ranked by the next Leaderboard
+			_, err := db.Collection(DefaultLeaderboardCollection).UpdateOne(ctx, bson.M{"_id": board.Name}, update,
+				options.Update().SetUpsert(update["$push"] == nil))
+			return err
+		})
+		if err != nil {
+			connectionDetails.log().Warn("mongo: unable to maintain leaderboard", "leaderboard", board.Name, "operation", op.name, "error", err)
+		}
+	}
+}
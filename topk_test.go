@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLeaderboard_entry(t *testing.T) {
+	board := Leaderboard{Name: "top", Collection: "players", ScoreField: "stats.score", Size: 10, Fields: []string{"name"}}
+
+	tests := []struct {
+		name     string
+		document interface{}
+		want     bson.D
+		ok       bool
+	}{
+		{"ranked", bson.M{"_id": "1", "name": "Akshay", "age": 30, "stats": bson.M{"score": 42}}, bson.D{
+			{Key: "_id", Value: "1"},
+			{Key: "stats", Value: bson.D{{Key: "score", Value: int32(42)}}},
+			{Key: "name", Value: "Akshay"},
+		}, true},
+		{"no score", bson.M{"_id": "2", "name": "Raj"}, nil, true},
+		{"no id", bson.M{"name": "Raj", "stats": bson.M{"score": 1}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := board.entry(tt.document)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	want := bson.D{{Key: "_id", Value: 1}, {Key: "stats.score", Value: 1}, {Key: "name", Value: 1}}
+	if projection := board.projection(); !reflect.DeepEqual(projection, want) {
+		t.Errorf("projection() = %v, want %v", projection, want)
+	}
+}
+
+func TestClient_Leaderboard_unknown(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:27017", "test")
+	var entries []bson.M
+	if err := client.Leaderboard("missing", &entries); !errors.Is(err, ErrUnknownLeaderboard) {
+		t.Errorf("Expected ErrUnknownLeaderboard, got %v", err)
+	}
+}
+
+func TestClient_TopK(t *testing.T) {
+	_, _ = client.DeleteMany("topk_collection", bson.M{}, AllowUnsafe("test cleanup"))
+	for i, name := range []string{"Akshay", "Raj", "Sam"} {
+		if _, err := client.Add("topk_collection", bson.M{"_id": name, "score": i}); err != nil {
+			t.Fatalf("Unable to add document. %s", err)
+		}
+	}
+
+	var top []struct {
+		ID string `bson:"_id"`
+	}
+	if err := client.TopK("topk_collection", "score", 2, nil, &top); err != nil {
+		t.Fatalf("Unable to get top documents. %s", err)
+	}
+	if len(top) != 2 || top[0].ID != "Sam" || top[1].ID != "Raj" {
+		t.Errorf("Unexpected top documents %v", top)
+	}
+}
@@ -0,0 +1,221 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Updates is an update document built with Set, Inc, Unset, Push and Pull. Update, UpdateCustom, UpdateMany and
+// UpdateWithVersion apply it as is instead of setting the fields of data:
+//
+//	client.Update("users", id, mongo.Set("name", name).Inc("logins", 1).Unset("resetToken"))
+//
+// The fields are checked against the model registered for the collection with WithModel, a typo is returned as a
+// ValidationError before anything is written.
+type Updates struct {
+	ops []updateOp
+}
+
+// updateOp is a single field of an update operator.
+type updateOp struct {
+	operator string
+	field    string
+	value    interface{}
+}
+
+// Set starts Updates setting field to value.
+func Set(field string, value interface{}) Updates {
+	return Updates{}.Set(field, value)
+}
+
+// Inc starts Updates incrementing field by delta.
+func Inc(field string, delta interface{}) Updates {
+	return Updates{}.Inc(field, delta)
+}
+
+// Unset starts Updates removing fields.
+func Unset(fields ...string) Updates {
+	return Updates{}.Unset(fields...)
+}
+
+// Push starts Updates appending value to the array field.
+func Push(field string, value interface{}) Updates {
+	return Updates{}.Push(field, value)
+}
+
+// Pull starts Updates removing every element equal to value from the array field, value can also be a condition
+// like bson.M{"$lt": 5}.
+func Pull(field string, value interface{}) Updates {
+	return Updates{}.Pull(field, value)
+}
+
+// Set also sets field to value.
+func (u Updates) Set(field string, value interface{}) Updates {
+	return u.with("$set", field, value)
+}
+
+// Inc also increments field by delta.
+func (u Updates) Inc(field string, delta interface{}) Updates {
+	return u.with("$inc", field, delta)
+}
+
+// Unset also removes fields.
+func (u Updates) Unset(fields ...string) Updates {
+	for _, field := range fields {
+		u = u.with("$unset", field, "")
+	}
+	return u
+}
+
+// Push also appends value to the array field.
+func (u Updates) Push(field string, value interface{}) Updates {
+	return u.with("$push", field, value)
+}
+
+// Pull also removes every element equal to value from the array field.
+func (u Updates) Pull(field string, value interface{}) Updates {
+	return u.with("$pull", field, value)
+}
+
+// with returns a copy of u with the operation added, u is left unchanged so Updates can be shared.
+func (u Updates) with(operator string, field string, value interface{}) Updates {
+	ops := append(u.ops[:len(u.ops):len(u.ops)], updateOp{operator: operator, field: field, value: value})
+	return Updates{ops: ops}
+}
+
+// without returns a copy of u without the operations on field.
+func (u Updates) without(field string) Updates {
+	var ops []updateOp
+	for _, op := range u.ops {
+		if op.field != field {
+			ops = append(ops, op)
+		}
+	}
+	return Updates{ops: ops}
+}
+
+// Fields returns the updated fields in the order they were added.
+func (u Updates) Fields() []string {
+	fields := make([]string, 0, len(u.ops))
+	seen := map[string]bool{}
+	for _, op := range u.ops {
+		if !seen[op.field] {
+			seen[op.field] = true
+			fields = append(fields, op.field)
+		}
+	}
+	return fields
+}
+
+// BSON returns the update document, the operators in the order they were first used.
+func (u Updates) BSON() bson.D {
+	update := bson.D{}
+	for _, op := range u.ops {
+		i := 0
+		for i < len(update) && update[i].Key != op.operator {
+			i++
+		}
+		if i == len(update) {
+			update = append(update, bson.E{Key: op.operator, Value: bson.D{}})
+		}
+		update[i].Value = append(update[i].Value.(bson.D), bson.E{Key: op.field, Value: op.value})
+	}
+	return update
+}
+
+// MarshalBSON lets Updates be used as an update document.
+func (u Updates) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(u.BSON())
+}
+
+// updateDocument returns the update applying data, Updates as is and any other data with $set.
+func updateDocument(data interface{}) bson.D {
+	if updates, ok := data.(Updates); ok {
+		return updates.BSON()
+	}
+	return bson.D{{Key: "$set", Value: data}}
+}
+
+// WithModel registers the struct model of the documents of collectionName, the fields of the Updates written to the
+// collection must be fields of model, matched by their bson name. Dotted paths are followed into nested structs,
+// array indexes and positional operators like "$" or "$[]" are allowed, maps and interfaces accept any field.
+func WithModel(collectionName string, model interface{}) ClientOption {
+	return func(client *Client) {
+		if client.models == nil {
+			client.models = map[string]reflect.Type{}
+		}
+		client.models[collectionName] = reflect.TypeOf(model)
+	}
+}
+
+// checkUpdates returns a ValidationError for the first field of the Updates in documents that is not a field of
+// model.
+func checkUpdates(collectionName string, model reflect.Type, documents []interface{}) error {
+	for i, document := range documents {
+		updates, ok := document.(Updates)
+		if !ok {
+			continue
+		}
+		for _, field := range updates.Fields() {
+			if !hasField(model, strings.Split(field, ".")) {
+				return &ValidationError{Collection: collectionName, Index: i, Err: fmt.Errorf("unknown field %q", field)}
+			}
+		}
+	}
+	return nil
+}
+
+// hasField reports whether path is a field of t.
+func hasField(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	if _, ok := bsonTypes[t]; ok {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+		if _, err := strconv.Atoi(path[0]); err == nil || strings.HasPrefix(path[0], "$") {
+			path = path[1:]
+		}
+		return hasField(t.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, tagOptions, _ := strings.Cut(field.Tag.Get("bson"), ",")
+			if name == "-" {
+				continue
+			}
+			if strings.Contains(tagOptions, "inline") {
+				if hasField(field.Type, path) {
+					return true
+				}
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			if name == path[0] {
+				return hasField(field.Type, path[1:])
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
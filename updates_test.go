@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUpdates_BSON(t *testing.T) {
+	base := Set("name", "Frodo")
+	updates := base.Inc("age", 1).Set("address.city", "Bree").Unset("tags")
+	want := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "name", Value: "Frodo"}, {Key: "address.city", Value: "Bree"}}},
+		{Key: "$inc", Value: bson.D{{Key: "age", Value: 1}}},
+		{Key: "$unset", Value: bson.D{{Key: "tags", Value: ""}}},
+	}
+	if got := updates.BSON(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if fields := updates.Fields(); !reflect.DeepEqual(fields, []string{"name", "age", "address.city", "tags"}) {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if got := base.Set("age", 2).BSON(); len(got) != 1 || len(got[0].Value.(bson.D)) != 2 {
+		t.Errorf("Expected base to be left unchanged by other updates, got %v", got)
+	}
+}
+
+func TestHasField(t *testing.T) {
+	type item struct {
+		SKU string `bson:"sku"`
+	}
+	type order struct {
+		ID       string                 `bson:"_id"`
+		Items    []item                 `bson:"items"`
+		Meta     map[string]interface{} `bson:"meta"`
+		Customer *fakeUser              `bson:"customer"`
+		Notes    string
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"_id", true},
+		{"notes", true},
+		{"items", true},
+		{"items.0.sku", true},
+		{"items.$.sku", true},
+		{"items.$[].sku", true},
+		{"items.$.price", false},
+		{"meta.anything.goes", true},
+		{"customer.address.city", true},
+		{"customer.adress.city", false},
+		{"notes.length", false},
+		{"total", false},
+	}
+	for _, tt := range tests {
+		if got := hasField(reflect.TypeOf(order{}), strings.Split(tt.path, ".")); got != tt.want {
+			t.Errorf("hasField(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWithModel(t *testing.T) {
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test", WithModel("users", fakeUser{}))
+	op := operation{name: "Update", collection: "users"}
+	if err := client.validate(op, Set("name", "Sam").Inc("age", 1)); err != nil {
+		t.Errorf("Expected known fields to be valid, got %v", err)
+	}
+	err := client.validate(op, Set("nmae", "Sam"))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Err.Error() != `unknown field "nmae"` {
+		t.Errorf("Expected a ValidationError for the typo, got %v", err)
+	}
+	if err := client.validate(operation{name: "Update", collection: "orders"}, Set("nmae", "Sam")); err != nil {
+		t.Errorf("Expected no check without a registered model, got %v", err)
+	}
+}
+
+func TestFakeClient_UpdateWithUpdates(t *testing.T) {
+	fake := newFakeUsers(t)
+	if _, err := fake.Update("users", "1", Inc("age", 1).Push("tags", "ringbearer")); err != nil {
+		t.Fatalf("Unable to update data. %s", err)
+	}
+	var user fakeUser
+	result, _ := fake.Get("users", "1")
+	if err := result.Decode(&user); err != nil || user.Age != 31 || len(user.Tags) != 3 {
+		t.Errorf("Expected the updates to be applied, got %+v (%v)", user, err)
+	}
+}
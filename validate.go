@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by models that check themselves before they are written, see WithValidator.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError is returned when a document is rejected before it is written to the database.
+type ValidationError struct {
+	// Collection the document was written to
+	Collection string
+
+	// Index of the document in AddMany, 0 for the other operations
+	Index int
+
+	// Err is the error returned by Validate or the validator function
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid document %d for %s: %s", e.Index, e.Collection, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WithValidator registers fn to validate the documents written to collectionName by Add, AddMany, Update,
+// UpdateCustom, UpdateMany, Replace and Save. fn receives the data as passed to the operation, for updates it only
+// holds the updated fields.
+//
+// Models implementing Validator are validated by every collection, before the registered functions.
+func WithValidator(collectionName string, fn func(document interface{}) error) ClientOption {
+	return func(client *Client) {
+		if client.validators == nil {
+			client.validators = map[string][]func(interface{}) error{}
+		}
+		client.validators[collectionName] = append(client.validators[collectionName], fn)
+	}
+}
+
+// validated marks the documents of an operation as already validated by the caller.
+func validated() Option {
+	return func(o *operationOptions) {
+		o.validated = true
+	}
+}
+
+// fromModels gives the models the documents of an operation were made from, like the stamped documents of a tenant
+// store, the after hooks of the models are called instead of the ones of the documents.
+func fromModels(models ...interface{}) Option {
+	return func(o *operationOptions) {
+		o.models = models
+	}
+}
+
+// validate calls the before hooks of the documents about to be written by op, then checks them. The error is wrapped
+// in an OperationError.
+func (connectionDetails *Client) validate(op operation, documents ...interface{}) error {
+	if op.options != nil && op.options.validated {
+		return nil
+	}
+	if err := beforeHooks(op.name, documents); err != nil {
+		return connectionDetails.wrapError(op, err)
+	}
+	if model := connectionDetails.models[op.collection]; model != nil {
+		if err := checkUpdates(op.collection, model, documents); err != nil {
+			return connectionDetails.wrapError(op, err)
+		}
+	}
+	err := validateDocuments(op.collection, connectionDetails.validators[op.collection], documents)
+	return connectionDetails.wrapError(op, err)
+}
+
+// validateDocuments calls Validate on the documents implementing Validator, then each of validators.
+func validateDocuments(collectionName string, validators []func(interface{}) error, documents []interface{}) error {
+	for i, document := range documents {
+		if validator, ok := asValidator(document); ok {
+			if err := validator.Validate(); err != nil {
+				return &ValidationError{Collection: collectionName, Index: i, Err: err}
+			}
+		}
+		for _, fn := range validators {
+			if err := fn(document); err != nil {
+				return &ValidationError{Collection: collectionName, Index: i, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// asValidator returns document as a Validator, including values whose Validate method has a pointer receiver.
+func asValidator(document interface{}) (Validator, bool) {
+	if validator, ok := document.(Validator); ok {
+		return validator, true
+	}
+	value := reflect.ValueOf(document)
+	if !value.IsValid() || value.Kind() == reflect.Ptr {
+		return nil, false
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	validator, ok := ptr.Interface().(Validator)
+	return validator, ok
+}
@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+)
+
+type validatedUser struct {
+	ID   string `bson:"_id"`
+	Name string `bson:"name"`
+}
+
+func (user *validatedUser) Validate() error {
+	if user.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func TestValidateDocuments(t *testing.T) {
+	errShort := errors.New("name too short")
+	short := func(document interface{}) error {
+		if user, ok := document.(validatedUser); ok && len(user.Name) < 3 {
+			return errShort
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		validators []func(interface{}) error
+		documents  []interface{}
+		index      int
+		want       error
+	}{
+		{"valid", []func(interface{}) error{short}, []interface{}{validatedUser{ID: "1", Name: "Akshay"}}, 0, nil},
+		{"value receiver", nil, []interface{}{validatedUser{ID: "1"}}, 0, errors.New("name is required")},
+		{"pointer", nil, []interface{}{&validatedUser{ID: "1"}}, 0, errors.New("name is required")},
+		{"registered", []func(interface{}) error{short}, []interface{}{validatedUser{ID: "1", Name: "Akshay"}, validatedUser{ID: "2", Name: "Al"}}, 1, errShort},
+		{"not a model", nil, []interface{}{data{ID: "1"}}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDocuments("users", tt.validators, tt.documents)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Collection != "users" || validationErr.Index != tt.index || validationErr.Err.Error() != tt.want.Error() {
+				t.Errorf("unexpected ValidationError %+v", validationErr)
+			}
+		})
+	}
+}
+
+func TestClient_validate(t *testing.T) {
+	errRejected := errors.New("rejected")
+	client := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test",
+		WithValidator("users", func(document interface{}) error { return errRejected }))
+
+	_, err := client.Add("users", data{ID: "1", Name: "Akshay"})
+	var operationErr *OperationError
+	if !errors.As(err, &operationErr) || operationErr.Operation != "Add" || !errors.Is(err, errRejected) {
+		t.Errorf("expected the validator error wrapped in an OperationError, got %v", err)
+	}
+
+	if err := client.validate(operation{name: "Add", collection: "users", options: newOperationOptions([]Option{validated()})}, data{}); err != nil {
+		t.Errorf("expected validated operations to be skipped, got %v", err)
+	}
+	if err := client.validate(operation{name: "Add", collection: "other"}, data{}); err != nil {
+		t.Errorf("unexpected error for a collection without validators: %v", err)
+	}
+}
+
+func TestFakeClient_validate(t *testing.T) {
+	fake := NewFakeClient()
+	if _, err := fake.Add("users", validatedUser{ID: "1"}); err == nil {
+		t.Errorf("expected invalid model to be rejected")
+	}
+	if _, err := fake.Add("users", validatedUser{ID: "1", Name: "Akshay"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := fake.Update("users", "1", validatedUser{ID: "1"}); err == nil {
+		t.Errorf("expected invalid update to be rejected")
+	}
+}
@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultVectorIndex is the name of the vector search index used by VectorSearch, unless Hint names another one.
+const DefaultVectorIndex = "vector_index"
+
+// VectorScoreField is the field VectorSearch stores the similarity of a document with the query vector in.
+const VectorScoreField = "score"
+
+// maxVectorCandidates is the maximum number of candidates of $vectorSearch.
+const maxVectorCandidates = 10000
+
+// VectorIndex describes an Atlas Vector Search index, see CreateVectorIndex.
+type VectorIndex struct {
+	// Name of the index, defaults to DefaultVectorIndex
+	Name string
+
+	// Field holding the embeddings, an array of numbers
+	Field string
+
+	// Dimensions is the length of the embeddings, as returned by the embedding model
+	Dimensions int
+
+	// Similarity of two embeddings, "cosine", "euclidean" or "dotProduct", defaults to "cosine"
+	Similarity string
+
+	// FilterFields are the fields the filter of VectorSearch can use
+	FilterFields []string
+}
+
+// definition returns the definition of the search index.
+func (index VectorIndex) definition() bson.D {
+	similarity := index.Similarity
+	if similarity == "" {
+		similarity = "cosine"
+	}
+	fields := bson.A{bson.D{
+		{Key: "type", Value: "vector"},
+		{Key: "path", Value: index.Field},
+		{Key: "numDimensions", Value: index.Dimensions},
+		{Key: "similarity", Value: similarity},
+	}}
+	for _, field := range index.FilterFields {
+		fields = append(fields, bson.D{{Key: "type", Value: "filter"}, {Key: "path", Value: field}})
+	}
+	return bson.D{{Key: "fields", Value: fields}}
+}
+
+// CreateVectorIndex creates the Atlas Vector Search index of collectionName and returns its name:
+//
+//	_, err := client.CreateVectorIndex("chunks", mongo.VectorIndex{Field: "embedding", Dimensions: 1536,
+//		FilterFields: []string{"tenantId"}})
+//
+// The index is built in the background, VectorSearch returns no documents until it is ready. Search indexes only
+// exist on Atlas and on deployments running mongot.
+func (connectionDetails *Client) CreateVectorIndex(collectionName string, index VectorIndex) (string, error) {
+	if index.Name == "" {
+		index.Name = DefaultVectorIndex
+	}
+	op := operation{name: "CreateVectorIndex", collection: collectionName}
+	var name string
+	err := connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		model := mongo.SearchIndexModel{
+			Definition: index.definition(),
+			Options:    options.SearchIndexes().SetName(index.Name).SetType("vectorSearch"),
+		}
+		var err error
+		name, err = db.Collection(collectionName).SearchIndexes().CreateOne(ctx, model)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// VectorSearch finds the k documents of collectionName whose embeddings in field are the most similar to
+// queryVector, the most similar first, and decodes them into result, a pointer to a slice. The similarity is stored
+// in VectorScoreField. filter, on the FilterFields of the index, restricts the documents searched, nil searches them
+// all:
+//
+//	var chunks []Chunk
+//	err := client.VectorSearch("chunks", "embedding", embedding, 5, bson.M{"tenantId": tenantID}, &chunks,
+//		mongo.Exclude("embedding"))
+//
+// The search is approximate, it compares queryVector with 10 candidates for each of the k documents. It uses the
+// index DefaultVectorIndex, Hint names another one.
+func (connectionDetails *Client) VectorSearch(collectionName string, field string, queryVector []float32, k int, filter interface{}, result interface{}, opts ...Option) error {
+	op := operation{name: "VectorSearch", collection: collectionName, filter: filter, options: newOperationOptions(opts)}
+	score := bson.D{{Key: VectorScoreField, Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}}}
+	pipeline := mongo.Pipeline{vectorSearchStage(op.options, field, queryVector, k, filter), {{Key: "$set", Value: score}}}
+	if op.options.projection != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: op.options.projection}})
+	}
+	return connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		aggregate := op.options.aggregate()
+		// the index is named in the stage, a hint is not valid with $vectorSearch
+		aggregate.Hint = nil
+		cursor, err := db.Collection(collectionName).Aggregate(ctx, pipeline, aggregate)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
+
+// vectorSearchStage returns the $vectorSearch stage of VectorSearch.
+func vectorSearchStage(o *operationOptions, field string, queryVector []float32, k int, filter interface{}) bson.D {
+	index := DefaultVectorIndex
+	if o.hint != nil {
+		index = fmt.Sprint(o.hint)
+	}
+	candidates := k * 10
+	if candidates > maxVectorCandidates {
+		candidates = maxVectorCandidates
+	}
+	stage := bson.D{
+		{Key: "index", Value: index},
+		{Key: "path", Value: field},
+		{Key: "queryVector", Value: queryVector},
+		{Key: "numCandidates", Value: candidates},
+		{Key: "limit", Value: k},
+	}
+	if filter != nil {
+		stage = append(stage, bson.E{Key: "filter", Value: filter})
+	}
+	return bson.D{{Key: "$vectorSearch", Value: stage}}
+}
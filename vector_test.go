@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestVectorSearchStage(t *testing.T) {
+	vector := []float32{0.1, 0.2}
+	stage := vectorSearchStage(newOperationOptions(nil), "embedding", vector, 5, bson.M{"tenantId": "a"})
+	want := bson.D{{Key: "$vectorSearch", Value: bson.D{
+		{Key: "index", Value: DefaultVectorIndex},
+		{Key: "path", Value: "embedding"},
+		{Key: "queryVector", Value: vector},
+		{Key: "numCandidates", Value: 50},
+		{Key: "limit", Value: 5},
+		{Key: "filter", Value: bson.M{"tenantId": "a"}},
+	}}}
+	if !reflect.DeepEqual(stage, want) {
+		t.Errorf("Unexpected stage\n got %v\nwant %v", stage, want)
+	}
+
+	stage = vectorSearchStage(newOperationOptions([]Option{Hint("chunks_index")}), "embedding", vector, 5000, nil)
+	if index, _ := pathValue(stage, []string{"$vectorSearch", "index"}).(string); index != "chunks_index" {
+		t.Errorf("Expected the index named by Hint, got %v", stage)
+	}
+	if candidates := pathValue(stage, []string{"$vectorSearch", "numCandidates"}); candidates != maxVectorCandidates {
+		t.Errorf("Expected the candidates to be capped, got %v", candidates)
+	}
+	if _, ok := lookupField(stage[0].Value.(bson.D), "filter"); ok {
+		t.Errorf("Expected no filter, got %v", stage)
+	}
+}
+
+func TestVectorIndex_definition(t *testing.T) {
+	definition := VectorIndex{Field: "embedding", Dimensions: 3, FilterFields: []string{"tenantId"}}.definition()
+	want := bson.D{{Key: "fields", Value: bson.A{
+		bson.D{{Key: "type", Value: "vector"}, {Key: "path", Value: "embedding"}, {Key: "numDimensions", Value: 3}, {Key: "similarity", Value: "cosine"}},
+		bson.D{{Key: "type", Value: "filter"}, {Key: "path", Value: "tenantId"}},
+	}}}
+	if !reflect.DeepEqual(definition, want) {
+		t.Errorf("Unexpected definition\n got %v\nwant %v", definition, want)
+	}
+}
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// VersionField is the field holding the version of a document, see UpdateWithVersion.
+const VersionField = "version"
+
+// UpdateWithVersion sets data on the document with the given ID like Update, only when its VersionField is
+// expectedVersion, and increments the version. The new version is returned. ErrVersionConflict is returned, and
+// nothing is written, when the document was changed since expectedVersion was read or does not exist:
+//
+//	version, err := client.UpdateWithVersion("orders", order.ID, order.Version, bson.M{"status": "shipped"})
+//	if errors.Is(err, mongo.ErrVersionConflict) {
+//		// read the order again and retry, or report the conflict
+//	}
+//
+// An expectedVersion of 0 also matches a document without a version. A VersionField in data is ignored.
+func (connectionDetails *Client) UpdateWithVersion(collectionName string, id string, expectedVersion int64, data interface{}, opts ...Option) (int64, error) {
+	var expected interface{} = expectedVersion
+	if expectedVersion == 0 {
+		expected = bson.D{{Key: "$in", Value: bson.A{int64(0), nil}}}
+	}
+	filter := bson.D{{Key: "_id", Value: id}, {Key: VersionField, Value: expected}}
+	op := operation{name: "UpdateWithVersion", collection: collectionName, filter: filter, documents: []interface{}{data}, options: newOperationOptions(opts)}
+	if err := connectionDetails.validate(op, data); err != nil {
+		return 0, err
+	}
+	update, err := versionedUpdate(data)
+	if err != nil {
+		return 0, err
+	}
+	err = connectionDetails.run(op, func(ctx context.Context, db *mongo.Database) error {
+		result, err := connectionDetails.collection(db, collectionName, op.options).UpdateOne(ctx, filter, update, op.options.update())
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expectedVersion + 1, nil
+}
+
+// versionedUpdate returns the update applying data and incrementing the VersionField, see UpdateWithVersion.
+func versionedUpdate(data interface{}) (bson.D, error) {
+	if updates, ok := data.(Updates); ok {
+		return updates.without(VersionField).Inc(VersionField, int64(1)).BSON(), nil
+	}
+	doc, err := toDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: VersionField, Value: int64(1)}}}}
+	if set := withoutField(doc, VersionField); len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	return update, nil
+}
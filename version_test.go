@@ -0,0 +1,35 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_UpdateWithVersion(t *testing.T) {
+	_ = client.DropCollections([]string{"versioned"})
+	if _, err := client.Add("versioned", data{ID: "1", Name: "Akshay"}); err != nil {
+		t.Fatalf("Unable to add data. %s", err)
+	}
+
+	version, err := client.UpdateWithVersion("versioned", "1", 0, bson.M{"name": "Raj", VersionField: 10})
+	if err != nil || version != 1 {
+		t.Fatalf("UpdateWithVersion() = %d, %v", version, err)
+	}
+	if _, err := client.UpdateWithVersion("versioned", "1", 0, bson.M{"name": "Lost"}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for a stale version, got %v", err)
+	}
+	if version, err = client.UpdateWithVersion("versioned", "1", 1, bson.M{"name": "Akshay"}); err != nil || version != 2 {
+		t.Errorf("UpdateWithVersion() = %d, %v", version, err)
+	}
+	if _, err := client.UpdateWithVersion("versioned", "2", 0, bson.M{"name": "Missing"}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for a missing document, got %v", err)
+	}
+
+	var doc bson.M
+	result, _ := client.Get("versioned", "1")
+	if err := result.Decode(&doc); err != nil || doc["name"] != "Akshay" || doc[VersionField] != int64(2) {
+		t.Errorf("Unexpected document %v, %v", doc, err)
+	}
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WindowOutput is a field computed by SetWindowFields.
+type WindowOutput struct {
+	field    string
+	operator bson.E
+	window   bson.D
+}
+
+// MovingAverage averages field over the current document and the documents-1 documents before it into output.
+func MovingAverage(field string, output string, documents int) WindowOutput {
+	return WindowOutput{
+		field:    output,
+		operator: bson.E{Key: "$avg", Value: "$" + field},
+		window:   bson.D{{Key: "documents", Value: bson.A{-(documents - 1), 0}}},
+	}
+}
+
+// MovingAverageByTime averages field over the documents sorted within size units of time before the current one into
+// output, unit is "week", "day", "hour", "minute", "second" or "millisecond". The sort must be on a single date field.
+func MovingAverageByTime(field string, output string, size int, unit string) WindowOutput {
+	return WindowOutput{
+		field:    output,
+		operator: bson.E{Key: "$avg", Value: "$" + field},
+		window:   bson.D{{Key: "range", Value: bson.A{-size, 0}}, {Key: "unit", Value: unit}},
+	}
+}
+
+// CumulativeSum adds field over the current document and every document before it into output.
+func CumulativeSum(field string, output string) WindowOutput {
+	return WindowOutput{
+		field:    output,
+		operator: bson.E{Key: "$sum", Value: "$" + field},
+		window:   bson.D{{Key: "documents", Value: bson.A{"unbounded", "current"}}},
+	}
+}
+
+// Rank sets output to the position of the document in its partition, documents with the same sort values have the
+// same rank and leave a gap after them.
+func Rank(output string) WindowOutput {
+	return WindowOutput{field: output, operator: bson.E{Key: "$rank", Value: bson.D{}}}
+}
+
+// DenseRank is Rank without gaps after documents with the same sort values.
+func DenseRank(output string) WindowOutput {
+	return WindowOutput{field: output, operator: bson.E{Key: "$denseRank", Value: bson.D{}}}
+}
+
+// SetWindowFields returns a $setWindowFields stage computing outputs over the documents sorted by sortBy, within each
+// partition of partitionBy, or over every document when partitionBy is empty. Requires MongoDB 5.0.
+//
+//	pipeline := mongo.Pipeline{
+//		mongo.SetWindowFields("store", bson.D{{Key: "day", Value: 1}},
+//			mongo.MovingAverage("sales", "weekAverage", 7),
+//			mongo.CumulativeSum("sales", "runningTotal")),
+//	}
+//	err := client.Aggregate("daily_sales", pipeline, &results)
+func SetWindowFields(partitionBy string, sortBy bson.D, outputs ...WindowOutput) bson.D {
+	stage := bson.D{}
+	if partitionBy != "" {
+		stage = append(stage, bson.E{Key: "partitionBy", Value: "$" + partitionBy})
+	}
+	if len(sortBy) > 0 {
+		stage = append(stage, bson.E{Key: "sortBy", Value: sortBy})
+	}
+	output := bson.D{}
+	for _, out := range outputs {
+		expression := bson.D{out.operator}
+		if len(out.window) > 0 {
+			expression = append(expression, bson.E{Key: "window", Value: out.window})
+		}
+		output = append(output, bson.E{Key: out.field, Value: expression})
+	}
+	stage = append(stage, bson.E{Key: "output", Value: output})
+	return bson.D{{Key: "$setWindowFields", Value: stage}}
+}
+
+// Aggregate runs the aggregation pipeline on collectionName - mongo.Pipeline{}, bson.A{} or []bson.D{}.
+//
+// The 'result' parameter needs to be a pointer.
+func (connectionDetails *Client) Aggregate(collectionName string, pipeline interface{}, result interface{}, opts ...Option) error {
+	op := operation{name: "Aggregate", collection: collectionName, options: newOperationOptions(opts)}
+	return connectionDetails.aggregate(op, pipeline, result)
+}
@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSetWindowFields(t *testing.T) {
+	stage := SetWindowFields("store", bson.D{{Key: "day", Value: 1}},
+		MovingAverage("sales", "weekAverage", 7),
+		MovingAverageByTime("sales", "hourAverage", 1, "hour"),
+		CumulativeSum("sales", "runningTotal"),
+		Rank("rank"),
+		DenseRank("denseRank"),
+	)
+
+	want := bson.D{{Key: "$setWindowFields", Value: bson.D{
+		{Key: "partitionBy", Value: "$store"},
+		{Key: "sortBy", Value: bson.D{{Key: "day", Value: 1}}},
+		{Key: "output", Value: bson.D{
+			{Key: "weekAverage", Value: bson.D{{Key: "$avg", Value: "$sales"}, {Key: "window", Value: bson.D{{Key: "documents", Value: bson.A{-6, 0}}}}}},
+			{Key: "hourAverage", Value: bson.D{{Key: "$avg", Value: "$sales"}, {Key: "window", Value: bson.D{{Key: "range", Value: bson.A{-1, 0}}, {Key: "unit", Value: "hour"}}}}},
+			{Key: "runningTotal", Value: bson.D{{Key: "$sum", Value: "$sales"}, {Key: "window", Value: bson.D{{Key: "documents", Value: bson.A{"unbounded", "current"}}}}}},
+			{Key: "rank", Value: bson.D{{Key: "$rank", Value: bson.D{}}}},
+			{Key: "denseRank", Value: bson.D{{Key: "$denseRank", Value: bson.D{}}}},
+		}},
+	}}}
+	if !reflect.DeepEqual(stage, want) {
+		t.Errorf("SetWindowFields() = %v, want %v", stage, want)
+	}
+
+	if stage := SetWindowFields("", nil, Rank("rank")); len(stage[0].Value.(bson.D)) != 1 {
+		t.Errorf("Expected only the output without partition and sort, got %v", stage)
+	}
+}
@@ -0,0 +1,188 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrWorkersStarted is returned by StartWorkers when the workers are already running.
+var ErrWorkersStarted = errors.New("mongo: workers already started")
+
+// Worker is a background task run by StartWorkers, like the polling of a Scheduler.
+type Worker struct {
+	// Name identifies the worker in WorkerStatuses and logs, it must be unique
+	Name string
+
+	// Run does the work until ctx is done. When it returns or panics before, it is restarted after RestartDelay
+	Run func(ctx context.Context) error
+
+	// RestartDelay is the wait before restarting Run, defaults to 5 seconds
+	RestartDelay time.Duration
+}
+
+// WorkerStatus is the health of a Worker, see WorkerStatuses.
+type WorkerStatus struct {
+	Name string
+
+	// Running is true while Run is running
+	Running bool
+
+	// StartedAt is when Run was last started
+	StartedAt time.Time
+
+	// Restarts is the number of times Run returned before it was stopped and was restarted
+	Restarts int
+
+	// LastError is the last error returned by Run, or its panic, and LastErrorAt when it happened
+	LastError   error
+	LastErrorAt time.Time
+}
+
+// workerGroup holds the workers of a Client and of its views.
+type workerGroup struct {
+	mu      sync.Mutex
+	workers []*workerState
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// workerState is a registered Worker and its status.
+type workerState struct {
+	Worker
+	status WorkerStatus
+}
+
+// RegisterWorker adds a worker run by StartWorkers, it is started right away when the workers are running. The
+// workers are shared by the views of the Client, like the ones of WithContext and WithDatabase.
+func (connectionDetails *Client) RegisterWorker(worker Worker) error {
+	if worker.Name == "" || worker.Run == nil {
+		return fmt.Errorf("mongo: a worker needs a Name and a Run function")
+	}
+	if worker.RestartDelay <= 0 {
+		worker.RestartDelay = 5 * time.Second
+	}
+	if connectionDetails.workers == nil {
+		connectionDetails.workers = &workerGroup{}
+	}
+	group := connectionDetails.workers
+	group.mu.Lock()
+	defer group.mu.Unlock()
+	for _, existing := range group.workers {
+		if existing.Name == worker.Name {
+			return fmt.Errorf("mongo: worker %q is already registered", worker.Name)
+		}
+	}
+	state := &workerState{Worker: worker, status: WorkerStatus{Name: worker.Name}}
+	group.workers = append(group.workers, state)
+	if group.ctx != nil {
+		connectionDetails.startWorker(group, state)
+	}
+	return nil
+}
+
+// StartWorkers runs every registered worker in its own goroutine until StopWorkers is called or ctx is done, so
+// background work is started and stopped in one place:
+//
+//	_ = client.RegisterWorker(scheduler.Worker())
+//	if err := client.StartWorkers(ctx); err != nil {
+//		...
+//	}
+//	defer client.StopWorkers()
+//
+// A worker returning or panicking before it is stopped is logged and restarted after its RestartDelay, see
+// WorkerStatuses. ErrWorkersStarted is returned when the workers are already running.
+func (connectionDetails *Client) StartWorkers(ctx context.Context) error {
+	if connectionDetails.workers == nil {
+		connectionDetails.workers = &workerGroup{}
+	}
+	group := connectionDetails.workers
+	group.mu.Lock()
+	defer group.mu.Unlock()
+	if group.ctx != nil {
+		return ErrWorkersStarted
+	}
+	group.ctx, group.cancel = context.WithCancel(ctx)
+	for _, state := range group.workers {
+		connectionDetails.startWorker(group, state)
+	}
+	return nil
+}
+
+// StopWorkers stops the workers and waits for them to return. The workers can be started again afterwards.
+func (connectionDetails *Client) StopWorkers() {
+	group := connectionDetails.workers
+	if group == nil {
+		return
+	}
+	group.mu.Lock()
+	cancel := group.cancel
+	group.ctx, group.cancel = nil, nil
+	group.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	group.wg.Wait()
+}
+
+// WorkerStatuses returns the status of every registered worker in the order they were registered, for example to
+// report them from a health endpoint.
+func (connectionDetails *Client) WorkerStatuses() []WorkerStatus {
+	group := connectionDetails.workers
+	if group == nil {
+		return nil
+	}
+	group.mu.Lock()
+	defer group.mu.Unlock()
+	statuses := make([]WorkerStatus, len(group.workers))
+	for i, state := range group.workers {
+		statuses[i] = state.status
+	}
+	return statuses
+}
+
+// startWorker runs state in a goroutine until the context of group is done, group.mu must be held.
+func (connectionDetails *Client) startWorker(group *workerGroup, state *workerState) {
+	ctx := group.ctx
+	group.wg.Add(1)
+	go func() {
+		defer group.wg.Done()
+		for {
+			group.mu.Lock()
+			state.status.Running, state.status.StartedAt = true, time.Now()
+			group.mu.Unlock()
+
+			err := runWorker(ctx, state.Run)
+
+			group.mu.Lock()
+			state.status.Running = false
+			if ctx.Err() == nil {
+				state.status.Restarts++
+				if err != nil {
+					state.status.LastError, state.status.LastErrorAt = err, time.Now()
+				}
+			}
+			group.mu.Unlock()
+			if ctx.Err() != nil {
+				return
+			}
+			connectionDetails.log().Warn("mongo: worker stopped, restarting", "worker", state.Name, "delay", state.RestartDelay, "error", err)
+			if sleep(ctx, state.RestartDelay) != nil {
+				return
+			}
+		}
+	}()
+}
+
+// runWorker calls run, returning its panic as an error.
+func runWorker(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("mongo: worker panicked: %v", r)
+		}
+	}()
+	return run(ctx)
+}
@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_StartWorkers(t *testing.T) {
+	workerClient := NewMongoClientDefault("mongodb://localhost:1/?serverSelectionTimeoutMS=100", "test")
+	var runs atomic.Int32
+	failing := Worker{Name: "failing", RestartDelay: time.Millisecond, Run: func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		if runs.Load() == 2 {
+			return errors.New("failed")
+		}
+		<-ctx.Done()
+		return nil
+	}}
+	if err := workerClient.RegisterWorker(failing); err != nil {
+		t.Fatalf("RegisterWorker() error = %v", err)
+	}
+	if err := workerClient.RegisterWorker(failing); err == nil {
+		t.Error("Expected an error for a duplicate name")
+	}
+	if err := workerClient.StartWorkers(context.Background()); err != nil {
+		t.Fatalf("StartWorkers() error = %v", err)
+	}
+	if err := workerClient.StartWorkers(context.Background()); !errors.Is(err, ErrWorkersStarted) {
+		t.Errorf("Expected ErrWorkersStarted, got %v", err)
+	}
+
+	// a worker registered while running starts right away
+	started := make(chan struct{})
+	err := workerClient.WithContext(context.Background()).RegisterWorker(Worker{Name: "late", Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("RegisterWorker() error = %v", err)
+	}
+	<-started
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	statuses := workerClient.WorkerStatuses()
+	if len(statuses) != 2 || statuses[0].Restarts != 2 || statuses[0].LastError == nil || statuses[0].LastError.Error() != "failed" {
+		t.Errorf("Unexpected statuses %+v", statuses)
+	}
+
+	workerClient.StopWorkers()
+	for _, status := range workerClient.WorkerStatuses() {
+		if status.Running {
+			t.Errorf("Expected %s to be stopped", status.Name)
+		}
+	}
+	if err := workerClient.StartWorkers(context.Background()); err != nil {
+		t.Errorf("Expected the workers to start again, got %v", err)
+	}
+	workerClient.StopWorkers()
+}